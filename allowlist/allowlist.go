@@ -0,0 +1,90 @@
+package allowlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Entry is a time-boxed exception for one issue
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	// Reason explains why the issue is accepted for now
+	Reason string `json:"reason"`
+	// Expires is the last date (YYYY-MM-DD) the exception holds
+	Expires string `json:"expires"`
+}
+
+// dateLayout is the format of Entry.Expires
+const dateLayout = "2006-01-02"
+
+// Allowlist is the set of issues accepted until a date
+type Allowlist struct {
+	Entries []Entry `json:"entries"`
+
+	index map[string]*Entry
+}
+
+// Load reads an allowlist file; a missing file yields an empty allowlist.
+// Every entry needs a fingerprint, a reason and a valid expiry date.
+func Load(path string) (*Allowlist, error) {
+	a := &Allowlist{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		a.buildIndex()
+		return a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, a); err != nil {
+		return nil, fmt.Errorf("failed to parse allowlist %s: %v", path, err)
+	}
+	for i, e := range a.Entries {
+		switch {
+		case e.Fingerprint == "":
+			return nil, fmt.Errorf("allowlist %s: entry %d has no fingerprint", path, i+1)
+		case e.Reason == "":
+			return nil, fmt.Errorf("allowlist %s: entry %s has no reason", path, e.Fingerprint)
+		}
+		if _, err := time.Parse(dateLayout, e.Expires); err != nil {
+			return nil, fmt.Errorf("allowlist %s: entry %s: expires %q is not a YYYY-MM-DD date", path, e.Fingerprint, e.Expires)
+		}
+	}
+	a.buildIndex()
+	return a, nil
+}
+
+func (a *Allowlist) buildIndex() {
+	a.index = make(map[string]*Entry, len(a.Entries))
+	for i := range a.Entries {
+		a.index[a.Entries[i].Fingerprint] = &a.Entries[i]
+	}
+}
+
+// Allows reports whether the fingerprint has an exception that holds on now's
+// date
+func (a *Allowlist) Allows(fingerprint string, now time.Time) bool {
+	e := a.index[fingerprint]
+	return e != nil && !expired(*e, now)
+}
+
+// Expired returns the entries whose expiry date is before now's date and
+// whose issue is still among the current fingerprints. Entries of fixed
+// issues are left alone until someone removes them.
+func (a *Allowlist) Expired(now time.Time, current map[string]bool) []Entry {
+	var entries []Entry
+	for _, e := range a.Entries {
+		if expired(e, now) && current[e.Fingerprint] {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// expired compares dates as text, which YYYY-MM-DD keeps in order
+func expired(e Entry, now time.Time) bool {
+	return now.Format(dateLayout) > e.Expires
+}
@@ -0,0 +1,76 @@
+package allowlist
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeAllowlist(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "allowlist.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAllowlist_Expiry(t *testing.T) {
+	path := writeAllowlist(t, `{"entries": [
+		{"fingerprint": "old", "reason": "legacy checkout", "expires": "2026-10-15"},
+		{"fingerprint": "fixed", "reason": "legacy refunds", "expires": "2026-10-01"},
+		{"fingerprint": "today", "reason": "migration", "expires": "2026-10-16"}
+	]}`)
+	a, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	if a.Allows("old", now) {
+		t.Error("expired entry still allows its issue")
+	}
+	if !a.Allows("today", now) {
+		t.Error("entry expiring today should still hold")
+	}
+	if a.Allows("unknown", now) {
+		t.Error("unknown fingerprint allowed")
+	}
+	expired := a.Expired(now, map[string]bool{"old": true, "today": true})
+	if len(expired) != 1 || expired[0].Fingerprint != "old" {
+		t.Fatalf("expected only the old entry of a current issue to expire, got %+v", expired)
+	}
+}
+
+func TestLoad_InvalidEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no fingerprint", `{"entries": [{"reason": "r", "expires": "2026-01-01"}]}`, "entry 1 has no fingerprint"},
+		{"no reason", `{"entries": [{"fingerprint": "f", "expires": "2026-01-01"}]}`, "entry f has no reason"},
+		{"bad date", `{"entries": [{"fingerprint": "f", "reason": "r", "expires": "01/02/2026"}]}`, "not a YYYY-MM-DD date"},
+		{"no date", `{"entries": [{"fingerprint": "f", "reason": "r"}]}`, "not a YYYY-MM-DD date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeAllowlist(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	a, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Allows("f", time.Now()) || len(a.Expired(time.Now(), map[string]bool{"f": true})) != 0 {
+		t.Error("missing file should yield an empty allowlist")
+	}
+}
@@ -1,11 +1,17 @@
 package analyzers
 
-import "code-analyzer/models"
+import (
+	"context"
+	"io"
+
+	"code-analyzer/models"
+)
 
 // Analyzer is the interface that all code analyzers must implement
 type Analyzer interface {
-	// Run executes the analysis and returns issues found
-	Run(config Config) ([]models.Issue, error)
+	// Run executes the analysis and returns issues found. Once ctx is done it
+	// stops walking and returns the issues found so far with ctx's error.
+	Run(ctx context.Context, config Config) ([]models.Issue, error)
 
 	// Name returns the analyzer name
 	Name() string
@@ -14,15 +20,79 @@ type Analyzer interface {
 	Description() string
 }
 
+// FileMatcher is implemented by language analyzers to declare which files they handle.
+// Language-agnostic analyzers (e.g. conflicts) do not implement it.
+type FileMatcher interface {
+	// Handles reports whether the analyzer would analyze the file at path
+	Handles(path string, config Config) bool
+}
+
+// CPUBound is implemented by analyzers whose run time is bound by parsing
+// rather than by reading files. Concurrent runs start at most one of them
+// per CPU core.
+type CPUBound interface {
+	// CPUBound reports whether the analyzer is CPU-bound
+	CPUBound() bool
+}
+
+// IsCPUBound reports whether a declares itself CPU-bound; analyzers that do
+// not say are IO-bound
+func IsCPUBound(a Analyzer) bool {
+	c, ok := a.(CPUBound)
+	return ok && c.CPUBound()
+}
+
+// RuleDocumenter is implemented by analyzers whose rules link to remediation
+// guidance; the rule_docs config overrides the URLs
+type RuleDocumenter interface {
+	// RuleDocs maps rule IDs to the URL of their documentation
+	RuleDocs() map[string]string
+}
+
+// RuleLister is implemented by analyzers to list the IDs of the rules whose
+// issues they report
+type RuleLister interface {
+	// RuleIDs returns the rule IDs, e.g. "php/commented-function"
+	RuleIDs() []string
+}
+
 // Config holds configuration for running an analyzer
 type Config struct {
-	RootDir      string
-	TopN         int
-	MinValue     int
-	MinRatio     float64 // Minimum ratio (0-100) to include
-	SortBy       string
-	OutputFile   string
-	ExcludePaths []string // Paths to exclude from analysis
+	RootDir            string
+	TopN               int
+	MinValue           int
+	MinRatio           float64 // Minimum ratio (0-100) to include
+	SortBy             string
+	OutputFile         string
+	ExcludePaths       []string            // Paths to exclude from analysis
+	OnlyExtensions     []string            // Runtime -ext filter, applied on top of each analyzer's own file selection
+	FollowSymlinks     bool                // Follow symlinks, visiting each real file once
+	MaxFileSize        int64               // Files larger than this many bytes are skipped (DefaultMaxFileSize when 0)
+	IgnoreComments     []string            // Extra regexes for comments that are never commented code
+	Extensions         []string            // File extensions to analyze (analyzer default when empty)
+	MarkerSizes        []int               // Conflict marker lengths to recognize (7 when empty)
+	TargetBranch       string              // Branch to simulate a merge with (conflicts analyzer)
+	MaxBytes           int                 // File size threshold in bytes (size analyzer)
+	MaxLines           int                 // Line count threshold (size analyzer)
+	MaxLineLength      int                 // Line length threshold (size analyzer)
+	MaxStringLength    int                 // SQL string length threshold in bytes (sql analyzer)
+	MaxComplexity      int                 // Cyclomatic complexity threshold per function (js analyzer)
+	MaxNesting         int                 // Nesting depth threshold per function (js analyzer)
+	LaravelPaths       []string            // Globs of the files the Laravel rules check (php analyzer)
+	Headers            map[string]string   // License header template per extension (license analyzer)
+	RequireCurrentYear bool                // Flag headers whose {year} is not the current year
+	WhitespaceChecks   map[string][]string // Whitespace checks per extension ("default" for the rest)
+	Quiet              bool                // Suppress console tables; warnings still go to stderr
+	Progress           *Progress           // Visited-file progress (may be nil)
+	Stats              *Stats              // Analyzed/skipped file counts (may be nil)
+	Verbose            io.Writer           // Receives per-file decisions with -verbose (nil otherwise)
+	Diagnostics        *Diagnostics        // Collector for non-fatal problems (may be nil)
+	Artifacts          *Artifacts          // Collector for the combined artifact (may be nil)
+	ArtifactKey        string              // Key of the report in the combined artifact
+
+	// Select picks the issues the artifact keeps and counts those over the
+	// cap; it keeps them all when nil. Runs may call it concurrently.
+	Select func([]models.Issue) ([]bool, int)
 }
 
 // Rule represents a single analysis rule that can be applied
@@ -33,3 +103,12 @@ type Rule interface {
 	// Apply applies the rule to content and returns findings
 	Apply(content string) interface{}
 }
+
+// StreamRule is a Rule that can also consume its input incrementally, so
+// very large files never have to be held in memory
+type StreamRule interface {
+	Rule
+
+	// ApplyReader applies the rule to everything read from r
+	ApplyReader(r io.Reader) (interface{}, error)
+}
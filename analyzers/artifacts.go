@@ -0,0 +1,167 @@
+package analyzers
+
+import (
+	"reflect"
+	"sync"
+
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Artifacts collects the reports of every analyzer so they can be written
+// as one combined artifact. A nil *Artifacts is valid and collects nothing.
+type Artifacts struct {
+	mu      sync.Mutex
+	reports map[string]interface{}
+}
+
+// Add records an analyzer's report under key, e.g. "php" or "api/php"
+func (a *Artifacts) Add(key string, report interface{}) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.reports == nil {
+		a.reports = make(map[string]interface{})
+	}
+	a.reports[key] = report
+}
+
+// Reports returns a copy of the collected reports by key
+func (a *Artifacts) Reports() map[string]interface{} {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	reports := make(map[string]interface{}, len(a.reports))
+	for key, report := range a.reports {
+		reports[key] = report
+	}
+	return reports
+}
+
+// WritesArtifact reports whether the run produces an artifact, either a
+// file of its own or a part of the combined artifact
+func (c Config) WritesArtifact() bool {
+	return c.OutputFile != "" || c.Artifacts != nil
+}
+
+// WriteArtifact writes the analyzer's report to OutputFile, if set, and adds
+// it to the combined artifact, if any. With Select set, the issues it drops
+// are left out of the report, wherever in it they are listed, and the ones
+// over the cap are counted in its stats.
+func (c Config) WriteArtifact(report interface{}) error {
+	if c.Select != nil && report != nil {
+		report = selectIssues(report, c.Select)
+	}
+	c.Artifacts.Add(c.ArtifactKey, report)
+	if c.OutputFile == "" {
+		return nil
+	}
+	return utils.WriteArtifact(c.OutputFile, report)
+}
+
+var (
+	issuesType = reflect.TypeOf([]models.Issue(nil))
+	statsType  = reflect.TypeOf((*models.AnalyzerStats)(nil))
+)
+
+// selectIssues returns a copy of report with the issues pick keeps. The
+// issues are passed to pick, and dropped, in the order they are listed.
+func selectIssues(report interface{}, pick func([]models.Issue) ([]bool, int)) interface{} {
+	var issues []models.Issue
+	filterIssues(reflect.ValueOf(report), func(issue models.Issue) bool {
+		issues = append(issues, issue)
+		return true
+	})
+	keep, overflow := pick(issues)
+	next := 0
+	v := filterIssues(reflect.ValueOf(report), func(models.Issue) bool {
+		next++
+		return keep[next-1]
+	})
+	if overflow > 0 && v.Kind() == reflect.Struct {
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).Type() != statsType {
+				continue
+			}
+			stats := &models.AnalyzerStats{}
+			if !v.Field(i).IsNil() {
+				*stats = *v.Field(i).Interface().(*models.AnalyzerStats)
+			}
+			stats.IssuesOverflow = overflow
+			copied.Field(i).Set(reflect.ValueOf(stats))
+		}
+		v = copied
+	}
+	return v.Interface()
+}
+
+// filterIssues returns a copy of v without the issues keep rejects. Structs,
+// slices and pointers holding issues are copied rather than changed, so the
+// analyzer's own results are left as they are.
+func filterIssues(v reflect.Value, keep func(models.Issue) bool) reflect.Value {
+	if !holdsIssues(v.Type()) {
+		return v
+	}
+	switch {
+	case v.Type() == issuesType:
+		if v.IsNil() {
+			return v
+		}
+		kept := make([]models.Issue, 0, v.Len())
+		for _, issue := range v.Interface().([]models.Issue) {
+			if keep(issue) {
+				kept = append(kept, issue)
+			}
+		}
+		return reflect.ValueOf(kept)
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Elem().Type())
+		copied.Elem().Set(filterIssues(v.Elem(), keep))
+		return copied
+	case v.Kind() == reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				copied.Field(i).Set(filterIssues(v.Field(i), keep))
+			}
+		}
+		return copied
+	case v.Kind() == reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(filterIssues(v.Index(i), keep))
+		}
+		return copied
+	}
+	return v
+}
+
+// holdsIssues reports whether values of t can list issues
+func holdsIssues(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return t.Elem().Kind() == reflect.Struct && holdsIssues(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() && (f.Type == issuesType || holdsIssues(f.Type)) {
+				return true
+			}
+		}
+	case reflect.Slice:
+		return t == issuesType || holdsIssues(t.Elem())
+	}
+	return false
+}
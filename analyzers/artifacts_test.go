@@ -0,0 +1,80 @@
+package analyzers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestWriteArtifact(t *testing.T) {
+	combined := &Artifacts{}
+	output := filepath.Join(t.TempDir(), "php", "report.json")
+	config := Config{OutputFile: output, Artifacts: combined, ArtifactKey: "api/php"}
+	if !config.WritesArtifact() {
+		t.Fatal("a run with an output file writes an artifact")
+	}
+	if err := config.WriteArtifact(map[string]int{"total_files": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("artifact not written: %v", err)
+	}
+	if _, ok := combined.Reports()["api/php"]; !ok {
+		t.Errorf("report not collected: %v", combined.Reports())
+	}
+
+	onlyCombined := Config{Artifacts: combined, ArtifactKey: "js"}
+	if !onlyCombined.WritesArtifact() {
+		t.Error("a run collected into the combined artifact writes an artifact")
+	}
+	if err := onlyCombined.WriteArtifact("report"); err != nil {
+		t.Fatal(err)
+	}
+	if len(combined.Reports()) != 2 {
+		t.Errorf("expected 2 reports, got %v", combined.Reports())
+	}
+
+	if (Config{}).WritesArtifact() {
+		t.Error("a run without output writes no artifact")
+	}
+	var nilArtifacts *Artifacts
+	nilArtifacts.Add("php", "report")
+	if nilArtifacts.Reports() != nil {
+		t.Error("a nil Artifacts must collect nothing")
+	}
+}
+
+func TestWriteArtifact_Select(t *testing.T) {
+	results := []models.PHPFileAnalysis{{
+		Path:   "a.php",
+		Issues: []models.Issue{{Severity: "minor", Line: 1}, {Severity: "critical", Line: 2}, {Severity: "critical", Line: 3}},
+	}}
+	combined := &Artifacts{}
+	config := Config{Artifacts: combined, ArtifactKey: "php", Select: func(issues []models.Issue) ([]bool, int) {
+		if len(issues) != 3 {
+			t.Errorf("expected every issue of the report, got %+v", issues)
+		}
+		// Below the minimum, kept, over the cap
+		return []bool{false, true, false}, 1
+	}}
+	stats := &models.AnalyzerStats{FilesAnalyzed: 1}
+	if err := config.WriteArtifact(models.PHPAnalysisReport{TotalFiles: 1, Results: results, Stats: stats}); err != nil {
+		t.Fatal(err)
+	}
+
+	report := combined.Reports()["php"].(models.PHPAnalysisReport)
+	if issues := report.Results[0].Issues; len(issues) != 1 || issues[0].Line != 2 {
+		t.Errorf("expected only the issue on line 2, got %+v", issues)
+	}
+	if report.TotalFiles != 1 || report.Results[0].Path != "a.php" {
+		t.Errorf("the rest of the report must be kept, got %+v", report)
+	}
+	if report.Stats.IssuesOverflow != 1 || report.Stats.FilesAnalyzed != 1 {
+		t.Errorf("expected the overflow in the stats, got %+v", report.Stats)
+	}
+	if len(results[0].Issues) != 3 || stats.IssuesOverflow != 0 {
+		t.Errorf("the analyzer's results must not change, got %+v and %+v", results[0].Issues, stats)
+	}
+}
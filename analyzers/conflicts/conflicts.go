@@ -2,9 +2,9 @@ package conflicts
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 
@@ -13,6 +13,13 @@ import (
 	"code-analyzer/utils"
 )
 
+// Rule IDs of the conflicts analyzer's issues
+const (
+	RuleMarker      = "conflicts/marker"
+	RuleMergeBackup = "conflicts/merge-backup"
+	RulePredicted   = "conflicts/predicted"
+)
+
 // ConflictsAnalyzer detects unresolved merge conflicts in files
 type ConflictsAnalyzer struct {
 	rules []analyzers.Rule
@@ -37,40 +44,88 @@ func (a *ConflictsAnalyzer) Description() string {
 	return "Detects unresolved Git merge conflict markers in files"
 }
 
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *ConflictsAnalyzer) RuleIDs() []string {
+	return []string{RuleMarker, RuleMergeBackup, RulePredicted}
+}
+
+// RuleDocs links the conflict rules to Git's documentation of conflict markers
+func (a *ConflictsAnalyzer) RuleDocs() map[string]string {
+	const markers = "https://git-scm.com/docs/git-merge#_how_conflicts_are_presented"
+	return map[string]string{RuleMarker: markers, RulePredicted: markers}
+}
+
 // Run executes the conflicts analysis
-func (a *ConflictsAnalyzer) Run(config analyzers.Config) ([]models.Issue, error) {
+func (a *ConflictsAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
 	results := []models.ConflictFileAnalysis{}
+	skipped := []models.SkippedFile{}
+	var backups []string
 	var allIssues []models.Issue
+	sizes := markerSizes(config)
 
-	err := filepath.Walk(config.RootDir, func(path string, info os.FileInfo, err error) error {
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return nil
 		}
-
-		// Skip binary files and very large files
-		if info.Size() > 10*1024*1024 { // Skip files > 10MB
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
 			return nil
 		}
 
 		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
 			return nil
 		}
 
-		analysis := a.analyzeFile(path)
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		if isMergeBackup(path) {
+			config.Tracef(path, "reported, leftover merge backup")
+			backups = append(backups, path)
+			allIssues = append(allIssues, mergeBackupIssue(path))
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, sizes)
 		if analysis != nil && len(analysis.ConflictLines) >= config.MinValue {
 			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
 			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
 		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return allIssues, err
+	}
+
+	// Predict conflicts with the target branch before they happen
+	var predicted []string
+	if config.TargetBranch != "" {
+		files, err := PredictConflicts(config.TargetBranch)
+		if err != nil {
+			utils.Warnf("Warning: Failed to predict conflicts with %s: %v\n", config.TargetBranch, err)
+		} else {
+			predicted = files
+			allIssues = append(allIssues, predictedIssues(files, config.TargetBranch)...)
+		}
 	}
 
 	// Sort by number of conflicts
 	sort.Slice(results, func(i, j int) bool {
-		return len(results[i].ConflictLines) > len(results[j].ConflictLines)
+		if len(results[i].ConflictLines) != len(results[j].ConflictLines) {
+			return len(results[i].ConflictLines) > len(results[j].ConflictLines)
+		}
+		return results[i].Path < results[j].Path
 	})
 
 	// Limit to top N
@@ -79,84 +134,104 @@ func (a *ConflictsAnalyzer) Run(config analyzers.Config) ([]models.Issue, error)
 	}
 
 	// Generate artifact if requested
-	if config.OutputFile != "" {
-		if err := a.generateArtifact(results, config); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to generate artifact: %v\n", err)
-		} else {
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, backups, predicted, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
 			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
 		}
 	}
 
 	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintSkipped(skipped)
 	a.printResults(results)
+	a.printMergeBackups(backups)
+	if config.TargetBranch != "" {
+		a.printPredicted(predicted, config.TargetBranch)
+	}
 	return allIssues, nil
 }
 
-func (a *ConflictsAnalyzer) analyzeFile(path string) *models.ConflictFileAnalysis {
-	file, err := os.Open(path)
-	if err != nil {
+func (a *ConflictsAnalyzer) analyzeFile(path string, sizes []int) *models.ConflictFileAnalysis {
+	content, encoding, err := utils.ReadText(path)
+	if err != nil || encoding == utils.EncodingBinary {
 		return nil
 	}
-	defer file.Close()
 
 	var conflictLines []int
 	var conflictSnippets []string
+	var blocks []models.ConflictBlock
+	var current *models.ConflictBlock
+	side := ""
 	lineNum := 0
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(strings.NewReader(content))
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
 		trimmed := strings.TrimSpace(line)
 
-		// Skip empty lines
-		if len(trimmed) == 0 {
-			continue
-		}
-
-		// Git conflict markers have VERY specific format:
-		// <<<<<<< HEAD (or branch) - exactly 7 '<', space, then text, NO other characters after
-		// ======= - EXACTLY and ONLY 7 '=' characters, nothing before or after
-		// >>>>>>> branch - exactly 7 '>', space, then text, NO other characters after
-
-		isConflictMarker := false
-
-		// Start marker: <<<<<<< (must have space after 7th '<')
-		if len(trimmed) >= 8 && trimmed[:7] == "<<<<<<<" && trimmed[7] == ' ' {
-			// Must NOT be in a comment (no /*, */)
-			if !strings.Contains(line, "/*") && !strings.Contains(line, "*/") {
-				isConflictMarker = true
+		kind := MarkerKind(line, sizes)
+		if kind == 0 {
+			if current != nil {
+				addToSide(current, side, line)
 			}
+			continue
 		}
 
-		// Separator: EXACTLY "=======" and nothing else
-		// This is key - CSS comments have more ='s or have */ at the end
-		if trimmed == "=======" {
-			isConflictMarker = true
+		conflictLines = append(conflictLines, lineNum)
+		if len(conflictSnippets) < quotedMarkers {
+			conflictSnippets = append(conflictSnippets, trimmed)
 		}
 
-		// End marker: >>>>>>> (must have space after 7th '>')
-		if len(trimmed) >= 8 && trimmed[:7] == ">>>>>>>" && trimmed[7] == ' ' {
-			// Must NOT be in a comment
-			if !strings.Contains(line, "/*") && !strings.Contains(line, "*/") {
-				isConflictMarker = true
+		switch kind {
+		case '<':
+			// A new start marker closes any unterminated block
+			if current != nil {
+				blocks = append(blocks, *current)
 			}
-		}
-
-		if isConflictMarker {
-			conflictLines = append(conflictLines, lineNum)
-			if len(conflictSnippets) < 5 {
-				conflictSnippets = append(conflictSnippets, trimmed)
+			current = &models.ConflictBlock{
+				BeginLine: lineNum,
+				EndLine:   lineNum,
+				OursLabel: markerLabel(trimmed, kind),
+			}
+			side = "ours"
+		case '|':
+			if current != nil {
+				current.EndLine = lineNum
+				current.BaseLabel = markerLabel(trimmed, kind)
+				current.Diff3 = true
+				side = "base"
+			}
+		case '=':
+			if current != nil {
+				current.EndLine = lineNum
+				side = "theirs"
+			}
+		case '>':
+			if current != nil {
+				current.EndLine = lineNum
+				current.TheirsLabel = markerLabel(trimmed, kind)
+				current.Complete = true
+				blocks = append(blocks, *current)
+				current = nil
+				side = ""
 			}
 		}
 	}
+	if current != nil {
+		blocks = append(blocks, *current)
+	}
 
 	if len(conflictLines) == 0 {
 		return nil
 	}
 
-	// Count conflict blocks (each block has <<<, ===, >>>)
-	conflictBlocks := len(conflictLines) / 3
+	// Count conflict blocks; stray markers without a start still count as one
+	conflictBlocks := len(blocks)
 	if conflictBlocks == 0 {
 		conflictBlocks = 1
 	}
@@ -171,15 +246,21 @@ func (a *ConflictsAnalyzer) analyzeFile(path string) *models.ConflictFileAnalysi
 	// The scanner logic adds lines with Markers.
 	// Let's create an issue for each marker found.
 	for i, line := range conflictLines {
-		desc := "Merge conflict marker"
+		snippet := ""
 		if i < len(conflictSnippets) {
-			desc = fmt.Sprintf("Merge conflict marker: %s", conflictSnippets[i])
+			snippet = conflictSnippets[i]
 		}
+		desc := MarkerDescription(i, snippet)
+		metadata, endLine := blockMetadata(blocks, line, lineNum)
 		issues = append(issues, models.Issue{
 			Path:        path,
 			Description: desc,
+			RuleID:      RuleMarker,
+			Category:    models.CategoryBugRisk,
 			Line:        line,
+			EndLine:     endLine,
 			Severity:    "critical",
+			Metadata:    metadata,
 		})
 	}
 
@@ -188,10 +269,214 @@ func (a *ConflictsAnalyzer) analyzeFile(path string) *models.ConflictFileAnalysi
 		ConflictLines:    conflictLines,
 		ConflictBlocks:   conflictBlocks,
 		ConflictSnippets: conflictSnippets,
+		Blocks:           blocks,
 		Issues:           issues,
 	}
 }
 
+// maxPreviewLines is the number of lines kept per side of a conflict block
+const maxPreviewLines = 3
+
+// addToSide records a content line on the current side of a conflict block
+func addToSide(block *models.ConflictBlock, side, line string) {
+	switch side {
+	case "ours":
+		block.OursLines++
+		if len(block.OursPreview) < maxPreviewLines {
+			block.OursPreview = append(block.OursPreview, "- "+line)
+		}
+	case "base":
+		block.BaseLines++
+		if len(block.BasePreview) < maxPreviewLines {
+			block.BasePreview = append(block.BasePreview, "  "+line)
+		}
+	case "theirs":
+		block.TheirsLines++
+		if len(block.TheirsPreview) < maxPreviewLines {
+			block.TheirsPreview = append(block.TheirsPreview, "+ "+line)
+		}
+	}
+}
+
+// markerLabel returns the branch label following a conflict marker (e.g. "HEAD")
+func markerLabel(marker string, kind byte) string {
+	return strings.TrimSpace(strings.TrimLeft(marker, string(kind)))
+}
+
+// DefaultMarkerSize is the length of Git's conflict markers unless overridden
+// with the conflict-marker-size attribute
+const DefaultMarkerSize = 7
+
+// IsConflictMarker reports whether a line is a Git conflict marker of the default size
+func IsConflictMarker(line string) bool {
+	return MarkerKind(line, []int{DefaultMarkerSize}) != 0
+}
+
+// quotedMarkers is how many markers per file quote their line in the
+// description
+const quotedMarkers = 5
+
+// MarkerDescription is the description of a file's index-th conflict marker,
+// counting from 0. Other analyzers reporting markers use it so their issues
+// share a fingerprint with this analyzer's and are merged with them.
+func MarkerDescription(index int, line string) string {
+	if index >= quotedMarkers {
+		return "Merge conflict marker"
+	}
+	return fmt.Sprintf("Merge conflict marker: %s", strings.TrimSpace(line))
+}
+
+// MarkerKind returns the marker character ('<', '|', '=' or '>') if the line is a
+// Git conflict marker of one of the given sizes, or 0 otherwise.
+//
+// Git conflict markers have VERY specific format (shown for size 7):
+// <<<<<<< HEAD (or branch) - exactly 7 '<', space, then text, NO other characters after
+// ||||||| base - exactly 7 '|', optionally followed by space and text (diff3 style)
+// ======= - EXACTLY and ONLY 7 '=' characters, nothing before or after
+// >>>>>>> branch - exactly 7 '>', space, then text, NO other characters after
+func MarkerKind(line string, sizes []int) byte {
+	trimmed := strings.TrimSpace(line)
+
+	// Skip empty lines
+	if len(trimmed) == 0 {
+		return 0
+	}
+
+	// Must NOT be in a comment (no /*, */)
+	inComment := strings.Contains(line, "/*") || strings.Contains(line, "*/")
+
+	for _, size := range sizes {
+		if size < 1 || len(trimmed) < size {
+			continue
+		}
+		marker := trimmed[0]
+		if trimmed[:size] != strings.Repeat(string(marker), size) {
+			continue
+		}
+
+		switch marker {
+		case '<', '>':
+			// Start/end markers must have a space after the last marker character
+			if len(trimmed) > size && trimmed[size] == ' ' && !inComment {
+				return marker
+			}
+		case '|':
+			// Base marker, label is optional
+			if (len(trimmed) == size || trimmed[size] == ' ') && !inComment {
+				return marker
+			}
+		case '=':
+			// Separator: EXACTLY the marker and nothing else
+			// This is key - CSS comments have more ='s or have */ at the end
+			if len(trimmed) == size {
+				return marker
+			}
+		}
+	}
+
+	return 0
+}
+
+// markerSizes returns the configured marker sizes, or the default
+func markerSizes(config analyzers.Config) []int {
+	if len(config.MarkerSizes) == 0 {
+		return []int{DefaultMarkerSize}
+	}
+	return config.MarkerSizes
+}
+
+// blockMetadata sizes the conflict block containing line. Only the opening
+// marker carries the effort estimate, and the block's last line as endLine,
+// so a block is not counted once per marker. Unterminated blocks run to
+// lastLine.
+func blockMetadata(blocks []models.ConflictBlock, line, lastLine int) (metadata *models.IssueMetadata, endLine int) {
+	for _, block := range blocks {
+		end := block.EndLine
+		if !block.Complete {
+			end = lastLine
+		}
+		if line < block.BeginLine || line > end {
+			continue
+		}
+
+		span := end - block.BeginLine + 1
+		metadata = &models.IssueMetadata{LineSpan: span}
+		if line == block.BeginLine {
+			metadata.EffortMinutes = analyzers.ConflictEffort(span)
+			endLine = end
+		}
+		return metadata, endLine
+	}
+	return nil, 0
+}
+
+// FastScan only looks for conflict markers and leftover .orig merge backups.
+// It skips sorting, artifacts and console tables so it can run in every pipeline stage.
+func FastScan(config analyzers.Config) ([]models.Issue, error) {
+	var issues []models.Issue
+	sizes := markerSizes(config)
+
+	err := utils.Walk(config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if config.TooLarge(info) {
+			return nil
+		}
+		if !config.MatchesExt(path) || utils.ShouldSkip(path, config.ExcludePaths) {
+			return nil
+		}
+
+		if isMergeBackup(path) {
+			issues = append(issues, mergeBackupIssue(path))
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+
+		lineNum, markers := 0, 0
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lineNum++
+			if MarkerKind(scanner.Text(), sizes) != 0 {
+				issues = append(issues, models.Issue{
+					Path:        path,
+					Description: MarkerDescription(markers, scanner.Text()),
+					RuleID:      RuleMarker,
+					Category:    models.CategoryBugRisk,
+					Line:        lineNum,
+					Severity:    "critical",
+				})
+				markers++
+			}
+		}
+		return nil
+	})
+
+	return issues, err
+}
+
+// isMergeBackup reports whether path is a leftover .orig backup of a merge
+func isMergeBackup(path string) bool {
+	return strings.HasSuffix(path, ".orig")
+}
+
+// mergeBackupIssue reports a leftover merge backup file
+func mergeBackupIssue(path string) models.Issue {
+	return models.Issue{
+		Path:        path,
+		Description: "Leftover merge backup file",
+		RuleID:      RuleMergeBackup,
+		Category:    models.CategoryBugRisk,
+		Line:        1,
+		Severity:    "critical",
+	}
+}
+
 func (a *ConflictsAnalyzer) printResults(results []models.ConflictFileAnalysis) {
 	if len(results) == 0 {
 		fmt.Println("✅ No files with unresolved merge conflicts found!")
@@ -233,28 +518,65 @@ func (a *ConflictsAnalyzer) printTop10(results []models.ConflictFileAnalysis) {
 		fmt.Printf("%2d. %s\n", i+1, r.Path)
 		fmt.Printf("    🚨 %d conflict blocks | 📍 Lines: %v\n",
 			r.ConflictBlocks, formatLineNumbers(r.ConflictLines[:utils.Min(6, len(r.ConflictLines))]))
-		if len(r.ConflictSnippets) > 0 {
+		if len(r.Blocks) > 0 {
+			b := r.Blocks[0]
+			fmt.Printf("    💬 Lines %d-%d: %s ↔ %s\n", b.BeginLine, b.EndLine, b.OursLabel, b.TheirsLabel)
+			preview := append(append(append([]string{}, b.OursPreview...), b.BasePreview...), b.TheirsPreview...)
+			for _, l := range preview {
+				fmt.Printf("       %s\n", utils.Truncate(l, 100))
+			}
+		} else if len(r.ConflictSnippets) > 0 {
 			fmt.Printf("    💬 Preview: %s\n", r.ConflictSnippets[0])
 		}
 	}
 	fmt.Println()
 }
 
-func (a *ConflictsAnalyzer) generateArtifact(results []models.ConflictFileAnalysis, config analyzers.Config) error {
+func (a *ConflictsAnalyzer) printMergeBackups(paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+
+	fmt.Printf("🗑️  %d leftover merge backup files:\n", len(paths))
+	for _, path := range paths {
+		fmt.Printf("    %s\n", path)
+	}
+	fmt.Println()
+}
+
+func (a *ConflictsAnalyzer) printPredicted(files []string, target string) {
+	if len(files) == 0 {
+		fmt.Printf("✅ No conflicts predicted when merging %s\n", target)
+		return
+	}
+
+	fmt.Printf("🔮 %d files likely to conflict when merging %s:\n", len(files), target)
+	for _, path := range files {
+		fmt.Printf("    %s\n", path)
+	}
+	fmt.Println()
+}
+
+func (a *ConflictsAnalyzer) generateArtifact(results []models.ConflictFileAnalysis, skipped []models.SkippedFile, backups, predicted []string, config analyzers.Config) error {
 	totalBlocks := 0
 	for _, r := range results {
 		totalBlocks += r.ConflictBlocks
 	}
 
 	report := models.ConflictAnalysisReport{
-		Timestamp:      utils.GetTimestamp(),
-		ScanDirectory:  config.RootDir,
-		TotalFiles:     len(results),
-		TotalConflicts: totalBlocks,
-		Results:        results,
+		Timestamp:          utils.GetTimestamp(),
+		ScanDirectory:      config.RootDir,
+		TotalFiles:         len(results),
+		TotalConflicts:     totalBlocks,
+		Results:            results,
+		SkippedTooLarge:    skipped,
+		MergeBackups:       backups,
+		TargetBranch:       config.TargetBranch,
+		PredictedConflicts: predicted,
+		Stats:              config.Stats.Summary(),
 	}
 
-	return utils.WriteArtifact(config.OutputFile, report)
+	return config.WriteArtifact(report)
 }
 
 func formatLineNumbers(lines []int) string {
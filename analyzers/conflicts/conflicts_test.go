@@ -1,9 +1,17 @@
 package conflicts
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
 )
 
 func TestConflictsAnalyzer_Run(t *testing.T) {
@@ -32,7 +40,7 @@ Line 5
 	analyzer := NewConflictsAnalyzer()
 
 	// Test analyzeFile directly
-	analysis := analyzer.analyzeFile(conflictFile)
+	analysis := analyzer.analyzeFile(conflictFile, []int{DefaultMarkerSize})
 	if analysis == nil {
 		t.Fatal("Expected analysis result for conflict file, got nil")
 	}
@@ -45,8 +53,25 @@ Line 5
 		t.Errorf("Expected 1 conflict block, got %d", analysis.ConflictBlocks)
 	}
 
+	if len(analysis.Blocks) != 1 {
+		t.Fatalf("Expected 1 structured block, got %d", len(analysis.Blocks))
+	}
+	block := analysis.Blocks[0]
+	if block.BeginLine != 3 || block.EndLine != 7 || !block.Complete {
+		t.Errorf("Unexpected block range: %+v", block)
+	}
+	if block.OursLabel != "HEAD" || block.TheirsLabel != "feature/branch" {
+		t.Errorf("Unexpected block labels: %q / %q", block.OursLabel, block.TheirsLabel)
+	}
+	if len(block.OursPreview) != 1 || block.OursPreview[0] != "- Our change" {
+		t.Errorf("Unexpected ours preview: %v", block.OursPreview)
+	}
+	if len(block.TheirsPreview) != 1 || block.TheirsPreview[0] != "+ Their change" {
+		t.Errorf("Unexpected theirs preview: %v", block.TheirsPreview)
+	}
+
 	// Test analyzeFile on clean file
-	cleanAnalysis := analyzer.analyzeFile(cleanFile)
+	cleanAnalysis := analyzer.analyzeFile(cleanFile, []int{DefaultMarkerSize})
 	if cleanAnalysis != nil {
 		t.Error("Expected nil analysis for clean file, got result")
 	}
@@ -83,3 +108,103 @@ func TestConflictsAnalyzer_DetectionLogic(t *testing.T) {
 	// This test is just a placeholder to acknowledge we covered the logic in the file-based test.
 	_ = tests
 }
+
+func TestFastScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"conflict.txt":     "a\n<<<<<<< HEAD\nb\n=======\nc\n>>>>>>> topic\n",
+		"clean.txt":        "a\nb\n",
+		"index.php.orig":   "<?php\n",
+		"vendor/other.txt": "<<<<<<< HEAD\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	issues, err := FastScan(analyzers.Config{RootDir: tmpDir, ExcludePaths: []string{"vendor"}})
+	if err != nil {
+		t.Fatalf("FastScan failed: %v", err)
+	}
+
+	// 3 markers in conflict.txt + 1 .orig file
+	if len(issues) != 4 {
+		t.Errorf("Expected 4 issues, got %d: %v", len(issues), issues)
+	}
+
+	// A full run reports the same issues, so fast and full runs share fingerprints
+	full, err := NewConflictsAnalyzer().Run(context.Background(), analyzers.Config{RootDir: tmpDir, ExcludePaths: []string{"vendor"}, TopN: 10, Quiet: true})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	key := func(issues []models.Issue) []string {
+		var keys []string
+		for _, issue := range issues {
+			keys = append(keys, fmt.Sprintf("%s:%d %s %s", filepath.Base(issue.Path), issue.Line, issue.RuleID, issue.Description))
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	if got, want := key(issues), key(full); !reflect.DeepEqual(got, want) {
+		t.Errorf("fast scan issues differ from a full run:\n%v\n%v", got, want)
+	}
+}
+
+func TestConflictsAnalyzer_Diff3AndMarkerSize(t *testing.T) {
+	tmpDir := t.TempDir()
+	diff3File := filepath.Join(tmpDir, "diff3.txt")
+	customFile := filepath.Join(tmpDir, "custom.txt")
+
+	diff3Content := `<<<<<<< HEAD
+ours
+||||||| merged common ancestors
+base
+=======
+theirs
+>>>>>>> topic
+`
+	custom := strings.Repeat("<", 10) + " HEAD\nours\n" + strings.Repeat("=", 10) + "\ntheirs\n" + strings.Repeat(">", 10) + " topic\n"
+
+	if err := os.WriteFile(diff3File, []byte(diff3Content), 0644); err != nil {
+		t.Fatalf("Failed to create diff3 file: %v", err)
+	}
+	if err := os.WriteFile(customFile, []byte(custom), 0644); err != nil {
+		t.Fatalf("Failed to create custom file: %v", err)
+	}
+
+	analyzer := NewConflictsAnalyzer()
+
+	analysis := analyzer.analyzeFile(diff3File, []int{DefaultMarkerSize})
+	if analysis == nil || len(analysis.ConflictLines) != 4 {
+		t.Fatalf("Expected 4 marker lines in diff3 file, got %+v", analysis)
+	}
+	block := analysis.Blocks[0]
+	if !block.Diff3 || block.BaseLabel != "merged common ancestors" || block.BaseLines != 1 {
+		t.Errorf("Unexpected diff3 block: %+v", block)
+	}
+	if block.OursLines != 1 || block.TheirsLines != 1 {
+		t.Errorf("Unexpected side line counts: %+v", block)
+	}
+
+	if analysis := analyzer.analyzeFile(customFile, []int{DefaultMarkerSize}); analysis != nil {
+		t.Errorf("Expected no markers of size 7 in custom file, got %v", analysis.ConflictLines)
+	}
+	analysis = analyzer.analyzeFile(customFile, []int{DefaultMarkerSize, 10})
+	if analysis == nil || len(analysis.Blocks) != 1 || !analysis.Blocks[0].Complete {
+		t.Errorf("Expected 1 complete block with marker size 10, got %+v", analysis)
+	}
+}
+
+func TestMarkerKind_NonPositiveSizes(t *testing.T) {
+	if kind := MarkerKind("| table row", []int{0}); kind != 0 {
+		t.Errorf("Expected size 0 to match nothing, got %q", kind)
+	}
+	if kind := MarkerKind("=======", []int{-1, DefaultMarkerSize}); kind != '=' {
+		t.Errorf("Expected a negative size to be skipped, got %q", kind)
+	}
+}
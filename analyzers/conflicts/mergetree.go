@@ -0,0 +1,71 @@
+package conflicts
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"code-analyzer/models"
+)
+
+// PredictConflicts simulates merging target into HEAD with `git merge-tree`
+// (git >= 2.38) and returns the files that would conflict, relative to the
+// current directory. The working tree and index are not touched.
+func PredictConflicts(target string) ([]string, error) {
+	top, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %v", err)
+	}
+	root := strings.TrimSpace(string(top))
+
+	cmd := exec.Command("git", "merge-tree", "--write-tree", "--name-only", "--no-messages", "HEAD", target)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// Exit code 1 means the merge has conflicts, anything else is a failure
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("git merge-tree HEAD %s failed: %s", target, strings.TrimSpace(stderr.String()))
+		}
+	}
+
+	// First line is the resulting tree OID, followed by conflicted paths
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	var files []string
+	seen := make(map[string]bool)
+	for _, line := range lines[1:] {
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+
+		path := filepath.Join(root, filepath.FromSlash(line))
+		if abs, err := filepath.Abs("."); err == nil {
+			if rel, err := filepath.Rel(abs, path); err == nil {
+				path = rel
+			}
+		}
+		files = append(files, path)
+	}
+
+	return files, nil
+}
+
+// predictedIssues turns predicted conflicting files into issues
+func predictedIssues(files []string, target string) []models.Issue {
+	var issues []models.Issue
+	for _, path := range files {
+		issues = append(issues, models.Issue{
+			Path:        path,
+			Description: fmt.Sprintf("Likely to conflict when merging %s", target),
+			RuleID:      RulePredicted,
+			Category:    models.CategoryBugRisk,
+			Line:        1,
+			Severity:    "major",
+		})
+	}
+	return issues
+}
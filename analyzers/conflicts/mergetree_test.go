@@ -0,0 +1,59 @@
+package conflicts
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestPredictConflicts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		if err := os.MkdirAll(filepath.Join(repo, filepath.Dir(name)), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	write("app/config.php", "<?php return ['v' => 1];\n")
+	write("README.md", "readme\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "base")
+
+	run("checkout", "-q", "-b", "release")
+	write("app/config.php", "<?php return ['v' => 2];\n")
+	run("commit", "-q", "-am", "release change")
+
+	run("checkout", "-q", "main")
+	write("app/config.php", "<?php return ['v' => 3];\n")
+	write("README.md", "readme updated\n")
+	run("commit", "-q", "-am", "main change")
+
+	t.Chdir(repo)
+	files, err := PredictConflicts("release")
+	if err != nil {
+		t.Skipf("git merge-tree --write-tree unsupported: %v", err)
+	}
+
+	if len(files) != 1 || files[0] != filepath.Join("app", "config.php") {
+		t.Errorf("Expected app/config.php to conflict, got %v", files)
+	}
+}
@@ -0,0 +1,75 @@
+package analyzers
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Diagnostics collects non-fatal problems (e.g. rule panics) encountered during a run.
+// A nil *Diagnostics is valid and discards everything.
+type Diagnostics struct {
+	mu      sync.Mutex
+	entries []models.Diagnostic
+}
+
+// Record adds a diagnostic entry
+func (d *Diagnostics) Record(diag models.Diagnostic) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = append(d.entries, diag)
+}
+
+// Entries returns a copy of all recorded diagnostics
+func (d *Diagnostics) Entries() []models.Diagnostic {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]models.Diagnostic{}, d.entries...)
+}
+
+// ApplyRule applies a rule to the content of a file, recovering from panics so a
+// single malformed file cannot crash the whole run. A panicking rule yields nil.
+func ApplyRule(rule Rule, path, content string, diags *Diagnostics) (result interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			utils.Warnf("⚠️  Rule %q panicked on %s: %v\n", rule.Name(), path, r)
+			diags.Record(models.Diagnostic{
+				Path:    path,
+				Rule:    rule.Name(),
+				Message: fmt.Sprint(r),
+				Stack:   string(debug.Stack()),
+				Value:   r,
+			})
+			result = nil
+		}
+	}()
+	return rule.Apply(content)
+}
+
+// ApplyStreamRule is ApplyRule for a StreamRule reading the file from r
+func ApplyStreamRule(rule StreamRule, path string, r io.Reader, diags *Diagnostics) (result interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			utils.Warnf("⚠️  Rule %q panicked on %s: %v\n", rule.Name(), path, p)
+			diags.Record(models.Diagnostic{
+				Path:    path,
+				Rule:    rule.Name(),
+				Message: fmt.Sprint(p),
+				Stack:   string(debug.Stack()),
+				Value:   p,
+			})
+			result, err = nil, nil
+		}
+	}()
+	return rule.ApplyReader(r)
+}
@@ -0,0 +1,33 @@
+package analyzers
+
+import "testing"
+
+type panickingRule struct{}
+
+func (r *panickingRule) Name() string { return "Panicking Rule" }
+
+func (r *panickingRule) Apply(content string) interface{} {
+	var m map[string]int
+	m["boom"] = 1
+	return nil
+}
+
+func TestApplyRule_RecoversFromPanic(t *testing.T) {
+	diags := &Diagnostics{}
+
+	result := ApplyRule(&panickingRule{}, "broken.php", "<?php", diags)
+	if result != nil {
+		t.Errorf("expected nil result from panicking rule, got %v", result)
+	}
+
+	entries := diags.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(entries))
+	}
+	if entries[0].Path != "broken.php" || entries[0].Rule != "Panicking Rule" || entries[0].Stack == "" {
+		t.Errorf("unexpected diagnostic: %+v", entries[0])
+	}
+
+	// A nil collector must not panic either
+	ApplyRule(&panickingRule{}, "broken.php", "<?php", nil)
+}
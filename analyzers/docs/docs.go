@@ -0,0 +1,236 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Rule IDs of the docs analyzer's issues
+const (
+	RuleCommentedHTML = "docs/commented-html"
+	RuleBrokenLink    = "docs/broken-link"
+)
+
+// DocsAnalyzer analyzes Markdown files for commented-out HTML and relative
+// links to files that do not exist
+type DocsAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewDocsAnalyzer creates a new docs analyzer with default rules
+func NewDocsAnalyzer() *DocsAnalyzer {
+	return &DocsAnalyzer{
+		rules: []analyzers.Rule{
+			&DocsRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *DocsAnalyzer) Name() string {
+	return "Docs Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *DocsAnalyzer) Description() string {
+	return "Analyzes Markdown files for commented-out HTML blocks and broken relative links"
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *DocsAnalyzer) RuleIDs() []string {
+	return []string{RuleCommentedHTML, RuleBrokenLink}
+}
+
+// Run executes the docs analysis
+func (a *DocsAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	results := []models.DocsFileAnalysis{}
+	kept := []models.KeptBlock{}
+	skipped := []models.SkippedFile{}
+	var allIssues []models.Issue
+
+	ignores, err := compileIgnores(config.IgnoreComments)
+	if err != nil {
+		return nil, err
+	}
+
+	err = utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, config.RootDir, ignores, config.Diagnostics)
+		if analysis != nil {
+			kept = append(kept, analysis.Kept...)
+			if len(analysis.Issues) == 0 {
+				config.Tracef(path, "not reported, only kept blocks")
+				return nil
+			}
+			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
+			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return allIssues, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if len(results[i].Issues) != len(results[j].Issues) {
+			return len(results[i].Issues) > len(results[j].Issues)
+		}
+		return results[i].Path < results[j].Path
+	})
+
+	// Limit to top N
+	if len(results) > config.TopN {
+		results = results[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, kept, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintKept(kept)
+	analyzers.PrintSkipped(skipped)
+	a.printResults(results)
+	return allIssues, nil
+}
+
+// Handles reports whether path has one of the configured extensions (.md
+// and .markdown by default)
+func (a *DocsAnalyzer) Handles(path string, config analyzers.Config) bool {
+	extensions := config.Extensions
+	if len(extensions) == 0 {
+		extensions = []string{".md", ".markdown"}
+	}
+	lower := strings.ToLower(path)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *DocsAnalyzer) analyzeFile(path, root string, ignores []*regexp.Regexp, diags *analyzers.Diagnostics) *models.DocsFileAnalysis {
+	content, encoding, err := utils.ReadText(path)
+	if err != nil || encoding == utils.EncodingBinary {
+		return nil
+	}
+
+	rule := &DocsRule{Path: path, Root: root, IgnorePatterns: ignores}
+	finding := analyzers.ApplyRule(rule, path, content, diags)
+	if finding == nil {
+		return nil
+	}
+
+	result := finding.(DocsFinding)
+	for i := range result.Issues {
+		result.Issues[i].Path = path
+	}
+	for i := range result.Kept {
+		result.Kept[i].Path = path
+	}
+	return &models.DocsFileAnalysis{
+		Path:           path,
+		CommentedLines: result.CommentedLines,
+		CommentedBytes: result.CommentedBytes,
+		BrokenLinks:    result.BrokenLinks,
+		Issues:         result.Issues,
+		Kept:           result.Kept,
+	}
+}
+
+func (a *DocsAnalyzer) printResults(results []models.DocsFileAnalysis) {
+	if len(results) == 0 {
+		fmt.Println("✅ No Markdown files with commented-out HTML or broken links found!")
+		return
+	}
+
+	fmt.Printf("Found %d Markdown files with issues\n\n", len(results))
+
+	fmt.Printf("%-5s %-70s %12s %14s\n", "Rank", "File", "Commented", "Broken links")
+	fmt.Println(strings.Repeat("-", 104))
+
+	for i, result := range results {
+		fmt.Printf("%-5d %-70s %12s %14d\n",
+			i+1, utils.Truncate(result.Path, 70),
+			utils.FormatBytes(result.CommentedBytes),
+			len(result.BrokenLinks))
+	}
+	fmt.Println()
+
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			if issue.RuleID == RuleBrokenLink {
+				fmt.Printf("🔗 %s:%d %s\n", result.Path, issue.Line, issue.Description)
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
+
+func (a *DocsAnalyzer) generateArtifact(results []models.DocsFileAnalysis, skipped []models.SkippedFile, kept []models.KeptBlock, config analyzers.Config) error {
+	totalCommented, totalBroken := 0, 0
+	for _, r := range results {
+		totalCommented += r.CommentedBytes
+		totalBroken += len(r.BrokenLinks)
+	}
+
+	report := models.DocsAnalysisReport{
+		Timestamp:         utils.GetTimestamp(),
+		ScanDirectory:     config.RootDir,
+		TotalFiles:        len(results),
+		TotalCommented:    totalCommented,
+		TotalBrokenLinks:  totalBroken,
+		Results:           results,
+		IntentionallyKept: kept,
+		SkippedTooLarge:   skipped,
+		Stats:             config.Stats.Summary(),
+	}
+
+	return config.WriteArtifact(report)
+}
@@ -0,0 +1,105 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDocsRule_BrokenLinks(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "docs", "images"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"README.md", "docs/setup.md", "docs/images/arch.png", "docs/My Guide.md"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content := "# Guide\n" +
+		"See [setup](setup.md#install), [guide](My%20Guide.md) and ![arch](images/arch.png \"Architecture\").\n" + // 2
+		"Back to the [readme](../README.md) or the [root readme](/README.md).\n" + // 3
+		"The [old page](removed.md) and the [old image](<images/old diagram.png>) are gone.\n" + // 4
+		"External: [site](https://example.com/x.md), [mail](mailto:a@b.c), [anchor](#guide).\n" + // 5
+		"Inline `[not a link](nowhere.md)` code.\n" + // 6
+		"```md\n[fenced](nowhere.md)\n```\n" + // 7-9
+		"<!-- [hidden](nowhere.md) -->\n" + // 10
+		"[ref]: ./missing/page.md\n" // 11
+
+	rule := &DocsRule{Path: filepath.Join(root, "docs", "guide.md"), Root: root}
+	result := rule.Apply(content)
+	if result == nil {
+		t.Fatal("expected broken links, got nil")
+	}
+	finding := result.(DocsFinding)
+
+	want := []struct {
+		target string
+		line   int
+	}{
+		{"removed.md", 4},
+		{"<images/old diagram.png>", 4},
+		{"./missing/page.md", 11},
+	}
+	if len(finding.Issues) != len(want) {
+		t.Fatalf("expected %d issues, got %d: %+v", len(want), len(finding.Issues), finding.Issues)
+	}
+	for i, w := range want {
+		if finding.BrokenLinks[i] != w.target {
+			t.Errorf("link %d: expected %q, got %q", i, w.target, finding.BrokenLinks[i])
+		}
+		issue := finding.Issues[i]
+		if issue.RuleID != RuleBrokenLink || issue.Line != w.line {
+			t.Errorf("issue %d: expected %s at line %d, got %s at line %d", i, RuleBrokenLink, w.line, issue.RuleID, issue.Line)
+		}
+	}
+}
+
+func TestDocsRule_CommentedHTML(t *testing.T) {
+	ignores, err := compileIgnores(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := `# Project
+
+<!-- TODO: write the intro -->
+<!-- <img src="badge.svg"> -->
+
+<!--
+<table>
+  <tr><td>Old matrix</td></tr>
+</table>
+-->
+
+<!-- markdownlint-disable MD033
+<details>
+</details>
+-->
+
+<!-- KEEP: restored when v2 ships -->
+<!--
+<h2>Version 2</h2>
+<p>Coming soon</p>
+-->
+
+` + "```html\n<!--\n<div>example</div>\n-->\n```\n"
+
+	rule := &DocsRule{Path: "README.md", Root: ".", IgnorePatterns: ignores}
+	result := rule.Apply(content)
+	if result == nil {
+		t.Fatal("expected a commented-out block, got nil")
+	}
+	finding := result.(DocsFinding)
+	if len(finding.Issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d: %+v", len(finding.Issues), finding.Issues)
+	}
+	issue := finding.Issues[0]
+	if issue.RuleID != RuleCommentedHTML || issue.Line != 6 || issue.Metadata.LineSpan != 5 {
+		t.Errorf("expected %s at line 6 spanning 5 lines, got %s at line %d spanning %d", RuleCommentedHTML, issue.RuleID, issue.Line, issue.Metadata.LineSpan)
+	}
+	if len(finding.Kept) != 1 || finding.Kept[0].Reason != "restored when v2 ships" {
+		t.Errorf("expected one kept block with the marker's reason, got %+v", finding.Kept)
+	}
+}
@@ -0,0 +1,245 @@
+package docs
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// MinBlockLines is the number of lines from which a commented-out HTML block
+// is reported; shorter comments are usually toggled badges or images
+const MinBlockLines = 3
+
+// defaultIgnoreComments match comments that carry meaning for Markdown
+// tooling and are therefore never commented-out code
+var defaultIgnoreComments = []string{
+	`^<!--\s*(markdownlint|prettier-ignore|prettier|textlint|vale)\b`, // linter/formatter directives
+	`^<!--\s*/?\s*(toc|TOC|BEGIN|END|ALL-CONTRIBUTORS)`,               // generated section markers
+}
+
+// compileIgnores compiles the default ignore patterns plus extra ones
+func compileIgnores(extra []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range append(append([]string{}, defaultIgnoreComments...), extra...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore_comments pattern %q: %v", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+var (
+	commentRegex = regexp.MustCompile(`(?s)<!--.*?-->`)
+	tagRegex     = regexp.MustCompile(`<[/a-zA-Z][^>]*>`)
+	// inlineLinkRegex matches [text](target) and ![alt](target); group 1 is the target
+	inlineLinkRegex = regexp.MustCompile(`!?\[[^\]\n]*\]\(\s*(<[^>\n]*>|[^)\s]+)`)
+	// referenceRegex matches a link reference definition: [id]: target
+	referenceRegex = regexp.MustCompile(`(?m)^ {0,3}\[[^\]\n]+\]:[ \t]*(<[^>\n]*>|\S+)`)
+	schemeRegex    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// DocsRule detects commented-out HTML blocks and relative links to missing
+// files in a Markdown file. Fenced code blocks and code spans are skipped.
+type DocsRule struct {
+	// Path is the Markdown file; relative links resolve from its directory
+	Path string
+	// Root is the scan root; links starting with "/" resolve from it
+	Root string
+	// IgnorePatterns are matched against the full comment; matches are skipped
+	IgnorePatterns []*regexp.Regexp
+}
+
+// DocsFinding holds the commented-out HTML and broken links of a file
+type DocsFinding struct {
+	CommentedBytes int
+	CommentedLines int
+	BrokenLinks    []string
+	Issues         []models.Issue
+	Kept           []models.KeptBlock
+}
+
+func (r *DocsRule) Name() string {
+	return "Docs Detector"
+}
+
+func (r *DocsRule) Apply(content string) interface{} {
+	finding := DocsFinding{}
+	masked := maskCode(content)
+
+	for _, loc := range commentRegex.FindAllStringIndex(masked, -1) {
+		start, end := loc[0], loc[1]
+		comment := content[start:end]
+		lines := strings.Count(comment, "\n") + 1
+		if lines < MinBlockLines || r.isIgnored(comment) || !tagRegex.MatchString(comment[4:len(comment)-3]) {
+			continue
+		}
+
+		lineNumber := strings.Count(content[:start], "\n") + 1
+		if reason, ok := analyzers.KeepReason(analyzers.PrecedingLine(content, start)); ok {
+			finding.Kept = append(finding.Kept, models.KeptBlock{
+				Line:        lineNumber,
+				Bytes:       end - start,
+				Description: "Commented out HTML block",
+				Reason:      reason,
+			})
+			continue
+		}
+
+		finding.CommentedBytes += end - start
+		finding.CommentedLines += lines
+		issue := models.Issue{
+			Description: fmt.Sprintf("Commented out HTML block (%d lines)", lines),
+			RuleID:      RuleCommentedHTML,
+			Category:    models.CategoryDeadCode,
+			Line:        lineNumber,
+			Severity:    "minor",
+			Metadata: &models.IssueMetadata{
+				Bytes:         end - start,
+				LineSpan:      lines,
+				EffortMinutes: analyzers.RemovalEffort(lines),
+			},
+		}
+		analyzers.SetRange(&issue, content, start, end)
+		finding.Issues = append(finding.Issues, issue)
+	}
+
+	// Links inside comments are not rendered
+	masked = commentRegex.ReplaceAllStringFunc(masked, blank)
+	var links [][]int
+	links = append(links, inlineLinkRegex.FindAllStringSubmatchIndex(masked, -1)...)
+	links = append(links, referenceRegex.FindAllStringSubmatchIndex(masked, -1)...)
+	sort.Slice(links, func(i, j int) bool { return links[i][2] < links[j][2] })
+	for _, loc := range links {
+		start, end := loc[2], loc[3]
+		target := content[start:end]
+		if r.exists(target) {
+			continue
+		}
+
+		finding.BrokenLinks = append(finding.BrokenLinks, target)
+		issue := models.Issue{
+			Description: fmt.Sprintf("Broken relative link to %s", target),
+			RuleID:      RuleBrokenLink,
+			Category:    models.CategoryBugRisk,
+			Line:        strings.Count(content[:start], "\n") + 1,
+			Severity:    "major",
+			Metadata:    &models.IssueMetadata{EffortMinutes: 5},
+		}
+		analyzers.SetRange(&issue, content, start, end)
+		finding.Issues = append(finding.Issues, issue)
+	}
+
+	if len(finding.Issues) == 0 && len(finding.Kept) == 0 {
+		return nil
+	}
+	sort.SliceStable(finding.Issues, func(i, j int) bool {
+		return finding.Issues[i].Line < finding.Issues[j].Line
+	})
+	return finding
+}
+
+func (r *DocsRule) isIgnored(comment string) bool {
+	for _, re := range r.IgnorePatterns {
+		if re.MatchString(comment) {
+			return true
+		}
+	}
+	return false
+}
+
+// exists reports whether a link target resolves to an existing file or
+// directory; URLs, anchors and templated targets are not checked
+func (r *DocsRule) exists(target string) bool {
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	if i := strings.IndexAny(target, "#?"); i >= 0 {
+		target = target[:i]
+	}
+	if target == "" || strings.HasPrefix(target, "//") || schemeRegex.MatchString(target) ||
+		strings.Contains(target, "{{") || strings.Contains(target, "{%") {
+		return true
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	var resolved string
+	if strings.HasPrefix(target, "/") {
+		resolved = filepath.Join(r.Root, filepath.FromSlash(target))
+	} else {
+		resolved = filepath.Join(filepath.Dir(r.Path), filepath.FromSlash(target))
+	}
+	_, err := os.Stat(resolved)
+	return err == nil
+}
+
+// maskCode blanks fenced code blocks and code spans, keeping newlines so
+// offsets and line numbers stay the same
+func maskCode(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		switch {
+		case fence != "":
+			if indent <= 3 && strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == "" {
+				fence = ""
+			}
+			lines[i] = blank(line)
+		case indent <= 3 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+			lines[i] = blank(line)
+		default:
+			lines[i] = maskCodeSpans(line)
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// maskCodeSpans blanks the code spans of a line: text between backtick runs
+// of the same length
+func maskCodeSpans(line string) string {
+	b := []byte(line)
+	for i := 0; i < len(b); {
+		if b[i] != '`' {
+			i++
+			continue
+		}
+		run := 1
+		for i+run < len(b) && b[i+run] == '`' {
+			run++
+		}
+		delim := strings.Repeat("`", run)
+		closing := strings.Index(line[i+run:], delim)
+		if closing == -1 {
+			i += run
+			continue
+		}
+		end := i + run + closing + run
+		for j := i; j < end; j++ {
+			b[j] = ' '
+		}
+		i = end
+	}
+	return string(b)
+}
+
+// blank replaces every byte of s but newlines with a space
+func blank(s string) string {
+	b := []byte(s)
+	for i := range b {
+		if b[i] != '\n' {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,27 @@
+package analyzers
+
+// Effort estimates are in minutes and attached to issues as
+// models.IssueMetadata.EffortMinutes. They are coarse on purpose: good enough
+// to rank fixes, not to plan work.
+
+// RemovalEffort estimates reviewing and deleting a commented-out block
+func RemovalEffort(lines int) int {
+	return 2 + lines/25
+}
+
+// ConflictEffort estimates resolving a merge conflict block
+func ConflictEffort(lines int) int {
+	return 5 + lines/10
+}
+
+// ExtractionEffort estimates moving inline code (e.g. a SQL query) into its
+// own unit such as a repository method
+func ExtractionEffort(lines int) int {
+	return 15 + lines/5
+}
+
+// RefactorEffort estimates splitting up a function by how far it exceeds a
+// limit such as the maximum complexity
+func RefactorEffort(excess int) int {
+	return 15 + 5*excess
+}
@@ -0,0 +1,234 @@
+package encoding
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Rule IDs of the encoding analyzer's issues
+const (
+	RuleUTF16       = "encoding/utf16"
+	RuleInvalidUTF8 = "encoding/invalid-utf8"
+)
+
+// EncodingAnalyzer flags text files that are not UTF-8
+type EncodingAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewEncodingAnalyzer creates a new encoding analyzer
+func NewEncodingAnalyzer() *EncodingAnalyzer {
+	return &EncodingAnalyzer{
+		rules: []analyzers.Rule{
+			&EncodingRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *EncodingAnalyzer) Name() string {
+	return "Encoding Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *EncodingAnalyzer) Description() string {
+	return "Flags UTF-16 files and files with invalid UTF-8 sequences"
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *EncodingAnalyzer) RuleIDs() []string {
+	return []string{RuleUTF16, RuleInvalidUTF8}
+}
+
+// Run executes the encoding analysis
+func (a *EncodingAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	results := []models.EncodingFileAnalysis{}
+	skipped := []models.SkippedFile{}
+	var allIssues []models.Issue
+
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, config.Diagnostics)
+		if analysis != nil {
+			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
+			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return allIssues, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+
+	// Limit to top N
+	if len(results) > config.TopN {
+		results = results[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintSkipped(skipped)
+	a.printResults(results)
+	return allIssues, nil
+}
+
+// Handles reports whether path matches the configured extensions (all files when unset)
+func (a *EncodingAnalyzer) Handles(path string, config analyzers.Config) bool {
+	if len(config.Extensions) == 0 {
+		return true
+	}
+	lower := strings.ToLower(path)
+	for _, ext := range config.Extensions {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *EncodingAnalyzer) analyzeFile(path string, diags *analyzers.Diagnostics) *models.EncodingFileAnalysis {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	rule := &EncodingRule{}
+	finding := analyzers.ApplyRule(rule, path, string(content), diags)
+	if finding == nil {
+		return nil
+	}
+
+	result := finding.(EncodingFinding)
+	result.Issue.Path = path
+	return &models.EncodingFileAnalysis{
+		Path:     path,
+		Encoding: result.Encoding,
+		Issues:   []models.Issue{result.Issue},
+	}
+}
+
+func (a *EncodingAnalyzer) printResults(results []models.EncodingFileAnalysis) {
+	if len(results) == 0 {
+		fmt.Println("✅ All text files are UTF-8!")
+		return
+	}
+
+	fmt.Printf("Found %d files that are not UTF-8\n\n", len(results))
+
+	fmt.Printf("%-5s %-70s %-12s %6s\n", "Rank", "File", "Encoding", "Line")
+	fmt.Println(strings.Repeat("-", 97))
+
+	for i, result := range results {
+		relPath := utils.Truncate(result.Path, 70)
+		fmt.Printf("%-5d %-70s %-12s %6d\n", i+1, relPath, result.Encoding, result.Issues[0].Line)
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
+
+func (a *EncodingAnalyzer) generateArtifact(results []models.EncodingFileAnalysis, skipped []models.SkippedFile, config analyzers.Config) error {
+	report := models.EncodingAnalysisReport{
+		Timestamp:       utils.GetTimestamp(),
+		ScanDirectory:   config.RootDir,
+		TotalFiles:      len(results),
+		Results:         results,
+		SkippedTooLarge: skipped,
+		Stats:           config.Stats.Summary(),
+	}
+
+	return config.WriteArtifact(report)
+}
+
+// EncodingRule detects files that are not valid UTF-8
+type EncodingRule struct{}
+
+// EncodingFinding holds the detected encoding of a non-UTF-8 file
+type EncodingFinding struct {
+	Encoding string
+	Issue    models.Issue
+}
+
+func (r *EncodingRule) Name() string {
+	return "Encoding Detector"
+}
+
+func (r *EncodingRule) Apply(content string) interface{} {
+	_, encoding := utils.DecodeText([]byte(content))
+
+	switch encoding {
+	case utils.EncodingUTF16LE, utils.EncodingUTF16BE:
+		return EncodingFinding{
+			Encoding: encoding,
+			Issue: models.Issue{
+				Description: fmt.Sprintf("File is encoded as %s instead of UTF-8", strings.ToUpper(encoding)),
+				RuleID:      RuleUTF16,
+				Category:    models.CategoryStyle,
+				Line:        1,
+				Severity:    "minor",
+				Metadata:    &models.IssueMetadata{EffortMinutes: 5},
+			},
+		}
+	case utils.EncodingLatin1:
+		return EncodingFinding{
+			Encoding: encoding,
+			Issue: models.Issue{
+				Description: "Invalid UTF-8 byte sequence (decoded as ISO-8859-1)",
+				RuleID:      RuleInvalidUTF8,
+				Category:    models.CategoryBugRisk,
+				Line:        utils.FirstInvalidUTF8Line([]byte(content)),
+				Severity:    "major",
+				Metadata:    &models.IssueMetadata{EffortMinutes: 5},
+			},
+		}
+	}
+	return nil
+}
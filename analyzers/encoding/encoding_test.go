@@ -0,0 +1,60 @@
+package encoding
+
+import (
+	"testing"
+)
+
+func TestEncodingRule_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		encoding string // Expected encoding, "" for no finding
+		line     int
+	}{
+		{
+			name:    "UTF-8",
+			content: "<p>café</p>\n",
+		},
+		{
+			name:    "UTF-8 with BOM",
+			content: "\xEF\xBB\xBF<p>café</p>\n",
+		},
+		{
+			name:    "Binary",
+			content: "\x89PNG\x00\x00\x00\x0D",
+		},
+		{
+			name:     "UTF-16LE",
+			content:  "\xFF\xFEh\x00i\x00\n\x00",
+			encoding: "utf-16le",
+			line:     1,
+		},
+		{
+			name:     "Latin-1",
+			content:  "<?php\n// Caf\xE9\n",
+			encoding: "iso-8859-1",
+			line:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &EncodingRule{}
+			result := rule.Apply(tt.content)
+			if result == nil {
+				if tt.encoding != "" {
+					t.Errorf("expected %s finding, got nil", tt.encoding)
+				}
+				return
+			}
+
+			finding := result.(EncodingFinding)
+			if finding.Encoding != tt.encoding {
+				t.Errorf("expected encoding %q, got %q", tt.encoding, finding.Encoding)
+			}
+			if finding.Issue.Line != tt.line {
+				t.Errorf("expected line %d, got %d", tt.line, finding.Issue.Line)
+			}
+		})
+	}
+}
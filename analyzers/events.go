@@ -0,0 +1,128 @@
+package analyzers
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"code-analyzer/models"
+)
+
+// Progress event names
+const (
+	EventRunStarted       = "run_started"
+	EventAnalyzerStarted  = "analyzer_started"
+	EventFilesProcessed   = "files_processed"
+	EventIssueFound       = "issue_found"
+	EventAnalyzerFinished = "analyzer_finished"
+	EventRunFinished      = "run_finished"
+)
+
+// EventStream writes progress events as JSON lines for build UIs to follow.
+// files_processed events are throttled to one per Interval per analyzer.
+// A nil *EventStream is valid and writes nothing.
+type EventStream struct {
+	mu       sync.Mutex
+	enc      *json.Encoder
+	analyzer string
+	lastSent time.Time
+
+	// Interval is the minimum time between files_processed events
+	Interval time.Duration
+}
+
+// NewEventStream creates an event stream writing to w
+func NewEventStream(w io.Writer) *EventStream {
+	return &EventStream{enc: json.NewEncoder(w), Interval: 250 * time.Millisecond}
+}
+
+// RunStarted announces the number of analyzers and files in the scan root
+func (s *EventStream) RunStarted(analyzers, totalFiles int) {
+	s.emit(models.ProgressEvent{Event: EventRunStarted, Analyzers: analyzers, TotalFiles: totalFiles})
+}
+
+// AnalyzerStarted announces an analyzer; later file and issue events refer to it
+func (s *EventStream) AnalyzerStarted(analyzer string, totalFiles int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.analyzer = analyzer
+	s.lastSent = time.Time{}
+	s.mu.Unlock()
+	s.emit(models.ProgressEvent{Event: EventAnalyzerStarted, Analyzer: analyzer, TotalFiles: totalFiles})
+}
+
+// FilesProcessed reports how many files the current analyzer has visited;
+// force bypasses throttling for the final count
+func (s *EventStream) FilesProcessed(processed, totalFiles int, force bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	now := time.Now()
+	if !force && now.Sub(s.lastSent) < s.Interval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSent = now
+	analyzer := s.analyzer
+	s.mu.Unlock()
+	s.emit(models.ProgressEvent{Event: EventFilesProcessed, Analyzer: analyzer, Processed: processed, TotalFiles: totalFiles})
+}
+
+// IssueFound reports one issue of the current analyzer
+func (s *EventStream) IssueFound(issue models.Issue) {
+	if s == nil {
+		return
+	}
+	s.emit(models.ProgressEvent{
+		Event:       EventIssueFound,
+		Analyzer:    s.current(),
+		Path:        issue.Path,
+		Line:        issue.Line,
+		Severity:    issue.Severity,
+		Description: issue.Description,
+	})
+}
+
+// AnalyzerFinished reports the outcome of the current analyzer
+func (s *EventStream) AnalyzerFinished(issues int, elapsed time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	event := models.ProgressEvent{
+		Event:      EventAnalyzerFinished,
+		Analyzer:   s.current(),
+		Issues:     issues,
+		DurationMS: elapsed.Milliseconds(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.emit(event)
+}
+
+// RunFinished reports the outcome of the whole run
+func (s *EventStream) RunFinished(succeeded, analyzers, issues int) {
+	s.emit(models.ProgressEvent{Event: EventRunFinished, Succeeded: succeeded, Analyzers: analyzers, Issues: issues})
+}
+
+func (s *EventStream) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.analyzer
+}
+
+// emit writes one event; write errors are ignored so a closed reader never
+// fails the analysis
+func (s *EventStream) emit(event models.ProgressEvent) {
+	if s == nil {
+		return
+	}
+	event.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.enc.Encode(event)
+}
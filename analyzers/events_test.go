@@ -0,0 +1,73 @@
+package analyzers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"code-analyzer/models"
+)
+
+func TestEventStream(t *testing.T) {
+	var out bytes.Buffer
+	s := NewEventStream(&out)
+	s.Interval = time.Hour
+
+	p := NewProgress(&bytes.Buffer{}, 3, false)
+	p.Events = s
+
+	s.RunStarted(1, 3)
+	s.AnalyzerStarted("php", 3)
+	p.Start("PHP")
+	p.Tick()
+	p.Tick() // throttled
+	p.Tick() // throttled
+	p.Finish()
+	s.IssueFound(models.Issue{Path: "a.php", Line: 4, Severity: "minor", Description: "x"})
+	s.AnalyzerFinished(1, time.Second, errors.New("boom"))
+	s.RunFinished(0, 1, 1)
+
+	var events []models.ProgressEvent
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var e models.ProgressEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid event line %q: %v", line, err)
+		}
+		events = append(events, e)
+	}
+
+	want := []string{
+		EventRunStarted, EventAnalyzerStarted, EventFilesProcessed, EventFilesProcessed,
+		EventIssueFound, EventAnalyzerFinished, EventRunFinished,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d:\n%s", len(want), len(events), out.String())
+	}
+	for i, e := range events {
+		if e.Event != want[i] {
+			t.Errorf("event %d: got %q, want %q", i, e.Event, want[i])
+		}
+	}
+	if events[3].Processed != 3 || events[3].Analyzer != "php" {
+		t.Errorf("final files_processed should report 3 php files, got %+v", events[3])
+	}
+	if events[4].Path != "a.php" || events[4].Analyzer != "php" {
+		t.Errorf("unexpected issue event %+v", events[4])
+	}
+	if events[5].Error != "boom" || events[5].DurationMS != 1000 {
+		t.Errorf("unexpected analyzer_finished event %+v", events[5])
+	}
+}
+
+func TestEventStream_Nil(t *testing.T) {
+	var s *EventStream
+	s.RunStarted(1, 1)
+	s.AnalyzerStarted("php", 1)
+	s.FilesProcessed(1, 1, true)
+	s.IssueFound(models.Issue{})
+	s.AnalyzerFinished(0, 0, nil)
+	s.RunFinished(1, 1, 0)
+}
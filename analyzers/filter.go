@@ -0,0 +1,38 @@
+package analyzers
+
+import "strings"
+
+// MatchesExt reports whether path passes the -ext filter, i.e. ends with one
+// of OnlyExtensions (case-insensitive). Every path matches when it is unset.
+func (c Config) MatchesExt(path string) bool {
+	return len(c.OnlyExtensions) == 0 || HasExtension(path, c.OnlyExtensions)
+}
+
+// HasExtension reports whether path ends with one of the extensions
+// (case-insensitive)
+func HasExtension(path string, extensions []string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseExtensions turns a comma-separated list such as "php,.blade.php" into
+// extensions with a leading dot, nil when empty
+func ParseExtensions(list string) []string {
+	var extensions []string
+	for _, ext := range strings.Split(list, ",") {
+		ext = strings.TrimSpace(ext)
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		extensions = append(extensions, ext)
+	}
+	return extensions
+}
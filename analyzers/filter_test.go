@@ -0,0 +1,37 @@
+package analyzers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExtensions(t *testing.T) {
+	got := ParseExtensions(" php, .blade.php,,JS ")
+	want := []string{".php", ".blade.php", ".JS"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if ParseExtensions("") != nil {
+		t.Error("expected nil for an empty list")
+	}
+}
+
+func TestConfig_MatchesExt(t *testing.T) {
+	tests := []struct {
+		extensions []string
+		path       string
+		want       bool
+	}{
+		{nil, "app/a.js", true},
+		{[]string{".php"}, "app/a.php", true},
+		{[]string{".php"}, "app/A.PHP", true},
+		{[]string{".blade.php"}, "views/home.blade.php", true},
+		{[]string{".blade.php"}, "app/Home.php", false},
+		{[]string{".php", ".js"}, "app/a.html", false},
+	}
+	for _, tt := range tests {
+		if got := (Config{OnlyExtensions: tt.extensions}).MatchesExt(tt.path); got != tt.want {
+			t.Errorf("MatchesExt(%v, %q) = %v, want %v", tt.extensions, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package html
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/analyzers/js"
+	"code-analyzer/models"
+)
+
+var scriptTypeRegex = regexp.MustCompile(`(?i)\btype\s*=\s*["']?([^"'\s>]+)`)
+
+// openScript starts the JS rule on an inline <script> block of JavaScript
+func (s *commentScanner) openScript(attrs string, start position) blockConsumer {
+	if !isJavaScriptType(attrs) {
+		return nil
+	}
+	return &scriptBlock{parent: s, start: start, scanner: js.NewScanner()}
+}
+
+// openStyle starts the CSS rule on an inline <style> block
+func (s *commentScanner) openStyle(attrs string, start position) blockConsumer {
+	return &styleBlock{parent: s}
+}
+
+// scriptBlock runs the JS rule over an inline <script> block, mapping issue
+// lines back to the enclosing file. Blocks inside HTML comments are already
+// counted as commented-out HTML.
+type scriptBlock struct {
+	parent  *commentScanner
+	start   position
+	scanner *js.Scanner
+}
+
+func (b *scriptBlock) feed(c byte, at position) {
+	b.scanner.WriteByte(c)
+}
+
+func (b *scriptBlock) end() {
+	finding := b.scanner.Finding()
+	if finding == nil {
+		return
+	}
+	f := finding.(js.CommentedCodeFinding)
+	mergeEmbedded(&b.parent.embed, b.start, CommentedCodeFinding{
+		CommentedBytes: f.CommentedBytes,
+		CommentedLines: f.CommentedLines,
+		LargestBlock:   f.LargestBlock,
+		Issues:         f.Issues,
+		Kept:           f.Kept,
+	})
+}
+
+// mergeEmbedded adds a block's finding to result, offsetting issue lines by
+// the number of lines preceding the block in the enclosing file. Columns on
+// the block's first line are shifted by the text before the block.
+func mergeEmbedded(result *CommentedCodeFinding, start position, block CommentedCodeFinding) {
+	lineOffset := start.line - 1
+	columnOffset := start.column - 1
+
+	result.CommentedBytes += block.CommentedBytes
+	result.CommentedLines += block.CommentedLines
+	if block.LargestBlock > result.LargestBlock {
+		result.LargestBlock = block.LargestBlock
+	}
+	for _, issue := range block.Issues {
+		if issue.Column > 0 && issue.Line == 1 {
+			issue.Column += columnOffset
+		}
+		if issue.EndLine > 0 {
+			if issue.EndLine == 1 {
+				issue.EndColumn += columnOffset
+			}
+			issue.EndLine += lineOffset
+		}
+		issue.Line += lineOffset
+		result.Issues = append(result.Issues, issue)
+	}
+	for _, k := range block.Kept {
+		k.Line += lineOffset
+		result.Kept = append(result.Kept, k)
+	}
+}
+
+// isJavaScriptType reports whether a <script> tag's attributes denote JavaScript
+// (templates such as type="text/x-template" are left alone)
+func isJavaScriptType(attrs string) bool {
+	m := scriptTypeRegex.FindStringSubmatch(attrs)
+	if m == nil {
+		return true
+	}
+	scriptType := strings.ToLower(m[1])
+	return strings.Contains(scriptType, "javascript") || scriptType == "module" || strings.Contains(scriptType, "babel")
+}
+
+var cssDeclarationRegex = regexp.MustCompile(`[a-zA-Z-]+\s*:\s*[^;{}]+;`)
+var cssRuleRegex = regexp.MustCompile(`[^{}]+\{[^{}]*\}`)
+
+// styleBlock detects commented-out CSS rules and declarations in an inline
+// <style> block. Positions are those of the enclosing file; a comment left
+// open at the end of the block is not reported.
+type styleBlock struct {
+	parent *commentScanner
+
+	// Current /* */ comment
+	inComment bool
+	prev      byte
+	prevAt    position
+	start     position
+	bytes     int
+	newlines  int
+	text      []byte
+	truncated bool
+
+	// Findings are only added to the file once the block is closed
+	result CommentedCodeFinding
+}
+
+func (b *styleBlock) feed(c byte, at position) {
+	switch {
+	case b.inComment:
+		b.bytes++
+		if c == '\n' {
+			b.newlines++
+		}
+		if b.prev == '*' && c == '/' {
+			b.closeComment(at)
+			return
+		}
+		if len(b.text) < maxRetainedComment {
+			b.text = append(b.text, c)
+		} else {
+			b.truncated = true
+		}
+	case b.prev == '/' && c == '*':
+		b.inComment = true
+		b.start = b.prevAt
+		b.bytes = 2
+		b.newlines = 0
+		b.text = b.text[:0]
+		b.truncated = false
+		b.prev = 0 // "/*/" does not close the comment
+		return
+	}
+	b.prev, b.prevAt = c, at
+}
+
+func (b *styleBlock) closeComment(end position) {
+	b.inComment = false
+	b.prev = 0 // "*/*" does not open a new comment
+
+	inner := b.text
+	if !b.truncated && len(inner) > 0 {
+		inner = inner[:len(inner)-1] // the '*' of the closing */
+	}
+	if !cssDeclarationRegex.Match(inner) && !cssRuleRegex.Match(inner) {
+		return
+	}
+
+	matchLen := b.bytes
+	matchLines := b.newlines + 1
+	b.result.CommentedBytes += matchLen
+	b.result.CommentedLines += matchLines
+	if matchLen > b.result.LargestBlock {
+		b.result.LargestBlock = matchLen
+	}
+	b.result.Issues = append(b.result.Issues, models.Issue{
+		Description: fmt.Sprintf("Commented out CSS code block (%d bytes)", matchLen),
+		RuleID:      RuleCommentedCSS,
+		Category:    models.CategoryDeadCode,
+		Line:        b.start.line,
+		Column:      b.start.column,
+		EndLine:     end.line,
+		EndColumn:   end.column,
+		Severity:    "minor",
+		Metadata: &models.IssueMetadata{
+			Bytes:         matchLen,
+			LineSpan:      matchLines,
+			EffortMinutes: analyzers.RemovalEffort(matchLines),
+		},
+	})
+}
+
+func (b *styleBlock) end() {
+	// Positions are already those of the enclosing file
+	mergeEmbedded(&b.parent.css, position{line: 1, column: 1}, b.result)
+}
@@ -1,9 +1,10 @@
 package html
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -13,6 +14,13 @@ import (
 	"code-analyzer/utils"
 )
 
+// Rule IDs of the HTML analyzer's issues; inline scripts are reported under
+// the JS rule
+const (
+	RuleCommentedCode = "html/commented-code"
+	RuleCommentedCSS  = "html/commented-css"
+)
+
 // HTMLAnalyzer analyzes HTML files for various code quality issues
 type HTMLAnalyzer struct {
 	rules []analyzers.Rule
@@ -37,48 +45,86 @@ func (a *HTMLAnalyzer) Description() string {
 	return "Analyzes HTML files for commented code blocks and other issues"
 }
 
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *HTMLAnalyzer) RuleIDs() []string {
+	return []string{RuleCommentedCode, RuleCommentedCSS}
+}
+
 // Run executes the HTML analysis
-func (a *HTMLAnalyzer) Run(config analyzers.Config) ([]models.Issue, error) {
+func (a *HTMLAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
 	results := []models.HTMLFileAnalysis{}
+	kept := []models.KeptBlock{}
+	skipped := []models.SkippedFile{}
 	var allIssues []models.Issue
 
-	err := filepath.Walk(config.RootDir, func(path string, info os.FileInfo, err error) error {
+	rule, err := NewCommentedCodeRule(config.IgnoreComments)
+	if err != nil {
+		return nil, err
+	}
+
+	err = utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return nil
 		}
-		if !strings.HasSuffix(strings.ToLower(path), ".html") {
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
 			return nil
 		}
 		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
 			return nil
 		}
 
-		analysis := a.analyzeFile(path)
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, rule, config.Diagnostics)
 		if analysis != nil {
-			if analysis.CommentedBytes < config.MinValue {
+			kept = append(kept, analysis.Kept...)
+			if analysis.CommentedBytes == 0 || analysis.CommentedBytes < config.MinValue {
+				config.Tracef(path, "not reported, below min")
 				return nil
 			}
 			if config.MinRatio > 0 && analysis.CommentRatio < config.MinRatio {
+				config.Tracef(path, "not reported, below min_ratio")
 				return nil
 			}
 			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
 			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
 		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return allIssues, err
 	}
 
 	// Sort results
 	if config.SortBy == "ratio" {
 		sort.Slice(results, func(i, j int) bool {
-			return results[i].CommentRatio > results[j].CommentRatio
+			if results[i].CommentRatio != results[j].CommentRatio {
+				return results[i].CommentRatio > results[j].CommentRatio
+			}
+			return results[i].Path < results[j].Path
 		})
 	} else {
 		sort.Slice(results, func(i, j int) bool {
-			return results[i].CommentedBytes > results[j].CommentedBytes
+			if results[i].CommentedBytes != results[j].CommentedBytes {
+				return results[i].CommentedBytes > results[j].CommentedBytes
+			}
+			return results[i].Path < results[j].Path
 		})
 	}
 
@@ -88,45 +134,63 @@ func (a *HTMLAnalyzer) Run(config analyzers.Config) ([]models.Issue, error) {
 	}
 
 	// Generate artifact if requested
-	if config.OutputFile != "" {
-		if err := a.generateArtifact(results, config); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to generate artifact: %v\n", err)
-		} else {
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, kept, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
 			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
 		}
 	}
 
 	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintKept(kept)
+	analyzers.PrintSkipped(skipped)
 	a.printResults(results)
 	return allIssues, nil
 }
 
-func (a *HTMLAnalyzer) analyzeFile(path string) *models.HTMLFileAnalysis {
-	content, err := os.ReadFile(path)
+// Handles reports whether path has one of the configured extensions (.html by default)
+func (a *HTMLAnalyzer) Handles(path string, config analyzers.Config) bool {
+	extensions := config.Extensions
+	if len(extensions) == 0 {
+		extensions = []string{".html"}
+	}
+	return analyzers.HasExtension(path, extensions)
+}
+
+func (a *HTMLAnalyzer) analyzeFile(path string, rule *CommentedCodeRule, diags *analyzers.Diagnostics) *models.HTMLFileAnalysis {
+	// Apply commented code rule, streaming the file once
+	file, encoding, err := utils.OpenText(path)
 	if err != nil {
 		return nil
 	}
-
-	// Apply commented code rule
-	rule := &CommentedCodeRule{}
-	finding := rule.Apply(string(content))
-
-	if finding == nil {
+	defer file.Close()
+	if encoding == utils.EncodingBinary {
+		return nil
+	}
+	finding, err := analyzers.ApplyStreamRule(rule, path, file, diags)
+	if err != nil || finding == nil {
 		return nil
 	}
 
 	result := finding.(CommentedCodeFinding)
-	if result.CommentedBytes == 0 {
+	if result.CommentedBytes == 0 && len(result.Kept) == 0 {
 		return nil
 	}
 
-	// Set path for issues
+	// Set path for issues and kept blocks
 	for i := range result.Issues {
 		result.Issues[i].Path = path
 	}
+	for i := range result.Kept {
+		result.Kept[i].Path = path
+	}
 
-	totalBytes := len(content)
-	totalLines := strings.Count(string(content), "\n") + 1
+	totalBytes := result.TotalBytes
+	totalLines := result.TotalLines
 	ratio := float64(result.CommentedBytes) / float64(totalBytes) * 100
 
 	return &models.HTMLFileAnalysis{
@@ -138,6 +202,7 @@ func (a *HTMLAnalyzer) analyzeFile(path string) *models.HTMLFileAnalysis {
 		CommentRatio:   ratio,
 		LargestBlock:   result.LargestBlock,
 		Issues:         result.Issues,
+		Kept:           result.Kept,
 	}
 }
 
@@ -192,33 +257,68 @@ func (a *HTMLAnalyzer) printTop10(results []models.HTMLFileAnalysis) {
 	fmt.Println()
 }
 
-func (a *HTMLAnalyzer) generateArtifact(results []models.HTMLFileAnalysis, config analyzers.Config) error {
+func (a *HTMLAnalyzer) generateArtifact(results []models.HTMLFileAnalysis, skipped []models.SkippedFile, kept []models.KeptBlock, config analyzers.Config) error {
 	totalCommented := 0
 	for _, r := range results {
 		totalCommented += r.CommentedBytes
 	}
 
 	report := models.HTMLAnalysisReport{
-		Timestamp:      utils.GetTimestamp(),
-		ScanDirectory:  config.RootDir,
-		TotalFiles:     len(results),
-		TotalCommented: totalCommented,
-		SortMode:       config.SortBy,
-		MinComments:    config.MinValue,
-		Results:        results,
+		Timestamp:         utils.GetTimestamp(),
+		ScanDirectory:     config.RootDir,
+		TotalFiles:        len(results),
+		TotalCommented:    totalCommented,
+		SortMode:          config.SortBy,
+		MinComments:       config.MinValue,
+		Results:           results,
+		SkippedTooLarge:   skipped,
+		IntentionallyKept: kept,
+		Stats:             config.Stats.Summary(),
 	}
 
-	return utils.WriteArtifact(config.OutputFile, report)
+	return config.WriteArtifact(report)
 }
 
+// defaultIgnoreComments match comments that carry meaning for browsers,
+// linters or servers and are therefore never commented-out code
+var defaultIgnoreComments = []string{
+	`^<!--\s*\[if\b`,      // IE conditional comment start
+	`^<!--\s*<!\[endif\]`, // downlevel-revealed conditional end
+	`<!\[endif\]\s*-->$`,  // IE conditional comment end
+	`^<!--\s*#\s*(include|echo|set|if|elif|else|endif|config|exec)\b`, // server-side includes
+	`^<!--\s*(htmlhint|prettier-ignore|prettier)\b`,                   // linter/formatter directives
+}
+
+// tagRegex matches an HTML tag; comments containing one are likely code
+var tagRegex = regexp.MustCompile(`<[/a-zA-Z][^>]*>`)
+
 // CommentedCodeRule detects commented-out HTML code
-type CommentedCodeRule struct{}
+type CommentedCodeRule struct {
+	// IgnorePatterns are matched against the full comment; matches are skipped
+	IgnorePatterns []*regexp.Regexp
+}
+
+// NewCommentedCodeRule creates the rule with the default ignore patterns plus extra ones
+func NewCommentedCodeRule(extraIgnores []string) (*CommentedCodeRule, error) {
+	rule := &CommentedCodeRule{}
+	for _, pattern := range append(append([]string{}, defaultIgnoreComments...), extraIgnores...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore_comments pattern %q: %v", pattern, err)
+		}
+		rule.IgnorePatterns = append(rule.IgnorePatterns, re)
+	}
+	return rule, nil
+}
 
 type CommentedCodeFinding struct {
 	CommentedBytes int
 	CommentedLines int
 	LargestBlock   int
+	TotalBytes     int
+	TotalLines     int
 	Issues         []models.Issue
+	Kept           []models.KeptBlock
 }
 
 func (r *CommentedCodeRule) Name() string {
@@ -226,58 +326,25 @@ func (r *CommentedCodeRule) Name() string {
 }
 
 func (r *CommentedCodeRule) Apply(content string) interface{} {
-	commentRegex := regexp.MustCompile(`(?s)<!--.*?-->`)
-	matches := commentRegex.FindAllStringIndex(content, -1)
-
-	commentedBytes := 0
-	commentedLines := 0
-	largestBlock := 0
-	var issues []models.Issue
-
-	tagRegex := regexp.MustCompile(`<[/a-zA-Z][^>]*>`)
-
-	for _, loc := range matches {
-		start, end := loc[0], loc[1]
-		match := content[start:end]
-
-		// Heuristic: It's likely commented code if it contains HTML tags
-		// We strip the comment markers first to avoid matching them (though standard regex handles that)
-		inner := match
-		if len(match) >= 7 {
-			inner = match[4 : len(match)-3]
-		}
-
-		if !tagRegex.MatchString(inner) {
-			continue
-		}
-
-		matchLen := len(match)
-		matchLines := strings.Count(match, "\n") + 1
-		commentedBytes += matchLen
-		commentedLines += matchLines
-		if matchLen > largestBlock {
-			largestBlock = matchLen
-		}
-
-		// Calculate line number
-		lineNumber := strings.Count(content[:start], "\n") + 1
-
-		issues = append(issues, models.Issue{
-			Description: fmt.Sprintf("Commented out HTML code block (%d bytes)", matchLen),
-			Line:        lineNumber,
-			Severity:    "minor",
-			Path:        "", // Will be populated by analyzeFile
-		})
-	}
+	finding, _ := r.ApplyReader(strings.NewReader(content))
+	return finding
+}
 
-	if commentedBytes == 0 {
-		return nil
+// ApplyReader scans the input once, inline scripts and styles included, so
+// files of any size can be analyzed without holding them in memory
+func (r *CommentedCodeRule) ApplyReader(reader io.Reader) (interface{}, error) {
+	scanner := newCommentScanner(r)
+	if err := scanner.scan(reader); err != nil {
+		return nil, err
 	}
+	return scanner.finding(), nil
+}
 
-	return CommentedCodeFinding{
-		CommentedBytes: commentedBytes,
-		CommentedLines: commentedLines,
-		LargestBlock:   largestBlock,
-		Issues:         issues,
+func (r *CommentedCodeRule) isIgnored(comment string) bool {
+	for _, re := range r.IgnorePatterns {
+		if re.MatchString(comment) {
+			return true
+		}
 	}
+	return false
 }
@@ -1,7 +1,9 @@
 package html
 
 import (
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestCommentedCodeRule_Apply(t *testing.T) {
@@ -34,9 +36,31 @@ func TestCommentedCodeRule_Apply(t *testing.T) {
 			`,
 			expected: 50, // Approximate
 		},
+		{
+			name: "IE conditional comment",
+			content: `
+				<!--[if lt IE 9]>
+				<script src="html5shiv.js"></script>
+				<![endif]-->
+			`,
+			expected: 0,
+		},
+		{
+			name:     "Server-side include",
+			content:  `<!--#include virtual="/footer.html" -->`,
+			expected: 0,
+		},
+		{
+			name:     "Formatter directive",
+			content:  `<!-- prettier-ignore --><div>  kept  </div>`,
+			expected: 0,
+		},
 	}
 
-	rule := &CommentedCodeRule{}
+	rule, err := NewCommentedCodeRule(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -58,3 +82,191 @@ func TestCommentedCodeRule_Apply(t *testing.T) {
 		})
 	}
 }
+
+func TestCommentedCodeRule_IgnoreComments(t *testing.T) {
+	content := `<!-- @component <my-widget></my-widget> -->`
+
+	rule, err := NewCommentedCodeRule(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Apply(content) == nil {
+		t.Fatal("expected component marker to be reported without custom pattern")
+	}
+
+	rule, err = NewCommentedCodeRule([]string{`^<!--\s*@component`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Apply(content) != nil {
+		t.Error("expected component marker to be ignored with custom pattern")
+	}
+
+	if _, err := NewCommentedCodeRule([]string{"("}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestCommentedCodeRule_EmbeddedBlocks(t *testing.T) {
+	content := `<html>
+<head>
+<style>
+/* Header styles */
+.header { color: red; }
+/* .banner { display: none; } */
+</style>
+</head>
+<body>
+<script>
+// Boot the page
+init();
+// var legacy = load();
+</script>
+<script type="text/x-template">
+// var template = true;
+</script>
+</body>
+</html>`
+
+	rule, err := NewCommentedCodeRule(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := rule.Apply(content)
+	if result == nil {
+		t.Fatal("expected embedded commented code, got nil")
+	}
+
+	finding := result.(CommentedCodeFinding)
+	lines := map[int]bool{}
+	for _, issue := range finding.Issues {
+		lines[issue.Line] = true
+	}
+
+	if len(finding.Issues) != 2 || !lines[6] || !lines[13] {
+		t.Errorf("expected issues on lines 6 (CSS) and 13 (JS), got %+v", finding.Issues)
+	}
+}
+
+func TestCommentedCodeRule_CommentedOutScript(t *testing.T) {
+	content := `<body>
+<!--
+<script>
+// var legacy = load();
+init();
+</script>
+-->
+</body>`
+
+	rule, err := NewCommentedCodeRule(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := rule.Apply(content)
+	if result == nil {
+		t.Fatal("expected the commented-out script block, got nil")
+	}
+
+	finding := result.(CommentedCodeFinding)
+	if len(finding.Issues) != 1 || finding.Issues[0].Line != 2 {
+		t.Fatalf("expected one HTML issue on line 2, got %+v", finding.Issues)
+	}
+	if finding.CommentedLines != 6 || finding.CommentedBytes != len("<!--\n<script>\n// var legacy = load();\ninit();\n</script>\n-->") {
+		t.Errorf("expected the block to be counted once, got %d lines and %d bytes", finding.CommentedLines, finding.CommentedBytes)
+	}
+}
+
+func TestCommentedCodeRule_Positions(t *testing.T) {
+	content := "<div>\n  <!-- <p>old</p>\n  <p>é</p> -->\n<style>/* .a { b: c; } */</style>\n</div>"
+
+	rule, err := NewCommentedCodeRule(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	finding := rule.Apply(content).(CommentedCodeFinding)
+	if len(finding.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %+v", finding.Issues)
+	}
+
+	want := [][4]int{{2, 3, 3, 14}, {4, 8, 4, 25}}
+	for i, issue := range finding.Issues {
+		got := [4]int{issue.Line, issue.Column, issue.EndLine, issue.EndColumn}
+		if got != want[i] {
+			t.Errorf("issue %d: got line:column-end %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestCommentedCodeRule_KeepMarker(t *testing.T) {
+	content := `<body>
+<!-- KEEP: holiday banner, re-enabled every December -->
+<!--
+<div class="holiday"><span>Season's greetings</span></div>
+-->
+</body>`
+
+	rule, err := NewCommentedCodeRule(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := rule.Apply(content)
+	if result == nil {
+		t.Fatal("expected kept block, got nil")
+	}
+
+	finding := result.(CommentedCodeFinding)
+	if finding.CommentedBytes != 0 || len(finding.Issues) != 0 {
+		t.Errorf("expected kept block to be excluded from metrics, got %+v", finding)
+	}
+	if len(finding.Kept) != 1 || finding.Kept[0].Line != 3 {
+		t.Errorf("expected 1 kept block on line 3, got %+v", finding.Kept)
+	}
+}
+
+func TestCommentedCodeRule_ApplyReader(t *testing.T) {
+	// A huge generated comment, then markup after a comment left open
+	content := "<!-- " + strings.Repeat("<p>x</p>", 200000) + " -->\n" +
+		"<style>/* .a { b: c; } */</style>\n" +
+		"<!-- never closed\n<script>\n// var legacy = load();\n</script>\n"
+
+	rule, err := NewCommentedCodeRule(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// One byte at a time, so every match straddles reads
+	finding, err := rule.ApplyReader(iotest.OneByteReader(strings.NewReader(content)))
+	if err != nil {
+		t.Fatalf("ApplyReader failed: %v", err)
+	}
+	if finding == nil {
+		t.Fatal("expected finding, got nil")
+	}
+
+	result := finding.(CommentedCodeFinding)
+	if result.TotalBytes != len(content) || result.TotalLines != 7 {
+		t.Errorf("expected %d bytes in 7 lines, got %d bytes in %d lines", len(content), result.TotalBytes, result.TotalLines)
+	}
+
+	// A comment left open is no comment, so the script after it is analyzed
+	want := []struct {
+		rule string
+		line int
+	}{{RuleCommentedCode, 1}, {"js/commented-code", 5}, {RuleCommentedCSS, 2}}
+	if len(result.Issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), result.Issues)
+	}
+	for i, w := range want {
+		if result.Issues[i].RuleID != w.rule || result.Issues[i].Line != w.line {
+			t.Errorf("issue %d: expected %s on line %d, got %s on line %d", i, w.rule, w.line, result.Issues[i].RuleID, result.Issues[i].Line)
+		}
+	}
+	if bytes := len(content[:strings.Index(content, "\n")]); result.Issues[0].Metadata.Bytes != bytes {
+		t.Errorf("expected the large comment to count %d bytes, got %d", bytes, result.Issues[0].Metadata.Bytes)
+	}
+	if css := result.Issues[2]; css.Column != 8 || css.EndLine != 2 || css.EndColumn != 25 {
+		t.Errorf("expected CSS issue at 2:8-2:25, got %d:%d-%d:%d", css.Line, css.Column, css.EndLine, css.EndColumn)
+	}
+}
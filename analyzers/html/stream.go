@@ -0,0 +1,463 @@
+package html
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// Text retained while scanning is capped so memory stays bounded on generated
+// files with huge comments or single-line markup. Counts and positions are
+// always exact; only the text fed to the ignore patterns, the tag heuristic
+// and KEEP markers is truncated.
+const (
+	maxRetainedComment = 1 << 20
+	maxRetainedLine    = 64 * 1024
+	maxRetainedAttrs   = 64 * 1024
+)
+
+// position is the 1-based line and character column of a byte
+type position struct {
+	line, column int
+}
+
+// cursor tracks the position of the next byte of a stream
+type cursor struct {
+	line  int
+	runes int // characters before the next byte on its line
+}
+
+func newCursor() cursor {
+	return cursor{line: 1}
+}
+
+func (c *cursor) pos() position {
+	return position{line: c.line, column: c.runes + 1}
+}
+
+func (c *cursor) advance(b byte) {
+	if b == '\n' {
+		c.line++
+		c.runes = 0
+	} else if utf8.RuneStart(b) {
+		c.runes++
+	}
+}
+
+// commentScanner finds <!-- --> comments in a single pass over the input and
+// passes the text, with comments blanked, on to the inline <script> and
+// <style> detectors. A comment is held back until it closes, since one left
+// open is no comment and its text is scanned like the rest of the file; only
+// the start of a comment over maxRetainedComment is held, the rest is blanked
+// as it is read.
+type commentScanner struct {
+	rule *CommentedCodeRule
+
+	commentedBytes int
+	commentedLines int
+	largestBlock   int
+	issues         []models.Issue
+	kept           []models.KeptBlock
+
+	totalBytes int
+	newlines   int
+	cur        cursor
+
+	// Current line, for KEEP markers
+	lineBuf       []byte
+	lineTruncated bool
+	lastNonBlank  string
+
+	// Start of a possible "<!--" and the position of its '<'
+	pending      []byte
+	pendingStart position
+
+	// Current comment
+	inComment        bool
+	commentStart     position
+	commentPreceding string
+	commentText      []byte
+	commentTruncated bool
+	commentBytes     int
+	commentNewlines  int
+	prev1, prev2     byte // the last two bytes after "<!--"
+
+	scripts *blockScanner
+	styles  *blockScanner
+	embed   CommentedCodeFinding
+	css     CommentedCodeFinding
+}
+
+func newCommentScanner(rule *CommentedCodeRule) *commentScanner {
+	s := &commentScanner{rule: rule, cur: newCursor()}
+	s.scripts = newBlockScanner("script", s.openScript)
+	s.styles = newBlockScanner("style", s.openStyle)
+	return s
+}
+
+// scan feeds everything read from r through the scanner
+func (s *commentScanner) scan(r io.Reader) error {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := r.Read(buf)
+		for _, b := range buf[:n] {
+			s.feed(b)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	s.flushPending()
+	if s.inComment && !s.commentTruncated {
+		for _, b := range s.commentText {
+			s.emit(b)
+		}
+	}
+	return nil
+}
+
+func (s *commentScanner) feed(b byte) {
+	s.totalBytes++
+	at := s.cur.pos()
+
+	switch {
+	case s.inComment:
+		s.commentBytes++
+		if b == '\n' {
+			s.commentNewlines++
+		}
+		if len(s.commentText) < maxRetainedComment {
+			s.commentText = append(s.commentText, b)
+		} else {
+			if !s.commentTruncated {
+				s.blank(s.commentText)
+			}
+			s.commentTruncated = true
+			s.blank([]byte{b})
+		}
+		if b == '>' && s.prev1 == '-' && s.prev2 == '-' {
+			s.closeComment(at)
+		} else {
+			s.prev2, s.prev1 = s.prev1, b
+		}
+	case b == "<!--"[len(s.pending)]:
+		if len(s.pending) == 0 {
+			s.pendingStart = at
+		}
+		s.pending = append(s.pending, b)
+		if len(s.pending) == 4 {
+			s.openComment()
+		}
+	default:
+		s.flushPending()
+		if b == '<' {
+			s.pendingStart = at
+			s.pending = append(s.pending, b)
+		} else {
+			s.emit(b)
+		}
+	}
+
+	// Line tracking for KEEP markers
+	if b == '\n' {
+		s.newlines++
+		s.endLine()
+	} else if len(s.lineBuf) < maxRetainedLine {
+		s.lineBuf = append(s.lineBuf, b)
+	} else {
+		s.lineTruncated = true
+	}
+	s.cur.advance(b)
+}
+
+// emit passes a byte outside comments on to the block detectors
+func (s *commentScanner) emit(b byte) {
+	s.scripts.feed(b)
+	s.styles.feed(b)
+}
+
+// blank passes comment bytes on to the block detectors as spaces, keeping
+// line breaks so positions still match the file
+func (s *commentScanner) blank(text []byte) {
+	for _, b := range text {
+		if b != '\n' && b != '\r' {
+			b = ' '
+		}
+		s.emit(b)
+	}
+}
+
+// flushPending passes on the start of a "<!--" that did not complete
+func (s *commentScanner) flushPending() {
+	for _, b := range s.pending {
+		s.emit(b)
+	}
+	s.pending = s.pending[:0]
+}
+
+func (s *commentScanner) openComment() {
+	// The text before the opening <!-- on this line, else the last non-blank line
+	prefix := s.lineBuf
+	if !s.lineTruncated && len(prefix) >= 3 {
+		prefix = prefix[:len(prefix)-3]
+	}
+	s.commentPreceding = strings.TrimSpace(string(prefix))
+	if s.commentPreceding == "" {
+		s.commentPreceding = s.lastNonBlank
+	}
+
+	s.inComment = true
+	s.commentStart = s.pendingStart
+	s.commentText = append(s.commentText[:0], s.pending...)
+	s.commentTruncated = false
+	s.commentBytes = len(s.pending)
+	s.commentNewlines = 0
+	s.prev1, s.prev2 = 0, 0 // "<!-->" does not close the comment
+	s.pending = s.pending[:0]
+}
+
+func (s *commentScanner) closeComment(end position) {
+	s.inComment = false
+	if !s.commentTruncated {
+		s.blank(s.commentText)
+	}
+
+	text := string(s.commentText)
+	inner := text[4:]
+	if !s.commentTruncated {
+		inner = text[4 : len(text)-3]
+	}
+	if s.rule.isIgnored(text) {
+		return
+	}
+	// Heuristic: It's likely commented code if it contains HTML tags
+	if !tagRegex.MatchString(inner) {
+		return
+	}
+
+	matchLen := s.commentBytes
+	matchLines := s.commentNewlines + 1
+
+	// A `<!-- KEEP: reason -->` line right before the block keeps it out of the metrics
+	if reason, ok := analyzers.KeepReason(s.commentPreceding); ok {
+		s.kept = append(s.kept, models.KeptBlock{
+			Line:        s.commentStart.line,
+			Bytes:       matchLen,
+			Description: "Commented out HTML code block",
+			Reason:      reason,
+		})
+		return
+	}
+
+	s.commentedBytes += matchLen
+	s.commentedLines += matchLines
+	if matchLen > s.largestBlock {
+		s.largestBlock = matchLen
+	}
+
+	s.issues = append(s.issues, models.Issue{
+		Description: fmt.Sprintf("Commented out HTML code block (%d bytes)", matchLen),
+		RuleID:      RuleCommentedCode,
+		Category:    models.CategoryDeadCode,
+		Line:        s.commentStart.line,
+		Column:      s.commentStart.column,
+		EndLine:     end.line,
+		EndColumn:   end.column,
+		Severity:    "minor",
+		Metadata: &models.IssueMetadata{
+			Bytes:         matchLen,
+			LineSpan:      matchLines,
+			EffortMinutes: analyzers.RemovalEffort(matchLines),
+		},
+	})
+}
+
+func (s *commentScanner) endLine() {
+	if trimmed := strings.TrimSpace(string(s.lineBuf)); trimmed != "" {
+		s.lastNonBlank = trimmed
+	}
+	s.lineBuf = s.lineBuf[:0]
+	s.lineTruncated = false
+}
+
+// finding returns the result in the order of the former passes: HTML
+// comments, then inline scripts, then inline styles. It is nil when nothing
+// was found.
+func (s *commentScanner) finding() interface{} {
+	result := CommentedCodeFinding{
+		CommentedBytes: s.commentedBytes,
+		CommentedLines: s.commentedLines,
+		LargestBlock:   s.largestBlock,
+		TotalBytes:     s.totalBytes,
+		TotalLines:     s.newlines + 1,
+		Issues:         s.issues,
+		Kept:           s.kept,
+	}
+	for _, block := range []CommentedCodeFinding{s.embed, s.css} {
+		result.CommentedBytes += block.CommentedBytes
+		result.CommentedLines += block.CommentedLines
+		if block.LargestBlock > result.LargestBlock {
+			result.LargestBlock = block.LargestBlock
+		}
+		result.Issues = append(result.Issues, block.Issues...)
+		result.Kept = append(result.Kept, block.Kept...)
+	}
+
+	if result.CommentedBytes == 0 && len(result.Kept) == 0 {
+		return nil
+	}
+	return result
+}
+
+// blockConsumer receives the content of one inline block
+type blockConsumer interface {
+	feed(b byte, at position)
+	// end is called once the closing tag is found; blocks left open are
+	// dropped without it
+	end()
+}
+
+// pendingByte is a byte held back while it may start a closing tag
+type pendingByte struct {
+	b  byte
+	at position
+}
+
+// Block detector states
+const (
+	blockText  = iota // looking for "<name"
+	blockName         // after "<name", which must end there
+	blockAttrs        // up to the '>' of the opening tag
+	blockBody         // up to "</name", spaces and '>'
+	blockClose        // after "</name", in the spaces before '>'
+)
+
+// blockScanner finds <name ...>...</name> blocks in a single pass, the way
+// the regex (?is)<name\b([^>]*)>(.*?)</name\s*> finds them in the whole text
+type blockScanner struct {
+	open  string // "<name"
+	close string // "</name"
+	// onOpen returns the consumer of a block's content, or nil to skip it
+	onOpen func(attrs string, start position) blockConsumer
+
+	cur     cursor
+	state   int
+	matched int
+	attrs   []byte
+	pending []pendingByte
+	content blockConsumer
+}
+
+func newBlockScanner(name string, onOpen func(string, position) blockConsumer) *blockScanner {
+	return &blockScanner{open: "<" + name, close: "</" + name, onOpen: onOpen, cur: newCursor()}
+}
+
+func (s *blockScanner) feed(b byte) {
+	at := s.cur.pos()
+	s.cur.advance(b)
+	s.step(b, at)
+}
+
+func (s *blockScanner) step(b byte, at position) {
+	switch s.state {
+	case blockText:
+		s.matchOpen(b)
+	case blockName:
+		if isWordByte(b) {
+			s.state, s.matched = blockText, 0
+			return
+		}
+		s.state = blockAttrs
+		s.attrs = s.attrs[:0]
+		s.step(b, at)
+	case blockAttrs:
+		if b != '>' {
+			if len(s.attrs) < maxRetainedAttrs {
+				s.attrs = append(s.attrs, b)
+			}
+			return
+		}
+		s.state, s.matched = blockBody, 0
+		s.pending = s.pending[:0]
+		s.content = s.onOpen(string(s.attrs), s.cur.pos())
+	case blockBody:
+		if lower(b) == s.close[s.matched] {
+			s.pending = append(s.pending, pendingByte{b, at})
+			if s.matched++; s.matched == len(s.close) {
+				s.state = blockClose
+			}
+			return
+		}
+		if s.matched > 0 {
+			s.flushPending()
+			s.step(b, at)
+			return
+		}
+		s.consume(b, at)
+	case blockClose:
+		switch {
+		case b == '>':
+			if s.content != nil {
+				s.content.end()
+			}
+			s.state, s.matched, s.content = blockText, 0, nil
+		case b == ' ' || b == '\t' || b == '\n' || b == '\f' || b == '\r':
+			s.pending = append(s.pending, pendingByte{b, at})
+		default:
+			s.state = blockBody
+			s.flushPending()
+			s.step(b, at)
+		}
+	}
+}
+
+// matchOpen advances the match of "<name" by b
+func (s *blockScanner) matchOpen(b byte) {
+	if lower(b) == s.open[s.matched] {
+		if s.matched++; s.matched == len(s.open) {
+			s.state = blockName
+		}
+		return
+	}
+	s.matched = 0
+	if b == '<' {
+		s.matched = 1
+	}
+}
+
+// flushPending hands the start of a closing tag that did not complete to the
+// content consumer
+func (s *blockScanner) flushPending() {
+	for _, p := range s.pending {
+		s.consume(p.b, p.at)
+	}
+	s.pending = s.pending[:0]
+	s.matched = 0
+}
+
+func (s *blockScanner) consume(b byte, at position) {
+	if s.content != nil {
+		s.content.feed(b, at)
+	}
+}
+
+// isWordByte reports whether b is an ASCII word character, as \b sees it
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// lower returns the ASCII lower case of b
+func lower(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
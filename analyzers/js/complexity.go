@@ -0,0 +1,191 @@
+package js
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// Rule IDs of the function-level rules
+const (
+	RuleComplexity = "js/complexity"
+	RuleNesting    = "js/nesting-depth"
+)
+
+// Default thresholds used when the config does not set them
+const (
+	DefaultMaxComplexity = 10
+	DefaultMaxNesting    = 4
+)
+
+// ComplexityRule reports functions whose cyclomatic complexity, 1 plus one
+// per if, for, while, case, catch, ternary and &&/||/?? operator, exceeds
+// Max; functions at twice Max or more are major. It also reports functions
+// nesting control blocks and callbacks deeper than MaxNesting.
+type ComplexityRule struct {
+	Max        int
+	MaxNesting int
+}
+
+// ComplexityFinding holds the functions of a file above the maximum
+// complexity, most complex first, the functions nested too deeply, deepest
+// first, and the size of the file
+type ComplexityFinding struct {
+	Functions     []models.FunctionComplexity
+	DeepFunctions []models.FunctionNesting
+	Issues        []models.Issue
+	TotalBytes    int
+	TotalLines    int
+}
+
+func (r *ComplexityRule) Name() string {
+	return "Complexity Detector"
+}
+
+func (r *ComplexityRule) Apply(content string) interface{} {
+	finding, _ := r.ApplyReader(strings.NewReader(content))
+	return finding
+}
+
+// ApplyReader lexes the input once; it returns nil when no function is too
+// complex or nested too deeply
+func (r *ComplexityRule) ApplyReader(reader io.Reader) (interface{}, error) {
+	max, maxNesting := r.Max, r.MaxNesting
+	if max == 0 {
+		max = DefaultMaxComplexity
+	}
+	if maxNesting == 0 {
+		maxNesting = DefaultMaxNesting
+	}
+
+	counted := &countingReader{r: reader}
+	lex := newLexer(counted)
+	functions, err := scanFunctions(lex)
+	if err != nil {
+		return nil, err
+	}
+
+	finding := ComplexityFinding{TotalBytes: counted.n, TotalLines: lex.line}
+	for _, fn := range functions {
+		if fn.Complexity > max {
+			finding.Functions = append(finding.Functions, models.FunctionComplexity{
+				Name:       fn.Name,
+				Line:       fn.Line,
+				EndLine:    fn.EndLine,
+				Complexity: fn.Complexity,
+			})
+		}
+	}
+	finding.DeepFunctions = deepFunctions(functions, maxNesting)
+	if len(finding.Functions) == 0 && len(finding.DeepFunctions) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(finding.Functions, func(i, j int) bool {
+		return finding.Functions[i].Line < finding.Functions[j].Line
+	})
+	for _, fn := range finding.Functions {
+		severity := "minor"
+		if fn.Complexity >= 2*max {
+			severity = "major"
+		}
+		finding.Issues = append(finding.Issues, models.Issue{
+			Description: fmt.Sprintf("Function %s has a cyclomatic complexity of %d (max %d)", fn.Name, fn.Complexity, max),
+			RuleID:      RuleComplexity,
+			Category:    models.CategoryComplexity,
+			Line:        fn.Line,
+			Severity:    severity,
+			Metadata: &models.IssueMetadata{
+				LineSpan:      fn.EndLine - fn.Line + 1,
+				EffortMinutes: analyzers.RefactorEffort(fn.Complexity - max),
+			},
+		})
+	}
+	for _, fn := range finding.DeepFunctions {
+		finding.Issues = append(finding.Issues, models.Issue{
+			Description: fmt.Sprintf("Function %s nests %d levels deep at line %d (max %d)", fn.Name, fn.Depth, fn.DepthLine, maxNesting),
+			RuleID:      RuleNesting,
+			Category:    models.CategoryComplexity,
+			Line:        fn.Line,
+			Severity:    "minor",
+			Metadata: &models.IssueMetadata{
+				LineSpan:      fn.EndLine - fn.Line + 1,
+				EffortMinutes: analyzers.RefactorEffort(fn.Depth - maxNesting),
+			},
+		})
+	}
+	sort.SliceStable(finding.Issues, func(i, j int) bool {
+		return finding.Issues[i].Line < finding.Issues[j].Line
+	})
+
+	sort.SliceStable(finding.Functions, func(i, j int) bool {
+		return finding.Functions[i].Complexity > finding.Functions[j].Complexity
+	})
+	sort.SliceStable(finding.DeepFunctions, func(i, j int) bool {
+		return finding.DeepFunctions[i].Depth > finding.DeepFunctions[j].Depth
+	})
+	return finding, nil
+}
+
+// deepFunctions returns the functions, in the order they start, nested
+// deeper than max. A function whose deepest point lies in a nested function
+// already reported is left out, so a deep callback is reported once rather
+// than with every function around it.
+func deepFunctions(functions []jsFunction, max int) []models.FunctionNesting {
+	parents := make(map[int]int, len(functions))
+	for _, fn := range functions {
+		parents[fn.ID] = fn.Parent
+	}
+
+	// Nested functions end, and are decided, before the functions around them
+	reported := make(map[int]bool)
+	var deep []models.FunctionNesting
+	for _, fn := range functions {
+		if fn.Depth <= max {
+			continue
+		}
+		covered := false
+		for id := fn.DepthIn; id != fn.ID; {
+			if reported[id] {
+				covered = true
+				break
+			}
+			parent, ok := parents[id]
+			if !ok {
+				break
+			}
+			id = parent
+		}
+		if covered {
+			continue
+		}
+		reported[fn.ID] = true
+		deep = append(deep, models.FunctionNesting{
+			Name:      fn.Name,
+			Line:      fn.Line,
+			EndLine:   fn.EndLine,
+			Depth:     fn.Depth,
+			DepthLine: fn.DepthLine,
+		})
+	}
+	sort.SliceStable(deep, func(i, j int) bool {
+		return deep[i].Line < deep[j].Line
+	})
+	return deep
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
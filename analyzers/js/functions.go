@@ -0,0 +1,289 @@
+package js
+
+// jsFunction is a function, method or block-bodied arrow function
+type jsFunction struct {
+	ID         int // Position among the functions in the order they start
+	Parent     int // ID of the enclosing function, -1 at the top level
+	Name       string
+	Line       int
+	EndLine    int
+	Complexity int // 1 + decision points
+	// Depth is how deeply control blocks and nested functions are nested
+	// inside the function, DepthLine where that depth is first reached and
+	// DepthIn the ID of the innermost function containing that point
+	Depth     int
+	DepthLine int
+	DepthIn   int
+}
+
+// nestingKeywords open control blocks that count for the nesting depth:
+// after "keyword (...)" or directly after the keyword
+var nestingKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "with": true,
+	"else": true, "try": true, "finally": true, "do": true,
+}
+
+// decisionKeywords each add a path through a function
+var decisionKeywords = map[string]bool{"if": true, "for": true, "while": true, "case": true, "catch": true}
+
+// decisionOperators each add a path through a function; "?" only counts as
+// a ternary
+var decisionOperators = map[string]bool{"&&": true, "||": true, "??": true, "&&=": true, "||=": true, "??=": true}
+
+// notMethods are keywords followed by a parenthesized expression and a
+// block that are not method definitions
+var notMethods = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true,
+	"with": true, "function": true, "return": true, "typeof": true, "await": true,
+}
+
+// paren remembers the two tokens before an opening parenthesis, which name
+// methods and arrow functions
+type paren struct {
+	before1, before2 token
+}
+
+// block is an open brace; fn is set when the brace opens a function body.
+// level counts the function bodies and control blocks open up to and
+// including this one.
+type block struct {
+	fn    *jsFunction
+	level int
+}
+
+// functionScanner finds functions in a token stream and counts the decision
+// points of each. Expression-bodied arrow functions are counted as part of
+// the function they are in.
+type functionScanner struct {
+	functions []jsFunction
+	started   int
+	blocks    []block
+	parens    []paren
+	closed    paren // The parenthesis closed last
+
+	prev, prev2, prev3 token
+
+	// A `function` keyword waiting for its body
+	pendingFn     bool
+	pendingName   string
+	pendingLine   int
+	pendingParens int // Open parentheses around the keyword
+	// Arrow function waiting for a block body
+	arrowName string
+	arrowLine int
+	// A ternary candidate, decided by the next token
+	question bool
+	// Tokens of a TypeScript return type between ")" and "{"
+	inReturnType bool
+}
+
+// scanFunctions reads every token of lex and returns the functions in the
+// order they end
+func scanFunctions(lex *lexer) ([]jsFunction, error) {
+	s := &functionScanner{}
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			break
+		}
+		s.feed(tok)
+	}
+	return s.functions, lex.err
+}
+
+// current returns the innermost open function, nil at the top level
+func (s *functionScanner) current() *jsFunction {
+	for i := len(s.blocks) - 1; i >= 0; i-- {
+		if s.blocks[i].fn != nil {
+			return s.blocks[i].fn
+		}
+	}
+	return nil
+}
+
+func (s *functionScanner) decision() {
+	if fn := s.current(); fn != nil {
+		fn.Complexity++
+	}
+}
+
+func (s *functionScanner) feed(tok token) {
+	if s.question {
+		s.question = false
+		// "a?: T" and "a?)" are optional TypeScript parameters
+		if tok.text != ":" && tok.text != ")" && tok.text != "," && tok.text != "=" {
+			s.decision()
+		}
+	}
+
+	if s.inReturnType && tok.text != "{" {
+		if tok.kind == tokenIdent || tok.text == "." || tok.text == "<" || tok.text == ">" ||
+			tok.text == "[" || tok.text == "]" || tok.text == "|" || tok.text == "&" || tok.text == "," {
+			s.shift(tok)
+			return
+		}
+		s.inReturnType = false
+	}
+
+	switch {
+	case tok.kind == tokenIdent && tok.text == "function":
+		s.pendingFn = true
+		s.pendingLine = tok.line
+		s.pendingParens = len(s.parens)
+		s.pendingName = s.assignedName()
+	case tok.kind == tokenIdent && s.pendingFn && s.prev.text == "function":
+		s.pendingName = tok.text
+	case tok.kind == tokenIdent && decisionKeywords[tok.text]:
+		s.decision()
+	case tok.kind == tokenPunct:
+		s.punct(tok)
+	}
+	s.shift(tok)
+}
+
+func (s *functionScanner) punct(tok token) {
+	switch tok.text {
+	case "(":
+		s.parens = append(s.parens, paren{before1: s.prev, before2: s.prev2})
+	case ")":
+		if n := len(s.parens); n > 0 {
+			s.closed = s.parens[n-1]
+			s.parens = s.parens[:n-1]
+		}
+	case ":":
+		// A return type annotation of a method or function
+		if s.prev.text == ")" && (s.pendingFn || s.isMethodHead()) {
+			s.inReturnType = true
+		}
+	case "=>":
+		s.arrowName, s.arrowLine = s.arrowHead()
+	case "?":
+		s.question = true
+	case "{":
+		s.openBlock(tok)
+	case "}":
+		s.closeBlock(tok)
+	default:
+		if decisionOperators[tok.text] {
+			s.decision()
+		}
+	}
+}
+
+func (s *functionScanner) openBlock(tok token) {
+	var fn *jsFunction
+	nesting := s.isControlBlock()
+	switch {
+	case s.pendingFn && len(s.parens) == s.pendingParens && (s.prev.text == ")" || s.inReturnType):
+		fn = &jsFunction{Name: s.pendingName, Line: s.pendingLine}
+		s.pendingFn = false
+	case s.prev.text == "=>":
+		fn = &jsFunction{Name: s.arrowName, Line: s.arrowLine}
+	case (s.prev.text == ")" || s.inReturnType) && s.isMethodHead():
+		fn = &jsFunction{Name: s.closed.before1.text, Line: s.closed.before1.line}
+	}
+	s.inReturnType = false
+
+	level := 0
+	if n := len(s.blocks); n > 0 {
+		level = s.blocks[n-1].level
+	}
+	if fn != nil || nesting {
+		level++
+		s.nest(level, tok.line)
+	}
+	if fn != nil {
+		if fn.Name == "" {
+			fn.Name = "<anonymous>"
+		}
+		fn.ID, fn.Parent, fn.DepthIn = s.started, -1, s.started
+		if parent := s.current(); parent != nil {
+			fn.Parent = parent.ID
+		}
+		fn.Complexity = 1
+		s.started++
+	}
+	s.blocks = append(s.blocks, block{fn: fn, level: level})
+}
+
+// isControlBlock reports whether the brace being opened is the block of a
+// control statement
+func (s *functionScanner) isControlBlock() bool {
+	if s.prev.kind == tokenIdent {
+		return nestingKeywords[s.prev.text]
+	}
+	return s.prev.text == ")" && s.closed.before1.kind == tokenIdent && nestingKeywords[s.closed.before1.text]
+}
+
+// nest records a function body or control block opening at level in the
+// depth of every enclosing function
+func (s *functionScanner) nest(level, line int) {
+	inner := -1
+	for i := len(s.blocks) - 1; i >= 0; i-- {
+		fn := s.blocks[i].fn
+		if fn == nil {
+			continue
+		}
+		if inner == -1 {
+			inner = fn.ID
+		}
+		if depth := level - s.blocks[i].level; depth > fn.Depth {
+			fn.Depth, fn.DepthLine, fn.DepthIn = depth, line, inner
+		}
+	}
+}
+
+func (s *functionScanner) closeBlock(tok token) {
+	n := len(s.blocks)
+	if n == 0 {
+		return
+	}
+	b := s.blocks[n-1]
+	s.blocks = s.blocks[:n-1]
+	if b.fn != nil {
+		b.fn.EndLine = tok.line
+		s.functions = append(s.functions, *b.fn)
+	}
+}
+
+// isMethodHead reports whether the parenthesis closed last follows a name
+// that makes "name(...) {" a method definition
+func (s *functionScanner) isMethodHead() bool {
+	name := s.closed.before1
+	return name.kind == tokenIdent && !notMethods[name.text] && !decisionKeywords[name.text]
+}
+
+// assignedName returns the name a function expression is assigned to in
+// "name = function" or "name: function", else ""
+func (s *functionScanner) assignedName() string {
+	prev, name := s.prev, s.prev2
+	if prev.kind == tokenIdent && prev.text == "async" {
+		prev, name = s.prev2, s.prev3
+	}
+	if (prev.text == "=" || prev.text == ":") && name.kind == tokenIdent {
+		return name.text
+	}
+	return ""
+}
+
+// arrowHead returns the name and line of the arrow function whose "=>" was
+// just read: "name = (a) =>", "name = a =>" or "name: () =>"
+func (s *functionScanner) arrowHead() (string, int) {
+	before1, before2 := s.prev2, s.prev3
+	line := s.prev.line
+	if s.prev.text == ")" {
+		before1, before2 = s.closed.before1, s.closed.before2
+	}
+	if before1.kind == tokenIdent && before1.text == "async" {
+		// Only one more token is known here; async arrows stay anonymous
+		return "", line
+	}
+	if (before1.text == "=" || before1.text == ":") && before2.kind == tokenIdent {
+		return before2.text, before2.line
+	}
+	return "", line
+}
+
+func (s *functionScanner) shift(tok token) {
+	s.prev3, s.prev2, s.prev = s.prev2, s.prev, tok
+}
@@ -1,10 +1,11 @@
 package js
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 
@@ -13,6 +14,9 @@ import (
 	"code-analyzer/utils"
 )
 
+// RuleCommentedCode is the rule ID of commented-out JS code blocks
+const RuleCommentedCode = "js/commented-code"
+
 // JSAnalyzer analyzes JavaScript/TypeScript files for commented code
 type JSAnalyzer struct {
 	rules []analyzers.Rule
@@ -23,6 +27,7 @@ func NewJSAnalyzer() *JSAnalyzer {
 	return &JSAnalyzer{
 		rules: []analyzers.Rule{
 			&CommentedCodeRule{},
+			&ComplexityRule{},
 		},
 	}
 }
@@ -34,54 +39,98 @@ func (a *JSAnalyzer) Name() string {
 
 // Description returns what this analyzer does
 func (a *JSAnalyzer) Description() string {
-	return "Analyzes JS/TS files for commented code blocks"
+	return "Analyzes JS/TS files for commented code blocks and overly complex functions"
+}
+
+// CPUBound reports that the analyzer's run time goes to parsing
+func (a *JSAnalyzer) CPUBound() bool {
+	return true
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *JSAnalyzer) RuleIDs() []string {
+	return []string{RuleCommentedCode, RuleComplexity, RuleNesting}
 }
 
 // Run executes the JS analysis
-func (a *JSAnalyzer) Run(config analyzers.Config) ([]models.Issue, error) {
+func (a *JSAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
 	results := []models.JSFileAnalysis{}
+	kept := []models.KeptBlock{}
+	skipped := []models.SkippedFile{}
 	var allIssues []models.Issue
 
-	err := filepath.Walk(config.RootDir, func(path string, info os.FileInfo, err error) error {
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return nil
 		}
-
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext != ".js" && ext != ".jsx" && ext != ".ts" && ext != ".tsx" {
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
 			return nil
 		}
 
 		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
 			return nil
 		}
 
-		analysis := a.analyzeFile(path)
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, config)
 		if analysis != nil {
-			if analysis.CommentedBytes < config.MinValue {
-				return nil
+			kept = append(kept, analysis.Kept...)
+			// Complex and deeply nested functions are reported whatever the
+			// commented-code thresholds
+			below := ""
+			if analysis.CommentedBytes == 0 || analysis.CommentedBytes < config.MinValue {
+				below = "min"
+			} else if config.MinRatio > 0 && analysis.CommentRatio < config.MinRatio {
+				below = "min_ratio"
 			}
-			if config.MinRatio > 0 && analysis.CommentRatio < config.MinRatio {
-				return nil
+			if below != "" {
+				if len(analysis.ComplexFunctions) == 0 && len(analysis.DeepFunctions) == 0 {
+					config.Tracef(path, "not reported, below %s", below)
+					return nil
+				}
+				config.Tracef(path, "commented code not reported, below %s", below)
+				analysis.Issues = complexityIssues(analysis.Issues)
 			}
 			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
 			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
 		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return allIssues, err
 	}
 
 	// Sort results
 	if config.SortBy == "ratio" {
 		sort.Slice(results, func(i, j int) bool {
-			return results[i].CommentRatio > results[j].CommentRatio
+			if results[i].CommentRatio != results[j].CommentRatio {
+				return results[i].CommentRatio > results[j].CommentRatio
+			}
+			return results[i].Path < results[j].Path
 		})
 	} else {
 		sort.Slice(results, func(i, j int) bool {
-			return results[i].CommentedBytes > results[j].CommentedBytes
+			if results[i].CommentedBytes != results[j].CommentedBytes {
+				return results[i].CommentedBytes > results[j].CommentedBytes
+			}
+			return results[i].Path < results[j].Path
 		})
 	}
 
@@ -91,46 +140,63 @@ func (a *JSAnalyzer) Run(config analyzers.Config) ([]models.Issue, error) {
 	}
 
 	// Generate artifact if requested
-	if config.OutputFile != "" {
-		if err := a.generateArtifact(results, config); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to generate artifact: %v\n", err)
-		} else {
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, kept, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
 			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
 		}
 	}
 
 	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintKept(kept)
+	analyzers.PrintSkipped(skipped)
 	a.printResults(results)
 	return allIssues, nil
 }
 
-func (a *JSAnalyzer) analyzeFile(path string) *models.JSFileAnalysis {
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return nil
-	}
-
-	// Apply commented code rule
-	rule := &CommentedCodeRule{}
-	finding := rule.Apply(string(content))
+// Handles reports whether path is a JS/TS file
+func (a *JSAnalyzer) Handles(path string, config analyzers.Config) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".js" || ext == ".jsx" || ext == ".ts" || ext == ".tsx"
+}
 
-	if finding == nil {
-		return nil
+func (a *JSAnalyzer) analyzeFile(path string, config analyzers.Config) *models.JSFileAnalysis {
+	// Apply commented code and complexity rules, streaming the file once each
+	var result CommentedCodeFinding
+	if finding := applyStreamRule(&CommentedCodeRule{}, path, config.Diagnostics); finding != nil {
+		result = finding.(CommentedCodeFinding)
 	}
-
-	result := finding.(CommentedCodeFinding)
-	if result.CommentedBytes == 0 {
+	var complexity ComplexityFinding
+	rule := &ComplexityRule{Max: config.MaxComplexity, MaxNesting: config.MaxNesting}
+	if finding := applyStreamRule(rule, path, config.Diagnostics); finding != nil {
+		complexity = finding.(ComplexityFinding)
+	}
+	if result.CommentedBytes == 0 && len(result.Kept) == 0 && len(complexity.Issues) == 0 {
 		return nil
 	}
+	if result.TotalBytes == 0 {
+		result.TotalBytes, result.TotalLines = complexity.TotalBytes, complexity.TotalLines
+	}
+	result.Issues = append(result.Issues, complexity.Issues...)
 
-	// Set path for issues
+	// Set path for issues and kept blocks
 	for i := range result.Issues {
 		result.Issues[i].Path = path
 	}
+	for i := range result.Kept {
+		result.Kept[i].Path = path
+	}
 
-	totalBytes := len(content)
-	totalLines := strings.Count(string(content), "\n") + 1
-	ratio := float64(result.CommentedBytes) / float64(totalBytes) * 100
+	totalBytes := result.TotalBytes
+	totalLines := result.TotalLines
+	ratio := 0.0
+	if totalBytes > 0 {
+		ratio = float64(result.CommentedBytes) / float64(totalBytes) * 100
+	}
 
 	return &models.JSFileAnalysis{
 		Path:           path,
@@ -141,12 +207,80 @@ func (a *JSAnalyzer) analyzeFile(path string) *models.JSFileAnalysis {
 		CommentRatio:   ratio,
 		LargestBlock:   result.LargestBlock,
 		Issues:         result.Issues,
+		Kept:           result.Kept,
+
+		ComplexFunctions: complexity.Functions,
+		DeepFunctions:    complexity.DeepFunctions,
 	}
 }
 
-func (a *JSAnalyzer) printResults(results []models.JSFileAnalysis) {
+// thresholds returns the configured complexity and nesting limits, falling
+// back to defaults
+func thresholds(config analyzers.Config) (maxComplexity, maxNesting int) {
+	maxComplexity, maxNesting = config.MaxComplexity, config.MaxNesting
+	if maxComplexity == 0 {
+		maxComplexity = DefaultMaxComplexity
+	}
+	if maxNesting == 0 {
+		maxNesting = DefaultMaxNesting
+	}
+	return
+}
+
+// applyStreamRule streams the text of path through rule. It returns nil for
+// binary and unreadable files.
+func applyStreamRule(rule analyzers.StreamRule, path string, diags *analyzers.Diagnostics) interface{} {
+	file, encoding, err := utils.OpenText(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	if encoding == utils.EncodingBinary {
+		return nil
+	}
+
+	finding, err := analyzers.ApplyStreamRule(rule, path, file, diags)
+	if err != nil {
+		return nil
+	}
+	return finding
+}
+
+// complexityOnly reports whether r was reported only for complex or deeply
+// nested functions
+func complexityOnly(r models.JSFileAnalysis) bool {
+	for _, issue := range r.Issues {
+		if issue.Category != models.CategoryComplexity {
+			return false
+		}
+	}
+	return true
+}
+
+// complexityIssues returns the complexity and nesting issues of issues
+func complexityIssues(issues []models.Issue) []models.Issue {
+	var kept []models.Issue
+	for _, issue := range issues {
+		if issue.Category == models.CategoryComplexity {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+func (a *JSAnalyzer) printResults(all []models.JSFileAnalysis) {
+	// Files reported only for complex or deep functions are left out of the tables
+	var results []models.JSFileAnalysis
+	for _, r := range all {
+		if complexityOnly(r) {
+			continue
+		}
+		results = append(results, r)
+	}
 	if len(results) == 0 {
 		fmt.Println("✅ No JS/TS files with significant commented code found!")
+		a.printComplexFunctions(all)
+		a.printDeepFunctions(all)
 		return
 	}
 
@@ -175,9 +309,66 @@ func (a *JSAnalyzer) printResults(results []models.JSFileAnalysis) {
 
 	fmt.Println()
 	a.printTop10(results)
+	a.printComplexFunctions(all)
+	a.printDeepFunctions(all)
 	fmt.Println("✅ Analysis complete!")
 }
 
+// printComplexFunctions lists the most complex functions of the reported files
+func (a *JSAnalyzer) printComplexFunctions(results []models.JSFileAnalysis) {
+	type located struct {
+		path string
+		fn   models.FunctionComplexity
+	}
+	var functions []located
+	for _, r := range results {
+		for _, fn := range r.ComplexFunctions {
+			functions = append(functions, located{r.Path, fn})
+		}
+	}
+	if len(functions) == 0 {
+		return
+	}
+	sort.SliceStable(functions, func(i, j int) bool {
+		return functions[i].fn.Complexity > functions[j].fn.Complexity
+	})
+
+	fmt.Printf("🌀 Most Complex Functions (%d above the maximum):\n", len(functions))
+	fmt.Println(strings.Repeat("-", 80))
+	for _, f := range functions[:utils.Min(10, len(functions))] {
+		fmt.Printf("%4d  %s:%d %s\n", f.fn.Complexity, f.path, f.fn.Line, f.fn.Name)
+	}
+	fmt.Println()
+}
+
+// printDeepFunctions lists the most deeply nested functions of the reported
+// files
+func (a *JSAnalyzer) printDeepFunctions(results []models.JSFileAnalysis) {
+	type located struct {
+		path string
+		fn   models.FunctionNesting
+	}
+	var functions []located
+	for _, r := range results {
+		for _, fn := range r.DeepFunctions {
+			functions = append(functions, located{r.Path, fn})
+		}
+	}
+	if len(functions) == 0 {
+		return
+	}
+	sort.SliceStable(functions, func(i, j int) bool {
+		return functions[i].fn.Depth > functions[j].fn.Depth
+	})
+
+	fmt.Printf("🪆 Most Deeply Nested Functions (%d above the maximum):\n", len(functions))
+	fmt.Println(strings.Repeat("-", 80))
+	for _, f := range functions[:utils.Min(10, len(functions))] {
+		fmt.Printf("%4d  %s:%d %s (deepest at line %d)\n", f.fn.Depth, f.path, f.fn.Line, f.fn.Name, f.fn.DepthLine)
+	}
+	fmt.Println()
+}
+
 func (a *JSAnalyzer) printTop10(results []models.JSFileAnalysis) {
 	fmt.Printf("📋 Top 10 High-Impact Files:\n")
 	fmt.Println(strings.Repeat("-", 80))
@@ -195,23 +386,29 @@ func (a *JSAnalyzer) printTop10(results []models.JSFileAnalysis) {
 	fmt.Println()
 }
 
-func (a *JSAnalyzer) generateArtifact(results []models.JSFileAnalysis, config analyzers.Config) error {
+func (a *JSAnalyzer) generateArtifact(results []models.JSFileAnalysis, skipped []models.SkippedFile, kept []models.KeptBlock, config analyzers.Config) error {
 	totalCommented := 0
 	for _, r := range results {
 		totalCommented += r.CommentedBytes
 	}
 
+	maxComplexity, maxNesting := thresholds(config)
 	report := models.JSAnalysisReport{
-		Timestamp:      utils.GetTimestamp(),
-		ScanDirectory:  config.RootDir,
-		TotalFiles:     len(results),
-		TotalCommented: totalCommented,
-		SortMode:       config.SortBy,
-		MinComments:    config.MinValue,
-		Results:        results,
+		Timestamp:         utils.GetTimestamp(),
+		ScanDirectory:     config.RootDir,
+		TotalFiles:        len(results),
+		TotalCommented:    totalCommented,
+		SortMode:          config.SortBy,
+		MinComments:       config.MinValue,
+		MaxComplexity:     maxComplexity,
+		MaxNesting:        maxNesting,
+		Results:           results,
+		SkippedTooLarge:   skipped,
+		IntentionallyKept: kept,
+		Stats:             config.Stats.Summary(),
 	}
 
-	return utils.WriteArtifact(config.OutputFile, report)
+	return config.WriteArtifact(report)
 }
 
 // CommentedCodeRule detects commented-out JS code
@@ -221,7 +418,10 @@ type CommentedCodeFinding struct {
 	CommentedBytes int
 	CommentedLines int
 	LargestBlock   int
+	TotalBytes     int
+	TotalLines     int
 	Issues         []models.Issue
+	Kept           []models.KeptBlock
 }
 
 func (r *CommentedCodeRule) Name() string {
@@ -229,116 +429,18 @@ func (r *CommentedCodeRule) Name() string {
 }
 
 func (r *CommentedCodeRule) Apply(content string) interface{} {
-	commentedBytes := 0
-	commentedLines := 0
-	largestBlock := 0
-	var issues []models.Issue
-
-	// 1. Detect multi-line comments /* ... */
-	multiLineRegex := regexp.MustCompile(`(?s)/\*(.*?)\*/`)
-	multiLineMatches := multiLineRegex.FindAllStringSubmatchIndex(content, -1)
-
-	for _, loc := range multiLineMatches {
-		// loc[0], loc[1] is the whole match
-		// loc[2], loc[3] is the first group (.*?)
-		if len(loc) >= 4 {
-			commentStart, commentEnd := loc[2], loc[3]
-			commentContent := content[commentStart:commentEnd]
-
-			if isCode(commentContent) {
-				fullMatch := content[loc[0]:loc[1]]
-				matchLen := len(fullMatch)
-				matchLines := strings.Count(fullMatch, "\n") + 1
-				commentedBytes += matchLen
-				commentedLines += matchLines
-				if matchLen > largestBlock {
-					largestBlock = matchLen
-				}
-
-				// Calculate line number
-				lineNumber := strings.Count(content[:loc[0]], "\n") + 1
-				issues = append(issues, models.Issue{
-					Description: fmt.Sprintf("Commented out JS code block (%d bytes)", matchLen),
-					Line:        lineNumber,
-					Severity:    "minor",
-				})
-			}
-		}
-	}
-
-	// 2. Detect single-line comments // ...
-	lines := strings.Split(content, "\n")
-	var currentBlock strings.Builder
-	inBlock := false
-	blockStartLine := 0
-
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		// Check for single line comment
-		if strings.HasPrefix(trimmed, "//") {
-			commentContent := strings.TrimPrefix(trimmed, "//")
-			if inBlock {
-				currentBlock.WriteString("\n" + commentContent)
-			} else {
-				inBlock = true
-				blockStartLine = i + 1
-				currentBlock.Reset()
-				currentBlock.WriteString(commentContent)
-			}
-		} else {
-			if inBlock {
-				// End of block, analyze it
-				blockContent := currentBlock.String()
-				if isCode(blockContent) {
-					linesInBlock := strings.Count(blockContent, "\n") + 1
-					// Approx bytes
-					blockOriginalBytes := len(blockContent) + (linesInBlock * 2)
-
-					commentedBytes += blockOriginalBytes
-					commentedLines += linesInBlock
-					if blockOriginalBytes > largestBlock {
-						largestBlock = blockOriginalBytes
-					}
-
-					issues = append(issues, models.Issue{
-						Description: fmt.Sprintf("Commented out JS code block (%d bytes)", blockOriginalBytes),
-						Line:        blockStartLine,
-						Severity:    "minor",
-					})
-				}
-				inBlock = false
-			}
-		}
-	}
-	// Check last block
-	if inBlock {
-		blockContent := currentBlock.String()
-		if isCode(blockContent) {
-			linesInBlock := strings.Count(blockContent, "\n") + 1
-			blockOriginalBytes := len(blockContent) + (linesInBlock * 2)
-			commentedBytes += blockOriginalBytes
-			commentedLines += linesInBlock
-			if blockOriginalBytes > largestBlock {
-				largestBlock = blockOriginalBytes
-			}
-			issues = append(issues, models.Issue{
-				Description: fmt.Sprintf("Commented out JS code block (%d bytes)", blockOriginalBytes),
-				Line:        blockStartLine,
-				Severity:    "minor",
-			})
-		}
-	}
-
-	if commentedBytes == 0 {
-		return nil
-	}
+	finding, _ := r.ApplyReader(strings.NewReader(content))
+	return finding
+}
 
-	return CommentedCodeFinding{
-		CommentedBytes: commentedBytes,
-		CommentedLines: commentedLines,
-		LargestBlock:   largestBlock,
-		Issues:         issues,
+// ApplyReader scans the input once, so files of any size can be analyzed
+// without holding them in memory
+func (r *CommentedCodeRule) ApplyReader(reader io.Reader) (interface{}, error) {
+	scanner := newCommentScanner()
+	if err := scanner.scan(reader); err != nil {
+		return nil, err
 	}
+	return scanner.finding(), nil
 }
 
 // isCode uses heuristics to determine if text looks like code
@@ -1,6 +1,7 @@
 package js
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -67,3 +68,180 @@ func TestCommentedCodeRule_Apply(t *testing.T) {
 		})
 	}
 }
+
+func TestCommentedCodeRule_KeepMarker(t *testing.T) {
+	content := `
+// KEEP: needed for the rollback drill
+// legacyInit();
+// return legacy;
+
+// KEEP: feature flag fallback
+/*
+function fallback() {
+	return true;
+}
+*/
+
+// var dead = 1;
+`
+	rule := &CommentedCodeRule{}
+	result := rule.Apply(content)
+	if result == nil {
+		t.Fatal("expected finding, got nil")
+	}
+
+	finding := result.(CommentedCodeFinding)
+	if len(finding.Issues) != 1 || finding.Issues[0].Line != 13 {
+		t.Errorf("expected 1 issue on line 13, got %+v", finding.Issues)
+	}
+	if len(finding.Kept) != 2 {
+		t.Fatalf("expected 2 kept blocks, got %+v", finding.Kept)
+	}
+	if finding.Kept[0].Reason != "feature flag fallback" || finding.Kept[1].Reason != "needed for the rollback drill" {
+		t.Errorf("unexpected kept reasons: %+v", finding.Kept)
+	}
+	if finding.Kept[1].Line != 3 {
+		t.Errorf("expected kept // block to start on line 3, got %d", finding.Kept[1].Line)
+	}
+}
+
+func TestCommentedCodeRule_ApplyReaderLongLines(t *testing.T) {
+	// A minified bundle line far beyond the retained line length
+	minified := "var a=1;" + strings.Repeat("b=a+1;", 50000)
+	content := minified + "\n// var dead = " + strings.Repeat("x", 100000) + ";  \n/* return legacy; */\n"
+
+	finding, err := (&CommentedCodeRule{}).ApplyReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ApplyReader failed: %v", err)
+	}
+	if finding == nil {
+		t.Fatal("expected finding, got nil")
+	}
+
+	result := finding.(CommentedCodeFinding)
+	if result.TotalBytes != len(content) || result.TotalLines != 4 {
+		t.Errorf("expected %d bytes in 4 lines, got %d bytes in %d lines", len(content), result.TotalBytes, result.TotalLines)
+	}
+	if len(result.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %+v", result.Issues)
+	}
+
+	// The truncated // line is still measured exactly: content plus the "//" allowance
+	lineBytes := len(" var dead = ") + 100000 + len(";") + 2
+	if result.Issues[1].Line != 2 || result.Issues[1].Metadata.Bytes != lineBytes {
+		t.Errorf("expected // block on line 2 with %d bytes, got %+v %+v", lineBytes, result.Issues[1], result.Issues[1].Metadata)
+	}
+}
+
+func TestScanFunctions(t *testing.T) {
+	content := `
+function plain(a) {
+	if (a && b) { return 1; }
+	return a ? 2 : 3;
+}
+const arrow = (x) => {
+	for (const y of x) {
+		if (y || z) continue;
+	}
+	return x.map(v => v ?? 0);
+};
+class Widget {
+	render(props): JSX.Element {
+		switch (props.kind) {
+			case 'a': return "it's";
+			case 'b': return /}/.test(props.name) ? 1 : 0;
+		}
+	}
+}
+$('#x').on('click', function () {
+	// if (commented) { }
+	try { run(); } catch (e) { log(e); }
+});
+`
+	functions, err := scanFunctions(newLexer(strings.NewReader(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []jsFunction{
+		{ID: 0, Parent: -1, Name: "plain", Line: 2, EndLine: 5, Complexity: 4, Depth: 1, DepthLine: 3, DepthIn: 0},
+		{ID: 1, Parent: -1, Name: "arrow", Line: 6, EndLine: 11, Complexity: 5, Depth: 1, DepthLine: 7, DepthIn: 1},
+		{ID: 2, Parent: -1, Name: "render", Line: 13, EndLine: 18, Complexity: 4, Depth: 1, DepthLine: 14, DepthIn: 2},
+		{ID: 3, Parent: -1, Name: "<anonymous>", Line: 20, EndLine: 23, Complexity: 2, Depth: 1, DepthLine: 22, DepthIn: 3},
+	}
+	if len(functions) != len(want) {
+		t.Fatalf("expected %d functions, got %+v", len(want), functions)
+	}
+	for i := range want {
+		if functions[i] != want[i] {
+			t.Errorf("function %d: expected %+v, got %+v", i, want[i], functions[i])
+		}
+	}
+}
+
+func TestComplexityRule_Apply(t *testing.T) {
+	content := "function simple() { return 1; }\n" +
+		"function busy(a) {\n" + strings.Repeat("\tif (a) a++;\n", 4) + "}\n" +
+		"function worse(a) {\n" + strings.Repeat("\tif (a) a++;\n", 6) + "}\n"
+
+	rule := &ComplexityRule{Max: 3}
+	if rule.Apply("function f() { if (a) {} }") != nil {
+		t.Error("expected nil for simple functions")
+	}
+
+	finding := rule.Apply(content).(ComplexityFinding)
+	if len(finding.Functions) != 2 || finding.Functions[0].Name != "worse" {
+		t.Fatalf("expected worse and busy, most complex first, got %+v", finding.Functions)
+	}
+	if len(finding.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %+v", finding.Issues)
+	}
+	busy, worse := finding.Issues[0], finding.Issues[1]
+	if busy.Line != 2 || busy.Severity != "minor" || busy.RuleID != RuleComplexity {
+		t.Errorf("unexpected issue for busy: %+v", busy)
+	}
+	if worse.Severity != "major" || worse.Metadata.LineSpan != 8 {
+		t.Errorf("unexpected issue for worse: %+v", worse)
+	}
+	if finding.TotalLines != 16 || finding.TotalBytes != len(content) {
+		t.Errorf("unexpected size %d lines, %d bytes", finding.TotalLines, finding.TotalBytes)
+	}
+}
+
+func TestComplexityRule_Nesting(t *testing.T) {
+	content := `$(function () {
+	$('#save').on('click', function () {
+		if (valid) {
+			$.ajax({ success: function (data) {
+				for (const row of data) {
+					if (row.ok) { render(row); }
+				}
+			} });
+		}
+	});
+	if (a) { if (b) { } }
+});
+function flat() {
+	if (a) { } else { }
+}
+`
+	rule := &ComplexityRule{MaxNesting: 3}
+	finding := rule.Apply(content).(ComplexityFinding)
+
+	// The click handler holds the deepest point; the ready callback around it
+	// is not reported again
+	if len(finding.DeepFunctions) != 1 {
+		t.Fatalf("expected one deep function, got %+v", finding.DeepFunctions)
+	}
+	deep := finding.DeepFunctions[0]
+	if deep.Line != 2 || deep.Depth != 4 || deep.DepthLine != 6 {
+		t.Errorf("unexpected deep function %+v", deep)
+	}
+	if len(finding.Issues) != 1 || finding.Issues[0].RuleID != RuleNesting || finding.Issues[0].Line != 2 {
+		t.Errorf("unexpected issues %+v", finding.Issues)
+	}
+
+	if rule := (&ComplexityRule{MaxNesting: 5}); rule.Apply(content) != nil {
+		t.Error("expected nil below the nesting limit")
+	}
+}
@@ -0,0 +1,262 @@
+package js
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// tokenKind is the coarse class of a token
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota // Identifiers and keywords
+	tokenPunct                  // Operators and brackets
+	tokenValue                  // Numbers, strings, template literals and regexes
+)
+
+// token is a lexed JS token; values keep no text
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// punctuators are the multi-byte operators the function scanner cares
+// about, longest first; any other byte is a one-byte punctuator
+var punctuators = []string{"??=", "||=", "&&=", "...", "=>", "&&", "||", "??", "?."}
+
+// regexAfter are the keywords after which a slash starts a regex, not a
+// division
+var regexAfter = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true,
+	"in": true, "of": true, "new": true, "delete": true, "void": true,
+	"throw": true, "instanceof": true, "yield": true, "await": true,
+}
+
+// lexer reads JS/TS tokens from a stream, skipping comments and whitespace.
+// Strings and regexes end at the line end when unterminated, so a stray
+// quote (e.g. in JSX text) cannot swallow the rest of the file.
+type lexer struct {
+	r    *bufio.Reader
+	line int
+	prev token
+	err  error
+}
+
+func newLexer(r io.Reader) *lexer {
+	return &lexer{r: bufio.NewReaderSize(r, 64*1024), line: 1, prev: token{kind: tokenPunct}}
+}
+
+// next returns the next token; ok is false at the end of the input or on a
+// read error (see err)
+func (l *lexer) next() (tok token, ok bool) {
+	for {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			if err != io.EOF {
+				l.err = err
+			}
+			return token{}, false
+		}
+
+		switch {
+		case b == '\n':
+			l.line++
+		case b == ' ' || b == '\t' || b == '\r' || b == '\v' || b == '\f':
+		case b == '/' && l.peekIs('/'):
+			l.skipLine()
+		case b == '/' && l.peekIs('*'):
+			l.skipBlockComment()
+		case b == '\'' || b == '"':
+			tok = l.emit(tokenValue, "", l.line)
+			l.skipString(b)
+			return tok, true
+		case b == '`':
+			tok = l.emit(tokenValue, "", l.line)
+			l.skipTemplate()
+			return tok, true
+		case b == '/' && l.regexAllowed():
+			tok = l.emit(tokenValue, "", l.line)
+			l.skipRegex()
+			return tok, true
+		case isIdentByte(b):
+			return l.emit(l.identOrNumber(b)), true
+		default:
+			return l.emit(tokenPunct, l.punctuator(b), l.line), true
+		}
+	}
+}
+
+func (l *lexer) emit(kind tokenKind, text string, line int) token {
+	l.prev = token{kind: kind, text: text, line: line}
+	return l.prev
+}
+
+func (l *lexer) peekIs(b byte) bool {
+	next, err := l.r.Peek(1)
+	return err == nil && next[0] == b
+}
+
+// regexAllowed reports whether a slash after the previous token starts a
+// regex: after operators, opening brackets and most keywords
+func (l *lexer) regexAllowed() bool {
+	switch l.prev.kind {
+	case tokenValue:
+		return false
+	case tokenIdent:
+		return regexAfter[l.prev.text]
+	}
+	return l.prev.text != ")" && l.prev.text != "]" && l.prev.text != "}"
+}
+
+func (l *lexer) skipLine() {
+	for {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			return
+		}
+		if b == '\n' {
+			l.line++
+			return
+		}
+	}
+}
+
+func (l *lexer) skipBlockComment() {
+	_, _ = l.r.ReadByte() // the '*' of the opening /*
+	var prev byte
+	for {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			return
+		}
+		if b == '\n' {
+			l.line++
+		}
+		if prev == '*' && b == '/' {
+			return
+		}
+		prev = b
+	}
+}
+
+// skipString skips a quoted string up to its closing quote or the line end
+func (l *lexer) skipString(quote byte) {
+	for {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			return
+		}
+		switch b {
+		case '\\':
+			if next, err := l.r.ReadByte(); err == nil && next == '\n' {
+				l.line++
+			}
+		case '\n':
+			l.line++
+			return
+		case quote:
+			return
+		}
+	}
+}
+
+// skipTemplate skips a template literal, including the braces of its ${}
+// placeholders; code in placeholders is not scanned
+func (l *lexer) skipTemplate() {
+	depth := 0
+	for {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			return
+		}
+		switch {
+		case b == '\\':
+			if next, err := l.r.ReadByte(); err == nil && next == '\n' {
+				l.line++
+			}
+		case b == '\n':
+			l.line++
+		case b == '$' && depth == 0 && l.peekIs('{'):
+			_, _ = l.r.ReadByte()
+			depth = 1
+		case b == '{' && depth > 0:
+			depth++
+		case b == '}' && depth > 0:
+			depth--
+		case b == '`' && depth == 0:
+			return
+		}
+	}
+}
+
+// skipRegex skips a regex literal and its flags
+func (l *lexer) skipRegex() {
+	inClass := false
+	for {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			return
+		}
+		switch {
+		case b == '\\':
+			_, _ = l.r.ReadByte()
+		case b == '\n':
+			l.line++
+			return
+		case b == '[':
+			inClass = true
+		case b == ']':
+			inClass = false
+		case b == '/' && !inClass:
+			for {
+				next, err := l.r.Peek(1)
+				if err != nil || !isIdentByte(next[0]) {
+					return
+				}
+				_, _ = l.r.ReadByte()
+			}
+		}
+	}
+}
+
+// identOrNumber reads the rest of an identifier, keyword or number
+func (l *lexer) identOrNumber(first byte) (tokenKind, string, int) {
+	var sb strings.Builder
+	sb.WriteByte(first)
+	for {
+		next, err := l.r.Peek(1)
+		if err != nil || !isIdentByte(next[0]) {
+			break
+		}
+		_, _ = l.r.ReadByte()
+		sb.WriteByte(next[0])
+	}
+	if first >= '0' && first <= '9' {
+		return tokenValue, "", l.line
+	}
+	return tokenIdent, sb.String(), l.line
+}
+
+// punctuator reads the longest known operator starting with first
+func (l *lexer) punctuator(first byte) string {
+	for _, p := range punctuators {
+		if p[0] != first {
+			continue
+		}
+		rest, err := l.r.Peek(len(p) - 1)
+		if err == nil && string(rest) == p[1:] {
+			_, _ = l.r.Discard(len(p) - 1)
+			return p
+		}
+	}
+	return string(first)
+}
+
+// isIdentByte reports whether b can be part of an identifier or number;
+// non-ASCII bytes are treated as identifier bytes
+func isIdentByte(b byte) bool {
+	return b == '_' || b == '$' || b >= 0x80 ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
@@ -0,0 +1,370 @@
+package js
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// Text retained while scanning is capped so memory stays bounded on generated
+// files with huge comments or minified single-line bundles. Counts are always
+// exact; only the text fed to isCode and KEEP markers is truncated.
+const (
+	maxRetainedComment = 1 << 20
+	maxRetainedLine    = 64 * 1024
+)
+
+// commentScanner finds /* */ blocks and runs of // lines in a single pass over
+// the input. The two detectors are independent, exactly like the regex and
+// line passes they replace.
+type commentScanner struct {
+	commentedBytes int
+	commentedLines int
+	largestBlock   int
+	blockIssues    []models.Issue
+	lineIssues     []models.Issue
+	blockKept      []models.KeptBlock
+	lineKept       []models.KeptBlock
+
+	totalBytes int
+	newlines   int
+
+	// Current line
+	line          int
+	lineBuf       []byte
+	lineLen       int
+	lineTruncated bool
+	trailingSpace int
+	lastNonBlank  string
+
+	// /* */ block state
+	inBlock        bool
+	prev           byte
+	blockLine      int
+	blockBytes     int
+	blockNewlines  int
+	blockText      []byte
+	blockTruncated bool
+	blockPreceding string
+
+	// Run of consecutive // lines
+	inRun       bool
+	runStart    int
+	runFirst    string
+	runFirstLen int
+	runText     strings.Builder
+	runLen      int
+	runCount    int
+}
+
+func newCommentScanner() *commentScanner {
+	return &commentScanner{line: 1}
+}
+
+// scan feeds everything read from r through the scanner
+func (s *commentScanner) scan(r io.Reader) error {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := r.Read(buf)
+		for _, b := range buf[:n] {
+			s.feed(b)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// The text after the last newline is a line too, even when empty
+	s.endLine()
+	s.flushRun()
+	return nil
+}
+
+func (s *commentScanner) feed(b byte) {
+	s.totalBytes++
+
+	// /* */ detection: the first */ after an opening /* closes the block
+	if s.inBlock {
+		s.blockBytes++
+		if b == '\n' {
+			s.blockNewlines++
+		}
+		if s.prev == '*' && b == '/' {
+			s.closeBlock()
+		} else {
+			if len(s.blockText) < maxRetainedComment {
+				s.blockText = append(s.blockText, b)
+			} else {
+				s.blockTruncated = true
+			}
+			s.prev = b
+		}
+	} else if s.prev == '/' && b == '*' {
+		s.openBlock()
+	} else {
+		s.prev = b
+	}
+
+	// Line tracking for // runs and KEEP markers
+	if b == '\n' {
+		s.newlines++
+		s.endLine()
+		return
+	}
+	s.lineLen++
+	if len(s.lineBuf) < maxRetainedLine {
+		s.lineBuf = append(s.lineBuf, b)
+	} else {
+		s.lineTruncated = true
+	}
+	if b == ' ' || b == '\t' || b == '\r' || b == '\v' || b == '\f' {
+		s.trailingSpace++
+	} else {
+		s.trailingSpace = 0
+	}
+}
+
+func (s *commentScanner) openBlock() {
+	// The text before the opening /* on this line, else the last non-blank line
+	prefix := s.lineBuf
+	if !s.lineTruncated && len(prefix) > 0 {
+		prefix = prefix[:len(prefix)-1]
+	}
+	s.blockPreceding = strings.TrimSpace(string(prefix))
+	if s.blockPreceding == "" {
+		s.blockPreceding = s.lastNonBlank
+	}
+
+	s.inBlock = true
+	s.blockLine = s.line
+	s.blockBytes = 2
+	s.blockNewlines = 0
+	s.blockText = s.blockText[:0]
+	s.blockTruncated = false
+	s.prev = 0 // "/*/" does not close the block
+}
+
+func (s *commentScanner) closeBlock() {
+	s.inBlock = false
+	s.prev = 0 // "*/*" does not open a new block
+
+	text := s.blockText
+	if !s.blockTruncated && len(text) > 0 {
+		text = text[:len(text)-1] // the '*' of the closing */
+	}
+	if !isCode(string(text)) {
+		return
+	}
+
+	matchLen := s.blockBytes
+	matchLines := s.blockNewlines + 1
+
+	// A `// KEEP: reason` line right before the block keeps it out of the metrics
+	if reason, ok := analyzers.KeepReason(s.blockPreceding); ok {
+		s.blockKept = append(s.blockKept, models.KeptBlock{
+			Line:        s.blockLine,
+			Bytes:       matchLen,
+			Description: "Commented out JS code block",
+			Reason:      reason,
+		})
+		return
+	}
+
+	s.commentedBytes += matchLen
+	s.commentedLines += matchLines
+	if matchLen > s.largestBlock {
+		s.largestBlock = matchLen
+	}
+
+	s.blockIssues = append(s.blockIssues, models.Issue{
+		Description: fmt.Sprintf("Commented out JS code block (%d bytes)", matchLen),
+		RuleID:      RuleCommentedCode,
+		Category:    models.CategoryDeadCode,
+		Line:        s.blockLine,
+		Severity:    "minor",
+		Metadata: &models.IssueMetadata{
+			Bytes:         matchLen,
+			LineSpan:      matchLines,
+			EffortMinutes: analyzers.RemovalEffort(matchLines),
+		},
+	})
+}
+
+func (s *commentScanner) endLine() {
+	var trimmed string
+	var contentLen int
+	if !s.lineTruncated {
+		trimmed = strings.TrimSpace(string(s.lineBuf))
+		contentLen = len(trimmed) - 2
+	} else {
+		// Only the start of the line is retained; derive the length from the counts
+		trimmed = strings.TrimLeftFunc(string(s.lineBuf), unicode.IsSpace)
+		leading := len(s.lineBuf) - len(trimmed)
+		contentLen = s.lineLen - leading - s.trailingSpace - 2
+	}
+
+	if strings.HasPrefix(trimmed, "//") {
+		s.addRunLine(strings.TrimPrefix(trimmed, "//"), contentLen)
+	} else {
+		s.flushRun()
+	}
+
+	if trimmed != "" {
+		s.lastNonBlank = trimmed
+	}
+
+	s.line++
+	s.lineBuf = s.lineBuf[:0]
+	s.lineLen = 0
+	s.lineTruncated = false
+	s.trailingSpace = 0
+}
+
+func (s *commentScanner) addRunLine(content string, contentLen int) {
+	if !s.inRun {
+		s.inRun = true
+		s.runStart = s.line
+		s.runFirst = content
+		s.runFirstLen = contentLen
+		s.runText.Reset()
+		s.runLen = 0
+		s.runCount = 0
+	}
+
+	if s.runCount > 0 {
+		s.runLen++ // joining newline
+		if s.runText.Len() < maxRetainedComment {
+			s.runText.WriteByte('\n')
+		}
+	}
+	if s.runText.Len() < maxRetainedComment {
+		s.runText.WriteString(content)
+	}
+	s.runLen += contentLen
+	s.runCount++
+}
+
+// flushRun analyzes the current run of consecutive // lines
+func (s *commentScanner) flushRun() {
+	if !s.inRun {
+		return
+	}
+	s.inRun = false
+
+	// A leading `// KEEP: reason` line marks the rest of the run as intentionally kept
+	reason, keep := analyzers.KeepReason("//" + s.runFirst)
+	startLine := s.runStart
+	text := s.runText.String()
+	linesInBlock := s.runCount
+	joinedLen := s.runLen
+	if keep {
+		startLine++
+		linesInBlock--
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = text[i+1:]
+		} else {
+			text = ""
+		}
+		joinedLen -= s.runFirstLen
+		if linesInBlock > 0 {
+			joinedLen-- // the newline after the marker
+		}
+	}
+	if linesInBlock == 0 {
+		return
+	}
+	if !isCode(text) {
+		return
+	}
+
+	// Approx bytes
+	blockOriginalBytes := joinedLen + (linesInBlock * 2)
+
+	if keep {
+		s.lineKept = append(s.lineKept, models.KeptBlock{
+			Line:        startLine,
+			Bytes:       blockOriginalBytes,
+			Description: "Commented out JS code block",
+			Reason:      reason,
+		})
+		return
+	}
+
+	s.commentedBytes += blockOriginalBytes
+	s.commentedLines += linesInBlock
+	if blockOriginalBytes > s.largestBlock {
+		s.largestBlock = blockOriginalBytes
+	}
+
+	s.lineIssues = append(s.lineIssues, models.Issue{
+		Description: fmt.Sprintf("Commented out JS code block (%d bytes)", blockOriginalBytes),
+		RuleID:      RuleCommentedCode,
+		Category:    models.CategoryDeadCode,
+		Line:        startLine,
+		Severity:    "minor",
+		Metadata: &models.IssueMetadata{
+			Bytes:         blockOriginalBytes,
+			LineSpan:      linesInBlock,
+			EffortMinutes: analyzers.RemovalEffort(linesInBlock),
+		},
+	})
+}
+
+// finding returns the result in the order of the former two passes: /* */
+// blocks first, then // runs. It is nil when nothing was found.
+func (s *commentScanner) finding() interface{} {
+	kept := append(append([]models.KeptBlock{}, s.blockKept...), s.lineKept...)
+	if s.commentedBytes == 0 && len(kept) == 0 {
+		return nil
+	}
+
+	return CommentedCodeFinding{
+		CommentedBytes: s.commentedBytes,
+		CommentedLines: s.commentedLines,
+		LargestBlock:   s.largestBlock,
+		TotalBytes:     s.totalBytes,
+		TotalLines:     s.newlines + 1,
+		Issues:         append(s.blockIssues, s.lineIssues...),
+		Kept:           kept,
+	}
+}
+
+// Scanner is the commented code scanner fed piece by piece, for text that is
+// not read from a file of its own, such as the inline scripts of HTML files
+type Scanner struct {
+	s *commentScanner
+}
+
+// NewScanner creates a Scanner at the start of its input
+func NewScanner() *Scanner {
+	return &Scanner{s: newCommentScanner()}
+}
+
+// Write feeds p through the scanner; it never fails
+func (s *Scanner) Write(p []byte) (int, error) {
+	for _, b := range p {
+		s.s.feed(b)
+	}
+	return len(p), nil
+}
+
+// WriteByte feeds b through the scanner; it never fails
+func (s *Scanner) WriteByte(b byte) error {
+	s.s.feed(b)
+	return nil
+}
+
+// Finding ends the input and returns the CommentedCodeFinding, or nil when
+// nothing was found. The Scanner must not be used afterwards.
+func (s *Scanner) Finding() interface{} {
+	s.s.endLine()
+	s.s.flushRun()
+	return s.s.finding()
+}
@@ -0,0 +1,49 @@
+package analyzers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code-analyzer/models"
+)
+
+// keepMarkerRegex matches a comment line such as `// KEEP: needed for rollback`,
+// including Twig/Jinja `{# #}` and Handlebars `{{!-- --}}` comments
+var keepMarkerRegex = regexp.MustCompile(`^\s*(?://|#|<!--|\{#|\{\{!(?:--)?|/\*+|\*)\s*KEEP:\s*(.*?)\s*(?:-->|#\}|(?:--)?\}\}|\*/)?\s*$`)
+
+// KeepReason returns the explanation of a `KEEP:` marker comment line.
+// Commented blocks directly preceded by a marker are intentionally kept and
+// excluded from dead-code metrics.
+func KeepReason(line string) (string, bool) {
+	m := keepMarkerRegex.FindStringSubmatch(line)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// PrecedingLine returns the last non-blank line before offset in content,
+// including any text on the same line before offset
+func PrecedingLine(content string, offset int) string {
+	lineStart := strings.LastIndex(content[:offset], "\n") + 1
+	if sameLine := strings.TrimSpace(content[lineStart:offset]); sameLine != "" {
+		return sameLine
+	}
+
+	lines := strings.Split(content[:lineStart], "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// PrintKept prints a one-line summary of intentionally kept blocks
+func PrintKept(kept []models.KeptBlock) {
+	if len(kept) == 0 {
+		return
+	}
+	fmt.Printf("📌 %d commented blocks intentionally kept (KEEP: marker), listed in the artifact\n\n", len(kept))
+}
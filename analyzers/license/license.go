@@ -0,0 +1,349 @@
+package license
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// RuleHeader is the rule ID of missing and outdated license headers
+const RuleHeader = "license/header"
+
+// headerReadLimit is how much of each file is read to find the header
+const headerReadLimit = 8 * 1024
+
+// LicenseAnalyzer verifies that source files start with the configured license header
+type LicenseAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewLicenseAnalyzer creates a new license header analyzer
+func NewLicenseAnalyzer() *LicenseAnalyzer {
+	return &LicenseAnalyzer{
+		rules: []analyzers.Rule{
+			&HeaderRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *LicenseAnalyzer) Name() string {
+	return "License Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *LicenseAnalyzer) Description() string {
+	return "Verifies source files start with the required license/copyright header"
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *LicenseAnalyzer) RuleIDs() []string {
+	return []string{RuleHeader}
+}
+
+// Run executes the license header analysis
+func (a *LicenseAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	results := []models.LicenseFileAnalysis{}
+	var allIssues []models.Issue
+
+	if len(config.Headers) == 0 {
+		return nil, fmt.Errorf("no header templates configured (set `headers` per extension)")
+	}
+
+	// One rule per extension, longest extension first so ".blade.php" wins over ".php"
+	rules := make(map[string]*HeaderRule)
+	var extensions []string
+	for ext, template := range config.Headers {
+		rules[strings.ToLower(ext)] = NewHeaderRule(template, config.RequireCurrentYear)
+		extensions = append(extensions, strings.ToLower(ext))
+	}
+	sort.Slice(extensions, func(i, j int) bool {
+		return len(extensions[i]) > len(extensions[j])
+	})
+
+	checked := 0
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		var rule *HeaderRule
+		lower := strings.ToLower(path)
+		for _, ext := range extensions {
+			if strings.HasSuffix(lower, ext) {
+				rule = rules[ext]
+				break
+			}
+		}
+		if rule == nil {
+			return nil
+		}
+
+		config.Stats.Analyzed(min(info.Size(), headerReadLimit))
+		checked++
+		analysis := a.analyzeFile(path, rule, config.Diagnostics)
+		if analysis != nil {
+			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
+			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return allIssues, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+
+	// Limit to top N
+	if len(results) > config.TopN {
+		results = results[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, config, checked); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	a.printResults(results, checked)
+	return allIssues, nil
+}
+
+func (a *LicenseAnalyzer) analyzeFile(path string, rule *HeaderRule, diags *analyzers.Diagnostics) *models.LicenseFileAnalysis {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	head, err := io.ReadAll(io.LimitReader(file, headerReadLimit))
+	if err != nil {
+		return nil
+	}
+
+	// The read limit may split a multi-byte character, which must not make the
+	// head look like Latin-1
+	if len(head) == headerReadLimit {
+		for i := 1; i <= utf8.UTFMax && i <= len(head); i++ {
+			if utf8.RuneStart(head[len(head)-i]) {
+				if !utf8.FullRune(head[len(head)-i:]) {
+					head = head[:len(head)-i]
+				}
+				break
+			}
+		}
+	}
+	text, encoding := utils.DecodeText(head)
+	if encoding == utils.EncodingBinary {
+		return nil
+	}
+
+	finding := analyzers.ApplyRule(rule, path, text, diags)
+	if finding == nil {
+		return nil
+	}
+
+	result := finding.(HeaderFinding)
+	severity := "major"
+	if result.Status == StatusOutdated {
+		severity = "minor"
+	}
+
+	return &models.LicenseFileAnalysis{
+		Path:   path,
+		Status: result.Status,
+		Detail: result.Detail,
+		Issues: []models.Issue{{
+			Path:        path,
+			Description: result.Detail,
+			RuleID:      RuleHeader,
+			Category:    models.CategoryCompliance,
+			Line:        1,
+			Severity:    severity,
+			Metadata:    &models.IssueMetadata{EffortMinutes: 1},
+		}},
+	}
+}
+
+func (a *LicenseAnalyzer) printResults(results []models.LicenseFileAnalysis, checked int) {
+	if len(results) == 0 {
+		fmt.Printf("✅ All %d checked files carry the required license header!\n", checked)
+		return
+	}
+
+	missing, outdated := countStatuses(results)
+	fmt.Printf("Found %d of %d files with header problems\n", len(results), checked)
+	fmt.Printf("📊 Missing: %d | Outdated: %d\n\n", missing, outdated)
+
+	fmt.Printf("%-5s %-70s %10s\n", "Rank", "File", "Status")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for i, result := range results {
+		fmt.Printf("%-5d %-70s %10s\n", i+1, utils.Truncate(result.Path, 70), result.Status)
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
+
+func (a *LicenseAnalyzer) generateArtifact(results []models.LicenseFileAnalysis, config analyzers.Config, checked int) error {
+	missing, outdated := countStatuses(results)
+
+	report := models.LicenseAnalysisReport{
+		Timestamp:     utils.GetTimestamp(),
+		ScanDirectory: config.RootDir,
+		CheckedFiles:  checked,
+		TotalFiles:    len(results),
+		Missing:       missing,
+		Outdated:      outdated,
+		Results:       results,
+		Stats:         config.Stats.Summary(),
+	}
+
+	return config.WriteArtifact(report)
+}
+
+func countStatuses(results []models.LicenseFileAnalysis) (missing, outdated int) {
+	for _, r := range results {
+		if r.Status == StatusMissing {
+			missing++
+		} else {
+			outdated++
+		}
+	}
+	return
+}
+
+// Header statuses
+const (
+	StatusMissing  = "missing"
+	StatusOutdated = "outdated"
+)
+
+// yearPattern matches a year or a year range such as 2019-2024
+const yearPattern = `(\d{4})(?:\s*[-–]\s*(\d{4}))?`
+
+// HeaderRule checks that content starts with a header template.
+// `{year}` in the template matches a year or year range.
+type HeaderRule struct {
+	Template           string
+	RequireCurrentYear bool
+	CurrentYear        int
+
+	pattern *regexp.Regexp
+}
+
+// HeaderFinding describes a missing or outdated header
+type HeaderFinding struct {
+	Status string
+	Detail string
+}
+
+// NewHeaderRule compiles a header template; whitespace differences are ignored
+func NewHeaderRule(template string, requireCurrentYear bool) *HeaderRule {
+	var parts []string
+	for _, token := range strings.Fields(template) {
+		quoted := regexp.QuoteMeta(token)
+		parts = append(parts, strings.ReplaceAll(quoted, regexp.QuoteMeta("{year}"), yearPattern))
+	}
+
+	return &HeaderRule{
+		Template:           template,
+		RequireCurrentYear: requireCurrentYear,
+		CurrentYear:        time.Now().Year(),
+		pattern:            regexp.MustCompile(`^\s*` + strings.Join(parts, `\s+`)),
+	}
+}
+
+var copyrightRegex = regexp.MustCompile(`(?i)copyright|\(c\)|©|license`)
+
+func (r *HeaderRule) Name() string {
+	return "License Header Detector"
+}
+
+func (r *HeaderRule) Apply(content string) interface{} {
+	body := skipPrelude(content)
+
+	m := r.pattern.FindStringSubmatch(body)
+	if m != nil {
+		if !r.RequireCurrentYear || len(m) < 3 || m[1] == "" {
+			return nil
+		}
+		year := m[1]
+		if m[2] != "" {
+			year = m[2]
+		}
+		if y, _ := strconv.Atoi(year); y < r.CurrentYear {
+			return HeaderFinding{
+				Status: StatusOutdated,
+				Detail: fmt.Sprintf("License header year %s is not current (%d)", year, r.CurrentYear),
+			}
+		}
+		return nil
+	}
+
+	// A copyright-looking comment that does not match the template is outdated
+	firstLines := strings.SplitN(body, "\n", 6)
+	if len(firstLines) > 5 {
+		firstLines = firstLines[:5]
+	}
+	if copyrightRegex.MatchString(strings.Join(firstLines, "\n")) {
+		return HeaderFinding{
+			Status: StatusOutdated,
+			Detail: "License header does not match the required template",
+		}
+	}
+
+	return HeaderFinding{
+		Status: StatusMissing,
+		Detail: "Missing license header",
+	}
+}
+
+// skipPrelude drops leading lines that must precede a header (shebang, <?php)
+func skipPrelude(content string) string {
+	for {
+		trimmed := strings.TrimLeft(content, " \t\r\n")
+		if !strings.HasPrefix(trimmed, "#!") && !strings.HasPrefix(trimmed, "<?php") && !strings.HasPrefix(trimmed, "<?=") {
+			return trimmed
+		}
+		idx := strings.Index(trimmed, "\n")
+		if idx == -1 {
+			return ""
+		}
+		content = trimmed[idx+1:]
+	}
+}
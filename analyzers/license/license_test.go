@@ -0,0 +1,90 @@
+package license
+
+import (
+	"testing"
+)
+
+func TestHeaderRule_Apply(t *testing.T) {
+	template := `/*
+ * Copyright (c) {year} Acme Corp.
+ * Licensed under the Acme License.
+ */`
+
+	tests := []struct {
+		name     string
+		content  string
+		current  bool
+		expected string // "" means header is fine
+	}{
+		{
+			name: "Valid header",
+			content: `/*
+ * Copyright (c) 2026 Acme Corp.
+ * Licensed under the Acme License.
+ */
+const x = 1;`,
+			expected: "",
+		},
+		{
+			name: "Valid header after php tag with different spacing",
+			content: `<?php
+/*
+  * Copyright (c)   2019-2026 Acme Corp.
+ * Licensed under the Acme License.
+ */
+echo 1;`,
+			expected: "",
+		},
+		{
+			name:     "Missing header",
+			content:  "const x = 1;\n",
+			expected: StatusMissing,
+		},
+		{
+			name: "Old company header",
+			content: `/*
+ * Copyright (c) 2015 Initech
+ */
+const x = 1;`,
+			expected: StatusOutdated,
+		},
+		{
+			name: "Stale year",
+			content: `/*
+ * Copyright (c) 2019 Acme Corp.
+ * Licensed under the Acme License.
+ */`,
+			current:  true,
+			expected: StatusOutdated,
+		},
+		{
+			name: "Current year range",
+			content: `/*
+ * Copyright (c) 2019-2026 Acme Corp.
+ * Licensed under the Acme License.
+ */`,
+			current:  true,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := NewHeaderRule(template, tt.current)
+			rule.CurrentYear = 2026
+
+			result := rule.Apply(tt.content)
+			if result == nil {
+				if tt.expected != "" {
+					t.Errorf("expected status %q, got nil", tt.expected)
+				}
+				return
+			}
+
+			finding := result.(HeaderFinding)
+			if finding.Status != tt.expected {
+				t.Errorf("expected status %q, got %q (%s)", tt.expected, finding.Status, finding.Detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package analyzers
+
+import (
+	"fmt"
+	"os"
+
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// DefaultMaxFileSize is the size in bytes above which files are skipped when
+// neither the analyzer nor the global config sets max_file_size
+const DefaultMaxFileSize = 10 * 1024 * 1024
+
+// TooLarge reports whether a file exceeds the max file size for this run
+func (c Config) TooLarge(info os.FileInfo) bool {
+	limit := c.MaxFileSize
+	if limit <= 0 {
+		limit = DefaultMaxFileSize
+	}
+	return info.Size() > limit
+}
+
+// PrintSkipped prints a one-line summary of files skipped for their size
+func PrintSkipped(skipped []models.SkippedFile) {
+	if len(skipped) == 0 {
+		return
+	}
+	var total int64
+	for _, s := range skipped {
+		total += s.Bytes
+	}
+	fmt.Printf("⏭️  %d files skipped as too large (%s), listed in the artifact\n\n", len(skipped), utils.FormatBytes(int(total)))
+}
@@ -0,0 +1,38 @@
+package analyzers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig_TooLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.js")
+	if err := os.WriteFile(path, make([]byte, 2048), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		limit    int64
+		expected bool
+	}{
+		{"Default limit", 0, false},
+		{"Below limit", 4096, false},
+		{"At limit", 2048, false},
+		{"Above limit", 1024, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{MaxFileSize: tt.limit}
+			if got := config.TooLarge(info); got != tt.expected {
+				t.Errorf("TooLarge() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
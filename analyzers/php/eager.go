@@ -0,0 +1,271 @@
+package php
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// RuleNPlusOne is the rule ID of probable N+1 queries: relationships of
+// Eloquent models read in a loop over a query without eager loading
+const RuleNPlusOne = "php/n-plus-one"
+
+var (
+	// assignmentRegex matches the start of an assignment to a variable
+	assignmentRegex = regexp.MustCompile(`(\$\w+)\s*=[^=>]`)
+	// queryRegex matches a static call starting an Eloquent query, such as
+	// Post::where( or \App\Models\Post::with(
+	queryRegex = regexp.MustCompile(`^\\?(?:\w+\\)*([A-Z]\w*)::\w+\s*\(`)
+	// callRegex matches the method calls of a query chain
+	callRegex = regexp.MustCompile(`(?:::|->)\s*(\w+)\s*\(`)
+	// eagerRegex matches the calls that eager load relationships
+	eagerRegex = regexp.MustCompile(`(?:::|->)\s*(?:with|load|loadMissing)\s*\(`)
+	// stringRegex matches a string literal
+	stringRegex = regexp.MustCompile(`'([^'\\]*)'|"([^"\\]*)"`)
+	// foreachRegex matches the start of a foreach loop
+	foreachRegex = regexp.MustCompile(`\bforeach\s*\(`)
+	// loopHeaderRegex splits the header of a foreach loop into the iterated
+	// expression and the value variable
+	loopHeaderRegex = regexp.MustCompile(`(?s)^\s*(.+?)\s+as\s+(?:\$\w+\s*=>\s*)?&?(\$\w+)\s*$`)
+)
+
+// fetchMethods end a query and return a collection of models
+var fetchMethods = map[string]bool{
+	"all": true, "get": true, "paginate": true, "simplePaginate": true,
+	"cursorPaginate": true, "cursor": true, "lazy": true, "lazyById": true,
+}
+
+// facades are the classes whose static get() or all() do not return models
+var facades = map[string]bool{
+	"App": true, "Arr": true, "Auth": true, "Cache": true, "Collection": true,
+	"Config": true, "Cookie": true, "DB": true, "File": true, "Http": true,
+	"Lang": true, "Redis": true, "Request": true, "Session": true,
+	"Storage": true, "Str": true,
+}
+
+// NPlusOneRule detects probable N+1 queries: in a loop over the result of an
+// Eloquent query, a relationship of the loop variable read as a property
+// ($post->author->name) without the query eager loading it with ->with() or
+// ->load(), or queried through its method ($post->comments()->count()), which
+// eager loading cannot help. Loops over variables whose query is not in the
+// same file are skipped, as are *_at attributes, which are dates.
+type NPlusOneRule struct{}
+
+// NPlusOneFinding lists the probable N+1 queries of a file
+type NPlusOneFinding struct {
+	Queries []models.NPlusOneQuery
+	Issues  []models.Issue
+}
+
+// assignment is an assignment to a variable; eager holds the relationships
+// it eager loads when it is a query fetching models, and is nil otherwise
+type assignment struct {
+	variable string
+	offset   int
+	eager    map[string]bool
+}
+
+func (r *NPlusOneRule) Name() string {
+	return "N+1 Query Detector"
+}
+
+func (r *NPlusOneRule) Apply(content string) interface{} {
+	code := blankComments(content)
+
+	var assignments []assignment
+	for _, m := range assignmentRegex.FindAllStringSubmatchIndex(code, -1) {
+		eager, _ := parseQuery(code[m[1]-1 : statementEnd(code, m[1]-1)])
+		assignments = append(assignments, assignment{variable: code[m[2]:m[3]], offset: m[0], eager: eager})
+	}
+
+	var finding NPlusOneFinding
+	for _, m := range foreachRegex.FindAllStringIndex(code, -1) {
+		headerEnd := matchClose(code, m[1]-1)
+		if headerEnd < 0 {
+			continue
+		}
+		header := loopHeaderRegex.FindStringSubmatch(code[m[1] : headerEnd-1])
+		if header == nil {
+			continue
+		}
+		source, item := header[1], header[2]
+
+		// The loop iterates a query, directly or through the variable last
+		// assigned one
+		eager, ok := parseQuery(source)
+		if !ok {
+			a := lastAssignment(assignments, source, m[0])
+			if a == nil || a.eager == nil {
+				continue
+			}
+			eager = make(map[string]bool)
+			for name := range a.eager {
+				eager[name] = true
+			}
+			for name := range loadedLater(code[a.offset:m[0]], source) {
+				eager[name] = true
+			}
+		}
+
+		body := skipSpace(code, headerEnd)
+		if body >= len(code) || code[body] != '{' {
+			continue
+		}
+		bodyEnd := matchClose(code, body)
+		if bodyEnd < 0 {
+			continue
+		}
+		for _, access := range relationAccesses(code[body:bodyEnd], item, eager) {
+			start, end := body+access.start, body+access.end
+			nPlusOne := models.NPlusOneQuery{
+				Access: content[start:end],
+				Loop:   source,
+				Line:   strings.Count(content[:start], "\n") + 1,
+			}
+			finding.Queries = append(finding.Queries, nPlusOne)
+
+			issue := models.Issue{
+				Description: fmt.Sprintf("Probable N+1 query: %s in a loop over %s", nPlusOne.Access, nPlusOne.Loop),
+				RuleID:      RuleNPlusOne,
+				Category:    models.CategoryPerformance,
+				Line:        nPlusOne.Line,
+				Severity:    "major",
+			}
+			analyzers.SetRange(&issue, content, start, end)
+			finding.Issues = append(finding.Issues, issue)
+		}
+	}
+	if len(finding.Issues) == 0 {
+		return nil
+	}
+	sort.SliceStable(finding.Issues, func(i, j int) bool { return finding.Issues[i].Line < finding.Issues[j].Line })
+	sort.SliceStable(finding.Queries, func(i, j int) bool { return finding.Queries[i].Line < finding.Queries[j].Line })
+	return finding
+}
+
+// parseQuery reports whether expr is an Eloquent query fetching models, a
+// static call on a model ending in a fetch method, and returns the
+// relationships it eager loads
+func parseQuery(expr string) (map[string]bool, bool) {
+	expr = strings.TrimSpace(expr)
+	m := queryRegex.FindStringSubmatch(expr)
+	if m == nil || facades[m[1]] {
+		return nil, false
+	}
+	calls := callRegex.FindAllStringSubmatch(expr, -1)
+	if len(calls) == 0 || !fetchMethods[calls[len(calls)-1][1]] {
+		return nil, false
+	}
+	return eagerLoaded(expr), true
+}
+
+// eagerLoaded returns the relationships the with(), load() and loadMissing()
+// calls of code name; for "comments.author" and "author:id,name" that is
+// comments and author
+func eagerLoaded(code string) map[string]bool {
+	eager := make(map[string]bool)
+	for _, m := range eagerRegex.FindAllStringIndex(code, -1) {
+		end := matchClose(code, m[1]-1)
+		if end < 0 {
+			end = len(code)
+		}
+		for _, s := range stringRegex.FindAllStringSubmatch(code[m[1]:end], -1) {
+			name := s[1] + s[2]
+			if i := strings.IndexAny(name, ".:"); i >= 0 {
+				name = name[:i]
+			}
+			eager[strings.TrimSpace(name)] = true
+		}
+	}
+	return eager
+}
+
+// loadedLater returns the relationships variable lazy eager loads in code,
+// with $posts->load('author')
+func loadedLater(code, variable string) map[string]bool {
+	loaded := make(map[string]bool)
+	loads := regexp.MustCompile(regexp.QuoteMeta(variable) + `\s*->\s*(?:load|loadMissing)\s*\(`)
+	for _, m := range loads.FindAllStringIndex(code, -1) {
+		for name := range eagerLoaded(code[m[0]:statementEnd(code, m[1])]) {
+			loaded[name] = true
+		}
+	}
+	return loaded
+}
+
+// lastAssignment returns the last assignment to variable before offset, or
+// nil when there is none
+func lastAssignment(assignments []assignment, variable string, offset int) *assignment {
+	var last *assignment
+	for i := range assignments {
+		if assignments[i].variable == variable && assignments[i].offset < offset {
+			last = &assignments[i]
+		}
+	}
+	return last
+}
+
+// span is a byte range
+type span struct {
+	start, end int
+}
+
+// relationAccesses returns the first access to each relationship of item in
+// body that queries: a property read further ($post->author->name, foreach
+// ($post->comments as ...)) that eager does not load, or a relationship
+// method queried further ($post->comments()->count())
+func relationAccesses(body, item string, eager map[string]bool) []span {
+	accesses := regexp.MustCompile(regexp.QuoteMeta(item) + `->(\w+)(\s*\(\s*\))?\s*->|\bforeach\s*\(\s*` + regexp.QuoteMeta(item) + `->(\w+)\s+as\b`)
+	var spans []span
+	seen := make(map[string]bool)
+	for _, m := range accesses.FindAllStringSubmatchIndex(body, -1) {
+		var name string
+		var s span
+		switch {
+		case m[2] >= 0:
+			name = body[m[2]:m[3]]
+			s = span{m[0], m[3]}
+			if m[4] >= 0 {
+				s.end = m[5]
+				name += "()"
+			} else if eager[name] || strings.HasSuffix(name, "_at") || name == "pivot" {
+				continue
+			}
+		default:
+			name = body[m[6]:m[7]]
+			if eager[name] {
+				continue
+			}
+			s = span{m[6] - len(item) - 2, m[7]}
+		}
+		if !seen[name] {
+			seen[name] = true
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+// statementEnd returns the offset of the semicolon ending the statement that
+// code[i:] is part of, or len(code)
+func statementEnd(code string, i int) int {
+	for ; i < len(code); i++ {
+		switch code[i] {
+		case '\'', '"':
+			i = skipQuoted(code, i) - 1
+		case '(', '[', '{':
+			end := matchClose(code, i)
+			if end < 0 {
+				return len(code)
+			}
+			i = end - 1
+		case ';', ')', ']', '}':
+			return i
+		}
+	}
+	return len(code)
+}
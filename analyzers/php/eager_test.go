@@ -0,0 +1,160 @@
+package php
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code-analyzer/analyzers"
+)
+
+func TestNPlusOneRule_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+		lines    []int
+	}{
+		{
+			name: "Relationship without eager loading",
+			content: `<?php
+$posts = Post::where('published', true)->get();
+foreach ($posts as $post) {
+    echo $post->author->name;
+    echo $post->author->email;
+    echo $post->title;
+    echo $post->created_at->format('Y-m-d');
+}
+`,
+			expected: []string{"Probable N+1 query: $post->author in a loop over $posts"},
+			lines:    []int{4},
+		},
+		{
+			name: "Eager loaded with with() and load()",
+			content: `<?php
+$posts = Post::with(['author:id,name', 'comments.user'])->latest()->paginate(20);
+foreach ($posts as $post) {
+    echo $post->author->name;
+    foreach ($post->comments as $comment) {
+        echo $comment->body;
+    }
+}
+
+$invoices = Invoice::all();
+$invoices->load('customer');
+foreach ($invoices as $invoice) {
+    echo $invoice->customer->name;
+}
+`,
+			expected: nil,
+		},
+		{
+			name: "Relationship queries and loops",
+			content: `<?php
+foreach (\App\Models\Post::with('comments')->get() as $id => $post) {
+    $count = $post->comments()->count();
+    foreach ($post->tags as $tag) {
+        echo $tag->name;
+    }
+}
+`,
+			expected: []string{
+				"Probable N+1 query: $post->comments() in a loop over \\App\\Models\\Post::with('comments')->get()",
+				"Probable N+1 query: $post->tags in a loop over \\App\\Models\\Post::with('comments')->get()",
+			},
+			lines: []int{3, 4},
+		},
+		{
+			name: "Not a query",
+			content: `<?php
+$items = Cache::get('items');
+foreach ($items as $item) {
+    echo $item->product->name;
+}
+foreach ($request->input('rows') as $row) {
+    echo $row->owner->name;
+}
+$users = User::all();
+$users = collect($rows);
+foreach ($users as $user) {
+    echo $user->team->name;
+}
+// foreach (Post::all() as $post) { echo $post->author->name; }
+`,
+			expected: nil,
+		},
+	}
+
+	rule := &NPlusOneRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.Apply(tt.content)
+			if result == nil {
+				if len(tt.expected) > 0 {
+					t.Fatalf("expected %v, got nil", tt.expected)
+				}
+				return
+			}
+
+			finding := result.(NPlusOneFinding)
+			if len(finding.Issues) != len(tt.expected) {
+				t.Fatalf("expected %d issues, got %+v", len(tt.expected), finding.Issues)
+			}
+			for i, issue := range finding.Issues {
+				if issue.Description != tt.expected[i] {
+					t.Errorf("expected %q, got %q", tt.expected[i], issue.Description)
+				}
+				if issue.Line != tt.lines[i] || issue.Severity != "major" {
+					t.Errorf("%s: expected a major issue on line %d, got %s on line %d", tt.expected[i], tt.lines[i], issue.Severity, issue.Line)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeFile_LaravelPaths(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel string) string {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := `<?php
+Route::get('/posts', function () {
+    $posts = Post::all();
+    foreach ($posts as $post) {
+        echo $post->author->name;
+    }
+});
+`
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	rules := func(path string, laravelPaths []string) map[string]bool {
+		got := make(map[string]bool)
+		analysis := NewPHPAnalyzer().analyzeFile(path, analyzers.Config{RootDir: root, LaravelPaths: laravelPaths})
+		if analysis != nil {
+			for _, issue := range analysis.Issues {
+				got[issue.RuleID] = true
+			}
+		}
+		return got
+	}
+
+	routes := write("routes/web.php")
+	vendor := write("vendor/acme/src/web.php")
+	if got := rules(routes, nil); !got[RuleRouteClosure] || !got[RuleNPlusOne] {
+		t.Errorf("expected both Laravel rules in routes/web.php by default, got %v", got)
+	}
+	if got := rules(vendor, nil); got[RuleRouteClosure] || !got[RuleNPlusOne] {
+		t.Errorf("expected only N+1 queries outside routes/ by default, got %v", got)
+	}
+	if got := rules(vendor, []string{"app", "routes/*.php"}); len(got) != 0 {
+		t.Errorf("expected no Laravel rules outside laravel_paths, got %v", got)
+	}
+	if got := rules(routes, []string{"app", "routes/*.php"}); !got[RuleRouteClosure] || !got[RuleNPlusOne] {
+		t.Errorf("expected both Laravel rules in laravel_paths, got %v", got)
+	}
+}
@@ -0,0 +1,210 @@
+package php
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// functionHeadRegex matches the head of a function declaration up to the
+// parenthesis opening its parameters. The guard before it skips ->function,
+// $function and ::function, and closures have no name to match.
+var functionHeadRegex = regexp.MustCompile(`(?:^|[^\w$>:\\])((?:(?:public|protected|private|static|abstract|final)\s+)*function\s+(?:&\s*)?(\w+)\s*\()`)
+
+// functionDecl is one function declaration: its name and its byte range,
+// modifiers and body included
+type functionDecl struct {
+	name       string
+	start, end int
+}
+
+// CommentedFunctionsRule detects commented-out PHP functions: comments,
+// docblocks aside, whose text parses as a function declaration
+type CommentedFunctionsRule struct{}
+
+type CommentedFunctionsFinding struct {
+	AllFunctions  []string
+	CommentedList []string
+	Issues        []models.Issue
+	Kept          []models.KeptBlock
+}
+
+func (r *CommentedFunctionsRule) Name() string {
+	return "Commented Functions Detector"
+}
+
+func (r *CommentedFunctionsRule) Apply(content string) interface{} {
+	type named struct {
+		name   string
+		offset int
+	}
+	var all []named
+	for _, m := range functionHeadRegex.FindAllStringSubmatchIndex(blankComments(content), -1) {
+		all = append(all, named{content[m[4]:m[5]], m[2]})
+	}
+
+	// A function in a /* */ comment is reported as the whole comment, so
+	// removing the issue's lines removes the markers with it
+	type commentedDecl struct {
+		functionDecl
+		from, to int
+	}
+	var commented []commentedDecl
+	for _, g := range groupComments(content) {
+		if g.kind == docComment {
+			continue
+		}
+		// Blanking the markers keeps the text at the offsets of content
+		for _, d := range findFunctionDecls(uncomment(content[g.start:g.end])) {
+			d.start += g.start
+			d.end += g.start
+			c := commentedDecl{d, d.start, d.end}
+			if g.kind == blockComment {
+				c.from, c.to = g.start, g.end
+			}
+			commented = append(commented, c)
+			all = append(all, named{d.name, d.start})
+		}
+	}
+	commented = slices.DeleteFunc(commented, func(d commentedDecl) bool { return isMagicLifecycle(d.name) })
+	if len(commented) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].offset < all[j].offset })
+	var allFunctions []string
+	for _, f := range all {
+		if !isMagicLifecycle(f.name) {
+			allFunctions = append(allFunctions, f.name)
+		}
+	}
+
+	var issues []models.Issue
+	kept := []models.KeptBlock{}
+	var activeCommented []string
+	lines := strings.Split(content, "\n")
+	for _, d := range commented {
+		description := fmt.Sprintf("Commented out PHP function: %s", d.name)
+		line := strings.Count(content[:d.from], "\n") + 1
+		// A `// KEEP: reason` line in the comment block above the function keeps it
+		if reason, ok := keepReasonAbove(lines, line); ok {
+			kept = append(kept, models.KeptBlock{Line: line, Description: description, Reason: reason})
+			continue
+		}
+
+		block := content[d.from:d.to]
+		span := strings.Count(block, "\n") + 1
+		issue := models.Issue{
+			Description: description,
+			RuleID:      RuleCommentedFunction,
+			Category:    models.CategoryDeadCode,
+			Line:        line,
+			Severity:    "major",
+			Metadata: &models.IssueMetadata{
+				Bytes:         len(block),
+				LineSpan:      span,
+				EffortMinutes: analyzers.RemovalEffort(span),
+			},
+		}
+		analyzers.SetRange(&issue, content, d.from, d.to)
+		activeCommented = append(activeCommented, d.name)
+		issues = append(issues, issue)
+	}
+
+	return CommentedFunctionsFinding{
+		AllFunctions:  allFunctions,
+		CommentedList: activeCommented,
+		Issues:        issues,
+		Kept:          kept,
+	}
+}
+
+// isMagicLifecycle reports whether a function is a constructor or destructor,
+// which are not counted
+func isMagicLifecycle(name string) bool {
+	return name == "__construct" || name == "__destruct"
+}
+
+// findFunctionDecls returns the function declarations of code that parse: a
+// head, balanced parameters, an optional return type, then a body with
+// balanced braces or, for abstract and interface methods, a semicolon. Prose
+// such as "call function load() first" does not parse.
+func findFunctionDecls(code string) []functionDecl {
+	var decls []functionDecl
+	for _, m := range functionHeadRegex.FindAllStringSubmatchIndex(code, -1) {
+		params := matchClose(code, m[3]-1)
+		if params < 0 {
+			continue
+		}
+		j := skipSpace(code, params)
+		if j < len(code) && code[j] == ':' {
+			// Return types: ?int, static, A|B, (A&B)|null, \Ns\Type
+			j = skipSpace(code, j+1)
+			for j < len(code) && (isIdentByte(code[j]) || strings.IndexByte("?\\|&() \t", code[j]) >= 0) {
+				j++
+			}
+			j = skipSpace(code, j)
+		}
+		if j >= len(code) {
+			continue
+		}
+		end := -1
+		switch code[j] {
+		case ';':
+			end = j + 1
+		case '{':
+			end = matchClose(code, j)
+		}
+		if end < 0 {
+			continue
+		}
+		decls = append(decls, functionDecl{name: code[m[4]:m[5]], start: m[2], end: end})
+	}
+	return decls
+}
+
+// matchClose returns the offset after the bracket closing the (, [ or { at
+// open, or -1 when it is unbalanced. Strings and comments are skipped, so a
+// brace in a literal or an apostrophe in a trailing comment does not count.
+func matchClose(code string, open int) int {
+	closer := map[byte]byte{'(': ')', '[': ']', '{': '}'}[code[open]]
+	depth := 0
+	for i := open; i < len(code); i++ {
+		switch c := code[i]; {
+		case c == '\'' || c == '"':
+			i = skipQuoted(code, i) - 1
+		case c == '#' && !strings.HasPrefix(code[i:], "#["), c == '/' && strings.HasPrefix(code[i:], "//"):
+			if nl := strings.IndexByte(code[i:], '\n'); nl >= 0 {
+				i += nl
+			} else {
+				return -1
+			}
+		case c == '/' && strings.HasPrefix(code[i:], "/*"):
+			close := strings.Index(code[i+2:], "*/")
+			if close < 0 {
+				return -1
+			}
+			i += 2 + close + 1
+		case c == code[open]:
+			depth++
+		case c == closer:
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+func skipSpace(code string, i int) int {
+	for i < len(code) && strings.IndexByte(" \t\r\n", code[i]) >= 0 {
+		i++
+	}
+	return i
+}
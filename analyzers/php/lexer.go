@@ -0,0 +1,215 @@
+package php
+
+import (
+	"strings"
+
+	"code-analyzer/analyzers"
+)
+
+// commentKind tells the syntax of a comment
+type commentKind int
+
+const (
+	lineComment  commentKind = iota // "// ..." or "# ..."
+	blockComment                    // "/* ... */"
+	docComment                      // "/** ... */"
+)
+
+// comment is one comment of a PHP file
+type comment struct {
+	kind commentKind
+	// start and end are the byte offsets of the comment, markers included;
+	// a line comment ends before its newline or a closing ?> tag
+	start, end int
+	// line is the 1-based line of start
+	line int
+	// alone reports whether only whitespace precedes the comment on its line
+	alone bool
+}
+
+// lexComments returns the comments of a PHP file in order. Text outside
+// <?php ... ?> tags and the contents of strings, heredocs and nowdocs are
+// skipped, so "http://" in markup or a literal is not a comment. #[ starts an
+// attribute, not a comment. An unterminated comment or string runs to the end.
+// A fragment without any open tag is lexed as code.
+func lexComments(src string) []comment {
+	var comments []comment
+	line := 1
+	lineStart := 0
+	// advance moves i to j, counting the newlines passed
+	advance := func(i, j int) int {
+		for ; i < j && i < len(src); i++ {
+			if src[i] == '\n' {
+				line++
+				lineStart = i + 1
+			}
+		}
+		return j
+	}
+	add := func(kind commentKind, start, end int) {
+		alone := strings.TrimSpace(src[lineStart:start]) == ""
+		comments = append(comments, comment{kind: kind, start: start, end: end, line: line, alone: alone})
+	}
+
+	i := 0
+	fragment := !strings.Contains(src, "<?")
+	for i < len(src) {
+		// Markup until the next open tag
+		if !fragment {
+			open := strings.Index(src[i:], "<?")
+			if open < 0 {
+				break
+			}
+			i = advance(i, i+open+2)
+			tag := strings.ToLower(src[i:min(i+3, len(src))])
+			if tag == "xml" {
+				continue
+			}
+			if tag == "php" {
+				i += 3
+			}
+		}
+
+	code:
+		for i < len(src) {
+			c := src[i]
+			switch {
+			case c == '?' && strings.HasPrefix(src[i:], "?>"):
+				i += 2
+				break code
+			case c == '\'' || c == '"' || c == '`':
+				i = advance(i, skipQuoted(src, i))
+			case c == '<' && strings.HasPrefix(src[i:], "<<<"):
+				i = advance(i, skipHeredoc(src, i))
+			case c == '#' && !strings.HasPrefix(src[i:], "#["), c == '/' && strings.HasPrefix(src[i:], "//"):
+				end := i
+				for end < len(src) && src[end] != '\n' && !strings.HasPrefix(src[end:], "?>") {
+					end++
+				}
+				add(lineComment, i, end)
+				i = end
+			case c == '/' && strings.HasPrefix(src[i:], "/*"):
+				end := len(src)
+				if close := strings.Index(src[i+2:], "*/"); close >= 0 {
+					end = i + 2 + close + 2
+				}
+				kind := blockComment
+				if strings.HasPrefix(src[i:], "/**") && !strings.HasPrefix(src[i:], "/**/") {
+					kind = docComment
+				}
+				add(kind, i, end)
+				i = advance(i, end)
+			default:
+				i = advance(i, i+1)
+			}
+		}
+	}
+	return comments
+}
+
+// commentGroup is a comment as a reader sees it: one /* */ or /** */
+// comment, or a run of line comments alone on consecutive lines. A trailing
+// line comment and a KEEP: line are groups of their own.
+type commentGroup struct {
+	kind       commentKind
+	start, end int
+	// line is the 1-based line of start, and lines the number of lines
+	line, lines int
+	alone       bool
+}
+
+// groupComments returns the comment groups of a PHP file in order
+func groupComments(src string) []commentGroup {
+	var groups []commentGroup
+	for _, c := range lexComments(src) {
+		g := commentGroup{kind: c.kind, start: c.start, end: c.end, line: c.line, alone: c.alone}
+		g.lines = strings.Count(src[c.start:c.end], "\n") + 1
+		if c.kind == lineComment && c.alone && len(groups) > 0 {
+			last := &groups[len(groups)-1]
+			if last.kind == lineComment && last.alone && last.line+last.lines == c.line &&
+				!isKeepLine(src[last.start:last.end]) && !isKeepLine(src[c.start:c.end]) {
+				last.end = c.end
+				last.lines++
+				continue
+			}
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// isKeepLine reports whether a line comment is a KEEP: marker. Runs only
+// join comments without one, so a marker is always a group of one line.
+func isKeepLine(text string) bool {
+	_, ok := analyzers.KeepReason(text)
+	return ok
+}
+
+// blankComments replaces the comments of a PHP file with spaces, keeping
+// line breaks, so the code stays at the same offsets
+func blankComments(src string) string {
+	b := []byte(src)
+	for _, c := range lexComments(src) {
+		for i := c.start; i < c.end; i++ {
+			if b[i] != '\n' && b[i] != '\r' {
+				b[i] = ' '
+			}
+		}
+	}
+	return string(b)
+}
+
+// skipQuoted returns the offset after the string opened by the quote at i
+func skipQuoted(src string, i int) int {
+	quote := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		}
+	}
+	return len(src)
+}
+
+// skipHeredoc returns the offset after the heredoc or nowdoc opened by the
+// <<< at i, or after the <<< when no identifier follows
+func skipHeredoc(src string, i int) int {
+	j := i + 3
+	for j < len(src) && (src[j] == ' ' || src[j] == '\t') {
+		j++
+	}
+	quoted := j < len(src) && (src[j] == '\'' || src[j] == '"')
+	if quoted {
+		j++
+	}
+	start := j
+	for j < len(src) && isIdentByte(src[j]) {
+		j++
+	}
+	label := src[start:j]
+	if label == "" {
+		return i + 3
+	}
+	if quoted {
+		j++
+	}
+
+	// The closing label starts a line, possibly indented (PHP 7.3+)
+	for {
+		nl := strings.IndexByte(src[j:], '\n')
+		if nl < 0 {
+			return len(src)
+		}
+		j += nl + 1
+		rest := strings.TrimLeft(src[j:], " \t")
+		if strings.HasPrefix(rest, label) && (len(rest) == len(label) || !isIdentByte(rest[len(label)])) {
+			return len(src) - len(rest) + len(label)
+		}
+	}
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b >= 0x80
+}
@@ -0,0 +1,72 @@
+package php
+
+import (
+	"testing"
+)
+
+func TestLexComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "Markup is not code",
+			content:  "<p>See http://example.com</p>\n<?php // note ?>\n<a>//x</a>",
+			expected: []string{"// note "},
+		},
+		{
+			name:     "Strings hide markers",
+			content:  "<?php\n$url = 'http://example.com'; $s = \"a /* b */ c # d\"; # real\n",
+			expected: []string{"# real"},
+		},
+		{
+			name:     "Heredoc hides markers",
+			content:  "<?php\n$h = <<<EOT\n// not a comment\n  EOT;\n/* after */\n$n = <<<'NOW'\n# nowdoc\nNOW;\n",
+			expected: []string{"/* after */"},
+		},
+		{
+			name:     "Attributes and docblocks",
+			content:  "<?php\n#[Route('/')]\n/** Doc */\n/**/\n",
+			expected: []string{"/** Doc */", "/**/"},
+		},
+		{
+			name:     "Unterminated block runs to the end",
+			content:  "<?php\n/* open\n$x = 1;",
+			expected: []string{"/* open\n$x = 1;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := lexComments(tt.content)
+			if len(comments) != len(tt.expected) {
+				t.Fatalf("expected %d comments, got %+v", len(tt.expected), comments)
+			}
+			for i, c := range comments {
+				if got := tt.content[c.start:c.end]; got != tt.expected[i] {
+					t.Errorf("expected %q, got %q", tt.expected[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestLexComments_Positions(t *testing.T) {
+	content := "<?php\n$a = 1; // trailing\n\n    // alone\n/**\n * Doc\n */\n"
+	comments := lexComments(content)
+	if len(comments) != 3 {
+		t.Fatalf("expected 3 comments, got %+v", comments)
+	}
+	want := []struct {
+		kind  commentKind
+		line  int
+		alone bool
+	}{{lineComment, 2, false}, {lineComment, 4, true}, {docComment, 5, true}}
+	for i, w := range want {
+		c := comments[i]
+		if c.kind != w.kind || c.line != w.line || c.alone != w.alone {
+			t.Errorf("comment %d: expected %+v, got %+v", i, w, c)
+		}
+	}
+}
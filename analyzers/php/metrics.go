@@ -0,0 +1,66 @@
+package php
+
+import (
+	"strings"
+
+	"code-analyzer/analyzers"
+)
+
+// commentedMetrics is the size of the commented-out code of a file
+type commentedMetrics struct {
+	bytes   int
+	lines   int
+	largest int
+}
+
+// measureCommentedCode adds up the comment groups of content that look like
+// code. Docblocks document the code after them and trailing comments their
+// line, so both are left out, and so are KEEP: markers and the groups they
+// keep.
+func measureCommentedCode(content string) commentedMetrics {
+	var m commentedMetrics
+	for _, g := range groupComments(content) {
+		text := content[g.start:g.end]
+		if g.kind == docComment || g.kind == lineComment && (!g.alone || isKeepLine(text)) {
+			continue
+		}
+		if !isPHPCode(uncomment(text)) {
+			continue
+		}
+		if _, ok := analyzers.KeepReason(analyzers.PrecedingLine(content, g.start)); ok {
+			continue
+		}
+		size := g.end - g.start
+		m.bytes += size
+		m.lines += g.lines
+		m.largest = max(m.largest, size)
+	}
+	return m
+}
+
+// isPHPCode uses heuristics to tell commented-out code from prose: two
+// signs of code are needed, and signs of prose count against it
+func isPHPCode(text string) bool {
+	indicators := []string{
+		";", "{", "}", "$", "->", "=>", "::", "function ", "return ", "echo ",
+		"new ", "if (", "foreach (", "for (", "while (", "class ", "use ",
+	}
+
+	score := 0
+	for _, ind := range indicators {
+		if strings.Contains(text, ind) {
+			score++
+		}
+	}
+
+	textIndicators := []string{
+		"TODO:", "FIXME:", "NOTE:", "http://", "https://", " This ", " The ", " To ",
+	}
+	for _, ind := range textIndicators {
+		if strings.Contains(text, ind) {
+			score--
+		}
+	}
+
+	return score >= 2
+}
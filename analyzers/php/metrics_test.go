@@ -0,0 +1,52 @@
+package php
+
+import (
+	"testing"
+)
+
+func TestMeasureCommentedCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		bytes   int
+		lines   int
+		largest int
+	}{
+		{
+			name:    "Prose and docblocks",
+			content: "<?php\n// Loads the invoices of a customer\n/**\n * @return array\n */\nfunction load() {}\n",
+		},
+		{
+			name:    "Run of line comments",
+			content: "<?php\n// $total = 0;\n// foreach ($items as $item) {\n//     $total += $item->price;\n// }\n",
+			bytes:   len("// $total = 0;\n// foreach ($items as $item) {\n//     $total += $item->price;\n// }"),
+			lines:   4,
+			largest: len("// $total = 0;\n// foreach ($items as $item) {\n//     $total += $item->price;\n// }"),
+		},
+		{
+			name:    "Block and hash runs",
+			content: "<?php\n/* $a->save(); */\n\n# $b = new Foo();\n# $b->run();\n",
+			bytes:   len("/* $a->save(); */") + len("# $b = new Foo();\n# $b->run();"),
+			lines:   3,
+			largest: len("# $b = new Foo();\n# $b->run();"),
+		},
+		{
+			name:    "Trailing comments describe their line",
+			content: "<?php\n$a = 1; // $a = $b->c();\n",
+		},
+		{
+			name:    "KEEP marker",
+			content: "<?php\n// KEEP: restored during incidents\n// $cache->flush();\n// return $this->reload();\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := measureCommentedCode(tt.content)
+			if m.bytes != tt.bytes || m.lines != tt.lines || m.largest != tt.largest {
+				t.Errorf("expected %d bytes, %d lines, largest %d; got %d bytes, %d lines, largest %d",
+					tt.bytes, tt.lines, tt.largest, m.bytes, m.lines, m.largest)
+			}
+		})
+	}
+}
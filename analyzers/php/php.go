@@ -1,10 +1,11 @@
 package php
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 
@@ -13,6 +14,9 @@ import (
 	"code-analyzer/utils"
 )
 
+// RuleCommentedFunction is the rule ID of commented-out PHP functions
+const RuleCommentedFunction = "php/commented-function"
+
 // PHPAnalyzer analyzes PHP files for various code quality issues
 type PHPAnalyzer struct {
 	rules []analyzers.Rule
@@ -23,6 +27,9 @@ func NewPHPAnalyzer() *PHPAnalyzer {
 	return &PHPAnalyzer{
 		rules: []analyzers.Rule{
 			&CommentedFunctionsRule{},
+			&CommentedTypesRule{},
+			&RouteClosuresRule{},
+			&NPlusOneRule{},
 		},
 	}
 }
@@ -34,56 +41,113 @@ func (a *PHPAnalyzer) Name() string {
 
 // Description returns what this analyzer does
 func (a *PHPAnalyzer) Description() string {
-	return "Analyzes PHP files for commented functions and other issues"
+	return "Analyzes PHP files for commented functions, classes, route closures, N+1 queries and other issues"
+}
+
+// CPUBound reports that the analyzer's run time goes to parsing
+func (a *PHPAnalyzer) CPUBound() bool {
+	return true
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *PHPAnalyzer) RuleIDs() []string {
+	return []string{RuleCommentedFunction, RuleCommentedType, RuleRouteClosure, RuleNPlusOne}
 }
 
 // Run executes the PHP analysis
-func (a *PHPAnalyzer) Run(config analyzers.Config) ([]models.Issue, error) {
+func (a *PHPAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
 	results := []models.PHPFileAnalysis{}
+	kept := []models.KeptBlock{}
 	totalFunctions := 0
 	totalCommented := 0
+	totalTypes := 0
+	skipped := []models.SkippedFile{}
 	var allIssues []models.Issue
 
-	err := filepath.Walk(config.RootDir, func(path string, info os.FileInfo, err error) error {
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return nil
 		}
-		if !strings.HasSuffix(strings.ToLower(path), ".php") {
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
 			return nil
 		}
 		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
 			return nil
 		}
 
-		analysis := a.analyzeFile(path)
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, config)
 		if analysis != nil {
-			if analysis.CommentedFunctions < config.MinValue {
-				return nil
+			kept = append(kept, analysis.Kept...)
+			// Route closures and N+1 queries are reported whatever the
+			// commented-code thresholds
+			below := ""
+			if commented := analysis.CommentedFunctions + analysis.CommentedTypes; commented == 0 || commented < config.MinValue {
+				below = "min"
+			} else if config.MinRatio > 0 && analysis.CommentRatio < config.MinRatio {
+				below = "min_ratio"
 			}
-			if config.MinRatio > 0 && analysis.CommentRatio < config.MinRatio {
-				return nil
+			if below != "" {
+				if len(analysis.RouteClosures) == 0 && len(analysis.NPlusOneQueries) == 0 {
+					config.Tracef(path, "not reported, below %s", below)
+					return nil
+				}
+				config.Tracef(path, "commented code not reported, below %s", below)
+				analysis.Issues = laravelIssues(analysis.Issues)
+			} else {
+				totalFunctions += analysis.TotalFunctions
+				totalCommented += analysis.CommentedFunctions
+				totalTypes += analysis.CommentedTypes
 			}
 
 			results = append(results, *analysis)
-			totalFunctions += analysis.TotalFunctions
-			totalCommented += analysis.CommentedFunctions
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
 			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
 		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return allIssues, err
 	}
 
 	// Sort results
-	if config.SortBy == "ratio" {
+	switch config.SortBy {
+	case "ratio":
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].CommentRatio != results[j].CommentRatio {
+				return results[i].CommentRatio > results[j].CommentRatio
+			}
+			return results[i].Path < results[j].Path
+		})
+	case "bytes":
 		sort.Slice(results, func(i, j int) bool {
-			return results[i].CommentRatio > results[j].CommentRatio
+			if results[i].CommentedBytes != results[j].CommentedBytes {
+				return results[i].CommentedBytes > results[j].CommentedBytes
+			}
+			return results[i].Path < results[j].Path
 		})
-	} else {
+	default:
 		sort.Slice(results, func(i, j int) bool {
-			return results[i].CommentedFunctions > results[j].CommentedFunctions
+			if results[i].CommentedFunctions != results[j].CommentedFunctions {
+				return results[i].CommentedFunctions > results[j].CommentedFunctions
+			}
+			return results[i].Path < results[j].Path
 		})
 	}
 
@@ -93,92 +157,271 @@ func (a *PHPAnalyzer) Run(config analyzers.Config) ([]models.Issue, error) {
 	}
 
 	// Generate artifact if requested
-	if config.OutputFile != "" {
-		if err := a.generateArtifact(results, config, totalFunctions, totalCommented); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to generate artifact: %v\n", err)
-		} else {
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, kept, config, totalFunctions, totalCommented, totalTypes); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
 			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
 		}
 	}
 
 	// Print results
-	a.printResults(results, totalFunctions, totalCommented)
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintKept(kept)
+	analyzers.PrintSkipped(skipped)
+	a.printResults(results, totalFunctions, totalCommented, totalTypes)
 	return allIssues, nil
 }
 
-func (a *PHPAnalyzer) analyzeFile(path string) *models.PHPFileAnalysis {
-	content, err := os.ReadFile(path)
-	if err != nil {
+// Handles reports whether path is a PHP file
+func (a *PHPAnalyzer) Handles(path string, config analyzers.Config) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".php")
+}
+
+func (a *PHPAnalyzer) analyzeFile(path string, config analyzers.Config) *models.PHPFileAnalysis {
+	// The rules need whole function bodies, classes and loops, so the file is
+	// read whole; max_file_size keeps huge generated files out of memory
+	content, encoding, err := utils.ReadText(path)
+	if err != nil || encoding == utils.EncodingBinary {
 		return nil
 	}
+	diags := config.Diagnostics
 
-	// Apply commented functions rule
-	rule := &CommentedFunctionsRule{}
-	finding := rule.Apply(string(content))
-
-	if finding == nil {
-		return nil
+	// Apply commented functions and types rules
+	var functions CommentedFunctionsFinding
+	if finding := analyzers.ApplyRule(&CommentedFunctionsRule{}, path, content, diags); finding != nil {
+		functions = finding.(CommentedFunctionsFinding)
+	}
+	var types CommentedTypesFinding
+	if finding := analyzers.ApplyRule(&CommentedTypesRule{}, path, content, diags); finding != nil {
+		types = finding.(CommentedTypesFinding)
 	}
 
-	result := finding.(CommentedFunctionsFinding)
-	if len(result.CommentedList) == 0 {
+	// The methods of a commented-out class are part of its issue
+	commentedList := []string{}
+	var issues []models.Issue
+	for i, issue := range functions.Issues {
+		if !withinTypes(issue, types.Issues) {
+			commentedList = append(commentedList, functions.CommentedList[i])
+			issues = append(issues, issue)
+		}
+	}
+	issues = append(issues, types.Issues...)
+	// laravel_paths scopes the Laravel rules, relative to the scanned directory
+	rel, err := filepath.Rel(config.RootDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = path
+	}
+	var routes RouteClosuresFinding
+	if isRouteFile(rel, config.LaravelPaths) {
+		if finding := analyzers.ApplyRule(&RouteClosuresRule{}, path, content, diags); finding != nil {
+			routes = finding.(RouteClosuresFinding)
+		}
+	}
+	var nPlusOne NPlusOneFinding
+	if len(config.LaravelPaths) == 0 || matchesPaths(rel, config.LaravelPaths) {
+		if finding := analyzers.ApplyRule(&NPlusOneRule{}, path, content, diags); finding != nil {
+			nPlusOne = finding.(NPlusOneFinding)
+		}
+	}
+	issues = append(issues, routes.Issues...)
+	issues = append(issues, nPlusOne.Issues...)
+	kept := append(functions.Kept, types.Kept...)
+	if len(commentedList) == 0 && len(types.CommentedList) == 0 && len(kept) == 0 && len(routes.Routes) == 0 && len(nPlusOne.Queries) == 0 {
 		return nil
 	}
 
-	// Set path for issues
-	for i := range result.Issues {
-		result.Issues[i].Path = path
+	// Set path for issues and kept blocks
+	for i := range issues {
+		issues[i].Path = path
+	}
+	for i := range kept {
+		kept[i].Path = path
 	}
 
+	metrics := measureCommentedCode(content)
 	totalBytes := len(content)
-	commentedBytes := len(result.CommentedList) * 20 // rough estimate
+	commentRatio := 0.0
+	if totalBytes > 0 {
+		commentRatio = float64(metrics.bytes) / float64(totalBytes) * 100
+	}
+	functionList, typeList := functions.AllFunctions, types.CommentedList
+	if functionList == nil {
+		functionList = []string{}
+	}
+	if typeList == nil {
+		typeList = []string{}
+	}
 	ratio := 0.0
-	if len(result.AllFunctions) > 0 {
-		ratio = float64(len(result.CommentedList)) / float64(len(result.AllFunctions)) * 100
+	if len(functions.AllFunctions) > 0 {
+		ratio = float64(len(commentedList)) / float64(len(functions.AllFunctions)) * 100
 	}
 
 	return &models.PHPFileAnalysis{
 		Path:               path,
-		TotalFunctions:     len(result.AllFunctions),
-		CommentedFunctions: len(result.CommentedList),
-		FunctionList:       result.AllFunctions,
-		CommentedList:      result.CommentedList,
-		CommentRatio:       ratio,
+		TotalFunctions:     len(functions.AllFunctions),
+		CommentedFunctions: len(commentedList),
+		FunctionList:       functionList,
+		CommentedList:      commentedList,
+		FunctionRatio:      ratio,
+		CommentedTypes:     len(types.CommentedList),
+		CommentedTypeList:  typeList,
+		TotalLines:         strings.Count(content, "\n") + 1,
+		CommentedLines:     metrics.lines,
+		CommentedBytes:     metrics.bytes,
 		TotalBytes:         totalBytes,
-		CommentedBytes:     commentedBytes,
-		Issues:             result.Issues,
+		CommentRatio:       commentRatio,
+		LargestBlock:       metrics.largest,
+		Issues:             issues,
+		Kept:               kept,
+		RouteClosures:      routes.Routes,
+		NPlusOneQueries:    nPlusOne.Queries,
+	}
+}
+
+// isLaravelRule reports whether a rule checks Laravel code rather than
+// commented-out code
+func isLaravelRule(ruleID string) bool {
+	return ruleID == RuleRouteClosure || ruleID == RuleNPlusOne
+}
+
+// laravelIssues returns the route closure and N+1 query issues of issues
+func laravelIssues(issues []models.Issue) []models.Issue {
+	var laravel []models.Issue
+	for _, issue := range issues {
+		if isLaravelRule(issue.RuleID) {
+			laravel = append(laravel, issue)
+		}
 	}
+	return laravel
 }
 
-func (a *PHPAnalyzer) printResults(results []models.PHPFileAnalysis, totalFunctions, totalCommented int) {
+// matchesPaths reports whether rel or one of its directories matches one of
+// the glob patterns (path.Match syntax), whatever the path separator
+func matchesPaths(rel string, patterns []string) bool {
+	for p := strings.ReplaceAll(rel, `\`, "/"); p != "." && p != "/"; p = path.Dir(p) {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// laravelOnly reports whether a file is reported only for route closures and
+// N+1 queries
+func laravelOnly(r models.PHPFileAnalysis) bool {
+	for _, issue := range r.Issues {
+		if !isLaravelRule(issue.RuleID) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *PHPAnalyzer) printResults(all []models.PHPFileAnalysis, totalFunctions, totalCommented, totalTypes int) {
+	// Files reported only for route closures and N+1 queries are left out of
+	// the tables
+	var results []models.PHPFileAnalysis
+	for _, r := range all {
+		if !laravelOnly(r) {
+			results = append(results, r)
+		}
+	}
 	if len(results) == 0 {
 		fmt.Println("✅ No PHP files with commented functions found!")
+		a.printRouteClosures(all)
+		a.printNPlusOneQueries(all)
 		return
 	}
 
 	fmt.Printf("Found %d files with commented functions\n", len(results))
+	ratio := 0.0
+	if totalFunctions > 0 {
+		ratio = float64(totalCommented) / float64(totalFunctions) * 100
+	}
 	fmt.Printf("📊 Total Functions: %d | Commented: %d (%.1f%%)\n\n",
-		totalFunctions, totalCommented,
-		float64(totalCommented)/float64(totalFunctions)*100)
+		totalFunctions, totalCommented, ratio)
+	if totalTypes > 0 {
+		fmt.Printf("🏛️  Commented classes, traits, interfaces and enums: %d\n\n", totalTypes)
+	}
 
-	fmt.Printf("%-5s %-60s %10s %10s %10s\n",
-		"Rank", "File", "Total", "Commented", "Ratio")
-	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-5s %-60s %10s %10s %12s %10s\n",
+		"Rank", "File", "Total", "Commented", "Code bytes", "Ratio")
+	fmt.Println(strings.Repeat("-", 112))
 
 	for i, result := range results {
 		relPath := utils.Truncate(result.Path, 60)
-		fmt.Printf("%-5d %-60s %10d %10d %9.1f%%\n",
+		fmt.Printf("%-5d %-60s %10d %10d %12s %9.1f%%\n",
 			i+1, relPath,
 			result.TotalFunctions,
 			result.CommentedFunctions,
+			utils.FormatBytes(result.CommentedBytes),
 			result.CommentRatio)
 	}
 
 	fmt.Println()
 	a.printTop10(results)
+	a.printRouteClosures(all)
+	a.printNPlusOneQueries(all)
 	fmt.Println("✅ Analysis complete!")
 }
 
+// printRouteClosures lists the routes of the reported files defined with
+// closures, those running queries or logic first
+func (a *PHPAnalyzer) printRouteClosures(results []models.PHPFileAnalysis) {
+	type located struct {
+		path  string
+		route models.RouteClosure
+	}
+	var routes []located
+	for _, r := range results {
+		for _, route := range r.RouteClosures {
+			routes = append(routes, located{r.Path, route})
+		}
+	}
+	if len(routes) == 0 {
+		return
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].route.Logic && !routes[j].route.Logic
+	})
+
+	fmt.Printf("🛣️  Route Closures (%d):\n", len(routes))
+	fmt.Println(strings.Repeat("-", 80))
+	for _, r := range routes[:utils.Min(10, len(routes))] {
+		logic := ""
+		if r.route.Logic {
+			logic = " (queries or logic)"
+		}
+		fmt.Printf("  %s:%d %s %s%s\n", r.path, r.route.Line, r.route.Method, r.route.URI, logic)
+	}
+	fmt.Println()
+}
+
+// printNPlusOneQueries lists the probable N+1 queries of the reported files
+func (a *PHPAnalyzer) printNPlusOneQueries(results []models.PHPFileAnalysis) {
+	var lines []string
+	for _, r := range results {
+		for _, q := range r.NPlusOneQueries {
+			lines = append(lines, fmt.Sprintf("  %s:%d %s in a loop over %s", r.Path, q.Line, q.Access, q.Loop))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Printf("🐢 Probable N+1 Queries (%d):\n", len(lines))
+	fmt.Println(strings.Repeat("-", 80))
+	for _, line := range lines[:utils.Min(10, len(lines))] {
+		fmt.Println(line)
+	}
+	fmt.Println()
+}
+
 func (a *PHPAnalyzer) printTop10(results []models.PHPFileAnalysis) {
 	fmt.Printf("📋 Top 10 Files with Commented Functions:\n")
 	fmt.Println(strings.Repeat("-", 80))
@@ -187,116 +430,87 @@ func (a *PHPAnalyzer) printTop10(results []models.PHPFileAnalysis) {
 	for i := 0; i < topCount; i++ {
 		r := results[i]
 		fmt.Printf("%2d. %s\n", i+1, r.Path)
-		fmt.Printf("    📊 %d/%d functions commented (%.1f%%)\n",
-			r.CommentedFunctions, r.TotalFunctions, r.CommentRatio)
+		fmt.Printf("    📊 %d/%d functions commented (%.1f%%), %s of commented code in %d lines\n",
+			r.CommentedFunctions, r.TotalFunctions, r.FunctionRatio, utils.FormatBytes(r.CommentedBytes), r.CommentedLines)
 		if len(r.CommentedList) > 0 {
 			fmt.Printf("    💀 Commented: %s\n",
 				strings.Join(r.CommentedList[:utils.Min(5, len(r.CommentedList))], ", "))
 		}
+		if len(r.CommentedTypeList) > 0 {
+			fmt.Printf("    🏛️  Commented types: %s\n",
+				strings.Join(r.CommentedTypeList[:utils.Min(5, len(r.CommentedTypeList))], ", "))
+		}
 	}
 	fmt.Println()
 }
 
-func (a *PHPAnalyzer) generateArtifact(results []models.PHPFileAnalysis, config analyzers.Config, totalFunctions, totalCommented int) error {
+func (a *PHPAnalyzer) generateArtifact(results []models.PHPFileAnalysis, skipped []models.SkippedFile, kept []models.KeptBlock, config analyzers.Config, totalFunctions, totalCommented, totalTypes int) error {
+	totalBytes := 0
+	for _, r := range results {
+		totalBytes += r.CommentedBytes
+	}
 	report := models.PHPAnalysisReport{
 		Timestamp:          utils.GetTimestamp(),
 		ScanDirectory:      config.RootDir,
 		TotalFiles:         len(results),
 		TotalFunctions:     totalFunctions,
 		CommentedFunctions: totalCommented,
+		CommentedTypes:     totalTypes,
+		TotalCommented:     totalBytes,
 		Results:            results,
+		SkippedTooLarge:    skipped,
+		IntentionallyKept:  kept,
+		Stats:              config.Stats.Summary(),
 	}
 
-	return utils.WriteArtifact(config.OutputFile, report)
+	return config.WriteArtifact(report)
 }
 
-// CommentedFunctionsRule detects commented-out PHP functions
-type CommentedFunctionsRule struct{}
-
-type CommentedFunctionsFinding struct {
-	AllFunctions  []string
-	CommentedList []string
-	Issues        []models.Issue
-}
-
-func (r *CommentedFunctionsRule) Name() string {
-	return "Commented Functions Detector"
-}
-
-func (r *CommentedFunctionsRule) Apply(content string) interface{} {
-	cleanCode := removePHPComments(content)
-	allFunctions := findPHPFunctions(content)
-	activeFunctions := findPHPFunctions(cleanCode)
-	commentedFunctions := difference(allFunctions, activeFunctions)
-
-	if len(commentedFunctions) == 0 {
-		return nil
-	}
-
-	var issues []models.Issue
-	for _, funcName := range commentedFunctions {
-		// Find line number of commented function
-		// We use a regex specific to this function name
-		funcRegex := regexp.MustCompile(`(?m)(?:^|[\s/]+|[*]+)\s*(?:public|private|protected|static)?\s*function\s+` + regexp.QuoteMeta(funcName) + `\s*\(`)
-		loc := funcRegex.FindStringIndex(content)
-
-		line := 0
-		if loc != nil {
-			line = strings.Count(content[:loc[0]], "\n") + 1
+// keepReasonAbove walks up from a commented declaration through the contiguous
+// comment lines above it (including its own line) looking for a KEEP: marker
+func keepReasonAbove(lines []string, declLine int) (string, bool) {
+	for i := declLine - 1; i >= 0 && i < len(lines); i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if reason, ok := analyzers.KeepReason(trimmed); ok {
+			return reason, true
 		}
-
-		issues = append(issues, models.Issue{
-			Description: fmt.Sprintf("Commented out PHP function: %s", funcName),
-			Line:        line,
-			Severity:    "major",
-		})
-	}
-
-	return CommentedFunctionsFinding{
-		AllFunctions:  allFunctions,
-		CommentedList: commentedFunctions,
-		Issues:        issues,
-	}
-}
-
-func removePHPComments(code string) string {
-	code = regexp.MustCompile(`(?s)/\*.*?\*/`).ReplaceAllString(code, "")
-	lines := strings.Split(code, "\n")
-	var cleanLines []string
-	for _, line := range lines {
-		if idx := strings.Index(line, "//"); idx != -1 {
-			line = line[:idx]
+		isComment := strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") ||
+			strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*")
+		if !isComment && i != declLine-1 {
+			return "", false
 		}
-		cleanLines = append(cleanLines, line)
 	}
-	return strings.Join(cleanLines, "\n")
+	return "", false
 }
 
-func findPHPFunctions(code string) []string {
-	functions := []string{}
-	functionRegex := regexp.MustCompile(`(?m)(?:^|[\s/]+|[*]+)\s*(?:public|private|protected|static)?\s*function\s+(\w+)\s*\(`)
-	matches := functionRegex.FindAllStringSubmatch(code, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			funcName := match[1]
-			if funcName != "__construct" && funcName != "__destruct" {
-				functions = append(functions, funcName)
+// functionExtent measures a function from its declaration at start to the
+// matching closing brace, returning its bytes and lines. Bodiless declarations
+// end at the semicolon; unbalanced braces run to the end of content.
+func functionExtent(content string, start int) (int, int) {
+	end := len(content)
+	depth := 0
+scan:
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case ';':
+			if depth == 0 {
+				end = i + 1
+				break scan
+			}
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				end = i + 1
+				break scan
 			}
 		}
 	}
-	return functions
-}
 
-func difference(a, b []string) []string {
-	mb := make(map[string]bool, len(b))
-	for _, x := range b {
-		mb[x] = true
-	}
-	var diff []string
-	for _, x := range a {
-		if !mb[x] {
-			diff = append(diff, x)
-		}
-	}
-	return diff
+	block := content[start:end]
+	return len(block), strings.Count(block, "\n") + 1
 }
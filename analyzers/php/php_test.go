@@ -1,6 +1,7 @@
 package php
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -64,3 +65,154 @@ func TestCommentedFunctionsRule_Apply(t *testing.T) {
 		})
 	}
 }
+
+func TestCommentedFunctionsRule_KeepMarker(t *testing.T) {
+	content := `<?php
+class Service
+{
+    // KEEP: restored during incident response
+    // public function emergencyFlush() {
+    //     return true;
+    // }
+
+    // public function dead() {
+    // }
+}
+`
+	rule := &CommentedFunctionsRule{}
+	result := rule.Apply(content)
+	if result == nil {
+		t.Fatal("expected finding, got nil")
+	}
+
+	finding := result.(CommentedFunctionsFinding)
+	if len(finding.CommentedList) != 1 || finding.CommentedList[0] != "dead" {
+		t.Errorf("expected only dead() to be reported, got %v", finding.CommentedList)
+	}
+	if len(finding.Kept) != 1 || finding.Kept[0].Reason != "restored during incident response" {
+		t.Errorf("expected emergencyFlush() to be kept, got %+v", finding.Kept)
+	}
+}
+
+func TestFunctionExtent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		lines   int
+	}{
+		{
+			name:    "Commented body",
+			content: "// function a() {\n//     if ($x) {\n//         return 1;\n//     }\n// }\n// other();",
+			lines:   5,
+		},
+		{
+			name:    "Bodiless declaration",
+			content: "/* abstract function a(); */",
+			lines:   1,
+		},
+		{
+			name:    "Unbalanced",
+			content: "// function a() {\n// return 1;",
+			lines:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := strings.Index(tt.content, "function")
+			_, lines := functionExtent(tt.content, start)
+			if lines != tt.lines {
+				t.Errorf("expected %d lines, got %d", tt.lines, lines)
+			}
+		})
+	}
+}
+
+func TestCommentedFunctionsRule_Parsing(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+		lines    []int
+		endLines []int
+	}{
+		{
+			name: "Docblock mentions",
+			content: `<?php
+/**
+ * Wraps function load() and @see function save($a) { ... }
+ */
+function wrap() {}
+`,
+			expected: nil,
+		},
+		{
+			name: "Prose",
+			content: `<?php
+// Call function load() before the first request
+// and function save() after it
+`,
+			expected: nil,
+		},
+		{
+			name: "Unusual formatting",
+			content: `<?php
+class Repo
+{
+    # public static function &find(
+    #     int $id,
+    #     array $with = []
+    # ): ?Model
+    # {
+    #     return $this->query(['id' => $id]); // don't cache
+    # }
+}
+`,
+			expected: []string{"find"},
+			lines:    []int{4},
+		},
+		{
+			name: "Declaration after prose in one block",
+			content: `<?php
+/*
+ Old implementation, kept for reference:
+
+ function legacyTotal($items)
+ {
+     return array_sum($items);
+ }
+*/
+`,
+			// The issue covers the whole comment, so -fix removes its markers
+			expected: []string{"legacyTotal"},
+			lines:    []int{2},
+			endLines: []int{9},
+		},
+	}
+
+	rule := &CommentedFunctionsRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.Apply(tt.content)
+			if result == nil {
+				if len(tt.expected) > 0 {
+					t.Fatalf("expected %v, got nil", tt.expected)
+				}
+				return
+			}
+
+			finding := result.(CommentedFunctionsFinding)
+			if strings.Join(finding.CommentedList, ",") != strings.Join(tt.expected, ",") {
+				t.Fatalf("expected %v, got %v", tt.expected, finding.CommentedList)
+			}
+			for i, issue := range finding.Issues {
+				if issue.Line != tt.lines[i] {
+					t.Errorf("expected %s on line %d, got %d", tt.expected[i], tt.lines[i], issue.Line)
+				}
+				if tt.endLines != nil && (issue.EndLine != tt.endLines[i] || issue.Metadata.LineSpan != tt.endLines[i]-tt.lines[i]+1) {
+					t.Errorf("expected %s to end on line %d, got %d (span %d)", tt.expected[i], tt.endLines[i], issue.EndLine, issue.Metadata.LineSpan)
+				}
+			}
+		})
+	}
+}
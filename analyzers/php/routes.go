@@ -0,0 +1,154 @@
+package php
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// RuleRouteClosure is the rule ID of Laravel routes whose action is an
+// inline closure
+const RuleRouteClosure = "php/route-closure"
+
+// routeRegex matches a route definition up to its URI: Route::get('/x', ...)
+// or a chained ->post('/x', ...). Route::match takes the methods first.
+var routeRegex = regexp.MustCompile(`(?:\bRoute::|->)\s*(get|post|put|patch|delete|options|any|match)\s*\(`)
+
+// closureRegex matches an inline closure at the start of an argument
+var closureRegex = regexp.MustCompile(`^(?:static\s+)?(?:function|fn)\s*&?\s*\(`)
+
+// logicRegex matches what makes a closure business logic rather than a thin
+// action: queries, persistence, instantiation and control flow
+var logicRegex = regexp.MustCompile(`\bDB::|::(?:where\w*|find\w*|all|create|query|first\w*|paginate)\s*\(|->(?:where\w*|save|update|delete|insert|create|paginate|first\w*)\s*\(|\bnew\s+[\w\\]|\b(?:if|foreach|for|while|switch)\s*\(`)
+
+// RouteClosuresRule detects route definitions whose action is an inline
+// closure. Closures keep `php artisan route:cache` from caching the routes on
+// older Laravel versions and hide controller logic in route files; those
+// running queries or other logic are major.
+type RouteClosuresRule struct{}
+
+// RouteClosuresFinding lists the routes defined with closures
+type RouteClosuresFinding struct {
+	Routes []models.RouteClosure
+	Issues []models.Issue
+}
+
+func (r *RouteClosuresRule) Name() string {
+	return "Route Closures Detector"
+}
+
+func (r *RouteClosuresRule) Apply(content string) interface{} {
+	// Blanking comments skips commented-out routes, at the same offsets
+	code := blankComments(content)
+	var finding RouteClosuresFinding
+	end := 0
+	for _, m := range routeRegex.FindAllStringSubmatchIndex(code, -1) {
+		// Routes inside the closure of a previous route are part of it
+		if m[0] < end {
+			continue
+		}
+		route, ok := parseRoute(code, code[m[2]:m[3]], m[1]-1)
+		if !ok {
+			continue
+		}
+		end = route.end
+
+		route.start = m[0]
+		if code[m[0]] == '-' {
+			route.start += 2
+		}
+		closure := models.RouteClosure{
+			Method:  strings.ToUpper(route.method),
+			URI:     route.uri,
+			Line:    strings.Count(content[:route.start], "\n") + 1,
+			EndLine: strings.Count(content[:route.end], "\n") + 1,
+			Logic:   logicRegex.MatchString(code[route.closure:route.end]),
+		}
+		finding.Routes = append(finding.Routes, closure)
+
+		description := fmt.Sprintf("Route closure: %s %s", closure.Method, closure.URI)
+		severity := "minor"
+		if closure.Logic {
+			description = fmt.Sprintf("Route closure with queries or logic: %s %s", closure.Method, closure.URI)
+			severity = "major"
+		}
+		issue := models.Issue{
+			Description: description,
+			RuleID:      RuleRouteClosure,
+			Category:    models.CategoryMaintainability,
+			Line:        closure.Line,
+			Severity:    severity,
+		}
+		analyzers.SetRange(&issue, content, route.start, route.end)
+		finding.Issues = append(finding.Issues, issue)
+	}
+	if len(finding.Issues) == 0 {
+		return nil
+	}
+	return finding
+}
+
+// route is a route definition whose action is a closure
+type route struct {
+	method, uri string
+	// start and end delimit the definition, closure the closure in it
+	start, closure, end int
+}
+
+// parseRoute parses the arguments of a route definition, open being its
+// opening parenthesis. It reports false unless the URI is a string literal
+// and the action that follows it an inline closure.
+func parseRoute(code, method string, open int) (route, bool) {
+	r := route{method: method}
+	if r.end = matchClose(code, open); r.end < 0 {
+		return r, false
+	}
+	i := skipSpace(code, open+1)
+	if method == "match" {
+		// Route::match(['get', 'post'], '/x', ...)
+		if i >= len(code) || code[i] != '[' {
+			return r, false
+		}
+		close := strings.IndexByte(code[i:], ']')
+		if close < 0 {
+			return r, false
+		}
+		methods := strings.NewReplacer("'", "", `"`, "", " ", "").Replace(code[i+1 : i+close])
+		r.method = strings.ReplaceAll(methods, ",", "|")
+		if i = skipSpace(code, i+close+1); i >= len(code) || code[i] != ',' {
+			return r, false
+		}
+		i = skipSpace(code, i+1)
+	}
+
+	if i >= len(code) || code[i] != '\'' && code[i] != '"' {
+		return r, false
+	}
+	uriEnd := skipQuoted(code, i)
+	r.uri = code[i+1 : max(i+1, uriEnd-1)]
+	if i = skipSpace(code, uriEnd); i >= len(code) || code[i] != ',' {
+		return r, false
+	}
+	r.closure = skipSpace(code, i+1)
+	if !closureRegex.MatchString(code[r.closure:r.end]) {
+		return r, false
+	}
+	return r, true
+}
+
+// DefaultRoutePaths are the files route closures are looked for in when
+// laravel_paths is unset
+var DefaultRoutePaths = []string{"routes/*.php"}
+
+// isRouteFile reports whether rel, a path relative to the scanned directory,
+// is a Laravel route file: one matching laravelPaths, or DefaultRoutePaths
+// when there are none
+func isRouteFile(rel string, laravelPaths []string) bool {
+	if len(laravelPaths) == 0 {
+		laravelPaths = DefaultRoutePaths
+	}
+	return matchesPaths(rel, laravelPaths)
+}
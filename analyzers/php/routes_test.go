@@ -0,0 +1,91 @@
+package php
+
+import (
+	"testing"
+)
+
+func TestRouteClosuresRule_Apply(t *testing.T) {
+	content := `<?php
+
+use App\Http\Controllers\InvoiceController;
+use Illuminate\Support\Facades\Route;
+
+Route::get('/', function () {
+    return view('welcome');
+});
+
+Route::get('/invoices', [InvoiceController::class, 'index']);
+
+Route::middleware('auth')->group(function () {
+    Route::post('/invoices/{id}/pay', function ($id) {
+        $invoice = Invoice::findOrFail($id);
+        $invoice->update(['paid' => true]);
+        return redirect('/invoices');
+    });
+});
+
+Route::match(['get', 'post'], '/search', fn () => view('search'));
+
+// Route::get('/old', function () { return DB::table('old')->get(); });
+`
+	result := (&RouteClosuresRule{}).Apply(content)
+	if result == nil {
+		t.Fatal("expected finding, got nil")
+	}
+
+	finding := result.(RouteClosuresFinding)
+	expected := []struct {
+		description string
+		severity    string
+		line        int
+		endLine     int
+	}{
+		{"Route closure: GET /", "minor", 6, 8},
+		{"Route closure with queries or logic: POST /invoices/{id}/pay", "major", 13, 17},
+		{"Route closure: GET|POST /search", "minor", 20, 20},
+	}
+	if len(finding.Issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %+v", len(expected), finding.Issues)
+	}
+	for i, want := range expected {
+		issue := finding.Issues[i]
+		if issue.Description != want.description || issue.Severity != want.severity {
+			t.Errorf("expected %q (%s), got %q (%s)", want.description, want.severity, issue.Description, issue.Severity)
+		}
+		if issue.Line != want.line || issue.EndLine != want.endLine {
+			t.Errorf("%s: expected lines %d-%d, got %d-%d", want.description, want.line, want.endLine, issue.Line, issue.EndLine)
+		}
+	}
+}
+
+func TestIsRouteFile(t *testing.T) {
+	tests := map[string]bool{
+		"routes/web.php":                    true,
+		`routes\api.php`:                    true,
+		"routes/admin/web.php":              false,
+		"app/routes/api.php":                false,
+		"vendor/foo/src/routes/web.php":     false,
+		"resources/js/routes/x.php":         false,
+		"app/Http/Controllers/Routes.php":   false,
+		"app/Services/RoutesController.php": false,
+	}
+	for path, want := range tests {
+		if got := isRouteFile(path, nil); got != want {
+			t.Errorf("isRouteFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	laravelPaths := []string{"routes", "Modules/*/routes/*.php"}
+	tests = map[string]bool{
+		"routes/web.php":                      true,
+		"routes/admin/web.php":                true,
+		`Modules\Billing\routes\web.php`:      true,
+		"Modules/Billing/src/routes/web.php":  false,
+		"vendor/foo/Modules/x/routes/web.php": false,
+	}
+	for path, want := range tests {
+		if got := isRouteFile(path, laravelPaths); got != want {
+			t.Errorf("isRouteFile(%q, %v) = %v, want %v", path, laravelPaths, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package php
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// RuleCommentedType is the rule ID of commented-out PHP classes, traits,
+// interfaces and enums
+const RuleCommentedType = "php/commented-type"
+
+// typeRegex matches a class, trait, interface or enum declaration up to its
+// opening brace. Requiring the brace keeps prose such as "this class handles"
+// out, and the guard before the keyword skips ::class, $class and ->class.
+var typeRegex = regexp.MustCompile(`(?:^|[^\w$:>\\])((?:(?:abstract|final|readonly)\s+)*(class|trait|interface|enum)\s+(\w+)(?:\s*:\s*\w+)?(?:\s+extends\s+[\w\\]+(?:\s*,\s*[\w\\]+)*)?(?:\s+implements\s+[\w\\]+(?:\s*,\s*[\w\\]+)*)?\s*\{)`)
+
+// CommentedTypesRule detects commented-out PHP classes, traits, interfaces
+// and enums
+type CommentedTypesRule struct{}
+
+// CommentedTypesFinding lists the declared types and the commented-out ones
+type CommentedTypesFinding struct {
+	AllTypes      []string
+	CommentedList []string
+	Issues        []models.Issue
+	Kept          []models.KeptBlock
+}
+
+// typeDecl is one type declaration: its kind, name and where its keyword
+// starts
+type typeDecl struct {
+	kind   string
+	name   string
+	offset int
+}
+
+func (r *CommentedTypesRule) Name() string {
+	return "Commented Types Detector"
+}
+
+func (r *CommentedTypesRule) Apply(content string) interface{} {
+	var commented []typeDecl
+	all := findPHPTypes(blankComments(content))
+	for _, g := range groupComments(content) {
+		if g.kind == docComment {
+			continue
+		}
+		// Blanking the markers lets a declaration split over commented lines
+		// ("// class A\n// {") match, at the same offsets as in content
+		for _, d := range findPHPTypes(uncomment(content[g.start:g.end])) {
+			d.offset += g.start
+			commented = append(commented, d)
+			all = append(all, d)
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].offset < all[j].offset })
+	var allTypes []string
+	for _, d := range all {
+		allTypes = append(allTypes, d.name)
+	}
+	if len(commented) == 0 {
+		return nil
+	}
+
+	var issues []models.Issue
+	kept := []models.KeptBlock{}
+	var activeCommented []string
+	lines := strings.Split(content, "\n")
+	for _, d := range commented {
+		description := fmt.Sprintf("Commented out PHP %s: %s", d.kind, d.name)
+		line := strings.Count(content[:d.offset], "\n") + 1
+		if reason, ok := keepReasonAbove(lines, line); ok {
+			kept = append(kept, models.KeptBlock{Line: line, Description: description, Reason: reason})
+			continue
+		}
+
+		bytes, span := functionExtent(content, d.offset)
+		activeCommented = append(activeCommented, d.name)
+		issues = append(issues, models.Issue{
+			Description: description,
+			RuleID:      RuleCommentedType,
+			Category:    models.CategoryDeadCode,
+			Line:        line,
+			Severity:    "major",
+			Metadata: &models.IssueMetadata{
+				Bytes:         bytes,
+				LineSpan:      span,
+				EffortMinutes: analyzers.RemovalEffort(span),
+			},
+		})
+	}
+
+	return CommentedTypesFinding{
+		AllTypes:      allTypes,
+		CommentedList: activeCommented,
+		Issues:        issues,
+		Kept:          kept,
+	}
+}
+
+// findPHPTypes returns the type declarations in code, in order
+func findPHPTypes(code string) []typeDecl {
+	var decls []typeDecl
+	for _, m := range typeRegex.FindAllStringSubmatchIndex(code, -1) {
+		decls = append(decls, typeDecl{
+			kind:   code[m[4]:m[5]],
+			name:   code[m[6]:m[7]],
+			offset: m[2],
+		})
+	}
+	return decls
+}
+
+// uncomment replaces the run of comment markers (//, #, /*, *) opening each
+// line, and the closing markers of block comments, with spaces. #[ starts an
+// attribute, not a comment.
+func uncomment(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "*/", "  "), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, "#[") {
+			continue
+		}
+		code := strings.TrimLeft(trimmed, "/*#")
+		if n := len(trimmed) - len(code); n > 0 {
+			indent := len(line) - len(trimmed)
+			lines[i] = line[:indent] + strings.Repeat(" ", n) + code
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withinTypes reports whether the lines of an issue lie inside one of the
+// commented types
+func withinTypes(issue models.Issue, types []models.Issue) bool {
+	end := issue.Line
+	if issue.Metadata != nil {
+		end += issue.Metadata.LineSpan - 1
+	}
+	for _, t := range types {
+		if t.Metadata != nil && issue.Line >= t.Line && end < t.Line+t.Metadata.LineSpan {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package php
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code-analyzer/analyzers"
+)
+
+func writePHP(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "file.php")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCommentedTypesRule_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "Active class",
+			content:  "<?php\nclass Invoice\n{\n}\n",
+			expected: nil,
+		},
+		{
+			name: "Line-commented class with brace on the next line",
+			content: `<?php
+// final class LegacyInvoice extends Invoice implements Exportable, \JsonSerializable
+// {
+//     public function total() {
+//         return 0;
+//     }
+// }
+`,
+			expected: []string{"Commented out PHP class: LegacyInvoice"},
+		},
+		{
+			name: "Block-commented trait, interface and enum",
+			content: `<?php
+/*
+trait Loggable {
+    public function log() {}
+}
+interface Payable {
+    public function pay();
+}
+enum Status: string {
+    case Paid = 'paid';
+}
+*/
+`,
+			expected: []string{
+				"Commented out PHP trait: Loggable",
+				"Commented out PHP interface: Payable",
+				"Commented out PHP enum: Status",
+			},
+		},
+		{
+			name: "Prose and class constants",
+			content: `<?php
+// This class handles refunds, see Refund::class
+$name = Invoice::class;
+`,
+			expected: nil,
+		},
+	}
+
+	rule := &CommentedTypesRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.Apply(tt.content)
+			if result == nil {
+				if len(tt.expected) > 0 {
+					t.Fatalf("expected %v, got nil", tt.expected)
+				}
+				return
+			}
+
+			finding := result.(CommentedTypesFinding)
+			if len(finding.Issues) != len(tt.expected) {
+				t.Fatalf("expected %d issues, got %+v", len(tt.expected), finding.Issues)
+			}
+			for i, issue := range finding.Issues {
+				if issue.Description != tt.expected[i] {
+					t.Errorf("expected %q, got %q", tt.expected[i], issue.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestCommentedTypesRule_Extent(t *testing.T) {
+	content := `<?php
+class Active
+{
+}
+
+// class Removed
+// {
+//     public function a() {
+//     }
+// }
+`
+	result := (&CommentedTypesRule{}).Apply(content)
+	if result == nil {
+		t.Fatal("expected finding, got nil")
+	}
+	issue := result.(CommentedTypesFinding).Issues[0]
+	if issue.Line != 6 || issue.Metadata.LineSpan != 5 {
+		t.Errorf("expected lines 6-10, got line %d spanning %d", issue.Line, issue.Metadata.LineSpan)
+	}
+	if issue.Metadata.Bytes == 0 {
+		t.Error("expected the size in bytes")
+	}
+}
+
+func TestAnalyzeFile_MethodsOfCommentedClass(t *testing.T) {
+	path := writePHP(t, `<?php
+// class Removed
+// {
+//     public function a() {
+//     }
+// }
+
+// function helper() {
+// }
+`)
+	analysis := NewPHPAnalyzer().analyzeFile(path, analyzers.Config{})
+	if analysis == nil {
+		t.Fatal("expected an analysis, got nil")
+	}
+	if analysis.CommentedTypes != 1 || len(analysis.CommentedList) != 1 || analysis.CommentedList[0] != "helper" {
+		t.Errorf("expected the class and helper() only, got types %v and functions %v", analysis.CommentedTypeList, analysis.CommentedList)
+	}
+	if len(analysis.Issues) != 2 {
+		t.Errorf("expected 2 issues, got %+v", analysis.Issues)
+	}
+}
@@ -0,0 +1,39 @@
+package analyzers
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"code-analyzer/models"
+)
+
+// SetRange records the exact extent of content[start:end] on issue: the
+// column of its first character and the line and column of its last one.
+// Columns are 1-based and count characters, not bytes. Line is left as the
+// rule computed it.
+func SetRange(issue *models.Issue, content string, start, end int) {
+	if start < 0 || end <= start || end > len(content) {
+		return
+	}
+	issue.Column = Column(content, start)
+
+	last := end - 1
+	for last > start && !utf8.RuneStart(content[last]) {
+		last--
+	}
+	// A block ending in a line break ends on the line before it
+	if content[last] == '\n' && last > start {
+		last--
+		if content[last] == '\r' && last > start {
+			last--
+		}
+	}
+	issue.EndLine = strings.Count(content[:last], "\n") + 1
+	issue.EndColumn = Column(content, last)
+}
+
+// Column returns the 1-based character column of offset in content
+func Column(content string, offset int) int {
+	lineStart := strings.LastIndex(content[:offset], "\n") + 1
+	return utf8.RuneCountInString(content[lineStart:offset]) + 1
+}
@@ -0,0 +1,26 @@
+package analyzers
+
+import (
+	"strings"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestSetRange(t *testing.T) {
+	content := "<p>é</p>\n  <!-- <b>x</b>\n  é -->\nrest"
+	start := strings.Index(content, "<!--")
+	end := start + len("<!-- <b>x</b>\n  é -->")
+
+	var issue models.Issue
+	SetRange(&issue, content, start, end)
+	if issue.Column != 3 || issue.EndLine != 3 || issue.EndColumn != 7 {
+		t.Errorf("got column %d, end %d:%d; want 3, 3:7", issue.Column, issue.EndLine, issue.EndColumn)
+	}
+
+	issue = models.Issue{}
+	SetRange(&issue, content, 0, 10)
+	if issue.Column != 1 || issue.EndLine != 1 || issue.EndColumn != 8 {
+		t.Errorf("got column %d, end %d:%d; want 1, 1:8", issue.Column, issue.EndLine, issue.EndColumn)
+	}
+}
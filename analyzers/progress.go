@@ -0,0 +1,152 @@
+package analyzers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"code-analyzer/utils"
+)
+
+// Progress reports how many files the current analyzer has visited out of the
+// files in the scan root. Interactive output redraws a bar in place; otherwise
+// (CI logs) a line is written every LogInterval.
+// A nil *Progress is valid and reports nothing.
+type Progress struct {
+	mu          sync.Mutex
+	out         io.Writer
+	interactive bool
+	total       int
+
+	label    string
+	done     int
+	started  time.Time
+	lastDraw time.Time
+
+	// LogInterval is the time between log lines in non-interactive mode
+	LogInterval time.Duration
+
+	// Events additionally receives files_processed events (may be nil)
+	Events *EventStream
+
+	// Structured writes non-interactive lines as structured log records
+	// (see utils.SetLogFormat) instead of to out
+	Structured bool
+}
+
+// NewProgress creates a progress reporter for a scan root holding total files
+func NewProgress(out io.Writer, total int, interactive bool) *Progress {
+	return &Progress{
+		out:         out,
+		interactive: interactive,
+		total:       total,
+		LogInterval: 15 * time.Second,
+	}
+}
+
+// Total returns the number of files in the scan root (0 for a nil Progress)
+func (p *Progress) Total() int {
+	if p == nil {
+		return 0
+	}
+	return p.total
+}
+
+// Start begins reporting for an analyzer
+func (p *Progress) Start(label string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.label = label
+	p.done = 0
+	p.started = time.Now()
+	p.lastDraw = p.started
+}
+
+// Tick records one visited file
+func (p *Progress) Tick() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.Events.FilesProcessed(p.done, p.total, false)
+
+	now := time.Now()
+	if p.interactive {
+		// Clear the bar once the walk is complete so tables print on a clean line
+		if p.done >= p.total {
+			fmt.Fprint(p.out, "\r\033[K")
+			return
+		}
+		if now.Sub(p.lastDraw) < 100*time.Millisecond {
+			return
+		}
+		p.lastDraw = now
+		fmt.Fprintf(p.out, "\r\033[K%s", p.render(now))
+		return
+	}
+
+	if now.Sub(p.lastDraw) >= p.LogInterval {
+		p.lastDraw = now
+		if p.Structured {
+			utils.LogAttrs(slog.LevelInfo, "progress",
+				slog.String("analyzer", p.label),
+				slog.Int("processed", p.done),
+				slog.Int("total_files", p.total),
+				slog.Int64("eta_seconds", int64(p.eta(now).Seconds())))
+			return
+		}
+		fmt.Fprintf(p.out, "⏳ %s\n", p.render(now))
+	}
+}
+
+// Finish ends reporting for the current analyzer
+func (p *Progress) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Events.FilesProcessed(p.done, p.total, true)
+	if p.interactive {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+}
+
+// render formats e.g. `[########------------] 40% 1200/3000 files · PHP · ETA 12s`
+func (p *Progress) render(now time.Time) string {
+	total := p.total
+	if p.done > total {
+		total = p.done
+	}
+	fraction := 0.0
+	if total > 0 {
+		fraction = float64(p.done) / float64(total)
+	}
+
+	eta := "?"
+	if p.done > 0 {
+		eta = p.eta(now).Round(time.Second).String()
+	}
+
+	const width = 20
+	filled := int(fraction * width)
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", width-filled)
+	return fmt.Sprintf("[%s] %3.0f%% %d/%d files · %s · ETA %s", bar, fraction*100, p.done, total, p.label, eta)
+}
+
+// eta estimates the time left for the current analyzer from its rate so far
+func (p *Progress) eta(now time.Time) time.Duration {
+	if p.done == 0 || p.done >= p.total {
+		return 0
+	}
+	elapsed := now.Sub(p.started)
+	return time.Duration(float64(elapsed) / float64(p.done) * float64(p.total-p.done))
+}
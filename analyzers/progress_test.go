@@ -0,0 +1,45 @@
+package analyzers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgress_LogLines(t *testing.T) {
+	var out bytes.Buffer
+	p := NewProgress(&out, 4, false)
+	p.LogInterval = 0
+
+	p.Start("PHP")
+	p.Tick()
+	p.Tick()
+	p.Finish()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %q", out.String())
+	}
+	if !strings.Contains(lines[1], "50% 2/4 files · PHP") {
+		t.Errorf("unexpected progress line: %q", lines[1])
+	}
+}
+
+func TestProgress_InteractiveClearsWhenComplete(t *testing.T) {
+	var out bytes.Buffer
+	p := NewProgress(&out, 1, true)
+
+	p.Start("JS")
+	p.Tick()
+
+	if out.String() != "\r\033[K" {
+		t.Errorf("expected the bar to be cleared, got %q", out.String())
+	}
+}
+
+func TestProgress_Nil(t *testing.T) {
+	var p *Progress
+	p.Start("HTML")
+	p.Tick()
+	p.Finish()
+}
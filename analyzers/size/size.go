@@ -0,0 +1,336 @@
+package size
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Rule IDs of the size analyzer's issues
+const (
+	RuleFileBytes = "size/file-bytes"
+	RuleFileLines = "size/file-lines"
+	RuleLongLines = "size/long-lines"
+)
+
+// Default thresholds used when the config does not set them
+const (
+	DefaultMaxBytes      = 512 * 1024
+	DefaultMaxLines      = 1000
+	DefaultMaxLineLength = 200
+)
+
+// SizeAnalyzer reports oversized files and overly long lines
+type SizeAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewSizeAnalyzer creates a new size analyzer
+func NewSizeAnalyzer() *SizeAnalyzer {
+	return &SizeAnalyzer{
+		rules: []analyzers.Rule{
+			&FileSizeRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *SizeAnalyzer) Name() string {
+	return "Size Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *SizeAnalyzer) Description() string {
+	return "Reports files exceeding size/line-count thresholds and lines exceeding a max length"
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *SizeAnalyzer) RuleIDs() []string {
+	return []string{RuleFileBytes, RuleFileLines, RuleLongLines}
+}
+
+// thresholds returns the configured limits, falling back to defaults
+func thresholds(config analyzers.Config) (maxBytes, maxLines, maxLineLength int) {
+	maxBytes, maxLines, maxLineLength = config.MaxBytes, config.MaxLines, config.MaxLineLength
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if maxLines == 0 {
+		maxLines = DefaultMaxLines
+	}
+	if maxLineLength == 0 {
+		maxLineLength = DefaultMaxLineLength
+	}
+	return
+}
+
+// Run executes the size analysis
+func (a *SizeAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	results := []models.SizeFileAnalysis{}
+	var allIssues []models.Issue
+	maxBytes, maxLines, maxLineLength := thresholds(config)
+
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if len(config.Extensions) > 0 && !analyzers.HasExtension(path, config.Extensions) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, maxBytes, maxLines, maxLineLength)
+		if analysis != nil {
+			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
+			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return allIssues, err
+	}
+
+	// Sort results
+	if config.SortBy == "bytes" {
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].TotalBytes != results[j].TotalBytes {
+				return results[i].TotalBytes > results[j].TotalBytes
+			}
+			return results[i].Path < results[j].Path
+		})
+	} else {
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].TotalLines != results[j].TotalLines {
+				return results[i].TotalLines > results[j].TotalLines
+			}
+			return results[i].Path < results[j].Path
+		})
+	}
+
+	// Limit to top N
+	if len(results) > config.TopN {
+		results = results[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, config, maxBytes, maxLines, maxLineLength); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	a.printResults(results)
+	return allIssues, nil
+}
+
+func (a *SizeAnalyzer) analyzeFile(path string, maxBytes, maxLines, maxLineLength int) *models.SizeFileAnalysis {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	// Lines are measured while streaming so huge files are never fully loaded
+	finding := measure(file, maxLineLength)
+	if finding == nil {
+		return nil
+	}
+
+	var issues []models.Issue
+	if finding.TotalBytes > maxBytes {
+		issues = append(issues, models.Issue{
+			Path:        path,
+			Description: fmt.Sprintf("File is %s (max %s)", utils.FormatBytes(finding.TotalBytes), utils.FormatBytes(maxBytes)),
+			RuleID:      RuleFileBytes,
+			Category:    models.CategoryComplexity,
+			Line:        1,
+			Severity:    "minor",
+			Metadata:    &models.IssueMetadata{Bytes: finding.TotalBytes, EffortMinutes: 60},
+		})
+	}
+	if finding.TotalLines > maxLines {
+		issues = append(issues, models.Issue{
+			Path:        path,
+			Description: fmt.Sprintf("File has %d lines (max %d)", finding.TotalLines, maxLines),
+			RuleID:      RuleFileLines,
+			Category:    models.CategoryComplexity,
+			Line:        1,
+			Severity:    "minor",
+			Metadata:    &models.IssueMetadata{LineSpan: finding.TotalLines, EffortMinutes: 60},
+		})
+	}
+	if len(finding.LongLines) > 0 {
+		issues = append(issues, models.Issue{
+			Path:        path,
+			Description: fmt.Sprintf("%d lines exceed %d characters (longest: %d)", len(finding.LongLines), maxLineLength, finding.LongestLine),
+			RuleID:      RuleLongLines,
+			Category:    models.CategoryStyle,
+			Line:        finding.LongLines[0],
+			Severity:    "info",
+			Metadata:    &models.IssueMetadata{EffortMinutes: len(finding.LongLines)},
+		})
+	}
+
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return &models.SizeFileAnalysis{
+		Path:          path,
+		TotalBytes:    finding.TotalBytes,
+		TotalLines:    finding.TotalLines,
+		LongestLine:   finding.LongestLine,
+		LongLineCount: len(finding.LongLines),
+		LongLines:     finding.LongLines[:utils.Min(20, len(finding.LongLines))],
+		Issues:        issues,
+	}
+}
+
+// measure streams a file and counts bytes, lines and long lines.
+// It returns nil for binary files.
+func measure(r io.Reader, maxLineLength int) *FileSizeFinding {
+	reader := bufio.NewReaderSize(r, 64*1024)
+
+	// Binary files (NUL byte in the first block) are not source code
+	head, _ := reader.Peek(8000)
+	if bytes.IndexByte(head, 0) != -1 {
+		return nil
+	}
+
+	finding := &FileSizeFinding{LongLines: []int{}}
+	lineLength := 0
+	lineNum := 1
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		finding.TotalBytes += len(chunk)
+		lineLength += len(chunk)
+
+		if len(chunk) > 0 && chunk[len(chunk)-1] == '\n' {
+			// Line length excludes the terminator
+			terminator := len(chunk) - len(bytes.TrimRight(chunk, "\r\n"))
+			finding.endLine(lineNum, lineLength-terminator, maxLineLength)
+			lineNum++
+			lineLength = 0
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			break
+		}
+	}
+	if lineLength > 0 {
+		finding.endLine(lineNum, lineLength, maxLineLength)
+	}
+	return finding
+}
+
+// FileSizeRule reports size metrics of file content
+type FileSizeRule struct {
+	MaxLineLength int
+}
+
+// FileSizeFinding holds the measured size of a file
+type FileSizeFinding struct {
+	TotalBytes  int
+	TotalLines  int
+	LongestLine int
+	LongLines   []int
+}
+
+// endLine records the length of a completed line
+func (f *FileSizeFinding) endLine(lineNum, length, maxLineLength int) {
+	f.TotalLines = lineNum
+	if length > f.LongestLine {
+		f.LongestLine = length
+	}
+	if length > maxLineLength {
+		f.LongLines = append(f.LongLines, lineNum)
+	}
+}
+
+func (r *FileSizeRule) Name() string {
+	return "File Size Detector"
+}
+
+func (r *FileSizeRule) Apply(content string) interface{} {
+	maxLineLength := r.MaxLineLength
+	if maxLineLength == 0 {
+		maxLineLength = DefaultMaxLineLength
+	}
+	finding := measure(strings.NewReader(content), maxLineLength)
+	if finding == nil {
+		return nil
+	}
+	return *finding
+}
+
+func (a *SizeAnalyzer) printResults(results []models.SizeFileAnalysis) {
+	if len(results) == 0 {
+		fmt.Println("✅ No oversized files or long lines found!")
+		return
+	}
+
+	fmt.Printf("Found %d files exceeding size thresholds\n\n", len(results))
+
+	fmt.Printf("%-5s %-60s %10s %10s %10s %10s\n",
+		"Rank", "File", "Size", "Lines", "Longest", "Long")
+	fmt.Println(strings.Repeat("-", 110))
+
+	for i, result := range results {
+		relPath := utils.Truncate(result.Path, 60)
+		fmt.Printf("%-5d %-60s %10s %10d %10d %10d\n",
+			i+1, relPath,
+			utils.FormatBytes(result.TotalBytes),
+			result.TotalLines,
+			result.LongestLine,
+			result.LongLineCount)
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
+
+func (a *SizeAnalyzer) generateArtifact(results []models.SizeFileAnalysis, config analyzers.Config, maxBytes, maxLines, maxLineLength int) error {
+	report := models.SizeAnalysisReport{
+		Timestamp:     utils.GetTimestamp(),
+		ScanDirectory: config.RootDir,
+		TotalFiles:    len(results),
+		MaxBytes:      maxBytes,
+		MaxLines:      maxLines,
+		MaxLineLength: maxLineLength,
+		Results:       results,
+		Stats:         config.Stats.Summary(),
+	}
+
+	return config.WriteArtifact(report)
+}
@@ -0,0 +1,70 @@
+package size
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFileSizeRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedLines int
+		expectedLong  int
+		expectedMax   int
+	}{
+		{
+			name:          "Short file",
+			content:       "a\nbb\nccc\n",
+			expectedLines: 3,
+			expectedLong:  0,
+			expectedMax:   3,
+		},
+		{
+			name:          "No trailing newline",
+			content:       "a\nbb",
+			expectedLines: 2,
+			expectedLong:  0,
+			expectedMax:   2,
+		},
+		{
+			name:          "Long CRLF lines",
+			content:       strings.Repeat("x", 20) + "\r\nshort\r\n" + strings.Repeat("y", 11) + "\r\n",
+			expectedLines: 3,
+			expectedLong:  2,
+			expectedMax:   20,
+		},
+	}
+
+	rule := &FileSizeRule{MaxLineLength: 10}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rule.Apply(tt.content)
+			if result == nil {
+				t.Fatal("expected finding, got nil")
+			}
+
+			finding := result.(FileSizeFinding)
+			if finding.TotalBytes != len(tt.content) {
+				t.Errorf("expected %d bytes, got %d", len(tt.content), finding.TotalBytes)
+			}
+			if finding.TotalLines != tt.expectedLines {
+				t.Errorf("expected %d lines, got %d", tt.expectedLines, finding.TotalLines)
+			}
+			if len(finding.LongLines) != tt.expectedLong {
+				t.Errorf("expected %d long lines, got %v", tt.expectedLong, finding.LongLines)
+			}
+			if finding.LongestLine != tt.expectedMax {
+				t.Errorf("expected longest line %d, got %d", tt.expectedMax, finding.LongestLine)
+			}
+		})
+	}
+}
+
+func TestFileSizeRule_Binary(t *testing.T) {
+	rule := &FileSizeRule{}
+	if result := rule.Apply("PNG\x00\x01\x02"); result != nil {
+		t.Errorf("expected nil for binary content, got %v", result)
+	}
+}
@@ -0,0 +1,184 @@
+package sql
+
+import (
+	"strings"
+)
+
+// literal is a string literal; start and end are byte offsets of the whole
+// token in the file (quotes included), text is its content
+type literal struct {
+	start  int
+	end    int
+	text   string
+	length int
+}
+
+// stringLiterals returns the single-, double- and backtick-quoted strings of
+// PHP or JS source, plus PHP heredocs/nowdocs, skipping comments. In PHP mode
+// only code between <?php (or <?=) and ?> is scanned.
+func stringLiterals(content string, php bool) []literal {
+	var literals []literal
+	inCode := !php
+	i := 0
+	for i < len(content) {
+		if !inCode {
+			next := strings.Index(content[i:], "<?")
+			if next < 0 {
+				break
+			}
+			i += next + 2
+			if strings.HasPrefix(content[i:], "php") {
+				i += 3
+			}
+			inCode = true
+			continue
+		}
+
+		c := content[i]
+		switch {
+		case php && strings.HasPrefix(content[i:], "?>"):
+			inCode = false
+			i += 2
+		case strings.HasPrefix(content[i:], "//") || (php && c == '#' && !strings.HasPrefix(content[i:], "#[")):
+			i = skipLineComment(content, i, php)
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				return literals
+			}
+			i += 2 + end + 2
+		case php && strings.HasPrefix(content[i:], "<<<"):
+			lit, next, ok := heredoc(content, i)
+			if !ok {
+				i += 3
+				continue
+			}
+			literals = append(literals, lit)
+			i = next
+		case c == '\'' || c == '"' || c == '`':
+			end := closingQuote(content, i+1, c)
+			literals = append(literals, newLiteral(content, i, end, i+1, end-1))
+			i = end
+		default:
+			i++
+		}
+	}
+	return literals
+}
+
+// newLiteral builds a literal spanning content[start:end] whose text is
+// content[textStart:textEnd]
+func newLiteral(content string, start, end, textStart, textEnd int) literal {
+	if textEnd < textStart {
+		textEnd = textStart
+	}
+	return literal{start: start, end: end, text: content[textStart:textEnd], length: textEnd - textStart}
+}
+
+// skipLineComment returns the offset of the newline ending a line comment;
+// a PHP line comment also ends before ?>
+func skipLineComment(content string, i int, php bool) int {
+	end := strings.IndexByte(content[i:], '\n')
+	if end < 0 {
+		end = len(content) - i
+	}
+	if php {
+		if close := strings.Index(content[i:i+end], "?>"); close >= 0 {
+			end = close
+		}
+	}
+	return i + end
+}
+
+// closingQuote returns the offset just past the quote closing a string that
+// starts at i, honoring backslash escapes (the end of content if unterminated)
+func closingQuote(content string, i int, quote byte) int {
+	for i < len(content) {
+		switch content[i] {
+		case '\\':
+			i += 2
+			continue
+		case quote:
+			return i + 1
+		}
+		i++
+	}
+	return len(content)
+}
+
+// heredoc parses a PHP heredoc or nowdoc starting at i ("<<<ID", "<<<'ID'" or
+// `<<<"ID"`), closed by ID at the start of a line (optionally indented)
+func heredoc(content string, i int) (literal, int, bool) {
+	lineEnd := strings.IndexByte(content[i:], '\n')
+	if lineEnd < 0 {
+		return literal{}, 0, false
+	}
+	id := strings.Trim(strings.TrimSpace(content[i+3:i+lineEnd]), `'"`)
+	if id == "" || !isIdentifier(id) {
+		return literal{}, 0, false
+	}
+
+	bodyStart := i + lineEnd + 1
+	pos := bodyStart
+	for pos < len(content) {
+		next := strings.IndexByte(content[pos:], '\n')
+		line := content[pos:]
+		if next >= 0 {
+			line = content[pos : pos+next]
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, id) && !isIdentifierByte(trimmed, len(id)) {
+			bodyEnd := pos - 1
+			if bodyEnd < bodyStart {
+				bodyEnd = bodyStart
+			}
+			end := pos + len(line) - len(trimmed) + len(id)
+			return newLiteral(content, i, end, bodyStart, bodyEnd), end, true
+		}
+		if next < 0 {
+			break
+		}
+		pos += next + 1
+	}
+	return literal{}, 0, false
+}
+
+func isIdentifier(s string) bool {
+	for i := range s {
+		if !isIdentifierByte(s, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifierByte reports whether s[i] exists and can be part of an identifier
+func isIdentifierByte(s string, i int) bool {
+	if i >= len(s) {
+		return false
+	}
+	c := s[i]
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// concatenated merges literals joined only by the concatenation operator
+// (`.` in PHP, `+` in JS), so a query split over several lines is measured whole
+func concatenated(content string, literals []literal, php bool) []literal {
+	operator := "+"
+	if php {
+		operator = "."
+	}
+
+	var merged []literal
+	for _, lit := range literals {
+		if n := len(merged); n > 0 && strings.TrimSpace(content[merged[n-1].end:lit.start]) == operator {
+			prev := &merged[n-1]
+			prev.end = lit.end
+			prev.text += lit.text
+			prev.length += lit.length
+			continue
+		}
+		merged = append(merged, lit)
+	}
+	return merged
+}
@@ -0,0 +1,290 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// RuleLongQuery is the rule ID of oversized inline SQL strings
+const RuleLongQuery = "sql/long-query"
+
+// DefaultMaxStringLength is the SQL string length in bytes above which an
+// inline query is reported when the config does not set max_string_length
+const DefaultMaxStringLength = 300
+
+// defaultExtensions are the application code files scanned when the config
+// does not set extensions
+var defaultExtensions = []string{".php", ".js", ".jsx", ".ts", ".tsx"}
+
+// SQLAnalyzer reports long SQL queries embedded in string literals
+type SQLAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewSQLAnalyzer creates a new inline SQL analyzer
+func NewSQLAnalyzer() *SQLAnalyzer {
+	return &SQLAnalyzer{
+		rules: []analyzers.Rule{
+			&InlineSQLRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *SQLAnalyzer) Name() string {
+	return "Inline SQL Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *SQLAnalyzer) Description() string {
+	return "Flags oversized SQL string literals in PHP/JS code"
+}
+
+// CPUBound reports that the analyzer's run time goes to parsing
+func (a *SQLAnalyzer) CPUBound() bool {
+	return true
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *SQLAnalyzer) RuleIDs() []string {
+	return []string{RuleLongQuery}
+}
+
+// Run executes the inline SQL analysis
+func (a *SQLAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	results := []models.SQLFileAnalysis{}
+	skipped := []models.SkippedFile{}
+	var allIssues []models.Issue
+
+	maxLength := config.MaxStringLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxStringLength
+	}
+
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, maxLength, config.Diagnostics)
+		if analysis != nil && len(analysis.Issues) >= config.MinValue {
+			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
+			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return allIssues, err
+	}
+
+	// Largest inline SQL first
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].SQLBytes != results[j].SQLBytes {
+			return results[i].SQLBytes > results[j].SQLBytes
+		}
+		return results[i].Path < results[j].Path
+	})
+
+	// Limit to top N
+	if len(results) > config.TopN {
+		results = results[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, maxLength, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintSkipped(skipped)
+	a.printResults(results, maxLength)
+	return allIssues, nil
+}
+
+// Handles reports whether path has one of the configured extensions (PHP and JS/TS by default)
+func (a *SQLAnalyzer) Handles(path string, config analyzers.Config) bool {
+	extensions := config.Extensions
+	if len(extensions) == 0 {
+		extensions = defaultExtensions
+	}
+	lower := strings.ToLower(path)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *SQLAnalyzer) analyzeFile(path string, maxLength int, diags *analyzers.Diagnostics) *models.SQLFileAnalysis {
+	content, encoding, err := utils.ReadText(path)
+	if err != nil || encoding == utils.EncodingBinary {
+		return nil
+	}
+
+	rule := &InlineSQLRule{
+		MaxLength: maxLength,
+		PHP:       strings.EqualFold(filepath.Ext(path), ".php"),
+	}
+	finding := analyzers.ApplyRule(rule, path, content, diags)
+	if finding == nil {
+		return nil
+	}
+
+	result := finding.(InlineSQLFinding)
+	for i := range result.Issues {
+		result.Issues[i].Path = path
+	}
+	return &models.SQLFileAnalysis{
+		Path:          path,
+		SQLStrings:    len(result.Issues),
+		SQLBytes:      result.SQLBytes,
+		LargestString: result.LargestString,
+		Issues:        result.Issues,
+	}
+}
+
+func (a *SQLAnalyzer) printResults(results []models.SQLFileAnalysis, maxLength int) {
+	if len(results) == 0 {
+		fmt.Printf("✅ No SQL strings over %d characters found!\n", maxLength)
+		return
+	}
+
+	totalStrings := 0
+	totalBytes := 0
+	for _, r := range results {
+		totalStrings += r.SQLStrings
+		totalBytes += r.SQLBytes
+	}
+
+	fmt.Printf("Found %d SQL strings over %d characters in %d files\n", totalStrings, maxLength, len(results))
+	fmt.Printf("📊 Total Inline SQL: %s\n\n", utils.FormatBytes(totalBytes))
+
+	fmt.Printf("%-5s %-70s %8s %10s %10s\n", "Rank", "File", "Strings", "SQL", "Largest")
+	fmt.Println(strings.Repeat("-", 107))
+
+	for i, result := range results {
+		relPath := utils.Truncate(result.Path, 70)
+		fmt.Printf("%-5d %-70s %8d %10s %10s\n", i+1, relPath,
+			result.SQLStrings,
+			utils.FormatBytes(result.SQLBytes),
+			utils.FormatBytes(result.LargestString))
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
+
+func (a *SQLAnalyzer) generateArtifact(results []models.SQLFileAnalysis, skipped []models.SkippedFile, maxLength int, config analyzers.Config) error {
+	report := models.SQLAnalysisReport{
+		Timestamp:       utils.GetTimestamp(),
+		ScanDirectory:   config.RootDir,
+		TotalFiles:      len(results),
+		MaxStringLength: maxLength,
+		Results:         results,
+		SkippedTooLarge: skipped,
+		Stats:           config.Stats.Summary(),
+	}
+
+	return config.WriteArtifact(report)
+}
+
+// InlineSQLRule detects SQL queries in string literals longer than MaxLength.
+// Literals joined by concatenation (`.` in PHP, `+` in JS) count as one string.
+type InlineSQLRule struct {
+	MaxLength int
+	// PHP scans only inside <?php ... ?> and treats # as a comment
+	PHP bool
+}
+
+// InlineSQLFinding holds the oversized SQL strings of a file
+type InlineSQLFinding struct {
+	SQLBytes      int
+	LargestString int
+	Issues        []models.Issue
+}
+
+// sqlRegex matches the statement shapes that distinguish SQL from prose
+var sqlRegex = regexp.MustCompile(`(?is)\b(?:select\b.+?\bfrom|insert\s+into|update\b.+?\bset|delete\s+from|create\s+(?:table|index|view)|alter\s+table|merge\s+into)\b`)
+
+func (r *InlineSQLRule) Name() string {
+	return "Inline SQL Detector"
+}
+
+func (r *InlineSQLRule) Apply(content string) interface{} {
+	maxLength := r.MaxLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxStringLength
+	}
+
+	var result InlineSQLFinding
+	for _, s := range concatenated(content, stringLiterals(content, r.PHP), r.PHP) {
+		if s.length <= maxLength || !sqlRegex.MatchString(s.text) {
+			continue
+		}
+
+		startLine := strings.Count(content[:s.start], "\n") + 1
+		lineSpan := strings.Count(content[s.start:s.end], "\n") + 1
+		result.SQLBytes += s.length
+		if s.length > result.LargestString {
+			result.LargestString = s.length
+		}
+		result.Issues = append(result.Issues, models.Issue{
+			Description: fmt.Sprintf("Inline SQL string of %d characters (max %d); move it to a query builder or repository", s.length, maxLength),
+			RuleID:      RuleLongQuery,
+			Category:    models.CategorySecurity,
+			Line:        startLine,
+			Severity:    "minor",
+			Metadata: &models.IssueMetadata{
+				Bytes:         s.length,
+				LineSpan:      lineSpan,
+				EffortMinutes: analyzers.ExtractionEffort(lineSpan),
+			},
+		})
+	}
+
+	if len(result.Issues) == 0 {
+		return nil
+	}
+	return result
+}
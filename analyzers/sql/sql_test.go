@@ -0,0 +1,108 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInlineSQLRule_Apply(t *testing.T) {
+	longColumns := strings.Repeat("t.column_name, ", 10)
+
+	tests := []struct {
+		name    string
+		php     bool
+		content string
+		lines   []int // Expected issue lines
+	}{
+		{
+			name:    "Short query",
+			php:     true,
+			content: "<?php\n$db->query('SELECT id FROM users');\n",
+		},
+		{
+			name:    "Long PHP query",
+			php:     true,
+			content: "<?php\n\n$db->query(\"SELECT " + longColumns + "t.id FROM things t WHERE t.id = ?\");\n",
+			lines:   []int{3},
+		},
+		{
+			name:    "Concatenated PHP query",
+			php:     true,
+			content: "<?php\n$sql = 'SELECT " + longColumns + "' .\n    't.id FROM things t ' .\n    'WHERE t.id = ?';\n",
+			lines:   []int{2},
+		},
+		{
+			name:    "Interpolation breaks the chain",
+			php:     true,
+			content: "<?php\n$sql = 'SELECT ' . $columns . ' FROM things WHERE id = ?';\n",
+		},
+		{
+			name:    "Heredoc",
+			php:     true,
+			content: "<?php\n$sql = <<<SQL\n    SELECT " + longColumns + "\n    t.id FROM things t\n    SQL;\n",
+			lines:   []int{2},
+		},
+		{
+			name:    "Commented out query",
+			php:     true,
+			content: "<?php\n// $db->query('SELECT " + longColumns + "t.id FROM things t');\n# 'SELECT " + longColumns + " FROM x'\n",
+		},
+		{
+			name:    "Outside PHP tags",
+			php:     true,
+			content: "<p>'SELECT " + longColumns + "t.id FROM things t'</p>\n<?php echo 1; ?>\n",
+		},
+		{
+			name:    "Long prose",
+			content: "const help = '" + strings.Repeat("Select the files you want and update your settings. ", 8) + "';\n",
+		},
+		{
+			name:    "JS template literal",
+			content: "const a = 1;\nconst q = `\n  UPDATE things\n  SET " + longColumns + "\n  WHERE id = $1`;\n",
+			lines:   []int{2},
+		},
+		{
+			name:    "JS concatenation",
+			content: "db.query('INSERT INTO things (" + longColumns + "id) ' +\n  'VALUES ($1)');\n",
+			lines:   []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &InlineSQLRule{MaxLength: 100, PHP: tt.php}
+			result := rule.Apply(tt.content)
+
+			var lines []int
+			if result != nil {
+				for _, issue := range result.(InlineSQLFinding).Issues {
+					lines = append(lines, issue.Line)
+				}
+			}
+			if len(lines) != len(tt.lines) {
+				t.Fatalf("expected issues on lines %v, got %v", tt.lines, lines)
+			}
+			for i := range lines {
+				if lines[i] != tt.lines[i] {
+					t.Errorf("expected issues on lines %v, got %v", tt.lines, lines)
+				}
+			}
+		})
+	}
+}
+
+func TestInlineSQLRule_Metadata(t *testing.T) {
+	content := "<?php\n$sql = <<<'SQL'\nSELECT *\nFROM things\nWHERE id = ?\nSQL;\n"
+	result := (&InlineSQLRule{MaxLength: 10, PHP: true}).Apply(content)
+	if result == nil {
+		t.Fatal("expected a finding")
+	}
+
+	issue := result.(InlineSQLFinding).Issues[0]
+	if issue.Metadata.Bytes != len("SELECT *\nFROM things\nWHERE id = ?") {
+		t.Errorf("unexpected bytes %d", issue.Metadata.Bytes)
+	}
+	if issue.Metadata.LineSpan != 5 {
+		t.Errorf("expected the heredoc to span 5 lines, got %d", issue.Metadata.LineSpan)
+	}
+}
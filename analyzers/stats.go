@@ -0,0 +1,77 @@
+package analyzers
+
+import (
+	"sync"
+	"time"
+
+	"code-analyzer/models"
+)
+
+// Stats counts the files an analyzer read, the bytes it read from them and
+// the files it skipped, and times the run. A nil *Stats is valid and counts
+// nothing.
+type Stats struct {
+	mu              sync.Mutex
+	started         time.Time
+	analyzed        int
+	bytesRead       int64
+	skippedTooLarge int
+	excluded        int
+}
+
+// NewStats returns Stats timing a run that starts now
+func NewStats() *Stats {
+	return &Stats{started: time.Now()}
+}
+
+// Analyzed records a file handed to the analyzer's rules, of which it reads
+// bytes
+func (s *Stats) Analyzed(bytes int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.analyzed++
+	s.bytesRead += bytes
+}
+
+// SkippedTooLarge records a file skipped for exceeding max_file_size
+func (s *Stats) SkippedTooLarge() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skippedTooLarge++
+}
+
+// Excluded records a file skipped for matching an exclude pattern
+func (s *Stats) Excluded() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.excluded++
+}
+
+// Summary returns the counts and the time elapsed since NewStats, or nil
+// for a nil Stats
+func (s *Stats) Summary() *models.AnalyzerStats {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summary := &models.AnalyzerStats{
+		FilesAnalyzed:   s.analyzed,
+		BytesRead:       s.bytesRead,
+		SkippedTooLarge: s.skippedTooLarge,
+		SkippedExcluded: s.excluded,
+	}
+	if !s.started.IsZero() {
+		summary.DurationMS = time.Since(s.started).Milliseconds()
+	}
+	return summary
+}
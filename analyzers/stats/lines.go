@@ -0,0 +1,181 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"code-analyzer/models"
+)
+
+// syntax is the comment syntax of a language
+type syntax struct {
+	language     string
+	lineComments []string
+	blocks       [][2]string // Start and end delimiters of block comments
+	// attributes marks PHP, where "#[" starts an attribute, not a comment
+	attributes bool
+}
+
+var (
+	cStyle   = [][2]string{{"/*", "*/"}}
+	htmlLike = [][2]string{{"<!--", "-->"}}
+)
+
+// languages maps file extensions to the syntax of their language
+var languages = map[string]syntax{
+	".php":  {language: "PHP", lineComments: []string{"//", "#"}, blocks: cStyle, attributes: true},
+	".js":   {language: "JavaScript", lineComments: []string{"//"}, blocks: cStyle},
+	".mjs":  {language: "JavaScript", lineComments: []string{"//"}, blocks: cStyle},
+	".cjs":  {language: "JavaScript", lineComments: []string{"//"}, blocks: cStyle},
+	".jsx":  {language: "JavaScript", lineComments: []string{"//"}, blocks: cStyle},
+	".ts":   {language: "TypeScript", lineComments: []string{"//"}, blocks: cStyle},
+	".tsx":  {language: "TypeScript", lineComments: []string{"//"}, blocks: cStyle},
+	".vue":  {language: "Vue", lineComments: []string{"//"}, blocks: append(append([][2]string{}, htmlLike...), cStyle...)},
+	".html": {language: "HTML", blocks: htmlLike},
+	".htm":  {language: "HTML", blocks: htmlLike},
+	".xml":  {language: "XML", blocks: htmlLike},
+	".css":  {language: "CSS", blocks: cStyle},
+	".scss": {language: "SCSS", lineComments: []string{"//"}, blocks: cStyle},
+	".less": {language: "Less", lineComments: []string{"//"}, blocks: cStyle},
+	".sql":  {language: "SQL", lineComments: []string{"--"}, blocks: cStyle},
+	".go":   {language: "Go", lineComments: []string{"//"}, blocks: cStyle},
+	".java": {language: "Java", lineComments: []string{"//"}, blocks: cStyle},
+	".py":   {language: "Python", lineComments: []string{"#"}},
+	".rb":   {language: "Ruby", lineComments: []string{"#"}},
+	".sh":   {language: "Shell", lineComments: []string{"#"}},
+	".yml":  {language: "YAML", lineComments: []string{"#"}},
+	".yaml": {language: "YAML", lineComments: []string{"#"}},
+	".json": {language: "JSON"},
+}
+
+// languageOf returns the syntax of the file's language
+func languageOf(path string) (syntax, bool) {
+	s, ok := languages[strings.ToLower(filepath.Ext(path))]
+	return s, ok
+}
+
+// LineCountRule counts the code, comment and blank lines of a file. A line
+// holding code and a comment counts as code; blank lines inside block
+// comments count as blank. Comment markers inside strings are not
+// recognized, which rarely matters for totals.
+type LineCountRule struct {
+	syntax syntax
+}
+
+// Name returns the rule name
+func (r *LineCountRule) Name() string {
+	return "Line Counter"
+}
+
+// Apply counts the lines of content
+func (r *LineCountRule) Apply(content string) interface{} {
+	counts, _ := r.ApplyReader(strings.NewReader(content))
+	return counts
+}
+
+// ApplyReader counts the lines read from r, streaming them. It returns nil
+// for binary files.
+func (r *LineCountRule) ApplyReader(reader io.Reader) (interface{}, error) {
+	buffered := bufio.NewReaderSize(reader, 64*1024)
+	head, _ := buffered.Peek(8000)
+	if bytes.IndexByte(head, 0) != -1 {
+		return nil, nil
+	}
+
+	counts := models.LineCounts{Files: 1}
+	inBlock := ""
+	var line []byte
+	for {
+		chunk, err := buffered.ReadSlice('\n')
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if len(line) > 0 {
+			inBlock = r.countLine(&counts, string(line), inBlock)
+			line = line[:0]
+		}
+		if err == io.EOF {
+			return counts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// countLine classifies one line and returns the end delimiter of the block
+// comment still open after it, if any
+func (r *LineCountRule) countLine(counts *models.LineCounts, line, inBlock string) string {
+	if strings.TrimSpace(line) == "" {
+		counts.Blank++
+		return inBlock
+	}
+
+	code, comment := false, false
+	rest := line
+	for rest != "" {
+		if inBlock != "" {
+			comment = true
+			end := strings.Index(rest, inBlock)
+			if end == -1 {
+				break
+			}
+			rest, inBlock = rest[end+len(inBlock):], ""
+			continue
+		}
+
+		start, marker, block := r.nextComment(rest)
+		if strings.TrimSpace(rest[:start]) != "" {
+			code = true
+		}
+		if marker == "" {
+			break
+		}
+		comment = true
+		if block == "" {
+			break
+		}
+		rest, inBlock = rest[start+len(marker):], block
+	}
+
+	switch {
+	case code:
+		counts.Code++
+	case comment:
+		counts.Comment++
+	default:
+		counts.Blank++
+	}
+	return inBlock
+}
+
+// nextComment finds the first comment marker in s. It returns its offset
+// (len(s) when there is none), the marker and, for block comments, the end
+// delimiter.
+func (r *LineCountRule) nextComment(s string) (offset int, marker, blockEnd string) {
+	offset = len(s)
+	for _, prefix := range r.syntax.lineComments {
+		i := strings.Index(s, prefix)
+		for i != -1 && r.syntax.attributes && prefix == "#" && strings.HasPrefix(s[i:], "#[") {
+			next := strings.Index(s[i+1:], prefix)
+			if next == -1 {
+				i = -1
+				break
+			}
+			i += 1 + next
+		}
+		if i != -1 && i < offset {
+			offset, marker, blockEnd = i, prefix, ""
+		}
+	}
+	for _, block := range r.syntax.blocks {
+		if i := strings.Index(s, block[0]); i != -1 && i < offset {
+			offset, marker, blockEnd = i, block[0], block[1]
+		}
+	}
+	return offset, marker, blockEnd
+}
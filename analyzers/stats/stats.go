@@ -0,0 +1,248 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// StatsAnalyzer counts code, comment and blank lines per language and per
+// top-level directory. It reports no issues.
+type StatsAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewStatsAnalyzer creates a new stats analyzer
+func NewStatsAnalyzer() *StatsAnalyzer {
+	return &StatsAnalyzer{
+		rules: []analyzers.Rule{
+			&LineCountRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *StatsAnalyzer) Name() string {
+	return "Stats Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *StatsAnalyzer) Description() string {
+	return "Counts code, comment and blank lines per language and directory"
+}
+
+// Run executes the line counting
+func (a *StatsAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	skipped := []models.SkippedFile{}
+	languages := make(map[string]*models.LanguageStats)
+	directories := make(map[string]*models.DirectoryStats)
+	var total models.LineCounts
+
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		s, _ := languageOf(path)
+		counts := a.countFile(path, s, config.Diagnostics)
+		if counts == nil {
+			config.Tracef(path, "analyzed, binary")
+			return nil
+		}
+		config.Tracef(path, "counted, %d code lines", counts.Code)
+
+		l, ok := languages[s.language]
+		if !ok {
+			l = &models.LanguageStats{Language: s.language}
+			languages[s.language] = l
+		}
+		add(&l.LineCounts, *counts)
+
+		name := topLevelDirectory(config.RootDir, path)
+		d, ok := directories[name]
+		if !ok {
+			d = &models.DirectoryStats{Directory: name}
+			directories[name] = d
+		}
+		add(&d.LineCounts, *counts)
+		add(&total, *counts)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := models.LanguageStatsReport{
+		Timestamp:     utils.GetTimestamp(),
+		ScanDirectory: config.RootDir,
+		Total:         total,
+		Languages:     []models.LanguageStats{},
+		Directories:   []models.DirectoryStats{},
+		Stats:         config.Stats.Summary(),
+	}
+	for _, l := range languages {
+		l.CommentRatio = commentRatio(l.LineCounts)
+		report.Languages = append(report.Languages, *l)
+	}
+	sort.Slice(report.Languages, func(i, j int) bool {
+		if report.Languages[i].Code != report.Languages[j].Code {
+			return report.Languages[i].Code > report.Languages[j].Code
+		}
+		return report.Languages[i].Language < report.Languages[j].Language
+	})
+	for _, d := range directories {
+		d.CommentRatio = commentRatio(d.LineCounts)
+		report.Directories = append(report.Directories, *d)
+	}
+	sort.Slice(report.Directories, func(i, j int) bool {
+		if report.Directories[i].Code != report.Directories[j].Code {
+			return report.Directories[i].Code > report.Directories[j].Code
+		}
+		return report.Directories[i].Directory < report.Directories[j].Directory
+	})
+
+	// Limit directories to top N; languages are few and always listed
+	if len(report.Directories) > config.TopN {
+		report.Directories = report.Directories[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := config.WriteArtifact(report); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return nil, nil
+	}
+	analyzers.PrintSkipped(skipped)
+	a.printResults(report)
+	return nil, nil
+}
+
+// Handles reports whether path is in a language the analyzer knows, or
+// matches the configured extensions when set
+func (a *StatsAnalyzer) Handles(path string, config analyzers.Config) bool {
+	if _, ok := languageOf(path); !ok {
+		return false
+	}
+	if len(config.Extensions) == 0 {
+		return true
+	}
+	lower := strings.ToLower(path)
+	for _, ext := range config.Extensions {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *StatsAnalyzer) countFile(path string, s syntax, diags *analyzers.Diagnostics) *models.LineCounts {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	rule := &LineCountRule{syntax: s}
+	finding, err := analyzers.ApplyStreamRule(rule, path, file, diags)
+	if err != nil || finding == nil {
+		return nil
+	}
+	counts := finding.(models.LineCounts)
+	return &counts
+}
+
+// add adds the counts of b to a
+func add(a *models.LineCounts, b models.LineCounts) {
+	a.Files += b.Files
+	a.Code += b.Code
+	a.Comment += b.Comment
+	a.Blank += b.Blank
+}
+
+// commentRatio returns the comment lines per 100 code and comment lines,
+// rounded to one decimal
+func commentRatio(c models.LineCounts) float64 {
+	if c.Code+c.Comment == 0 {
+		return 0
+	}
+	return math.Round(float64(c.Comment)/float64(c.Code+c.Comment)*1000) / 10
+}
+
+// topLevelDirectory returns the first directory of path below root, "." for
+// files directly in root
+func topLevelDirectory(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = path
+	}
+	if first, _, ok := strings.Cut(filepath.ToSlash(rel), "/"); ok {
+		return first
+	}
+	return "."
+}
+
+func (a *StatsAnalyzer) printResults(report models.LanguageStatsReport) {
+	if len(report.Languages) == 0 {
+		fmt.Println("✅ No source files found!")
+		return
+	}
+
+	fmt.Printf("Counted %d files\n\n", report.Total.Files)
+
+	fmt.Printf("%-30s %8s %10s %10s %10s %9s\n", "Language", "Files", "Blank", "Comment", "Code", "Comment%")
+	fmt.Println(strings.Repeat("-", 82))
+	for _, l := range report.Languages {
+		fmt.Printf("%-30s %8d %10d %10d %10d %8.1f%%\n",
+			l.Language, l.Files, l.Blank, l.Comment, l.Code, l.CommentRatio)
+	}
+	fmt.Println(strings.Repeat("-", 82))
+	fmt.Printf("%-30s %8d %10d %10d %10d %8.1f%%\n",
+		"Total", report.Total.Files, report.Total.Blank, report.Total.Comment, report.Total.Code, commentRatio(report.Total))
+
+	fmt.Println()
+	fmt.Printf("%-30s %8s %10s %10s %10s %9s\n", "Directory", "Files", "Blank", "Comment", "Code", "Comment%")
+	fmt.Println(strings.Repeat("-", 82))
+	for _, d := range report.Directories {
+		fmt.Printf("%-30s %8d %10d %10d %10d %8.1f%%\n",
+			utils.Truncate(d.Directory, 30), d.Files, d.Blank, d.Comment, d.Code, d.CommentRatio)
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestLineCountRule_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		expected models.LineCounts
+	}{
+		{
+			name:     "PHP line and block comments",
+			path:     "a.php",
+			content:  "<?php\n// note\n# hash\n\n/**\n * Doc\n\n */\n$a = 1; // trailing\n",
+			expected: models.LineCounts{Files: 1, Code: 2, Comment: 5, Blank: 2},
+		},
+		{
+			name:     "PHP attribute is code",
+			path:     "a.php",
+			content:  "#[Route('/')]\nfunction a() {}\n",
+			expected: models.LineCounts{Files: 1, Code: 2},
+		},
+		{
+			name:     "Code after a closing block comment",
+			path:     "a.js",
+			content:  "/* start\nend */ let a = 1;\n/* a */ /* b */\n",
+			expected: models.LineCounts{Files: 1, Code: 1, Comment: 2},
+		},
+		{
+			name:     "HTML comments",
+			path:     "a.html",
+			content:  "<!-- a -->\n<p>x</p>\n<!--\nb\n-->",
+			expected: models.LineCounts{Files: 1, Code: 1, Comment: 4},
+		},
+		{
+			name:     "SQL dashes",
+			path:     "a.sql",
+			content:  "-- query\nSELECT 1;\r\n\r\n",
+			expected: models.LineCounts{Files: 1, Code: 1, Comment: 1, Blank: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := languageOf(tt.path)
+			if !ok {
+				t.Fatalf("no language for %s", tt.path)
+			}
+			rule := &LineCountRule{syntax: s}
+			result := rule.Apply(tt.content)
+			if result == nil {
+				t.Fatal("expected counts, got nil")
+			}
+			if got := result.(models.LineCounts); got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLineCountRule_Binary(t *testing.T) {
+	rule := &LineCountRule{syntax: languages[".js"]}
+	if result := rule.Apply("a\x00b\n"); result != nil {
+		t.Errorf("expected nil for binary content, got %+v", result)
+	}
+}
+
+func TestCommentRatio(t *testing.T) {
+	if got := commentRatio(models.LineCounts{Code: 2, Comment: 1}); got != 33.3 {
+		t.Errorf("expected 33.3, got %v", got)
+	}
+	if got := commentRatio(models.LineCounts{Blank: 3}); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+}
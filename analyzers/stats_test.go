@@ -0,0 +1,23 @@
+package analyzers
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	s := NewStats()
+	s.Analyzed(100)
+	s.Analyzed(20)
+	s.SkippedTooLarge()
+	s.Excluded()
+	summary := s.Summary()
+	if summary.FilesAnalyzed != 2 || summary.BytesRead != 120 || summary.SkippedTooLarge != 1 || summary.SkippedExcluded != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	var nilStats *Stats
+	nilStats.Analyzed(10)
+	nilStats.SkippedTooLarge()
+	nilStats.Excluded()
+	if nilStats.Summary() != nil {
+		t.Error("a nil Stats must count nothing")
+	}
+}
@@ -0,0 +1,175 @@
+package structured
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"code-analyzer/models"
+)
+
+// jsonFrame is an open object or array
+type jsonFrame struct {
+	object    bool
+	keys      map[string]int // Line of each key of an object
+	expectKey bool
+}
+
+// checkJSON reads the JSON document token by token and reports duplicate
+// object keys, and the first syntax error, which ends the check
+func checkJSON(content string) []models.Issue {
+	var issues []models.Issue
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+
+	var stack []*jsonFrame
+	values := 0
+	// valueDone is called after a value: an object expects its next key
+	valueDone := func() {
+		if n := len(stack); n > 0 {
+			stack[n-1].expectKey = stack[n-1].object
+		} else {
+			values++
+		}
+	}
+
+	for {
+		before := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if len(stack) > 0 {
+				return append(issues, syntaxIssue(FormatJSON, lineAt(content, len(content)), "unexpected end of input"))
+			}
+			return issues
+		}
+		if err != nil {
+			return append(issues, jsonSyntaxIssue(content, err, before))
+		}
+		offset := int(dec.InputOffset())
+		if len(stack) == 0 && values > 0 {
+			return append(issues, syntaxIssue(FormatJSON, lineAt(content, offset), "unexpected data after the top-level value"))
+		}
+
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				stack = append(stack, &jsonFrame{object: t == '{', keys: map[string]int{}, expectKey: t == '{'})
+			default:
+				stack = stack[:len(stack)-1]
+				valueDone()
+			}
+		case string:
+			if n := len(stack); n > 0 && stack[n-1].expectKey {
+				frame := stack[n-1]
+				frame.expectKey = false
+				line := lineAt(content, offset)
+				if first, ok := frame.keys[t]; ok {
+					issues = append(issues, duplicateIssue(t, line, first))
+				} else {
+					frame.keys[t] = line
+				}
+				continue
+			}
+			valueDone()
+		default:
+			valueDone()
+		}
+	}
+}
+
+// jsonSyntaxIssue turns a decoding error into an issue at the line it
+// happened on
+func jsonSyntaxIssue(content string, err error, offset int64) models.Issue {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return syntaxIssue(FormatJSON, lineAt(content, len(content)), "unexpected end of input")
+	}
+	return syntaxIssue(FormatJSON, lineAt(content, int(offset)), err.Error())
+}
+
+// lineAt returns the 1-based line of a byte offset
+func lineAt(content string, offset int) int {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	// The offset is just past the byte that ended the token or error
+	if offset > 0 && content[offset-1] == '\n' {
+		offset--
+	}
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+// stripJSONC blanks the comments and trailing commas of a JSON-with-comments
+// document, keeping newlines so lines stay the same
+func stripJSONC(content string) string {
+	b := []byte(content)
+	// Comments first, so a comment between a comma and a bracket is gone
+	// when trailing commas are looked for
+	forEachOutsideStrings(b, func(i int) int {
+		switch {
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			end := i
+			for end < len(b) && b[end] != '\n' {
+				end++
+			}
+			return blankRange(b, i, end)
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			end := len(b)
+			if stop := strings.Index(string(b[i+2:]), "*/"); stop >= 0 {
+				end = i + 2 + stop + 2
+			}
+			return blankRange(b, i, end)
+		}
+		return i + 1
+	})
+	forEachOutsideStrings(b, func(i int) int {
+		if b[i] == ',' {
+			j := i + 1
+			for j < len(b) && (b[j] == ' ' || b[j] == '\t' || b[j] == '\r' || b[j] == '\n') {
+				j++
+			}
+			if j < len(b) && (b[j] == '}' || b[j] == ']') {
+				b[i] = ' '
+			}
+		}
+		return i + 1
+	})
+	return string(b)
+}
+
+// forEachOutsideStrings calls visit with the index of every byte outside
+// JSON strings; visit returns the index to continue from
+func forEachOutsideStrings(b []byte, visit func(i int) int) {
+	inString := false
+	for i := 0; i < len(b); {
+		switch {
+		case inString:
+			if b[i] == '\\' {
+				i++
+			} else if b[i] == '"' || b[i] == '\n' {
+				inString = false
+			}
+			i++
+		case b[i] == '"':
+			inString = true
+			i++
+		default:
+			i = visit(i)
+		}
+	}
+}
+
+// blankRange replaces b[start:end] but newlines with spaces and returns end
+func blankRange(b []byte, start, end int) int {
+	for i := start; i < end; i++ {
+		if b[i] != '\n' {
+			b[i] = ' '
+		}
+	}
+	return end
+}
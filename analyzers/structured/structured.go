@@ -0,0 +1,315 @@
+package structured
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/analyzers/conflicts"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Rule IDs of the structured analyzer's issues
+const (
+	RuleSyntaxError    = "structured/syntax-error"
+	RuleDuplicateKey   = "structured/duplicate-key"
+	RuleConflictMarker = "structured/conflict-marker"
+)
+
+// Formats of the files the analyzer parses
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// StructuredAnalyzer parses JSON and YAML files and reports the ones that
+// are broken
+type StructuredAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewStructuredAnalyzer creates a new JSON/YAML validity analyzer
+func NewStructuredAnalyzer() *StructuredAnalyzer {
+	return &StructuredAnalyzer{
+		rules: []analyzers.Rule{
+			&ValidityRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *StructuredAnalyzer) Name() string {
+	return "Structured Config Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *StructuredAnalyzer) Description() string {
+	return "Reports JSON and YAML files with syntax errors, duplicate keys or merge conflict markers"
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *StructuredAnalyzer) RuleIDs() []string {
+	return []string{RuleSyntaxError, RuleDuplicateKey, RuleConflictMarker}
+}
+
+// Run executes the JSON/YAML validity analysis
+func (a *StructuredAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	results := []models.StructuredFileAnalysis{}
+	skipped := []models.SkippedFile{}
+	var allIssues []models.Issue
+
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, config.Diagnostics)
+		if analysis != nil {
+			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
+			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return allIssues, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+
+	// Limit to top N
+	if len(results) > config.TopN {
+		results = results[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintSkipped(skipped)
+	a.printResults(results)
+	return allIssues, nil
+}
+
+// Handles reports whether path has one of the configured extensions (.json,
+// .yaml and .yml by default)
+func (a *StructuredAnalyzer) Handles(path string, config analyzers.Config) bool {
+	if len(config.Extensions) == 0 {
+		return formatOf(path) != ""
+	}
+	lower := strings.ToLower(path)
+	for _, ext := range config.Extensions {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatOf returns the format of path by extension, "" if unknown
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return ""
+}
+
+func (a *StructuredAnalyzer) analyzeFile(path string, diags *analyzers.Diagnostics) *models.StructuredFileAnalysis {
+	content, encoding, err := utils.ReadText(path)
+	if err != nil || encoding == utils.EncodingBinary {
+		return nil
+	}
+
+	format := formatOf(path)
+	if format == "" {
+		// A configured extension of no known format, e.g. .babelrc
+		format = FormatJSON
+	}
+	rule := &ValidityRule{Format: format, Lenient: isJSONC(path)}
+	finding := analyzers.ApplyRule(rule, path, content, diags)
+	if finding == nil {
+		return nil
+	}
+
+	issues := finding.([]models.Issue)
+	for i := range issues {
+		issues[i].Path = path
+	}
+	return &models.StructuredFileAnalysis{
+		Path:   path,
+		Format: format,
+		Issues: issues,
+	}
+}
+
+// isJSONC reports whether path is a JSON file that tools read with comments
+// and trailing commas allowed, such as tsconfig.json and VS Code settings
+func isJSONC(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	if strings.HasPrefix(base, "tsconfig") || strings.HasPrefix(base, "jsconfig") {
+		return true
+	}
+	return filepath.Base(filepath.Dir(path)) == ".vscode"
+}
+
+func (a *StructuredAnalyzer) printResults(results []models.StructuredFileAnalysis) {
+	if len(results) == 0 {
+		fmt.Println("✅ All JSON and YAML files parse cleanly!")
+		return
+	}
+
+	fmt.Printf("Found %d broken JSON/YAML files\n\n", len(results))
+
+	fmt.Printf("%-60s %6s  %s\n", "File", "Line", "Problem")
+	fmt.Println(strings.Repeat("-", 110))
+
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			fmt.Printf("%-60s %6d  %s\n", utils.Truncate(result.Path, 60), issue.Line, issue.Description)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
+
+func (a *StructuredAnalyzer) generateArtifact(results []models.StructuredFileAnalysis, skipped []models.SkippedFile, config analyzers.Config) error {
+	report := models.StructuredAnalysisReport{
+		Timestamp:       utils.GetTimestamp(),
+		ScanDirectory:   config.RootDir,
+		TotalFiles:      len(results),
+		Results:         results,
+		SkippedTooLarge: skipped,
+		Stats:           config.Stats.Summary(),
+	}
+
+	return config.WriteArtifact(report)
+}
+
+// ValidityRule parses a JSON or YAML document and reports syntax errors,
+// duplicate keys and leftover merge conflict markers. A file with conflict
+// markers is not parsed: the markers are the error.
+type ValidityRule struct {
+	Format string
+	// Lenient allows comments and trailing commas in JSON
+	Lenient bool
+}
+
+func (r *ValidityRule) Name() string {
+	return "Validity Detector"
+}
+
+func (r *ValidityRule) Apply(content string) interface{} {
+	issues := conflictIssues(content)
+	if len(issues) == 0 && strings.TrimSpace(content) != "" {
+		if r.Format == FormatYAML {
+			issues = checkYAML(content)
+		} else {
+			if r.Lenient {
+				content = stripJSONC(content)
+			}
+			issues = checkJSON(content)
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// conflictIssues reports every conflict start marker, and end or separator
+// markers outside a conflict, described like the conflicts analyzer does so
+// both report one issue per marker
+func conflictIssues(content string) []models.Issue {
+	var issues []models.Issue
+	open := false
+	markers := 0
+	for i, line := range strings.Split(content, "\n") {
+		kind := conflicts.MarkerKind(line, []int{conflicts.DefaultMarkerSize})
+		if kind == 0 {
+			continue
+		}
+		if kind == '<' || !open {
+			issues = append(issues, models.Issue{
+				Description: conflicts.MarkerDescription(markers, line),
+				RuleID:      RuleConflictMarker,
+				Category:    models.CategoryBugRisk,
+				Line:        i + 1,
+				Severity:    "critical",
+				Metadata:    &models.IssueMetadata{EffortMinutes: analyzers.ConflictEffort(1)},
+			})
+		}
+		markers++
+		open = kind != '>'
+	}
+	return issues
+}
+
+// syntaxIssue is the issue of a file that does not parse
+func syntaxIssue(format string, line int, message string) models.Issue {
+	return models.Issue{
+		Description: fmt.Sprintf("Invalid %s: %s", strings.ToUpper(format), message),
+		RuleID:      RuleSyntaxError,
+		Category:    models.CategoryBugRisk,
+		Line:        line,
+		Severity:    "critical",
+		Metadata:    &models.IssueMetadata{EffortMinutes: 5},
+	}
+}
+
+// duplicateIssue is the issue of a key defined twice in the same object
+func duplicateIssue(key string, line, firstLine int) models.Issue {
+	return models.Issue{
+		Description: fmt.Sprintf("Duplicate key %q (first defined at line %d); only one value is used", key, firstLine),
+		RuleID:      RuleDuplicateKey,
+		Category:    models.CategoryBugRisk,
+		Line:        line,
+		Severity:    "major",
+		Metadata:    &models.IssueMetadata{EffortMinutes: 5},
+	}
+}
@@ -0,0 +1,147 @@
+package structured
+
+import (
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestValidityRule_Apply(t *testing.T) {
+	type want struct {
+		rule string
+		line int
+	}
+	tests := []struct {
+		name    string
+		format  string
+		lenient bool
+		content string
+		want    []want
+	}{
+		{
+			name:    "Valid JSON",
+			format:  FormatJSON,
+			content: "{\n  \"name\": \"app\",\n  \"tags\": [\"a\", {\"name\": \"b\"}]\n}\n",
+		},
+		{
+			name:    "JSON missing comma",
+			format:  FormatJSON,
+			content: "{\n  \"a\": 1\n  \"b\": 2\n}\n",
+			want:    []want{{RuleSyntaxError, 3}},
+		},
+		{
+			name:    "Truncated JSON",
+			format:  FormatJSON,
+			content: "{\n  \"a\": [1, 2\n",
+			want:    []want{{RuleSyntaxError, 2}},
+		},
+		{
+			name:    "JSON duplicate keys",
+			format:  FormatJSON,
+			content: "{\n  \"a\": {\"x\": 1},\n  \"b\": {\"x\": 2},\n  \"a\": 3\n}\n",
+			want:    []want{{RuleDuplicateKey, 4}},
+		},
+		{
+			name:    "JSON data after the value",
+			format:  FormatJSON,
+			content: "{}\n{}\n",
+			want:    []want{{RuleSyntaxError, 2}},
+		},
+		{
+			name:    "JSON with comments and trailing commas",
+			format:  FormatJSON,
+			lenient: true,
+			content: "{\n  // Strict mode\n  \"strict\": true, /* always */\n  \"paths\": [\"src/*\",],\n  \"url\": \"http://x\",\n}\n",
+		},
+		{
+			name:    "Comments in strict JSON",
+			format:  FormatJSON,
+			content: "{\n  // Strict mode\n  \"strict\": true\n}\n",
+			want:    []want{{RuleSyntaxError, 2}},
+		},
+		{
+			name:    "Valid YAML documents",
+			format:  FormatYAML,
+			content: "base: &base\n  a: 1\nprod:\n  <<: *base\n  b: 2\n---\nbase: 1\n",
+		},
+		{
+			name:    "YAML duplicate keys",
+			format:  FormatYAML,
+			content: "services:\n  web:\n    image: nginx\n    image: caddy\n  db: {}\nservices: {}\n",
+			want:    []want{{RuleDuplicateKey, 4}, {RuleDuplicateKey, 6}},
+		},
+		{
+			name:    "YAML syntax error",
+			format:  FormatYAML,
+			content: "a: 1\nb: : x\nc: 3\n",
+			want:    []want{{RuleSyntaxError, 2}},
+		},
+		{
+			name:    "Helm template",
+			format:  FormatYAML,
+			content: "{{- if .Values.enabled }}\nkind: Service\n{{- end }}\n",
+		},
+		{
+			name:    "Conflict markers",
+			format:  FormatJSON,
+			content: "{\n<<<<<<< HEAD\n  \"v\": 1\n=======\n  \"v\": 2\n>>>>>>> feature\n}\n",
+			want:    []want{{RuleConflictMarker, 2}},
+		},
+		{
+			name:    "Empty file",
+			format:  FormatYAML,
+			content: "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &ValidityRule{Format: tt.format, Lenient: tt.lenient}
+			var issues []models.Issue
+			if result := rule.Apply(tt.content); result != nil {
+				issues = result.([]models.Issue)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("expected %d issues, got %d: %+v", len(tt.want), len(issues), issues)
+			}
+			for i, w := range tt.want {
+				if issues[i].RuleID != w.rule || issues[i].Line != w.line {
+					t.Errorf("issue %d: expected %s at line %d, got %s at line %d (%s)", i, w.rule, w.line, issues[i].RuleID, issues[i].Line, issues[i].Description)
+				}
+			}
+		})
+	}
+}
+
+func TestConflictIssues_Descriptions(t *testing.T) {
+	block := "<<<<<<< HEAD\na: 1\n=======\na: 2\n>>>>>>> feature\n"
+	issues := conflictIssues(block + block + block)
+	want := []string{
+		"Merge conflict marker: <<<<<<< HEAD",
+		"Merge conflict marker: <<<<<<< HEAD",
+		// Like the conflicts analyzer, only the first five markers are quoted
+		"Merge conflict marker",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), issues)
+	}
+	for i, issue := range issues {
+		if issue.Description != want[i] {
+			t.Errorf("issue %d: expected %q, got %q", i, want[i], issue.Description)
+		}
+	}
+}
+
+func TestIsJSONC(t *testing.T) {
+	for path, want := range map[string]bool{
+		"tsconfig.json":                true,
+		"packages/tsconfig.build.json": true,
+		".vscode/settings.json":        true,
+		"package.json":                 false,
+		"composer.json":                false,
+	} {
+		if got := isJSONC(path); got != want {
+			t.Errorf("isJSONC(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
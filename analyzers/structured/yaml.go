@@ -0,0 +1,70 @@
+package structured
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"code-analyzer/models"
+)
+
+// yamlErrorRegex splits a parser error into its line and message
+var yamlErrorRegex = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// checkYAML parses every document of a YAML stream and reports duplicate
+// mapping keys, and the first syntax error, which ends the check. Files
+// holding template tags ({{ }}, e.g. Helm charts) are not YAML until
+// rendered, so their syntax errors are not reported.
+func checkYAML(content string) []models.Issue {
+	var issues []models.Issue
+	dec := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return issues
+		}
+		if err != nil {
+			if strings.Contains(content, "{{") {
+				return issues
+			}
+			line, message := 1, strings.TrimPrefix(err.Error(), "yaml: ")
+			if m := yamlErrorRegex.FindStringSubmatch(err.Error()); m != nil {
+				line, _ = strconv.Atoi(m[1])
+				message = m[2]
+			}
+			return append(issues, syntaxIssue(FormatYAML, line, message))
+		}
+		issues = append(issues, duplicateKeys(&doc)...)
+	}
+}
+
+// duplicateKeys reports the scalar keys defined twice in the same mapping,
+// anywhere under node. Merge keys (<<) may repeat; aliases are not followed.
+func duplicateKeys(node *yaml.Node) []models.Issue {
+	var issues []models.Issue
+	if node.Kind == yaml.MappingNode {
+		seen := make(map[string]int)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Kind != yaml.ScalarNode || key.Value == "<<" {
+				continue
+			}
+			if first, ok := seen[key.Value]; ok {
+				issues = append(issues, duplicateIssue(key.Value, key.Line, first))
+			} else {
+				seen[key.Value] = key.Line
+			}
+		}
+	}
+	if node.Kind != yaml.AliasNode {
+		for _, child := range node.Content {
+			issues = append(issues, duplicateKeys(child)...)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,201 @@
+package templates
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// delimiters open and close one comment syntax
+type delimiters struct {
+	open, close string
+}
+
+// engine is a template language and the comment syntaxes it understands
+type engine struct {
+	name     string
+	label    string
+	comments []delimiters
+}
+
+var (
+	htmlComment = delimiters{"<!--", "-->"}
+	jinjaLike   = delimiters{"{#", "#}"}
+	// The long form comes first: it may hold "}}" and wins at the same offset
+	handlebarsLong  = delimiters{"{{!--", "--}}"}
+	handlebarsShort = delimiters{"{{!", "}}"}
+
+	twig       = &engine{name: "twig", label: "Twig", comments: []delimiters{jinjaLike, htmlComment}}
+	jinja      = &engine{name: "jinja", label: "Jinja", comments: []delimiters{jinjaLike, htmlComment}}
+	handlebars = &engine{name: "handlebars", label: "Handlebars", comments: []delimiters{handlebarsLong, handlebarsShort, htmlComment}}
+	// anyEngine scans templates with a configured extension of no known engine
+	anyEngine = &engine{name: "template", label: "template", comments: []delimiters{handlebarsLong, handlebarsShort, jinjaLike, htmlComment}}
+)
+
+// engines maps the default extensions to their template engine
+var engines = map[string]*engine{
+	".twig":       twig,
+	".j2":         jinja,
+	".jinja":      jinja,
+	".jinja2":     jinja,
+	".hbs":        handlebars,
+	".handlebars": handlebars,
+}
+
+// engineOf returns the template engine of path by extension, nil if unknown
+func engineOf(path string) *engine {
+	return engines[strings.ToLower(filepath.Ext(path))]
+}
+
+// defaultIgnoreComments match HTML comments that carry meaning for browsers
+var defaultIgnoreComments = []string{
+	`^<!--\s*\[if\b`,      // IE conditional comment start
+	`^<!--\s*<!\[endif\]`, // downlevel-revealed conditional end
+	`<!\[endif\]\s*-->$`,  // IE conditional comment end
+}
+
+// compileIgnores compiles the default ignore patterns plus extra ones
+func compileIgnores(extra []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range append(append([]string{}, defaultIgnoreComments...), extra...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore_comments pattern %q: %v", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// codeRegex matches template tags, output expressions and HTML tags; a
+// comment holding one is commented-out code rather than prose
+var codeRegex = regexp.MustCompile(`\{%|\{\{|<[/a-zA-Z][^>]*>`)
+
+// CommentedCodeRule detects commented-out code in the comment syntaxes of a
+// template engine
+type CommentedCodeRule struct {
+	Engine *engine
+	// IgnorePatterns are matched against the full comment; matches are skipped
+	IgnorePatterns []*regexp.Regexp
+}
+
+type CommentedCodeFinding struct {
+	CommentedBytes int
+	CommentedLines int
+	LargestBlock   int
+	Issues         []models.Issue
+	Kept           []models.KeptBlock
+}
+
+func (r *CommentedCodeRule) Name() string {
+	return "Commented Code Detector"
+}
+
+func (r *CommentedCodeRule) Apply(content string) interface{} {
+	eng := r.Engine
+	if eng == nil {
+		eng = anyEngine
+	}
+
+	finding := CommentedCodeFinding{}
+	for _, c := range findComments(content, eng.comments) {
+		start, end := c.start, c.end
+		comment := content[start:end]
+		if r.isIgnored(comment) {
+			continue
+		}
+		if !codeRegex.MatchString(content[start+len(c.delims.open) : end-len(c.delims.close)]) {
+			continue
+		}
+
+		size := end - start
+		lines := strings.Count(comment, "\n") + 1
+		lineNumber := strings.Count(content[:start], "\n") + 1
+		description := fmt.Sprintf("Commented out %s code block", eng.label)
+
+		// A `{# KEEP: reason #}` line right before the block keeps it out of the metrics
+		if reason, ok := analyzers.KeepReason(analyzers.PrecedingLine(content, start)); ok {
+			finding.Kept = append(finding.Kept, models.KeptBlock{
+				Line:        lineNumber,
+				Bytes:       size,
+				Description: description,
+				Reason:      reason,
+			})
+			continue
+		}
+
+		finding.CommentedBytes += size
+		finding.CommentedLines += lines
+		if size > finding.LargestBlock {
+			finding.LargestBlock = size
+		}
+
+		issue := models.Issue{
+			Description: fmt.Sprintf("%s (%d bytes)", description, size),
+			RuleID:      RuleCommentedCode,
+			Category:    models.CategoryDeadCode,
+			Line:        lineNumber,
+			Severity:    "minor",
+			Metadata: &models.IssueMetadata{
+				Bytes:         size,
+				LineSpan:      lines,
+				EffortMinutes: analyzers.RemovalEffort(lines),
+			},
+		}
+		analyzers.SetRange(&issue, content, start, end)
+		finding.Issues = append(finding.Issues, issue)
+	}
+
+	if finding.CommentedBytes == 0 && len(finding.Kept) == 0 {
+		return nil
+	}
+	return finding
+}
+
+func (r *CommentedCodeRule) isIgnored(comment string) bool {
+	for _, re := range r.IgnorePatterns {
+		if re.MatchString(comment) {
+			return true
+		}
+	}
+	return false
+}
+
+// comment is the byte range of one closed comment
+type comment struct {
+	start, end int
+	delims     delimiters
+}
+
+// findComments returns the closed comments of content in order. Comment
+// syntaxes do not nest: a "<!--" inside "{# #}" is part of that comment.
+// An unclosed comment ends the scan.
+func findComments(content string, syntaxes []delimiters) []comment {
+	var comments []comment
+	pos := 0
+	for pos < len(content) {
+		start := -1
+		var delims delimiters
+		for _, d := range syntaxes {
+			i := strings.Index(content[pos:], d.open)
+			if i >= 0 && (start == -1 || pos+i < start) {
+				start, delims = pos+i, d
+			}
+		}
+		if start == -1 {
+			break
+		}
+		closeAt := strings.Index(content[start+len(delims.open):], delims.close)
+		if closeAt == -1 {
+			break
+		}
+		end := start + len(delims.open) + closeAt + len(delims.close)
+		comments = append(comments, comment{start: start, end: end, delims: delims})
+		pos = end
+	}
+	return comments
+}
@@ -0,0 +1,258 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// RuleCommentedCode is the rule ID of commented-out template code
+const RuleCommentedCode = "templates/commented-code"
+
+// TemplatesAnalyzer analyzes Twig, Jinja and Handlebars templates for
+// commented-out code
+type TemplatesAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewTemplatesAnalyzer creates a new template analyzer with default rules
+func NewTemplatesAnalyzer() *TemplatesAnalyzer {
+	return &TemplatesAnalyzer{
+		rules: []analyzers.Rule{
+			&CommentedCodeRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *TemplatesAnalyzer) Name() string {
+	return "Templates Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *TemplatesAnalyzer) Description() string {
+	return "Analyzes Twig, Jinja and Handlebars templates for commented-out code"
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *TemplatesAnalyzer) RuleIDs() []string {
+	return []string{RuleCommentedCode}
+}
+
+// Run executes the template analysis
+func (a *TemplatesAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	results := []models.TemplateFileAnalysis{}
+	kept := []models.KeptBlock{}
+	skipped := []models.SkippedFile{}
+	var allIssues []models.Issue
+
+	ignores, err := compileIgnores(config.IgnoreComments)
+	if err != nil {
+		return nil, err
+	}
+
+	err = utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if !a.Handles(path, config) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, ignores, config.Diagnostics)
+		if analysis != nil {
+			kept = append(kept, analysis.Kept...)
+			if analysis.CommentedBytes == 0 || analysis.CommentedBytes < config.MinValue {
+				config.Tracef(path, "not reported, below min")
+				return nil
+			}
+			if config.MinRatio > 0 && analysis.CommentRatio < config.MinRatio {
+				config.Tracef(path, "not reported, below min_ratio")
+				return nil
+			}
+			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
+			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return allIssues, err
+	}
+
+	// Sort results
+	if config.SortBy == "ratio" {
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].CommentRatio != results[j].CommentRatio {
+				return results[i].CommentRatio > results[j].CommentRatio
+			}
+			return results[i].Path < results[j].Path
+		})
+	} else {
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].CommentedBytes != results[j].CommentedBytes {
+				return results[i].CommentedBytes > results[j].CommentedBytes
+			}
+			return results[i].Path < results[j].Path
+		})
+	}
+
+	// Limit to top N
+	if len(results) > config.TopN {
+		results = results[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, kept, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintKept(kept)
+	analyzers.PrintSkipped(skipped)
+	a.printResults(results)
+	return allIssues, nil
+}
+
+// Handles reports whether path has one of the configured extensions (the
+// Twig, Jinja and Handlebars ones by default)
+func (a *TemplatesAnalyzer) Handles(path string, config analyzers.Config) bool {
+	if len(config.Extensions) == 0 {
+		return engineOf(path) != nil
+	}
+	lower := strings.ToLower(path)
+	for _, ext := range config.Extensions {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *TemplatesAnalyzer) analyzeFile(path string, ignores []*regexp.Regexp, diags *analyzers.Diagnostics) *models.TemplateFileAnalysis {
+	content, encoding, err := utils.ReadText(path)
+	if err != nil || encoding == utils.EncodingBinary {
+		return nil
+	}
+
+	engine := engineOf(path)
+	if engine == nil {
+		engine = anyEngine
+	}
+	rule := &CommentedCodeRule{Engine: engine, IgnorePatterns: ignores}
+
+	finding := analyzers.ApplyRule(rule, path, content, diags)
+	if finding == nil {
+		return nil
+	}
+
+	result := finding.(CommentedCodeFinding)
+	for i := range result.Issues {
+		result.Issues[i].Path = path
+	}
+	for i := range result.Kept {
+		result.Kept[i].Path = path
+	}
+
+	totalBytes := len(content)
+	return &models.TemplateFileAnalysis{
+		Path:           path,
+		Engine:         engine.name,
+		TotalLines:     strings.Count(content, "\n") + 1,
+		CommentedLines: result.CommentedLines,
+		CommentedBytes: result.CommentedBytes,
+		TotalBytes:     totalBytes,
+		CommentRatio:   float64(result.CommentedBytes) / float64(totalBytes) * 100,
+		LargestBlock:   result.LargestBlock,
+		Issues:         result.Issues,
+		Kept:           result.Kept,
+	}
+}
+
+func (a *TemplatesAnalyzer) printResults(results []models.TemplateFileAnalysis) {
+	if len(results) == 0 {
+		fmt.Println("✅ No templates with significant commented code found!")
+		return
+	}
+
+	totalCommented := 0
+	for _, r := range results {
+		totalCommented += r.CommentedBytes
+	}
+
+	fmt.Printf("Found %d templates with commented code\n", len(results))
+	fmt.Printf("📊 Total Commented Code: %s (%.2f KB)\n\n",
+		utils.FormatBytes(totalCommented), float64(totalCommented)/1024)
+
+	fmt.Printf("%-5s %-50s %-10s %12s %10s %8s %10s\n",
+		"Rank", "File", "Engine", "Commented", "Total", "Ratio", "Largest")
+	fmt.Println(strings.Repeat("-", 115))
+
+	for i, result := range results {
+		fmt.Printf("%-5d %-50s %-10s %12s %10s %7.1f%% %10s\n",
+			i+1, utils.Truncate(result.Path, 50), result.Engine,
+			utils.FormatBytes(result.CommentedBytes),
+			utils.FormatBytes(result.TotalBytes),
+			result.CommentRatio,
+			utils.FormatBytes(result.LargestBlock))
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
+
+func (a *TemplatesAnalyzer) generateArtifact(results []models.TemplateFileAnalysis, skipped []models.SkippedFile, kept []models.KeptBlock, config analyzers.Config) error {
+	totalCommented := 0
+	for _, r := range results {
+		totalCommented += r.CommentedBytes
+	}
+
+	report := models.TemplateAnalysisReport{
+		Timestamp:         utils.GetTimestamp(),
+		ScanDirectory:     config.RootDir,
+		TotalFiles:        len(results),
+		TotalCommented:    totalCommented,
+		SortMode:          config.SortBy,
+		MinComments:       config.MinValue,
+		Results:           results,
+		SkippedTooLarge:   skipped,
+		IntentionallyKept: kept,
+		Stats:             config.Stats.Summary(),
+	}
+
+	return config.WriteArtifact(report)
+}
@@ -0,0 +1,148 @@
+package templates
+
+import (
+	"testing"
+
+	"code-analyzer/analyzers"
+)
+
+func TestCommentedCodeRule_Apply(t *testing.T) {
+	tests := []struct {
+		name    string
+		engine  *engine
+		content string
+		lines   []int // Lines of the reported blocks
+	}{
+		{
+			name:    "Twig prose comment",
+			engine:  twig,
+			content: "{# Renders the page header #}\n<h1>{{ title }}</h1>\n",
+		},
+		{
+			name:   "Twig commented-out tags",
+			engine: twig,
+			content: `<h1>{{ title }}</h1>
+{#
+{% if user %}
+    <p>{{ user.name }}</p>
+{% endif %}
+#}
+`,
+			lines: []int{2},
+		},
+		{
+			name:    "Jinja HTML comment",
+			engine:  jinja,
+			content: "<ul>\n<!-- <li>{{ item }}</li> -->\n</ul>\n",
+			lines:   []int{2},
+		},
+		{
+			name:    "HTML comment inside a Jinja comment",
+			engine:  jinja,
+			content: "{# <!-- <b>old</b> --> #}\n",
+			lines:   []int{1},
+		},
+		{
+			name:   "Handlebars long comment",
+			engine: handlebars,
+			content: `{{!-- Sidebar --}}
+{{!--
+{{#if sidebar}}
+  {{> sidebar}}
+{{/if}}
+--}}
+`,
+			lines: []int{2},
+		},
+		{
+			name:    "Handlebars short comment",
+			engine:  handlebars,
+			content: "{{! <span class=\"badge\"> }}\n{{! just a note }}\n",
+			lines:   []int{1},
+		},
+		{
+			name:    "Jinja syntax in a Handlebars template",
+			engine:  handlebars,
+			content: "{# {{ not a comment }} #}\n",
+		},
+		{
+			name:    "IE conditional comment",
+			engine:  twig,
+			content: "<!--[if lt IE 9]><script src=\"html5shiv.js\"></script><![endif]-->\n",
+		},
+		{
+			name:    "Unclosed comment",
+			engine:  twig,
+			content: "{# {% block old %}\n",
+		},
+	}
+
+	ignores, err := compileIgnores(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &CommentedCodeRule{Engine: tt.engine, IgnorePatterns: ignores}
+			result := rule.Apply(tt.content)
+			if result == nil {
+				if len(tt.lines) > 0 {
+					t.Fatalf("expected blocks at lines %v, got nil", tt.lines)
+				}
+				return
+			}
+
+			finding := result.(CommentedCodeFinding)
+			if len(finding.Issues) != len(tt.lines) {
+				t.Fatalf("expected %d issues, got %d: %+v", len(tt.lines), len(finding.Issues), finding.Issues)
+			}
+			for i, issue := range finding.Issues {
+				if issue.Line != tt.lines[i] {
+					t.Errorf("issue %d: expected line %d, got %d", i, tt.lines[i], issue.Line)
+				}
+				if issue.RuleID != RuleCommentedCode {
+					t.Errorf("issue %d: expected rule %s, got %s", i, RuleCommentedCode, issue.RuleID)
+				}
+			}
+		})
+	}
+}
+
+func TestCommentedCodeRule_Keep(t *testing.T) {
+	content := "{# KEEP: restored for the spring campaign #}\n{# {% include 'banner.twig' %} #}\n"
+
+	rule := &CommentedCodeRule{Engine: twig}
+	result := rule.Apply(content)
+	if result == nil {
+		t.Fatal("expected a kept block")
+	}
+	finding := result.(CommentedCodeFinding)
+	if len(finding.Issues) != 0 || finding.CommentedBytes != 0 {
+		t.Errorf("expected no issues, got %+v", finding.Issues)
+	}
+	if len(finding.Kept) != 1 || finding.Kept[0].Reason != "restored for the spring campaign" {
+		t.Errorf("expected one kept block with the marker's reason, got %+v", finding.Kept)
+	}
+}
+
+func TestHandles(t *testing.T) {
+	a := NewTemplatesAnalyzer()
+	tests := []struct {
+		path       string
+		extensions []string
+		want       bool
+	}{
+		{"views/page.html.twig", nil, true},
+		{"roles/web/templates/nginx.conf.j2", nil, true},
+		{"src/card.HBS", nil, true},
+		{"views/page.html", nil, false},
+		{"templates/page.html", []string{".html"}, true},
+		{"views/page.twig", []string{".html"}, false},
+	}
+	for _, tt := range tests {
+		if got := a.Handles(tt.path, analyzers.Config{Extensions: tt.extensions}); got != tt.want {
+			t.Errorf("Handles(%q, %v) = %v, want %v", tt.path, tt.extensions, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package analyzers
+
+import (
+	"fmt"
+	"log/slog"
+
+	"code-analyzer/utils"
+)
+
+// Tracef logs a per-file decision (skip reason, outcome) when running with
+// -verbose; it does nothing otherwise
+func (c Config) Tracef(path, format string, args ...interface{}) {
+	if c.Verbose == nil {
+		return
+	}
+	if utils.JSONLogging() {
+		utils.LogAttrs(slog.LevelDebug, fmt.Sprintf(format, args...), slog.String("path", path))
+		return
+	}
+	fmt.Fprintf(c.Verbose, "   %s: %s\n", path, fmt.Sprintf(format, args...))
+}
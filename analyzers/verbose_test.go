@@ -0,0 +1,17 @@
+package analyzers
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConfig_Tracef(t *testing.T) {
+	var buf bytes.Buffer
+	Config{Verbose: &buf}.Tracef("a/b.js", "reported, %d issues", 2)
+	if got, want := buf.String(), "   a/b.js: reported, 2 issues\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Without -verbose nothing is written (and a nil writer must not panic)
+	Config{}.Tracef("a/b.js", "skipped")
+}
@@ -0,0 +1,354 @@
+package whitespace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Rule IDs of the whitespace analyzer's issues
+const (
+	RuleTrailing    = "whitespace/trailing"
+	RuleLineEndings = "whitespace/line-endings"
+	RuleIndentation = "whitespace/indentation"
+)
+
+// Check names that can be enabled per extension
+const (
+	CheckTrailing = "trailing"
+	CheckEOL      = "eol"
+	CheckIndent   = "indent"
+)
+
+// allChecks are applied when no checks are configured for an extension
+var allChecks = []string{CheckTrailing, CheckEOL, CheckIndent}
+
+// WhitespaceAnalyzer reports formatting debt: trailing whitespace, mixed line
+// endings and mixed indentation
+type WhitespaceAnalyzer struct {
+	rules []analyzers.Rule
+}
+
+// NewWhitespaceAnalyzer creates a new whitespace analyzer
+func NewWhitespaceAnalyzer() *WhitespaceAnalyzer {
+	return &WhitespaceAnalyzer{
+		rules: []analyzers.Rule{
+			&WhitespaceRule{},
+		},
+	}
+}
+
+// Name returns the analyzer name
+func (a *WhitespaceAnalyzer) Name() string {
+	return "Whitespace Analyzer"
+}
+
+// Description returns what this analyzer does
+func (a *WhitespaceAnalyzer) Description() string {
+	return "Flags trailing whitespace, mixed CRLF/LF line endings and mixed indentation"
+}
+
+// RuleIDs returns the IDs of the rules the analyzer reports
+func (a *WhitespaceAnalyzer) RuleIDs() []string {
+	return []string{RuleTrailing, RuleLineEndings, RuleIndentation}
+}
+
+// Run executes the whitespace analysis
+func (a *WhitespaceAnalyzer) Run(ctx context.Context, config analyzers.Config) ([]models.Issue, error) {
+	results := []models.WhitespaceFileAnalysis{}
+	skipped := []models.SkippedFile{}
+	var allIssues []models.Issue
+
+	err := utils.WalkContext(ctx, config.RootDir, config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		config.Progress.Tick()
+		if !config.MatchesExt(path) {
+			return nil
+		}
+		if len(config.Extensions) > 0 && !analyzers.HasExtension(path, config.Extensions) {
+			return nil
+		}
+		if utils.ShouldSkip(path, config.ExcludePaths) {
+			config.Tracef(path, "skipped, excluded")
+			config.Stats.Excluded()
+			return nil
+		}
+
+		checks := checksFor(path, config.WhitespaceChecks)
+		if len(checks) == 0 {
+			return nil
+		}
+
+		if config.TooLarge(info) {
+			config.Tracef(path, "skipped, larger than max_file_size (%s)", utils.FormatBytes(int(info.Size())))
+			config.Stats.SkippedTooLarge()
+			skipped = append(skipped, models.SkippedFile{Path: path, Bytes: info.Size()})
+			return nil
+		}
+
+		config.Stats.Analyzed(info.Size())
+		analysis := a.analyzeFile(path, checks, config.Diagnostics)
+		if analysis != nil {
+			results = append(results, *analysis)
+			config.Tracef(path, "reported, %d issues", len(analysis.Issues))
+			allIssues = append(allIssues, analysis.Issues...)
+		} else {
+			config.Tracef(path, "analyzed, nothing to report")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return allIssues, err
+	}
+
+	// Sort by number of problems
+	sort.Slice(results, func(i, j int) bool {
+		if len(results[i].Issues) != len(results[j].Issues) {
+			return len(results[i].Issues) > len(results[j].Issues)
+		}
+		if results[i].TrailingLines != results[j].TrailingLines {
+			return results[i].TrailingLines > results[j].TrailingLines
+		}
+		return results[i].Path < results[j].Path
+	})
+
+	// Limit to top N
+	if len(results) > config.TopN {
+		results = results[:config.TopN]
+	}
+
+	// Generate artifact if requested
+	if config.WritesArtifact() {
+		if err := a.generateArtifact(results, skipped, config); err != nil {
+			utils.Warnf("Warning: Failed to generate artifact: %v\n", err)
+		} else if !config.Quiet && config.OutputFile != "" {
+			fmt.Printf("✅ Artifact generated: %s\n\n", config.OutputFile)
+		}
+	}
+
+	// Print results
+	if config.Quiet {
+		return allIssues, nil
+	}
+	analyzers.PrintSkipped(skipped)
+	a.printResults(results)
+	return allIssues, nil
+}
+
+// checksFor returns the checks configured for a file's extension, falling back
+// to the "default" entry and then to all checks
+func checksFor(path string, configured map[string][]string) []string {
+	if len(configured) == 0 {
+		return allChecks
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if checks, ok := configured[ext]; ok {
+		return checks
+	}
+	if checks, ok := configured["default"]; ok {
+		return checks
+	}
+	return allChecks
+}
+
+func (a *WhitespaceAnalyzer) analyzeFile(path string, checks []string, diags *analyzers.Diagnostics) *models.WhitespaceFileAnalysis {
+	content, encoding, err := utils.ReadText(path)
+	if err != nil || encoding == utils.EncodingBinary {
+		return nil
+	}
+
+	rule := &WhitespaceRule{Checks: checks}
+	finding := analyzers.ApplyRule(rule, path, content, diags)
+	if finding == nil {
+		return nil
+	}
+
+	result := finding.(WhitespaceFinding)
+	for i := range result.Issues {
+		result.Issues[i].Path = path
+	}
+
+	return &models.WhitespaceFileAnalysis{
+		Path:          path,
+		TrailingLines: result.TrailingLines,
+		CRLFLines:     result.CRLFLines,
+		LFLines:       result.LFLines,
+		TabIndented:   result.TabIndented,
+		SpaceIndented: result.SpaceIndented,
+		Issues:        result.Issues,
+	}
+}
+
+func (a *WhitespaceAnalyzer) printResults(results []models.WhitespaceFileAnalysis) {
+	if len(results) == 0 {
+		fmt.Println("✅ No whitespace problems found!")
+		return
+	}
+
+	fmt.Printf("Found %d files with whitespace problems\n\n", len(results))
+
+	fmt.Printf("%-5s %-60s %10s %12s %14s\n",
+		"Rank", "File", "Trailing", "CRLF/LF", "Tabs/Spaces")
+	fmt.Println(strings.Repeat("-", 105))
+
+	for i, result := range results {
+		relPath := utils.Truncate(result.Path, 60)
+		fmt.Printf("%-5d %-60s %10d %12s %14s\n",
+			i+1, relPath,
+			result.TrailingLines,
+			fmt.Sprintf("%d/%d", result.CRLFLines, result.LFLines),
+			fmt.Sprintf("%d/%d", result.TabIndented, result.SpaceIndented))
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Analysis complete!")
+}
+
+func (a *WhitespaceAnalyzer) generateArtifact(results []models.WhitespaceFileAnalysis, skipped []models.SkippedFile, config analyzers.Config) error {
+	report := models.WhitespaceAnalysisReport{
+		Timestamp:       utils.GetTimestamp(),
+		ScanDirectory:   config.RootDir,
+		TotalFiles:      len(results),
+		Results:         results,
+		SkippedTooLarge: skipped,
+		Stats:           config.Stats.Summary(),
+	}
+
+	return config.WriteArtifact(report)
+}
+
+// WhitespaceRule detects whitespace hygiene problems
+type WhitespaceRule struct {
+	// Checks to run; all checks when empty
+	Checks []string
+}
+
+// WhitespaceFinding holds the whitespace statistics of a file
+type WhitespaceFinding struct {
+	TrailingLines int
+	CRLFLines     int
+	LFLines       int
+	TabIndented   int
+	SpaceIndented int
+	Issues        []models.Issue
+}
+
+func (r *WhitespaceRule) Name() string {
+	return "Whitespace Hygiene Detector"
+}
+
+func (r *WhitespaceRule) enabled(check string) bool {
+	if len(r.Checks) == 0 {
+		return true
+	}
+	for _, c := range r.Checks {
+		if c == check {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *WhitespaceRule) Apply(content string) interface{} {
+	var f WhitespaceFinding
+	firstTrailing, firstTab, firstSpace := 0, 0, 0
+	// Extent of the whitespace at the end of the first trailing line
+	trailingStart, trailingEnd := 0, 0
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lineNum := i + 1
+
+		// The last element has no terminator
+		if i < len(lines)-1 {
+			if strings.HasSuffix(line, "\r") {
+				f.CRLFLines++
+			} else {
+				f.LFLines++
+			}
+		}
+		line = strings.TrimSuffix(line, "\r")
+
+		if len(line) > 0 && (line[len(line)-1] == ' ' || line[len(line)-1] == '\t') {
+			f.TrailingLines++
+			if firstTrailing == 0 {
+				firstTrailing = lineNum
+				trailingStart = utf8.RuneCountInString(strings.TrimRight(line, " \t")) + 1
+				trailingEnd = utf8.RuneCountInString(line)
+			}
+		}
+
+		// Docblock continuation lines (" * ") are aligned with a space by convention
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || strings.HasPrefix(trimmed, "*") || len(trimmed) == len(line) {
+			continue
+		}
+		if line[0] == '\t' {
+			f.TabIndented++
+			if firstTab == 0 {
+				firstTab = lineNum
+			}
+		} else {
+			f.SpaceIndented++
+			if firstSpace == 0 {
+				firstSpace = lineNum
+			}
+		}
+	}
+
+	if r.enabled(CheckTrailing) && f.TrailingLines > 0 {
+		f.Issues = append(f.Issues, models.Issue{
+			Description: fmt.Sprintf("Trailing whitespace on %d lines", f.TrailingLines),
+			RuleID:      RuleTrailing,
+			Category:    models.CategoryStyle,
+			Line:        firstTrailing,
+			Column:      trailingStart,
+			EndLine:     firstTrailing,
+			EndColumn:   trailingEnd,
+			Severity:    "info",
+			Metadata:    &models.IssueMetadata{EffortMinutes: 1},
+		})
+	}
+	if r.enabled(CheckEOL) && f.CRLFLines > 0 && f.LFLines > 0 {
+		f.Issues = append(f.Issues, models.Issue{
+			Description: fmt.Sprintf("Mixed line endings: %d CRLF and %d LF lines", f.CRLFLines, f.LFLines),
+			RuleID:      RuleLineEndings,
+			Category:    models.CategoryStyle,
+			Line:        1,
+			Severity:    "minor",
+			Metadata:    &models.IssueMetadata{EffortMinutes: 1},
+		})
+	}
+	if r.enabled(CheckIndent) && f.TabIndented > 0 && f.SpaceIndented > 0 {
+		// Point at the first line using the minority style
+		line := firstSpace
+		if f.TabIndented < f.SpaceIndented {
+			line = firstTab
+		}
+		f.Issues = append(f.Issues, models.Issue{
+			Description: fmt.Sprintf("Mixed indentation: %d tab-indented and %d space-indented lines", f.TabIndented, f.SpaceIndented),
+			RuleID:      RuleIndentation,
+			Category:    models.CategoryStyle,
+			Line:        line,
+			Severity:    "minor",
+			Metadata:    &models.IssueMetadata{EffortMinutes: 5},
+		})
+	}
+
+	if len(f.Issues) == 0 {
+		return nil
+	}
+	return f
+}
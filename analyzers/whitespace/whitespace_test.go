@@ -0,0 +1,94 @@
+package whitespace
+
+import (
+	"testing"
+)
+
+func TestWhitespaceRule_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		checks   []string
+		expected int // Expected number of issues
+	}{
+		{
+			name:     "Clean file",
+			content:  "function a() {\n    return 1;\n}\n",
+			expected: 0,
+		},
+		{
+			name:     "Trailing whitespace",
+			content:  "a = 1;  \nb = 2;\t\n",
+			expected: 1,
+		},
+		{
+			name:     "Mixed line endings",
+			content:  "a = 1;\r\nb = 2;\n",
+			expected: 1,
+		},
+		{
+			name:     "Consistent CRLF",
+			content:  "a = 1;\r\nb = 2;\r\n",
+			expected: 0,
+		},
+		{
+			name:     "Mixed indentation",
+			content:  "if (a) {\n\tb();\n    c();\n}\n",
+			expected: 1,
+		},
+		{
+			name:     "Tabs with docblock",
+			content:  "/**\n * Doc\n */\nfunction a() {\n\treturn 1;\n}\n",
+			expected: 0,
+		},
+		{
+			name:     "Check disabled",
+			content:  "a = 1;  \n",
+			checks:   []string{CheckEOL, CheckIndent},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &WhitespaceRule{Checks: tt.checks}
+			result := rule.Apply(tt.content)
+			if result == nil {
+				if tt.expected > 0 {
+					t.Errorf("expected %d issues, got nil", tt.expected)
+				}
+				return
+			}
+
+			finding := result.(WhitespaceFinding)
+			if len(finding.Issues) != tt.expected {
+				t.Errorf("expected %d issues, got %+v", tt.expected, finding.Issues)
+			}
+		})
+	}
+}
+
+func TestWhitespaceRule_TrailingPosition(t *testing.T) {
+	finding := (&WhitespaceRule{}).Apply("a = 1;\r\nbé = 2; \t\r\n").(WhitespaceFinding)
+	issue := finding.Issues[0]
+	if issue.Line != 2 || issue.Column != 8 || issue.EndLine != 2 || issue.EndColumn != 9 {
+		t.Errorf("got %d:%d-%d:%d, want 2:8-2:9", issue.Line, issue.Column, issue.EndLine, issue.EndColumn)
+	}
+}
+
+func TestChecksFor(t *testing.T) {
+	configured := map[string][]string{
+		".md":     {CheckEOL},
+		"default": {CheckTrailing},
+	}
+
+	if checks := checksFor("README.md", configured); len(checks) != 1 || checks[0] != CheckEOL {
+		t.Errorf("expected eol check for .md, got %v", checks)
+	}
+	if checks := checksFor("app.js", configured); len(checks) != 1 || checks[0] != CheckTrailing {
+		t.Errorf("expected default checks for .js, got %v", checks)
+	}
+	if checks := checksFor("app.js", nil); len(checks) != 3 {
+		t.Errorf("expected all checks without config, got %v", checks)
+	}
+}
@@ -0,0 +1,99 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"code-analyzer/utils"
+)
+
+// Entry is a single accepted issue recorded in the baseline
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	CheckName   string `json:"check_name"`
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	Description string `json:"description"`
+	// AddedAt is the date (YYYY-MM-DD) the issue was first accepted
+	AddedAt string `json:"added_at,omitempty"`
+}
+
+// dateLayout is the format of Entry.AddedAt
+const dateLayout = "2006-01-02"
+
+// Baseline is the set of known issues that are not reported as new
+type Baseline struct {
+	Timestamp string  `json:"timestamp"`
+	Entries   []Entry `json:"entries"`
+
+	index map[string]*Entry
+}
+
+// Load reads a baseline file; a missing file yields an empty baseline
+func Load(path string) (*Baseline, error) {
+	b := &Baseline{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		b.buildIndex()
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %v", path, err)
+	}
+	b.buildIndex()
+	return b, nil
+}
+
+func (b *Baseline) buildIndex() {
+	b.index = make(map[string]*Entry, len(b.Entries))
+	for i := range b.Entries {
+		b.index[b.Entries[i].Fingerprint] = &b.Entries[i]
+	}
+}
+
+// Contains reports whether the fingerprint is part of the baseline
+func (b *Baseline) Contains(fingerprint string) bool {
+	return b.index[fingerprint] != nil
+}
+
+// AddedAt returns when the fingerprint was accepted, or "" if unknown
+func (b *Baseline) AddedAt(fingerprint string) string {
+	if e := b.index[fingerprint]; e != nil {
+		return e.AddedAt
+	}
+	return ""
+}
+
+// Expire drops entries accepted more than maxAge before now, so their issues
+// are reported again, and returns them. Entries without a date never expire.
+func (b *Baseline) Expire(maxAge time.Duration, now time.Time) []Entry {
+	var expired []Entry
+	for _, e := range b.Entries {
+		added, err := time.Parse(dateLayout, e.AddedAt)
+		if err != nil || now.Sub(added) <= maxAge {
+			continue
+		}
+		expired = append(expired, e)
+		delete(b.index, e.Fingerprint)
+	}
+	return expired
+}
+
+// Today returns the current date in the Entry.AddedAt format
+func Today() string {
+	return time.Now().Format(dateLayout)
+}
+
+// Write stores the entries as a new baseline file
+func Write(path string, entries []Entry) error {
+	return utils.WriteArtifact(path, Baseline{
+		Timestamp: utils.GetTimestamp(),
+		Entries:   entries,
+	})
+}
@@ -0,0 +1,50 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBaseline_Expire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	entries := []Entry{
+		{Fingerprint: "old", Path: "a.php", Line: 1, AddedAt: "2026-01-01"},
+		{Fingerprint: "recent", Path: "b.php", Line: 2, AddedAt: "2026-09-01"},
+		{Fingerprint: "undated", Path: "c.php", Line: 3},
+	}
+	if err := Write(path, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	expired := b.Expire(90*24*time.Hour, now)
+
+	if len(expired) != 1 || expired[0].Fingerprint != "old" {
+		t.Fatalf("expected only the old entry to expire, got %+v", expired)
+	}
+	if b.Contains("old") {
+		t.Error("expired entry is still contained")
+	}
+	if !b.Contains("recent") || !b.Contains("undated") {
+		t.Error("unexpired entries were dropped")
+	}
+	if b.AddedAt("recent") != "2026-09-01" {
+		t.Errorf("unexpected added_at %q", b.AddedAt("recent"))
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	b, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Contains("anything") || len(b.Expire(0, time.Now())) != 0 {
+		t.Error("expected an empty baseline")
+	}
+}
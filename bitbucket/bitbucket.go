@@ -0,0 +1,143 @@
+// Package bitbucket publishes Code Insights reports and annotations on a
+// commit through the Bitbucket Cloud API
+package bitbucket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Limits of the Code Insights API
+const (
+	maxAnnotations      = 1000 // Per report
+	annotationBatchSize = 100  // Per request
+)
+
+// pipelinesProxy authenticates API calls made from a Bitbucket Pipelines step
+const pipelinesProxy = "http://localhost:29418"
+
+// Report is a Code Insights report
+type Report struct {
+	Title      string  `json:"title"`
+	Details    string  `json:"details"`
+	ReportType string  `json:"report_type"`
+	Reporter   string  `json:"reporter"`
+	Link       string  `json:"link,omitempty"`
+	Result     string  `json:"result"` // PASSED or FAILED
+	Data       []Datum `json:"data"`
+}
+
+// Datum is a key figure shown on the report
+type Datum struct {
+	Title string      `json:"title"`
+	Type  string      `json:"type"` // BOOLEAN, DATE, DURATION, LINK, NUMBER, PERCENTAGE or TEXT
+	Value interface{} `json:"value"`
+}
+
+// Annotation is an issue attached to a line of the commit
+type Annotation struct {
+	ExternalID     string `json:"external_id"`
+	AnnotationType string `json:"annotation_type"` // BUG, CODE_SMELL or VULNERABILITY
+	Summary        string `json:"summary"`
+	Severity       string `json:"severity"` // CRITICAL, HIGH, MEDIUM or LOW
+	Path           string `json:"path"`
+	Line           int    `json:"line,omitempty"`
+}
+
+// Commit identifies the commit a report is attached to
+type Commit struct {
+	Workspace string
+	RepoSlug  string
+	Hash      string
+}
+
+// CommitFromEnv reads the commit of the running Bitbucket Pipelines build;
+// ok is false outside Pipelines
+func CommitFromEnv() (Commit, bool) {
+	c := Commit{
+		Workspace: os.Getenv("BITBUCKET_WORKSPACE"),
+		RepoSlug:  os.Getenv("BITBUCKET_REPO_SLUG"),
+		Hash:      os.Getenv("BITBUCKET_COMMIT"),
+	}
+	return c, c.Workspace != "" && c.RepoSlug != "" && c.Hash != ""
+}
+
+// Client calls the Bitbucket Cloud API
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient returns a client authenticated with token, or, when token is
+// empty, through the Pipelines authentication proxy
+func NewClient(token string) *Client {
+	if token != "" {
+		return &Client{
+			baseURL: "https://api.bitbucket.org/2.0",
+			token:   token,
+			http:    &http.Client{Timeout: 30 * time.Second},
+		}
+	}
+	proxy, _ := url.Parse(pipelinesProxy)
+	return &Client{
+		// The proxy only handles plain HTTP requests
+		baseURL: "http://api.bitbucket.org/2.0",
+		http: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{Proxy: http.ProxyURL(proxy)},
+		},
+	}
+}
+
+// Publish creates or replaces the report reportID on the commit and attaches
+// the annotations, at most 1000. It returns the number of annotations sent.
+func (c *Client) Publish(commit Commit, reportID string, report Report, annotations []Annotation) (int, error) {
+	reportURL := fmt.Sprintf("%s/repositories/%s/%s/commit/%s/reports/%s", c.baseURL,
+		url.PathEscape(commit.Workspace), url.PathEscape(commit.RepoSlug), url.PathEscape(commit.Hash), url.PathEscape(reportID))
+
+	if err := c.send(http.MethodPut, reportURL, report); err != nil {
+		return 0, fmt.Errorf("create report: %v", err)
+	}
+
+	if len(annotations) > maxAnnotations {
+		annotations = annotations[:maxAnnotations]
+	}
+	for start := 0; start < len(annotations); start += annotationBatchSize {
+		end := min(start+annotationBatchSize, len(annotations))
+		if err := c.send(http.MethodPost, reportURL+"/annotations", annotations[start:end]); err != nil {
+			return start, fmt.Errorf("add annotations: %v", err)
+		}
+	}
+	return len(annotations), nil
+}
+
+func (c *Client) send(method, endpoint string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %s", method, req.URL.Path, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	var report Report
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("missing token")
+		}
+		switch {
+		case req.Method == http.MethodPut && req.URL.Path == "/repositories/ws/repo/commit/abc/reports/code-analyzer":
+			_ = json.NewDecoder(req.Body).Decode(&report)
+		case req.Method == http.MethodPost && req.URL.Path == "/repositories/ws/repo/commit/abc/reports/code-analyzer/annotations":
+			var batch []Annotation
+			_ = json.NewDecoder(req.Body).Decode(&batch)
+			batches = append(batches, len(batch))
+		default:
+			t.Errorf("unexpected %s %s", req.Method, req.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("tok")
+	client.baseURL = server.URL
+
+	annotations := make([]Annotation, 1050)
+	for i := range annotations {
+		annotations[i] = Annotation{ExternalID: fmt.Sprint(i), AnnotationType: "CODE_SMELL", Summary: "x", Severity: "LOW", Path: "a.php", Line: i + 1}
+	}
+	sent, err := client.Publish(Commit{Workspace: "ws", RepoSlug: "repo", Hash: "abc"}, "code-analyzer",
+		Report{Title: "Code Analysis", ReportType: "BUG", Result: "PASSED"}, annotations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Title != "Code Analysis" || report.Result != "PASSED" {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if sent != 1000 || len(batches) != 10 || batches[0] != 100 {
+		t.Errorf("expected 1000 annotations in batches of 100, got %d in %v", sent, batches)
+	}
+}
+
+func TestCommitFromEnv(t *testing.T) {
+	t.Setenv("BITBUCKET_WORKSPACE", "ws")
+	t.Setenv("BITBUCKET_REPO_SLUG", "repo")
+	t.Setenv("BITBUCKET_COMMIT", "")
+	if _, ok := CommitFromEnv(); ok {
+		t.Error("expected no commit without BITBUCKET_COMMIT")
+	}
+	t.Setenv("BITBUCKET_COMMIT", "abc")
+	if c, ok := CommitFromEnv(); !ok || c.Hash != "abc" {
+		t.Errorf("got %+v, %v", c, ok)
+	}
+}
@@ -0,0 +1,79 @@
+package blame
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"code-analyzer/models"
+)
+
+// uncommitted is the commit git blame reports for lines not committed yet
+const uncommitted = "0000000000000000000000000000000000000000"
+
+// Annotator looks up who last changed a line, running git blame at most
+// once per file
+type Annotator struct {
+	files map[string]map[int]*models.Blame
+}
+
+// NewAnnotator creates an annotator for the repository of the current directory
+func NewAnnotator() *Annotator {
+	return &Annotator{files: make(map[string]map[int]*models.Blame)}
+}
+
+// Line returns the attribution of a 1-based line of path, or nil when the
+// file is not tracked or the line is not committed yet
+func (a *Annotator) Line(path string, line int) *models.Blame {
+	lines, ok := a.files[path]
+	if !ok {
+		out, err := exec.Command("git", "blame", "--line-porcelain", "--", path).Output()
+		if err == nil {
+			lines = Parse(string(out))
+		}
+		// Failures are cached too, so untracked files are blamed once
+		a.files[path] = lines
+	}
+	return lines[line]
+}
+
+// Parse reads `git blame --line-porcelain` output into the attribution of
+// each final line number. Uncommitted lines are left out.
+func Parse(porcelain string) map[int]*models.Blame {
+	lines := make(map[int]*models.Blame)
+	var current *models.Blame
+	line := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(porcelain))
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			// The line content ends each entry
+			if current != nil && current.Commit != uncommitted {
+				lines[line] = current
+			}
+			current = nil
+		case current == nil:
+			// Header: <commit> <original line> <final line> [<group size>]
+			fields := strings.Fields(text)
+			if len(fields) < 3 {
+				continue
+			}
+			line, _ = strconv.Atoi(fields[2])
+			current = &models.Blame{Commit: fields[0]}
+		case strings.HasPrefix(text, "author "):
+			current.Author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-mail "):
+			current.Email = strings.Trim(strings.TrimPrefix(text, "author-mail "), "<>")
+		case strings.HasPrefix(text, "author-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64); err == nil {
+				current.Date = time.Unix(sec, 0).UTC().Format("2006-01-02")
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,47 @@
+package blame
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	porcelain := `bd2e24d022f9868d2b74ed821d24b3e65739586b 1 1 2
+author Ann O
+author-mail <ann@example.com>
+author-time 1792112511
+author-tz +0000
+committer Ann O
+committer-mail <ann@example.com>
+committer-time 1792112511
+committer-tz +0000
+summary Add f
+filename f
+	a
+bd2e24d022f9868d2b74ed821d24b3e65739586b 2 2
+author Ann O
+author-mail <ann@example.com>
+author-time 1792112511
+author-tz +0000
+summary Add f
+filename f
+	b
+0000000000000000000000000000000000000000 3 3 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1792112511
+author-tz +0000
+summary Version of f from f
+filename f
+	c
+`
+
+	lines := Parse(porcelain)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 committed lines, got %d", len(lines))
+	}
+	b := lines[2]
+	if b == nil || b.Author != "Ann O" || b.Email != "ann@example.com" || b.Commit != "bd2e24d022f9868d2b74ed821d24b3e65739586b" || b.Date != "2026-10-16" {
+		t.Errorf("unexpected blame %+v", b)
+	}
+	if lines[3] != nil {
+		t.Errorf("uncommitted line should not be attributed, got %+v", lines[3])
+	}
+}
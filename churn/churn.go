@@ -0,0 +1,148 @@
+package churn
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"code-analyzer/models"
+)
+
+// RuleChurnedFile is the rule ID of churn issues
+const RuleChurnedFile = "churn/churned-file"
+
+// Analyzer is the key churn issues are reported under
+const Analyzer = "churn"
+
+// DescriptionPrefix starts every churn issue description; the signals follow
+const DescriptionPrefix = "File shows signs of repeated paste-over edits and needs refactoring: "
+
+// Defaults used when the config leaves thresholds unset
+const (
+	DefaultMinSmallBlocks = 3
+	DefaultMinSignals     = 2
+	// smallBlockLines is the line span up to which a commented block counts as small
+	smallBlockLines = 3
+)
+
+// commentedCodeAnalyzers report commented-out code blocks
+var commentedCodeAnalyzers = map[string]bool{"html": true, "php": true, "js": true}
+
+// Detector combines per-file signals from other analyzers' issues into one
+// informational "needs refactor" issue per file. The signals are:
+//   - many small commented-out blocks (repeated disable/re-enable edits)
+//   - conflict markers left in the file
+//   - commented blocks indented with tabs in some places and spaces in
+//     others, a sign of code pasted over from different sources
+type Detector struct {
+	MinSmallBlocks int
+	MinSignals     int
+}
+
+// fileSignals holds what the detector found out about one file
+type fileSignals struct {
+	smallBlocks   int
+	blockLines    []int
+	conflicts     int
+	mixedIndented bool
+}
+
+// Detect returns churn issues for the files whose signals reach MinSignals;
+// issues maps analyzer keys to their (final) issues
+func (d Detector) Detect(issues map[string][]models.Issue) []models.Issue {
+	minSmallBlocks := d.MinSmallBlocks
+	if minSmallBlocks <= 0 {
+		minSmallBlocks = DefaultMinSmallBlocks
+	}
+	minSignals := d.MinSignals
+	if minSignals <= 0 {
+		minSignals = DefaultMinSignals
+	}
+
+	files := make(map[string]*fileSignals)
+	signalsOf := func(path string) *fileSignals {
+		if files[path] == nil {
+			files[path] = &fileSignals{}
+		}
+		return files[path]
+	}
+	for analyzer, list := range issues {
+		for _, issue := range list {
+			switch {
+			case analyzer == "conflicts":
+				signalsOf(issue.Path).conflicts++
+			case commentedCodeAnalyzers[analyzer] && issue.Category == models.CategoryDeadCode:
+				s := signalsOf(issue.Path)
+				s.blockLines = append(s.blockLines, issue.Line)
+				if issue.Metadata != nil && issue.Metadata.LineSpan <= smallBlockLines {
+					s.smallBlocks++
+				}
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var result []models.Issue
+	for _, path := range paths {
+		s := files[path]
+		if len(s.blockLines) >= 2 {
+			s.mixedIndented = mixedIndentation(path, s.blockLines)
+		}
+
+		var reasons []string
+		if s.smallBlocks >= minSmallBlocks {
+			reasons = append(reasons, fmt.Sprintf("small commented-out blocks (%d)", s.smallBlocks))
+		}
+		if s.conflicts > 0 {
+			reasons = append(reasons, fmt.Sprintf("unresolved merge conflicts (%d)", s.conflicts))
+		}
+		if s.mixedIndented {
+			reasons = append(reasons, "commented blocks indented with both tabs and spaces")
+		}
+		if len(reasons) < minSignals {
+			continue
+		}
+
+		result = append(result, models.Issue{
+			Path:        path,
+			Line:        1,
+			Severity:    "info",
+			Description: DescriptionPrefix + strings.Join(reasons, ", "),
+			RuleID:      RuleChurnedFile,
+			Analyzer:    Analyzer,
+			Category:    models.CategoryMaintainability,
+			Metadata:    &models.IssueMetadata{EffortMinutes: 60},
+		})
+	}
+	return result
+}
+
+// mixedIndentation reports whether the given lines of a file include both
+// lines indented with a tab and lines indented with a space
+func mixedIndentation(path string, lines []int) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	fileLines := strings.Split(string(data), "\n")
+
+	tabs, spaces := false, false
+	for _, n := range lines {
+		if n < 1 || n > len(fileLines) {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(fileLines[n-1], "\t"):
+			tabs = true
+		case strings.HasPrefix(fileLines[n-1], " "):
+			spaces = true
+		}
+	}
+	return tabs && spaces
+}
@@ -0,0 +1,71 @@
+package churn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func small(path string, line int) models.Issue {
+	return models.Issue{Path: path, Line: line, Category: models.CategoryDeadCode, Metadata: &models.IssueMetadata{LineSpan: 1}}
+}
+
+func TestDetector_Detect(t *testing.T) {
+	dir := t.TempDir()
+	mixed := filepath.Join(dir, "mixed.js")
+	content := "a();\n\t// b();\n    // c();\n\t// d();\n"
+	if err := os.WriteFile(mixed, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := map[string][]models.Issue{
+		"js": {
+			small(mixed, 2), small(mixed, 3), small(mixed, 4),
+			small("conflicted.js", 1), small("conflicted.js", 5), small("conflicted.js", 9),
+			small("only-blocks.js", 1), small("only-blocks.js", 5), small("only-blocks.js", 9),
+		},
+		"conflicts": {
+			{Path: "conflicted.js", Line: 20},
+			{Path: "only-conflicts.php", Line: 3},
+		},
+	}
+
+	got := make(map[string]models.Issue)
+	for _, issue := range (Detector{}).Detect(issues) {
+		got[issue.Path] = issue
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 churned files, got %+v", got)
+	}
+	if !strings.Contains(got["conflicted.js"].Description, "unresolved merge conflicts (1)") {
+		t.Errorf("unexpected issue %+v", got["conflicted.js"])
+	}
+	if !strings.Contains(got[mixed].Description, "both tabs and spaces") {
+		t.Errorf("unexpected issue %+v", got[mixed])
+	}
+	if got[mixed].Severity != "info" || got[mixed].Line != 1 {
+		t.Errorf("churn issues should be informational file-level issues, got %+v", got[mixed])
+	}
+}
+
+func TestDetector_MinSignals(t *testing.T) {
+	issues := map[string][]models.Issue{
+		"php": {small("a.php", 1), small("a.php", 5), small("a.php", 9)},
+	}
+	if got := (Detector{MinSignals: 1}).Detect(issues); len(got) != 1 {
+		t.Errorf("expected one issue with min_signals 1, got %+v", got)
+	}
+
+	// Only commented-out code counts, not other issues of the same analyzers
+	complex := models.Issue{Path: "b.js", Line: 1, Category: models.CategoryComplexity, Metadata: &models.IssueMetadata{LineSpan: 1}}
+	issues = map[string][]models.Issue{"js": {complex, complex, complex}}
+	if got := (Detector{MinSignals: 1}).Detect(issues); len(got) != 0 {
+		t.Errorf("expected complexity issues to be ignored, got %+v", got)
+	}
+	if got := (Detector{MinSignals: 1, MinSmallBlocks: 4}).Detect(issues); len(got) != 0 {
+		t.Errorf("expected no issue below min_small_blocks, got %+v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Locations are where GitHub and GitLab look for a CODEOWNERS file,
+// relative to the repository root, in order
+var Locations = []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule assigns owners to the paths matching a pattern
+type Rule struct {
+	Pattern string
+	Owners  []string
+
+	regex *regexp.Regexp
+}
+
+// Ruleset is a parsed CODEOWNERS file. As on GitHub and GitLab, the last
+// matching rule wins.
+type Ruleset struct {
+	Rules []Rule
+}
+
+// Find returns the first CODEOWNERS file in the standard locations under root
+func Find(root string) (string, error) {
+	for _, location := range Locations {
+		path := filepath.Join(root, location)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no CODEOWNERS file in %s", strings.Join(Locations, ", "))
+}
+
+// Load reads and parses a CODEOWNERS file
+func Load(path string) (*Ruleset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rs, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return rs, nil
+}
+
+// Parse reads CODEOWNERS rules: a pattern followed by owners per line.
+// Comments and GitLab section headers are skipped.
+func Parse(r io.Reader) (*Ruleset, error) {
+	rs := &Ruleset{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		rule := Rule{Pattern: strings.ReplaceAll(fields[0], `\#`, "#")}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+
+		regex, err := compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %v", n, rule.Pattern, err)
+		}
+		rule.regex = regex
+		rs.Rules = append(rs.Rules, rule)
+	}
+	return rs, scanner.Err()
+}
+
+// Owners returns the owners of a slash-separated path relative to the
+// repository root; none when no rule matches or the matching rule lists none
+func (rs *Ruleset) Owners(path string) []string {
+	path = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+	for i := len(rs.Rules) - 1; i >= 0; i-- {
+		if rs.Rules[i].regex.MatchString(path) {
+			return rs.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// compile turns a gitignore-style pattern into a regular expression. A
+// pattern with a leading or inner slash is relative to the root, otherwise it
+// matches at any depth. A pattern naming a directory covers everything below
+// it, but wildcards in the last segment only match within one directory
+// (`docs/*` does not own `docs/api/index.md`).
+func compile(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+
+	last := p[strings.LastIndex(p, "/")+1:]
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.ContainsAny(last, "*?"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,43 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOwners(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`# Default owners
+*                   @org/platform
+
+[Documentation]
+docs/*              @org/docs        # top-level docs only
+*.sql               @org/data
+/app/Http/          @org/web @alice
+apps/**/legacy      @org/legacy
+/app/Http/Kernel.php
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"README.md", "@org/platform"},
+		{"docs/index.md", "@org/docs"},
+		{"docs/api/index.md", "@org/platform"},
+		{"db/migrations/001.sql", "@org/data"},
+		{"app/Http/Controllers/UserController.php", "@org/web @alice"},
+		{"./app/Http/routes.php", "@org/web @alice"},
+		{"lib/app/Http/x.php", "@org/platform"},
+		{"apps/legacy/a.js", "@org/legacy"},
+		{"apps/shop/v1/legacy/b.js", "@org/legacy"},
+		{"app/Http/Kernel.php", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(rs.Owners(tt.path), " "); got != tt.want {
+			t.Errorf("Owners(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultArtifactName is the artifact name used when `artifacts.name` is unset
+const DefaultArtifactName = "{analyzer}-analysis.json"
+
+// ArtifactTimestampLayout formats {timestamp}; it holds no characters that
+// are invalid in file names
+const ArtifactTimestampLayout = "20060102T150405Z"
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// ArtifactName returns the path of an analyzer's artifact relative to
+// `output`. Outside a monorepo project is empty; within one, a name without
+// {project} is placed in a directory named after the project.
+func (a ArtifactsConfig) ArtifactName(analyzer, project, timestamp string) string {
+	name := a.Name
+	if name == "" {
+		name = DefaultArtifactName
+	}
+	if project != "" && !strings.Contains(name, "{project}") {
+		name = project + "/" + name
+	}
+	name = strings.NewReplacer(
+		"{analyzer}", analyzer,
+		"{project}", project,
+		"{timestamp}", timestamp,
+	).Replace(name)
+	return a.compressed(path.Clean(name))
+}
+
+// CombinedName returns the path of the combined artifact relative to
+// `output`, or "" when there is none
+func (a ArtifactsConfig) CombinedName(timestamp string) string {
+	if a.Combined == "" {
+		return ""
+	}
+	return a.compressed(path.Clean(strings.ReplaceAll(a.Combined, "{timestamp}", timestamp)))
+}
+
+// compressed adds .gz to name when artifacts are gzip-compressed
+func (a ArtifactsConfig) compressed(name string) string {
+	if a.Gzip && !strings.HasSuffix(name, ".gz") {
+		return name + ".gz"
+	}
+	return name
+}
+
+// checkArtifacts reports artifact names that use unknown placeholders, would
+// write every analyzer to the same file or point outside `output`
+func checkArtifacts(a ArtifactsConfig, add func(key, format string, args ...interface{})) {
+	for _, setting := range []struct {
+		key, value string
+		known      []string
+	}{
+		{"artifacts.name", a.Name, []string{"{analyzer}", "{project}", "{timestamp}"}},
+		{"artifacts.combined", a.Combined, []string{"{timestamp}"}},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		for _, placeholder := range placeholderPattern.FindAllString(setting.value, -1) {
+			known := false
+			for _, k := range setting.known {
+				known = known || placeholder == k
+			}
+			if !known {
+				add(setting.key, "unknown placeholder %s, expected one of %s", placeholder, strings.Join(setting.known, ", "))
+			}
+		}
+		if cleaned := path.Clean(setting.value); path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			add(setting.key, "%q is not a path under `output`", setting.value)
+		}
+	}
+	if a.Name != "" && a.Combined == "" && !strings.Contains(a.Name, "{analyzer}") {
+		add("artifacts.name", "%q has no {analyzer}, so every analyzer would write the same file", a.Name)
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestArtifactName(t *testing.T) {
+	tests := []struct {
+		name, analyzer, project, want string
+	}{
+		{"", "php", "", "php-analysis.json"},
+		{"", "php", "api", "api/php-analysis.json"},
+		{"{analyzer}/{timestamp}.json", "js", "", "js/20261016T120000Z.json"},
+		{"{analyzer}/{timestamp}.json", "js", "web", "web/js/20261016T120000Z.json"},
+		{"{project}-{analyzer}.json", "html", "web", "web-html.json"},
+		{"./reports//{analyzer}.json", "sql", "", "reports/sql.json"},
+	}
+	for _, tt := range tests {
+		a := ArtifactsConfig{Name: tt.name}
+		if got := a.ArtifactName(tt.analyzer, tt.project, "20261016T120000Z"); got != tt.want {
+			t.Errorf("ArtifactName(%q, %q, %q) = %q, want %q", tt.name, tt.analyzer, tt.project, got, tt.want)
+		}
+	}
+
+	gzipped := ArtifactsConfig{Gzip: true}
+	if got := gzipped.ArtifactName("php", "", "20261016T120000Z"); got != "php-analysis.json.gz" {
+		t.Errorf("unexpected gzip artifact name %q", got)
+	}
+	gzipped.Name = "{analyzer}.json.gz"
+	if got := gzipped.ArtifactName("php", "", "20261016T120000Z"); got != "php.json.gz" {
+		t.Errorf("unexpected gzip artifact name %q", got)
+	}
+
+	if got := (ArtifactsConfig{}).CombinedName("20261016T120000Z"); got != "" {
+		t.Errorf("no combined artifact expected, got %q", got)
+	}
+	if got := (ArtifactsConfig{Combined: "analysis-{timestamp}.json"}).CombinedName("20261016T120000Z"); got != "analysis-20261016T120000Z.json" {
+		t.Errorf("unexpected combined name %q", got)
+	}
+}
+
+func TestCheckArtifacts(t *testing.T) {
+	tests := []struct {
+		artifacts ArtifactsConfig
+		want      string // substring of the problem, "" for none
+	}{
+		{ArtifactsConfig{}, ""},
+		{ArtifactsConfig{Name: "{analyzer}/{timestamp}.json"}, ""},
+		{ArtifactsConfig{Name: "report.json"}, "every analyzer"},
+		{ArtifactsConfig{Name: "report.json", Combined: "all.json"}, ""},
+		{ArtifactsConfig{Name: "{analyzer}-{date}.json"}, "unknown placeholder {date}"},
+		{ArtifactsConfig{Combined: "{analyzer}.json"}, "unknown placeholder {analyzer}"},
+		{ArtifactsConfig{Name: "../{analyzer}.json"}, "not a path under"},
+	}
+	for _, tt := range tests {
+		var problems []string
+		checkArtifacts(tt.artifacts, func(key, format string, args ...interface{}) {
+			problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+		})
+		if tt.want == "" {
+			if len(problems) > 0 {
+				t.Errorf("%+v: unexpected problems %v", tt.artifacts, problems)
+			}
+			continue
+		}
+		if len(problems) != 1 || !strings.Contains(problems[0], tt.want) {
+			t.Errorf("%+v: got %v, want a problem mentioning %q", tt.artifacts, problems, tt.want)
+		}
+	}
+}
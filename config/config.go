@@ -1,7 +1,10 @@
 package config
 
 import (
-	"os"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -9,10 +12,253 @@ import (
 // AppConfig represents the application configuration
 // AppConfig represents the application configuration
 type AppConfig struct {
-	Dir          string                    `yaml:"dir"`
-	Output       string                    `yaml:"output"`
-	GitLabReport string                    `yaml:"gitlab_report"`
-	Analyzers    map[string]AnalyzerConfig `yaml:"analyzers"`
+	Dir          string `yaml:"dir"`
+	Output       string `yaml:"output"`
+	GitLabReport string `yaml:"gitlab_report"`
+	Baseline     string `yaml:"baseline"`
+	// BaselineMaxAgeDays expires baseline entries accepted more than this many days ago (0 keeps them forever)
+	BaselineMaxAgeDays int `yaml:"baseline_max_age_days"`
+	// DiffBase is the git ref MR diffs are computed against (defaults to $CI_MERGE_REQUEST_DIFF_BASE_SHA)
+	DiffBase string `yaml:"diff_base"`
+	// GitLabReportScope is "all" (default) or "changed_lines"
+	GitLabReportScope string   `yaml:"gitlab_report_scope"`
+	Policies          []string `yaml:"policies"`
+	// Severities maps severities rules emit onto the canonical set and overrides how each output format writes them
+	Severities SeverityConfig `yaml:"severities"`
+	// TestPaths downgrades issues in test and fixture code by one severity level
+	TestPaths TestPathsConfig `yaml:"test_paths"`
+	// Artifacts names the analyzer artifacts written under `output`
+	Artifacts ArtifactsConfig `yaml:"artifacts"`
+	// HTMLReport writes a standalone HTML report with one section per analyzer
+	HTMLReport string `yaml:"html_report"`
+	// SummaryFile writes totals per analyzer and severity, durations and the gate result as JSON
+	SummaryFile string `yaml:"summary_file"`
+	// CSVReport writes every reported issue as one CSV row for spreadsheets
+	CSVReport string `yaml:"csv_report"`
+	// SARIFReport writes every reported issue as a SARIF 2.1.0 result for code scanning tools
+	SARIFReport string `yaml:"sarif_report"`
+	// SuppressionReport lists the baseline and false positive entries that matched no issue, and those that hid issues
+	SuppressionReport string `yaml:"suppression_report"`
+	// RuleDocs points findings at remediation guidance, e.g. an internal wiki
+	RuleDocs RuleDocsConfig `yaml:"rule_docs"`
+	// History appends each run's findings and totals to this JSON Lines file for `code-analyzer history`
+	History string `yaml:"history"`
+	// FalsePositives is the store of issues marked with `code-analyzer feedback mark`; they are left out of every run
+	FalsePositives string `yaml:"false_positives"`
+	// Allowlist holds time-boxed exceptions: matching issues are demoted to info until their entry expires, then fail the run
+	Allowlist string `yaml:"allowlist"`
+	// SnippetLines is the number of lines of context kept around each issue's line in its snippet (default 2, negative disables snippets)
+	SnippetLines int `yaml:"snippet_lines"`
+	// Blame attributes each reported issue to the author and commit date of its line via git blame
+	Blame bool `yaml:"blame"`
+	// OlderThanDays reports only issues first observed more than this many days ago (0 reports all)
+	OlderThanDays int `yaml:"older_than_days"`
+	// CodeOwners groups reported issues by the CODEOWNERS owners of their files
+	CodeOwners CodeOwnersConfig `yaml:"codeowners"`
+	// Ratchet fails the run only when issue counts per rule and directory go up
+	Ratchet RatchetConfig `yaml:"ratchet"`
+	// Projects splits a monorepo into projects analyzed with their own directory, excludes and analyzers
+	Projects []ProjectConfig `yaml:"projects"`
+	// FollowSymlinks walks into symlinked files and directories, each real file once
+	FollowSymlinks bool `yaml:"follow_symlinks"`
+	// UnstableInodes turns off hard link deduplication on filesystems without stable inode numbers
+	UnstableInodes bool `yaml:"unstable_inodes"`
+	// MaxFileSize is the default size limit in bytes for analyzers that read whole files (10MB when unset)
+	MaxFileSize int64 `yaml:"max_file_size"`
+	// TimeoutSeconds stops the analyzers after this many seconds; the issues
+	// found so far are still reported (no limit when unset)
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+	// Concurrency is the number of analyzers run at the same time (default 1)
+	Concurrency int `yaml:"concurrency"`
+	// MinSeverity leaves issues below this severity out of the output, the
+	// reports and the gate (all issues when unset)
+	MinSeverity string `yaml:"min_severity"`
+	// MaxIssuesPerAnalyzer caps the issues each analyzer contributes to the
+	// reports, keeping the most severe (no limit when unset)
+	MaxIssuesPerAnalyzer int `yaml:"max_issues_per_analyzer"`
+	// MRComment writes a Markdown summary comment body for MR bots
+	MRComment MRCommentConfig `yaml:"mr_comment"`
+	// BitbucketInsights publishes a Code Insights report on the commit in Bitbucket Pipelines
+	BitbucketInsights BitbucketInsightsConfig `yaml:"bitbucket_insights"`
+	// Notifications post a run summary to Slack or Teams webhooks after the analysis
+	Notifications []NotificationConfig `yaml:"notifications"`
+	// Webhook posts the unified findings report to an HTTP endpoint
+	Webhook WebhookConfig `yaml:"webhook"`
+	// Heatmap exports commented-code density per directory
+	Heatmap HeatmapConfig `yaml:"heatmap"`
+	// Debt ranks the worst files per severity and the debt per top-level directory
+	Debt DebtConfig `yaml:"debt"`
+	// Hotspots ranks files by git churn × issues
+	Hotspots HotspotsConfig `yaml:"hotspots"`
+	// Churn reports files combining several signs of repeated paste-over edits
+	Churn ChurnConfig `yaml:"churn"`
+	// CrashReporting opts in to sending anonymized tool health reports
+	CrashReporting CrashReportingConfig      `yaml:"crash_reporting"`
+	Analyzers      map[string]AnalyzerConfig `yaml:"analyzers"`
+}
+
+// CodeOwnersConfig configures ownership grouping
+type CodeOwnersConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Path is the CODEOWNERS file (default: CODEOWNERS, .github/, .gitlab/ or docs/)
+	Path string `yaml:"path"`
+	// Artifacts writes one JSON file of issues per owner into this directory
+	Artifacts string `yaml:"artifacts"`
+}
+
+// SeverityConfig configures severity mapping
+type SeverityConfig struct {
+	// Aliases maps other severities (e.g. "medium") to canonical ones, over the built-in aliases
+	Aliases map[string]string `yaml:"aliases"`
+	// GitLab, Bitbucket, Azure, Plain and SARIF override the value written for a canonical severity in that output
+	GitLab    map[string]string `yaml:"gitlab"`
+	Bitbucket map[string]string `yaml:"bitbucket"`
+	Azure     map[string]string `yaml:"azure"`
+	Plain     map[string]string `yaml:"plain"`
+	SARIF     map[string]string `yaml:"sarif"`
+}
+
+// RuleDocsConfig overrides the documentation URLs of rules
+type RuleDocsConfig struct {
+	// BaseURL is the URL of every rule's docs, with {rule} replaced by the rule ID
+	BaseURL string `yaml:"base_url"`
+	// Rules maps rule IDs to their docs URL, over BaseURL
+	Rules map[string]string `yaml:"rules"`
+}
+
+// RatchetConfig configures the ratchet check
+type RatchetConfig struct {
+	// Path is the committed ratchet file holding the allowed counts
+	Path string `yaml:"path"`
+	// Depth is the directory depth counts are kept at (2 when unset)
+	Depth int `yaml:"depth"`
+}
+
+// BitbucketInsightsConfig configures the Bitbucket Code Insights report
+type BitbucketInsightsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// ReportID identifies the report on the commit (default "code-analyzer")
+	ReportID string `yaml:"report_id"`
+	// TokenEnv names the variable holding an access token; the Pipelines auth proxy is used without one
+	TokenEnv string `yaml:"token_env"`
+}
+
+// NotificationConfig configures one chat webhook
+type NotificationConfig struct {
+	// Type is "slack" or "teams"
+	Type string `yaml:"type"`
+	// WebhookURL is the incoming webhook; $VARIABLES are expanded so the secret can stay in CI variables
+	WebhookURL string `yaml:"webhook_url"`
+	// Template is a Go text/template for the message (a built-in summary when empty)
+	Template string `yaml:"template"`
+	// Criticals lists the critical and blocker issues in the message
+	Criticals bool `yaml:"criticals"`
+	// MinIssues only notifies when the run reports at least this many issues (new ones with a baseline)
+	MinIssues int `yaml:"min_issues"`
+}
+
+// WebhookConfig configures the findings report webhook
+type WebhookConfig struct {
+	// URL receives the report as a POST; $VARIABLES are expanded. No report is sent when empty.
+	URL string `yaml:"url"`
+	// Secret signs the body with HMAC-SHA256 in X-Code-Analyzer-Signature; $VARIABLES are expanded
+	Secret string `yaml:"secret"`
+	// Retries is the number of extra attempts after a network error, 429 or 5xx (default 3, negative disables)
+	Retries int `yaml:"retries"`
+}
+
+// TestPathsConfig configures the built-in severity downgrade for test code
+type TestPathsConfig struct {
+	// Disabled keeps test and fixture issues at their original severity
+	Disabled bool `yaml:"disabled"`
+	// Patterns are globs matched against each directory and file name (built-in list when empty)
+	Patterns []string `yaml:"patterns"`
+}
+
+// HeatmapConfig configures the commented-code density export
+type HeatmapConfig struct {
+	// Path of the JSON directory tree; no heatmap is generated when empty
+	Path string `yaml:"path"`
+	// SVG optionally also renders the tree as a treemap image
+	SVG string `yaml:"svg"`
+	// Depth is the directory depth files are aggregated to (default 3)
+	Depth int `yaml:"depth"`
+}
+
+// MRCommentConfig configures the MR summary comment body
+type MRCommentConfig struct {
+	// Path of the Markdown file to write; no comment is generated when empty
+	Path string `yaml:"path"`
+	// ReportURL is linked as the full report (defaults to the job's artifacts in GitLab CI)
+	ReportURL string `yaml:"report_url"`
+}
+
+// DebtConfig configures the ranking of the files and directories carrying
+// the most debt
+type DebtConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Files is the number of worst files listed per severity (default 5)
+	Files int `yaml:"files"`
+	// Directories is the number of top-level directories listed (default 10)
+	Directories int `yaml:"directories"`
+	// Path optionally writes the ranking as JSON
+	Path string `yaml:"path"`
+}
+
+// HotspotsConfig configures the ranking of files that change often and
+// carry many issues
+type HotspotsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Since is the git date the history is counted from (default "6 months ago")
+	Since string `yaml:"since"`
+	// Files is the number of hotspots listed (default 20)
+	Files int `yaml:"files"`
+	// Path of the JSON ranking (default "hotspots.json")
+	Path string `yaml:"path"`
+}
+
+// ChurnConfig configures the churned-file heuristic
+type ChurnConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MinSmallBlocks is the number of small commented blocks that counts as a signal (default 3)
+	MinSmallBlocks int `yaml:"min_small_blocks"`
+	// MinSignals is the number of signals a file needs to be reported (default 2)
+	MinSignals int `yaml:"min_signals"`
+}
+
+// ArtifactsConfig configures how analyzer artifacts are named
+type ArtifactsConfig struct {
+	// Name is the path of each analyzer's artifact under `output`. It may use
+	// {analyzer}, {project} and {timestamp}, and "/" for subdirectories
+	// (default "{analyzer}-analysis.json")
+	Name string `yaml:"name"`
+	// Combined writes the reports of every analyzer to this one file under
+	// `output` instead; it may use {timestamp}
+	Combined string `yaml:"combined"`
+	// Gzip compresses the artifacts and adds .gz to their names
+	Gzip bool `yaml:"gzip"`
+}
+
+// ProjectConfig is one project of a monorepo. Its analyzers write their
+// artifacts to a directory named after it under `output`, unless the
+// artifact name places {project} itself, while the reports merge the issues
+// of every project.
+type ProjectConfig struct {
+	Name string `yaml:"name"`
+	// Dir is the project's directory, relative to the top-level dir
+	Dir string `yaml:"dir"`
+	// Exclude adds paths to skip on top of each analyzer's excludes
+	Exclude []string `yaml:"exclude"`
+	// Analyzers lists the analyzers to run on the project (the top-level enabled ones when empty)
+	Analyzers []string `yaml:"analyzers"`
+}
+
+// CrashReportingConfig configures the opt-in crash/error reporter
+type CrashReportingConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Endpoint string `yaml:"endpoint"`
+	// SlowAnalyzerSeconds reports analyzers running longer as timing outliers (default 300)
+	SlowAnalyzerSeconds int `yaml:"slow_analyzer_seconds"`
 }
 
 // AnalyzerConfig represents configuration for a specific analyzer
@@ -23,15 +269,79 @@ type AnalyzerConfig struct {
 	MinRatio float64  `yaml:"min_ratio"`
 	Sort     string   `yaml:"sort"`
 	Exclude  []string `yaml:"exclude"`
+	// MaxFileSize overrides the global max_file_size for this analyzer
+	MaxFileSize int64 `yaml:"max_file_size"`
+	// TimeoutSeconds is this analyzer's time budget; an analyzer running out
+	// of it fails with the issues found so far (no limit when unset)
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+	// Budget is a soft time limit such as "90s": an analyzer running out of
+	// it stops and reports the issues found so far with a warning, but does
+	// not fail the run
+	Budget string `yaml:"budget"`
+	// IgnoreComments lists extra regexes for comments to never report (HTML only)
+	IgnoreComments []string `yaml:"ignore_comments"`
+	// Extensions overrides the file extensions an analyzer scans (HTML only)
+	Extensions []string `yaml:"extensions"`
+	// MarkerSizes lists conflict marker lengths to detect (conflicts only, default 7)
+	MarkerSizes []int `yaml:"marker_sizes"`
+	// TargetBranch predicts conflicts with this branch via git merge-tree (conflicts only)
+	TargetBranch string `yaml:"target_branch"`
+	// License analyzer: header template per extension, {year} matches a year or range
+	Headers            map[string]string `yaml:"headers"`
+	RequireCurrentYear bool              `yaml:"require_current_year"`
+	// Whitespace analyzer: checks ("trailing", "eol", "indent") per extension or "default"
+	Checks map[string][]string `yaml:"checks"`
+	// Size analyzer thresholds
+	MaxBytes      int `yaml:"max_bytes"`
+	MaxLines      int `yaml:"max_lines"`
+	MaxLineLength int `yaml:"max_line_length"`
+	// MaxStringLength is the SQL string length above which inline queries are reported (sql only, default 300)
+	MaxStringLength int `yaml:"max_string_length"`
+	// MaxComplexity is the cyclomatic complexity above which functions are reported (js only, default 10)
+	MaxComplexity int `yaml:"max_complexity"`
+	// MaxNesting is the depth of nested control blocks and callbacks above which functions are reported (js only, default 4)
+	MaxNesting int `yaml:"max_nesting"`
+	// LaravelPaths are globs of the files the Laravel rules check (php only;
+	// route closures in routes/*.php and N+1 queries everywhere when unset)
+	LaravelPaths []string `yaml:"laravel_paths"`
+}
+
+// BudgetDuration parses Budget; it is 0 when unset
+func (a AnalyzerConfig) BudgetDuration() (time.Duration, error) {
+	if a.Budget == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(a.Budget)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s is negative", a.Budget)
+	}
+	return d, nil
 }
 
-// LoadConfig loads configuration from a YAML file
+// LoadConfig loads configuration from a YAML or JSON file, from stdin when
+// path is "-" or from an http(s) URL (see Read)
 func LoadConfig(path string) (*AppConfig, error) {
-	data, err := os.ReadFile(path)
+	data, err := Read(path)
 	if err != nil {
 		return nil, err
 	}
 
+	return ParseConfig(data)
+}
+
+// ParseConfig parses YAML or JSON configuration. JSON is converted to YAML
+// first so both formats are decoded and validated identically.
+func ParseConfig(data []byte) (*AppConfig, error) {
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		// A YAML flow mapping also starts with "{"; only valid JSON is converted
+		if converted, err := jsonToYAML(trimmed); err == nil {
+			data = converted
+		}
+	}
+
 	config := &AppConfig{}
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return nil, err
@@ -39,3 +349,37 @@ func LoadConfig(path string) (*AppConfig, error) {
 
 	return config, nil
 }
+
+// jsonToYAML re-encodes a JSON document as YAML, keeping integers exact
+func jsonToYAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(normalizeNumbers(doc))
+}
+
+// normalizeNumbers replaces json.Number values with int64 or float64, which
+// YAML encodes as plain scalars
+func normalizeNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConfig_JSONMatchesYAML(t *testing.T) {
+	yamlConfig := `
+dir: "api"
+output: "artifacts"
+max_file_size: 10485760
+policies:
+  - 'severity = critical when path startsWith "app/Payments"'
+analyzers:
+  js:
+    enabled: true
+    min_ratio: 12.5
+    exclude: ["node_modules", "dist"]
+  license:
+    enabled: true
+    headers:
+      ".php": "// Copyright {year} Acme"
+`
+	jsonConfig := `{
+	"dir": "api",
+	"output": "artifacts",
+	"max_file_size": 10485760,
+	"policies": ["severity = critical when path startsWith \"app/Payments\""],
+	"analyzers": {
+		"js": {"enabled": true, "min_ratio": 12.5, "exclude": ["node_modules", "dist"]},
+		"license": {"enabled": true, "headers": {".php": "// Copyright {year} Acme"}}
+	}
+}`
+
+	fromYAML, err := ParseConfig([]byte(yamlConfig))
+	if err != nil {
+		t.Fatalf("YAML config failed: %v", err)
+	}
+	fromJSON, err := ParseConfig([]byte(jsonConfig))
+	if err != nil {
+		t.Fatalf("JSON config failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("JSON config differs from YAML:\n%+v\n%+v", fromJSON, fromYAML)
+	}
+}
+
+func TestParseConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"YAML type mismatch", "analyzers:\n  js:\n    top: many\n"},
+		{"JSON type mismatch", `{"analyzers": {"js": {"top": "many"}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseConfig([]byte(tt.config)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseConfig_YAMLFlowMapping(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{dir: api, analyzers: {php: {enabled: true}}}`))
+	if err != nil {
+		t.Fatalf("flow mapping failed: %v", err)
+	}
+	if cfg.Dir != "api" || !cfg.Analyzers["php"].Enabled {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
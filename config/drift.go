@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code-analyzer/models"
+)
+
+// Drift kinds
+const (
+	DriftDisabled  = "disabled"  // Analyzer enabled in the preset is off
+	DriftLoosened  = "loosened"  // Setting reports fewer issues than the preset
+	DriftTightened = "tightened" // Setting reports more issues than the preset
+	DriftChanged   = "changed"   // Setting differs with no clear direction
+)
+
+// Drift compares a repository config with an organization preset and returns
+// every place the effective config diverges, ordered by analyzer and field.
+// Analyzers only present in the repository config are not drift.
+func Drift(preset, actual *AppConfig) []models.ConfigDrift {
+	var drift []models.ConfigDrift
+
+	for _, p := range missing(preset.Policies, actual.Policies) {
+		drift = append(drift, models.ConfigDrift{Field: "policies", Kind: DriftChanged, Preset: p, Actual: "(missing)"})
+	}
+	if preset.GitLabReport != "" && actual.GitLabReport == "" {
+		drift = append(drift, models.ConfigDrift{Field: "gitlab_report", Kind: DriftDisabled, Preset: preset.GitLabReport, Actual: "(missing)"})
+	}
+
+	names := make([]string, 0, len(preset.Analyzers))
+	for name := range preset.Analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := effective(preset.Analyzers[name])
+		a, ok := actual.Analyzers[name]
+		if !ok {
+			if p.Enabled {
+				drift = append(drift, models.ConfigDrift{Analyzer: name, Field: "enabled", Kind: DriftDisabled, Preset: "true", Actual: "(missing)"})
+			}
+			continue
+		}
+		a = effective(a)
+
+		if p.Enabled != a.Enabled {
+			kind := DriftDisabled
+			if a.Enabled {
+				kind = DriftTightened
+			}
+			drift = append(drift, models.ConfigDrift{Analyzer: name, Field: "enabled", Kind: kind, Preset: fmt.Sprint(p.Enabled), Actual: fmt.Sprint(a.Enabled)})
+		}
+
+		// A lower top N or higher minimums hide issues
+		drift = appendNumber(drift, name, "top", float64(p.TopN), float64(a.TopN), false)
+		drift = appendNumber(drift, name, "min", float64(p.Min), float64(a.Min), true)
+		drift = appendNumber(drift, name, "min_ratio", p.MinRatio, a.MinRatio, true)
+		drift = appendNumber(drift, name, "max_bytes", float64(p.MaxBytes), float64(a.MaxBytes), true)
+		drift = appendNumber(drift, name, "max_lines", float64(p.MaxLines), float64(a.MaxLines), true)
+		drift = appendNumber(drift, name, "max_line_length", float64(p.MaxLineLength), float64(a.MaxLineLength), true)
+		drift = appendNumber(drift, name, "max_string_length", float64(p.MaxStringLength), float64(a.MaxStringLength), true)
+		drift = appendNumber(drift, name, "max_complexity", float64(p.MaxComplexity), float64(a.MaxComplexity), true)
+		drift = appendNumber(drift, name, "max_nesting", float64(p.MaxNesting), float64(a.MaxNesting), true)
+
+		if p.Sort != a.Sort {
+			drift = append(drift, models.ConfigDrift{Analyzer: name, Field: "sort", Kind: DriftChanged, Preset: p.Sort, Actual: a.Sort})
+		}
+		if p.RequireCurrentYear && !a.RequireCurrentYear {
+			drift = append(drift, models.ConfigDrift{Analyzer: name, Field: "require_current_year", Kind: DriftLoosened, Preset: "true", Actual: "false"})
+		}
+
+		// Extra excludes and ignores hide issues, dropped ones surface more
+		drift = appendList(drift, name, "exclude", p.Exclude, a.Exclude)
+		drift = appendList(drift, name, "ignore_comments", p.IgnoreComments, a.IgnoreComments)
+	}
+
+	return drift
+}
+
+// effective applies the defaults main uses when running an analyzer
+func effective(c AnalyzerConfig) AnalyzerConfig {
+	if c.Sort == "" {
+		c.Sort = "ratio"
+	}
+	if c.Min == 0 {
+		c.Min = 1
+	}
+	if c.TopN == 0 {
+		c.TopN = 100
+	}
+	return c
+}
+
+// appendNumber compares a threshold. Zero means the analyzer default, so a
+// change from or to zero has no known direction.
+func appendNumber(drift []models.ConfigDrift, analyzer, field string, preset, actual float64, higherIsLooser bool) []models.ConfigDrift {
+	if preset == actual {
+		return drift
+	}
+
+	kind := DriftChanged
+	if preset != 0 && actual != 0 {
+		if (actual > preset) == higherIsLooser {
+			kind = DriftLoosened
+		} else {
+			kind = DriftTightened
+		}
+	}
+	return append(drift, models.ConfigDrift{
+		Analyzer: analyzer,
+		Field:    field,
+		Kind:     kind,
+		Preset:   formatNumber(preset),
+		Actual:   formatNumber(actual),
+	})
+}
+
+// appendList reports entries added to (loosened) or removed from (tightened) a suppression list
+func appendList(drift []models.ConfigDrift, analyzer, field string, preset, actual []string) []models.ConfigDrift {
+	for _, v := range missing(actual, preset) {
+		drift = append(drift, models.ConfigDrift{Analyzer: analyzer, Field: field, Kind: DriftLoosened, Preset: "(missing)", Actual: v})
+	}
+	for _, v := range missing(preset, actual) {
+		drift = append(drift, models.ConfigDrift{Analyzer: analyzer, Field: field, Kind: DriftTightened, Preset: v, Actual: "(missing)"})
+	}
+	return drift
+}
+
+// missing returns the values of want that are not in have
+func missing(want, have []string) []string {
+	set := make(map[string]bool, len(have))
+	for _, v := range have {
+		set[v] = true
+	}
+	var out []string
+	for _, v := range want {
+		if !set[v] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func formatNumber(v float64) string {
+	if v == 0 {
+		return "(default)"
+	}
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
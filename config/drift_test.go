@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestDrift(t *testing.T) {
+	preset := &AppConfig{
+		GitLabReport: "gl-code-quality-report.json",
+		Policies:     []string{`severity = critical when path startsWith "app/Payments"`},
+		Analyzers: map[string]AnalyzerConfig{
+			"php":       {Enabled: true, Min: 1, Exclude: []string{"vendor"}},
+			"js":        {Enabled: true, Min: 50, Exclude: []string{"node_modules", "dist"}},
+			"size":      {Enabled: true, MaxLines: 1000},
+			"conflicts": {Enabled: true},
+		},
+	}
+	actual := &AppConfig{
+		GitLabReport: "gl-code-quality-report.json",
+		Analyzers: map[string]AnalyzerConfig{
+			"php":  {Enabled: false, Exclude: []string{"vendor"}},
+			"js":   {Enabled: true, Min: 100, Exclude: []string{"node_modules", "legacy"}},
+			"size": {Enabled: true, MaxLines: 500},
+			"html": {Enabled: true},
+		},
+	}
+
+	drift := Drift(preset, actual)
+
+	expected := []struct {
+		analyzer, field, kind string
+	}{
+		{"", "policies", DriftChanged},
+		{"conflicts", "enabled", DriftDisabled},
+		{"js", "min", DriftLoosened},
+		{"js", "exclude", DriftLoosened},
+		{"js", "exclude", DriftTightened},
+		{"php", "enabled", DriftDisabled},
+		{"size", "max_lines", DriftTightened},
+	}
+	if len(drift) != len(expected) {
+		t.Fatalf("expected %d drift entries, got %+v", len(expected), drift)
+	}
+	for i, e := range expected {
+		d := drift[i]
+		if d.Analyzer != e.analyzer || d.Field != e.field || d.Kind != e.kind {
+			t.Errorf("entry %d: expected %s.%s %s, got %+v", i, e.analyzer, e.field, e.kind, d)
+		}
+	}
+}
+
+func TestDrift_Identical(t *testing.T) {
+	cfg := &AppConfig{Analyzers: map[string]AnalyzerConfig{"php": {Enabled: true, Min: 1}}}
+	defaults := &AppConfig{Analyzers: map[string]AnalyzerConfig{"php": {Enabled: true}}}
+
+	if drift := Drift(cfg, defaults); len(drift) != 0 {
+		t.Errorf("expected no drift when only defaults differ, got %+v", drift)
+	}
+}
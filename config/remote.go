@@ -0,0 +1,81 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxRemoteConfigSize bounds what is read from a config URL
+const maxRemoteConfigSize = 4 << 20
+
+// remoteClient fetches configs given as URLs
+var remoteClient = &http.Client{Timeout: 30 * time.Second}
+
+// IsRemote reports whether path is an http(s) URL
+func IsRemote(path string) bool {
+	return strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://")
+}
+
+// Read returns the content of a config file, of stdin when path is "-", or
+// of an http(s) URL. A URL may pin the expected content with a
+// `#sha256=<hex>` fragment; plain http URLs must be pinned.
+func Read(path string) ([]byte, error) {
+	switch {
+	case path == "-":
+		return io.ReadAll(os.Stdin)
+	case IsRemote(path):
+		return fetch(path)
+	default:
+		return os.ReadFile(path)
+	}
+}
+
+func fetch(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL: %v", err)
+	}
+	var pin string
+	if u.Fragment != "" {
+		algorithm, sum, _ := strings.Cut(u.Fragment, "=")
+		if algorithm != "sha256" || sum == "" {
+			return nil, fmt.Errorf("config URL %s: the fragment must be #sha256=<hex>", u.Redacted())
+		}
+		pin = strings.ToLower(sum)
+		u.Fragment = ""
+	}
+	if u.Scheme == "http" && pin == "" {
+		return nil, fmt.Errorf("config URL %s: plain http is only allowed with a #sha256= checksum", u.Redacted())
+	}
+
+	resp, err := remoteClient.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("fetching config: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching config %s: %s", u.Redacted(), resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching config: %v", err)
+	}
+	if len(data) > maxRemoteConfigSize {
+		return nil, fmt.Errorf("config %s is larger than %d bytes", u.Redacted(), maxRemoteConfigSize)
+	}
+
+	if pin != "" {
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); got != pin {
+			return nil, fmt.Errorf("config %s: checksum mismatch, got sha256 %s, pinned %s", u.Redacted(), got, pin)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig_Remote(t *testing.T) {
+	body := "dir: src\nanalyzers:\n  php:\n    enabled: true\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/analyzer.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	sum := sha256.Sum256([]byte(body))
+	pin := hex.EncodeToString(sum[:])
+	url := server.URL + "/analyzer.yaml"
+
+	cfg, err := LoadConfig(url + "#sha256=" + pin)
+	if err != nil {
+		t.Fatalf("pinned config failed to load: %v", err)
+	}
+	if cfg.Dir != "src" || !cfg.Analyzers["php"].Enabled {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	if _, err := LoadConfig(url + "#sha256=" + strings.Repeat("0", 64)); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected a checksum mismatch, got %v", err)
+	}
+	// The test server speaks plain http, which needs a pin
+	if _, err := LoadConfig(url); err == nil || !strings.Contains(err.Error(), "only allowed with") {
+		t.Errorf("expected unpinned http to be refused, got %v", err)
+	}
+	if _, err := LoadConfig(server.URL + "/missing.yaml#sha256=" + pin); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected a 404 error, got %v", err)
+	}
+	if _, err := LoadConfig(url + "#md5=abc"); err == nil {
+		t.Error("expected an unsupported fragment to be rejected")
+	}
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is a mistake found in a config file
+type Problem struct {
+	Key     string `json:"key"`            // Dotted path of the setting, e.g. analyzers.html.min_ratio
+	Line    int    `json:"line,omitempty"` // Line in the file, 0 when unknown
+	Message string `json:"message"`
+	Warning bool   `json:"warning,omitempty"` // Valid but probably not what was meant
+}
+
+// String describes the problem with the key it is about
+func (p Problem) String() string {
+	if p.Key == "" {
+		return p.Message
+	}
+	return fmt.Sprintf("%s: %s", p.Key, p.Message)
+}
+
+// UnknownKeys reports the keys of a YAML or JSON config that match no
+// setting. Decoding ignores them, so a typo such as `min_ration` would
+// otherwise silently leave the default in place.
+func UnknownKeys(data []byte) ([]Problem, error) {
+	isJSON := false
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		if converted, err := jsonToYAML(trimmed); err == nil {
+			data, isJSON = converted, true
+		}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	var problems []Problem
+	checkKeys(&root, reflect.TypeOf(AppConfig{}), "", &problems)
+	if isJSON {
+		// Lines refer to the converted document, not to the file
+		for i := range problems {
+			problems[i].Line = 0
+		}
+	}
+	return problems, nil
+}
+
+// KeyLines maps the dotted path of every key in a YAML config to its line,
+// so problems found on the decoded config can point into the file. JSON
+// configs have no lines.
+func KeyLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		return lines
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return lines
+	}
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, path)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := join(path, node.Content[i].Value)
+				lines[key] = node.Content[i].Line
+				walk(node.Content[i+1], key)
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				key := fmt.Sprintf("%s[%d]", path, i)
+				lines[key] = item.Line
+				walk(item, key)
+			}
+		}
+	}
+	walk(&root, "")
+	return lines
+}
+
+// checkKeys walks node alongside the type it decodes into
+func checkKeys(node *yaml.Node, t reflect.Type, path string, problems *[]Problem) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			checkKeys(child, t, path, problems)
+		}
+		return
+	case yaml.AliasNode:
+		checkKeys(node.Alias, t, path, problems)
+		return
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			field, ok := fields[key.Value]
+			if !ok {
+				message := fmt.Sprintf("unknown key %q", key.Value)
+				if suggestion := closest(key.Value, fields); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				*problems = append(*problems, Problem{Key: join(path, key.Value), Line: key.Line, Message: message})
+				continue
+			}
+			checkKeys(value, field, join(path, key.Value), problems)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkKeys(node.Content[i+1], t.Elem(), join(path, node.Content[i].Value), problems)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	}
+}
+
+// yamlFields maps the keys of a struct's fields to their types
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// closest returns the known key within two edits of key, if any
+func closest(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// Check reports settings that are out of range or contradict each other
+func Check(cfg *AppConfig) []Problem {
+	var problems []Problem
+	add := func(key, format string, args ...interface{}) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	warn := func(key, format string, args ...interface{}) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...), Warning: true})
+	}
+
+	switch cfg.GitLabReportScope {
+	case "", "all", "changed_lines":
+	default:
+		add("gitlab_report_scope", "%q is not \"all\" or \"changed_lines\"", cfg.GitLabReportScope)
+	}
+	if cfg.BaselineMaxAgeDays < 0 {
+		add("baseline_max_age_days", "cannot be negative")
+	} else if cfg.BaselineMaxAgeDays > 0 && cfg.Baseline == "" {
+		warn("baseline_max_age_days", "has no effect without `baseline`")
+	}
+	if cfg.MaxFileSize < 0 {
+		add("max_file_size", "cannot be negative")
+	}
+	if cfg.TimeoutSeconds < 0 {
+		add("timeout_seconds", "cannot be negative")
+	}
+	if cfg.Concurrency < 0 {
+		add("concurrency", "cannot be negative")
+	}
+	if cfg.MaxIssuesPerAnalyzer < 0 {
+		add("max_issues_per_analyzer", "cannot be negative")
+	}
+	if cfg.Debt.Files < 0 {
+		add("debt.files", "cannot be negative")
+	}
+	if cfg.Debt.Directories < 0 {
+		add("debt.directories", "cannot be negative")
+	}
+	if cfg.Hotspots.Files < 0 {
+		add("hotspots.files", "cannot be negative")
+	}
+	checkArtifacts(cfg.Artifacts, add)
+	if (cfg.Artifacts.Name != "" || cfg.Artifacts.Combined != "" || cfg.Artifacts.Gzip) && cfg.Output == "" {
+		warn("artifacts", "has no effect without `output`")
+	}
+
+	for name, a := range cfg.Analyzers {
+		key := "analyzers." + name
+		for setting, value := range map[string]int64{
+			"top":               int64(a.TopN),
+			"min":               int64(a.Min),
+			"max_file_size":     a.MaxFileSize,
+			"timeout_seconds":   int64(a.TimeoutSeconds),
+			"max_bytes":         int64(a.MaxBytes),
+			"max_lines":         int64(a.MaxLines),
+			"max_line_length":   int64(a.MaxLineLength),
+			"max_string_length": int64(a.MaxStringLength),
+			"max_complexity":    int64(a.MaxComplexity),
+			"max_nesting":       int64(a.MaxNesting),
+		} {
+			if value < 0 {
+				add(key+"."+setting, "cannot be negative")
+			}
+		}
+		for i, size := range a.MarkerSizes {
+			if size < 1 {
+				add(fmt.Sprintf("%s.marker_sizes[%d]", key, i), "%d is not a positive marker length", size)
+			}
+		}
+		for i, pattern := range a.LaravelPaths {
+			if _, err := path.Match(pattern, ""); err != nil {
+				add(fmt.Sprintf("%s.laravel_paths[%d]", key, i), "invalid pattern %q: %v", pattern, err)
+			}
+		}
+		if _, err := a.BudgetDuration(); err != nil {
+			add(key+".budget", "%v", err)
+		}
+		if a.MinRatio < 0 || a.MinRatio > 100 {
+			add(key+".min_ratio", "%g is not a percentage between 0 and 100", a.MinRatio)
+		}
+		if cfg.TimeoutSeconds > 0 && a.TimeoutSeconds >= cfg.TimeoutSeconds {
+			warn(key+".timeout_seconds", "%ds is not below the run's timeout_seconds (%ds), so the budget never applies", a.TimeoutSeconds, cfg.TimeoutSeconds)
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Key < problems[j].Key
+	})
+	return problems
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	data := []byte(`dir: "."
+gitlab_reprot: "gl.json"
+analyzers:
+  html:
+    enabled: true
+    min_ration: 10
+  php:
+    enabled: true
+projects:
+  - name: api
+    dirr: services/api
+`)
+	problems, err := UnknownKeys(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		key        string
+		line       int
+		suggestion string
+	}{
+		{"gitlab_reprot", 2, `"gitlab_report"`},
+		{"analyzers.html.min_ration", 6, `"min_ratio"`},
+		{"projects[0].dirr", 11, `"dir"`},
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %v", len(want), problems)
+	}
+	for i, w := range want {
+		p := problems[i]
+		if p.Key != w.key || p.Line != w.line || !strings.Contains(p.Message, w.suggestion) || p.Warning {
+			t.Errorf("problem %d: got %+v, want key %s on line %d suggesting %s", i, p, w.key, w.line, w.suggestion)
+		}
+	}
+}
+
+func TestUnknownKeys_JSON(t *testing.T) {
+	problems, err := UnknownKeys([]byte(`{"dir": ".", "analyzers": {"size": {"max_line": 120}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Key != "analyzers.size.max_line" || problems[0].Line != 0 {
+		t.Errorf("expected one problem without a line, got %+v", problems)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`baseline_max_age_days: 30
+timeout_seconds: 60
+gitlab_report_scope: changed
+artifacts:
+  name: "{analyzer}/{date}.json"
+analyzers:
+  html:
+    min_ratio: 150
+    timeout_seconds: 120
+    budget: soon
+  conflicts:
+    marker_sizes: [7, 0]
+  php:
+    laravel_paths: ["app", "routes/[.php"]
+  size:
+    max_lines: -1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _, p := range Check(cfg) {
+		got[p.Key] = p.Warning
+	}
+	want := map[string]bool{
+		"analyzers.html.budget":               false,
+		"analyzers.html.min_ratio":            false,
+		"analyzers.html.timeout_seconds":      true,
+		"analyzers.conflicts.marker_sizes[1]": false,
+		"analyzers.php.laravel_paths[1]":      false,
+		"analyzers.size.max_lines":            false,
+		"artifacts":                           true,
+		"artifacts.name":                      false,
+		"baseline_max_age_days":               true,
+		"gitlab_report_scope":                 false,
+	}
+	if len(got) != len(want) {
+		t.Errorf("got problems %v, want %v", got, want)
+	}
+	for key, warning := range want {
+		if w, ok := got[key]; !ok || w != warning {
+			t.Errorf("%s: got reported=%v warning=%v, want warning=%v", key, ok, w, warning)
+		}
+	}
+}
+
+func TestKeyLines(t *testing.T) {
+	lines := KeyLines([]byte("dir: .\nanalyzers:\n  size:\n    max_lines: 10\npolicies:\n  - severity = info when path contains \"x\"\n"))
+	if lines["analyzers.size.max_lines"] != 4 || lines["policies[0]"] != 6 {
+		t.Errorf("unexpected lines: %v", lines)
+	}
+}
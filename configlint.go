@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"code-analyzer/config"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// runConfigCommand handles `code-analyzer config <subcommand>` and exits
+func runConfigCommand(args []string) {
+	if len(args) == 0 || args[0] != "lint" {
+		utils.Errorf("Usage: code-analyzer config lint --against <preset.yaml> [--config <file>] [--output <drift.json>]\n")
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("config lint", flag.ExitOnError)
+	configFile := fs.String("config", "analysis-config.yaml", "Path or http(s) URL of the YAML or JSON configuration file (\"-\" reads stdin)")
+	against := fs.String("against", "", "Organization preset to compare the config with (path or http(s) URL)")
+	output := fs.String("output", "", "Write the machine-readable drift report to this file")
+	fs.Parse(args[1:])
+
+	if *against == "" {
+		utils.Errorf("❌ config lint requires --against <preset.yaml>\n")
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig(*configFile)
+	if err != nil {
+		utils.Errorf("❌ Failed to load config file: %v\n", err)
+		os.Exit(2)
+	}
+	preset, err := config.LoadConfig(*against)
+	if err != nil {
+		utils.Errorf("❌ Failed to load preset: %v\n", err)
+		os.Exit(2)
+	}
+
+	drift := config.Drift(preset, cfg)
+	report := models.ConfigDriftReport{
+		Timestamp: utils.GetTimestamp(),
+		Config:    *configFile,
+		Preset:    *against,
+		Drift:     drift,
+	}
+	if report.Drift == nil {
+		report.Drift = []models.ConfigDrift{}
+	}
+	for _, d := range drift {
+		if d.Kind == config.DriftLoosened || d.Kind == config.DriftDisabled {
+			report.Loosened++
+		}
+	}
+
+	printDrift(report)
+
+	if *output != "" {
+		if err := utils.WriteArtifact(*output, report); err != nil {
+			utils.Errorf("❌ Failed to write drift report: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Printf("✅ Drift report generated: %s\n", *output)
+	}
+
+	// Loosened settings fail governance checks; other drift is informational
+	if report.Loosened > 0 {
+		os.Exit(1)
+	}
+}
+
+func printDrift(report models.ConfigDriftReport) {
+	if len(report.Drift) == 0 {
+		fmt.Printf("✅ %s matches preset %s\n", report.Config, report.Preset)
+		return
+	}
+
+	fmt.Printf("Found %d differences from preset %s (%d loosened)\n\n", len(report.Drift), report.Preset, report.Loosened)
+	fmt.Printf("%-12s %-22s %-10s %-30s %-30s\n", "Analyzer", "Field", "Kind", "Preset", "Actual")
+	fmt.Println(strings.Repeat("-", 108))
+	for _, d := range report.Drift {
+		analyzer := d.Analyzer
+		if analyzer == "" {
+			analyzer = "-"
+		}
+		fmt.Printf("%-12s %-22s %-10s %-30s %-30s\n", analyzer, d.Field, d.Kind, utils.Truncate(d.Preset, 30), utils.Truncate(d.Actual, 30))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/utils"
+)
+
+// extensionCount is the number of unanalyzed files sharing an extension
+type extensionCount struct {
+	Extension string
+	Files     int
+}
+
+// scheduledAnalyzer is an analyzer together with the config it runs with
+type scheduledAnalyzer struct {
+	Name     string // Config name, e.g. "php"
+	Analyzer analyzers.Analyzer
+	Config   analyzers.Config
+}
+
+// inRoot reports whether path is under the directory s scans, which is not
+// the scan root for the analyzers of a monorepo project
+func (s scheduledAnalyzer) inRoot(path string) bool {
+	if s.Config.RootDir == "" {
+		return true
+	}
+	rel, err := filepath.Rel(s.Config.RootDir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// computeCoverage walks the scan root and counts, per extension, the files that no
+// language analyzer handles. Files excluded by every analyzer (or by -ext) are not counted.
+func computeCoverage(rootDir string, followSymlinks bool, scheduled []scheduledAnalyzer) ([]extensionCount, int, error) {
+	counts := make(map[string]int)
+	total := 0
+
+	err := utils.Walk(rootDir, followSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		excludedEverywhere := true
+		handled := false
+		for _, s := range scheduled {
+			if !s.inRoot(path) || utils.ShouldSkip(path, s.Config.ExcludePaths) || !s.Config.MatchesExt(path) {
+				continue
+			}
+			excludedEverywhere = false
+			if matcher, ok := s.Analyzer.(analyzers.FileMatcher); ok && matcher.Handles(path, s.Config) {
+				handled = true
+				break
+			}
+		}
+		if excludedEverywhere || handled {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == "" {
+			ext = "(none)"
+		}
+		counts[ext]++
+		total++
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result []extensionCount
+	for ext, n := range counts {
+		result = append(result, extensionCount{Extension: ext, Files: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Files != result[j].Files {
+			return result[i].Files > result[j].Files
+		}
+		return result[i].Extension < result[j].Extension
+	})
+	return result, total, nil
+}
+
+// printCoverage prints the extensions of files no analyzer handled
+func printCoverage(coverage []extensionCount, total int) {
+	fmt.Fprintln(stdout)
+	if total == 0 {
+		fmt.Fprintln(stdout, "🗺️  Coverage: every file was handled by an analyzer")
+		return
+	}
+
+	fmt.Fprintf(stdout, "🗺️  Coverage: %d files matched no language analyzer\n", total)
+	fmt.Fprintln(stdout, strings.Repeat("-", 40))
+	for _, c := range coverage[:utils.Min(10, len(coverage))] {
+		fmt.Fprintf(stdout, "   %-20s %8d files\n", c.Extension, c.Files)
+	}
+	if len(coverage) > 10 {
+		fmt.Fprintf(stdout, "   ... and %d more extensions\n", len(coverage)-10)
+	}
+}
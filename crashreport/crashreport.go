@@ -0,0 +1,215 @@
+// Package crashreport sends opt-in, anonymized tool health reports (panics,
+// analyzer failures and slow analyzers) to an HTTP endpoint. Reports never
+// contain source content or file paths.
+package crashreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"code-analyzer/models"
+)
+
+// Event kinds
+const (
+	KindPanic         = "panic"
+	KindRulePanic     = "rule_panic"
+	KindAnalyzerError = "analyzer_error"
+	KindSlowAnalyzer  = "slow_analyzer"
+)
+
+// DefaultSlowAfter is the analyzer duration reported as a timing outlier
+const DefaultSlowAfter = 5 * time.Minute
+
+// Event is a single health event
+type Event struct {
+	Kind       string `json:"kind"`
+	Analyzer   string `json:"analyzer,omitempty"`
+	Rule       string `json:"rule,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Stack      string `json:"stack,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+// Report is the payload posted to the endpoint
+type Report struct {
+	Tool       string  `json:"tool"`
+	Project    string  `json:"project"`
+	PipelineID string  `json:"pipeline_id,omitempty"`
+	OS         string  `json:"os"`
+	Arch       string  `json:"arch"`
+	GoVersion  string  `json:"go_version"`
+	Events     []Event `json:"events"`
+}
+
+// Reporter buffers events and posts them in one request.
+// A nil *Reporter is valid and discards everything.
+type Reporter struct {
+	endpoint  string
+	slowAfter time.Duration
+	client    *http.Client
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// New creates a reporter posting to endpoint. Analyzers running longer than
+// slowAfter are reported (DefaultSlowAfter when zero).
+func New(endpoint string, slowAfter time.Duration) *Reporter {
+	if slowAfter <= 0 {
+		slowAfter = DefaultSlowAfter
+	}
+	return &Reporter{
+		endpoint:  endpoint,
+		slowAfter: slowAfter,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (r *Reporter) record(event Event) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// Events returns a copy of the buffered events
+func (r *Reporter) Events() []Event {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event{}, r.events...)
+}
+
+// Panic records a panic of the tool itself. Only the class of the panic
+// value is sent, as panic messages can carry file paths.
+func (r *Reporter) Panic(value interface{}, stack []byte) {
+	r.record(Event{Kind: KindPanic, Message: panicClass(value), Stack: string(stack)})
+}
+
+// panicClass describes a panic value without its text: the class of errors,
+// else its type, e.g. "not_exist (open)" or "string"
+func panicClass(value interface{}) string {
+	if err, ok := value.(error); ok {
+		return errorClass(err)
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// AnalyzerFailed records an analyzer returning an error. Only its class is
+// sent, as error texts usually carry file paths.
+func (r *Reporter) AnalyzerFailed(analyzer string, err error) {
+	r.record(Event{Kind: KindAnalyzerError, Analyzer: analyzer, Message: errorClass(err)})
+}
+
+// errorClass describes err without its text: the kind of failure, when known,
+// and the failing operation of path errors, else the type of the innermost
+// error, e.g. "permission (open)" or "*json.SyntaxError"
+func errorClass(err error) string {
+	class := ""
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		class = "timeout"
+	case errors.Is(err, context.Canceled):
+		class = "canceled"
+	case errors.Is(err, fs.ErrPermission):
+		class = "permission"
+	case errors.Is(err, fs.ErrNotExist):
+		class = "not_exist"
+	case errors.Is(err, fs.ErrExist):
+		class = "exist"
+	}
+
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		if class == "" {
+			class = "io"
+		}
+		return fmt.Sprintf("%s (%s)", class, pathErr.Op)
+	}
+	if class != "" {
+		return class
+	}
+	for next := errors.Unwrap(err); next != nil; next = errors.Unwrap(err) {
+		err = next
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// AnalyzerFinished records the analyzer as a timing outlier if it was slow
+func (r *Reporter) AnalyzerFinished(analyzer string, elapsed time.Duration) {
+	if r == nil || elapsed < r.slowAfter {
+		return
+	}
+	r.record(Event{Kind: KindSlowAnalyzer, Analyzer: analyzer, DurationMs: elapsed.Milliseconds()})
+}
+
+// RulePanics records recovered rule panics, dropping the file paths and
+// sending the class of the panic value in place of its message
+func (r *Reporter) RulePanics(diags []models.Diagnostic) {
+	for _, diag := range diags {
+		r.record(Event{Kind: KindRulePanic, Rule: diag.Rule, Message: panicClass(diag.Value), Stack: diag.Stack})
+	}
+}
+
+// Flush posts the buffered events, if any
+func (r *Reporter) Flush() error {
+	events := r.Events()
+	if len(events) == 0 {
+		return nil
+	}
+
+	report := Report{
+		Tool:       "code-analyzer",
+		Project:    projectID(),
+		PipelineID: os.Getenv("CI_PIPELINE_ID"),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		GoVersion:  runtime.Version(),
+		Events:     events,
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Post(r.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+
+	r.mu.Lock()
+	r.events = nil
+	r.mu.Unlock()
+	return nil
+}
+
+// projectID identifies the project without revealing it: a hash of the
+// GitLab project path, or of the working directory outside CI
+func projectID() string {
+	name := os.Getenv("CI_PROJECT_PATH")
+	if name == "" {
+		name, _ = os.Getwd()
+	}
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])[:16]
+}
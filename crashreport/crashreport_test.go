@@ -0,0 +1,171 @@
+package crashreport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"code-analyzer/models"
+)
+
+func TestReporter_Flush(t *testing.T) {
+	var received Report
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if err := json.NewDecoder(req.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode report: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("CI_PROJECT_PATH", "group/secret-project")
+
+	r := New(server.URL, time.Second)
+	r.AnalyzerFailed("php", errors.New("walk failed"))
+	r.AnalyzerFinished("js", 10*time.Millisecond)
+	r.AnalyzerFinished("html", 2*time.Second)
+	r.RulePanics([]models.Diagnostic{{Path: "app/Secret.php", Rule: "Commented Functions Detector", Message: "boom"}})
+
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if len(received.Events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", received.Events)
+	}
+	if received.Events[1].Kind != KindSlowAnalyzer || received.Events[1].Analyzer != "html" {
+		t.Errorf("expected slow html event, got %+v", received.Events[1])
+	}
+
+	payload, _ := json.Marshal(received)
+	for _, leaked := range []string{"app/Secret.php", "secret-project"} {
+		if strings.Contains(string(payload), leaked) {
+			t.Errorf("report leaks %q: %s", leaked, payload)
+		}
+	}
+
+	// Nothing left to send
+	if err := r.Flush(); err != nil || requests != 1 {
+		t.Errorf("expected no second request, got %d requests (err %v)", requests, err)
+	}
+}
+
+func TestReporter_Nil(t *testing.T) {
+	var r *Reporter
+	r.Panic("boom", nil)
+	r.AnalyzerFailed("php", errors.New("failed"))
+	if err := r.Flush(); err != nil {
+		t.Errorf("nil reporter Flush returned %v", err)
+	}
+}
+
+func TestReporter_FlushError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	r := New(server.URL, 0)
+	r.Panic("boom", []byte("stack"))
+	if err := r.Flush(); err == nil {
+		t.Error("expected error for 500 response")
+	}
+}
+
+func TestReporter_AnalyzerFailedDropsPaths(t *testing.T) {
+	var received Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode report: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	path := "/home/alice/secret-repo/app/User.php"
+	pathErr := &fs.PathError{Op: "open", Path: path, Err: fs.ErrPermission}
+
+	r := New(server.URL, time.Second)
+	r.AnalyzerFailed("php", pathErr)
+	r.AnalyzerFailed("js", fmt.Errorf("walk %s: %w", path, &fs.PathError{Op: "lstat", Path: path, Err: errors.New("input/output error")}))
+	r.AnalyzerFailed("html", fmt.Errorf("scanning %s: %w", path, context.DeadlineExceeded))
+	r.AnalyzerFailed("sql", fmt.Errorf("parse %s failed", path))
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	want := []string{"permission (open)", "io (lstat)", "timeout", "*errors.errorString"}
+	if len(received.Events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), received.Events)
+	}
+	for i, w := range want {
+		if received.Events[i].Message != w {
+			t.Errorf("event %d: expected message %q, got %q", i, w, received.Events[i].Message)
+		}
+	}
+
+	payload, _ := json.Marshal(received)
+	for _, leaked := range []string{path, "alice", "secret-repo"} {
+		if strings.Contains(string(payload), leaked) {
+			t.Errorf("report leaks %q: %s", leaked, payload)
+		}
+	}
+}
+
+func TestReporter_PanicsDropPaths(t *testing.T) {
+	var received Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode report: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	path := "/home/alice/secret-repo/app/User.php"
+	r := New(server.URL, time.Second)
+	func() {
+		defer func() {
+			if p := recover(); p != nil {
+				r.Panic(p, []byte("goroutine 1 [running]:\nmain.main()"))
+			}
+		}()
+		panic(&fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist})
+	}()
+	r.Panic("cannot parse "+path, nil)
+	r.RulePanics([]models.Diagnostic{{
+		Path:    path,
+		Rule:    "Commented Functions Detector",
+		Message: "open " + path + ": permission denied",
+		Value:   &fs.PathError{Op: "open", Path: path, Err: fs.ErrPermission},
+	}})
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	want := []string{"not_exist (open)", "string", "permission (open)"}
+	if len(received.Events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), received.Events)
+	}
+	for i, w := range want {
+		if received.Events[i].Message != w {
+			t.Errorf("event %d: expected message %q, got %q", i, w, received.Events[i].Message)
+		}
+	}
+	if received.Events[0].Stack == "" {
+		t.Error("expected the panic stack to be sent")
+	}
+
+	payload, _ := json.Marshal(received)
+	for _, leaked := range []string{path, "alice", "secret-repo"} {
+		if strings.Contains(string(payload), leaked) {
+			t.Errorf("report leaks %q: %s", leaked, payload)
+		}
+	}
+}
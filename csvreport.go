@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// csvHeader names the columns of the csv_report
+var csvHeader = []string{"analyzer", "project", "rule", "category", "path", "line", "severity", "description", "first_seen"}
+
+// writeCSVReport writes one row per finding, ordered by path and line, for
+// spreadsheets
+func writeCSVReport(path string, findings []finding) error {
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := writeCSV(file, findings); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writeCSV writes the CSV report of findings to out
+func writeCSV(out io.Writer, findings []finding) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, f := range sortedByLocation(findings) {
+		row := []string{
+			f.Analyzer,
+			f.Project,
+			f.checkName(),
+			f.Issue.Category,
+			csvCell(f.Issue.Path),
+			strconv.Itoa(f.Issue.Line),
+			f.Issue.Severity,
+			csvCell(f.Issue.Description),
+			f.Issue.FirstSeen,
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvCell keeps spreadsheets from evaluating a value as a formula: text
+// starting with =, +, -, @ or a tab (e.g. a "=======" conflict marker in a
+// description) is prefixed with a quote
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestWriteCSV(t *testing.T) {
+	description := "Commented out \"legacy\" block, lines 3-9\nstill referenced"
+	findings := []finding{
+		{Analyzer: "php", Project: "api", Issue: models.Issue{Path: "app/b.php", Line: 9, Severity: "minor", RuleID: "php/commented-code", Category: models.CategoryDeadCode, Description: description, FirstSeen: "2026-01-02"}},
+		{Analyzer: "conflicts", Issue: models.Issue{Path: "app/a, b.php", Line: 3, Severity: "critical", Description: "Merge conflict marker: ======="}},
+	}
+
+	var out bytes.Buffer
+	if err := writeCSV(&out, findings); err != nil {
+		t.Fatalf("writeCSV failed: %v", err)
+	}
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("the report does not parse as CSV: %v\n%s", err, out.String())
+	}
+	want := [][]string{
+		csvHeader,
+		{"conflicts", "", "conflicts-check", "", "app/a, b.php", "3", "critical", "Merge conflict marker: =======", ""},
+		{"php", "api", "php/commented-code", models.CategoryDeadCode, "app/b.php", "9", "minor", description, "2026-01-02"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got\n%q\nwant\n%q", rows, want)
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"app/a.php", "app/a.php"},
+		{"=======", "'======="},
+		{"+1", "'+1"},
+		{"-x", "'-x"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcell", "'\tcell"},
+	}
+	for _, tt := range tests {
+		if got := csvCell(tt.value); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
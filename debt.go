@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/config"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// buildDebtReport ranks the files with the most issues of each severity and
+// the top-level directories of dir by the effort to fix their issues, then by
+// severity-weighted issue count
+func buildDebtReport(cfg config.DebtConfig, dir string, findings []finding) models.DebtReport {
+	maxFiles, maxDirectories := cfg.Files, cfg.Directories
+	if maxFiles == 0 {
+		maxFiles = 5
+	}
+	if maxDirectories == 0 {
+		maxDirectories = 10
+	}
+
+	perFile := make(map[string]map[string]int)
+	directories := make(map[string]*models.DirectoryDebt)
+	weights := make(map[string]int)
+	for _, f := range findings {
+		if perFile[f.Issue.Severity] == nil {
+			perFile[f.Issue.Severity] = make(map[string]int)
+		}
+		perFile[f.Issue.Severity][f.Issue.Path]++
+
+		name := topLevelDirectory(dir, f.Issue.Path)
+		d, ok := directories[name]
+		if !ok {
+			d = &models.DirectoryDebt{Directory: name, BySeverity: map[string]int{}}
+			directories[name] = d
+		}
+		d.Issues++
+		d.BySeverity[f.Issue.Severity]++
+		if f.Issue.Metadata != nil {
+			d.EffortMinutes += f.Issue.Metadata.EffortMinutes
+		}
+		weights[name] += severityWeight[f.Issue.Severity]
+	}
+
+	report := models.DebtReport{
+		Timestamp:       utils.GetTimestamp(),
+		FilesBySeverity: map[string][]models.FileDebt{},
+		Directories:     []models.DirectoryDebt{},
+	}
+	for _, severity := range severityOrder {
+		files := make([]models.FileDebt, 0, len(perFile[severity]))
+		for path, n := range perFile[severity] {
+			files = append(files, models.FileDebt{Path: path, Issues: n})
+		}
+		if len(files) == 0 {
+			continue
+		}
+		sort.Slice(files, func(i, j int) bool {
+			if files[i].Issues != files[j].Issues {
+				return files[i].Issues > files[j].Issues
+			}
+			return files[i].Path < files[j].Path
+		})
+		report.FilesBySeverity[severity] = files[:min(len(files), maxFiles)]
+	}
+
+	for _, d := range directories {
+		report.Directories = append(report.Directories, *d)
+	}
+	sort.Slice(report.Directories, func(i, j int) bool {
+		a, b := report.Directories[i], report.Directories[j]
+		if a.EffortMinutes != b.EffortMinutes {
+			return a.EffortMinutes > b.EffortMinutes
+		}
+		if weights[a.Directory] != weights[b.Directory] {
+			return weights[a.Directory] > weights[b.Directory]
+		}
+		return a.Directory < b.Directory
+	})
+	report.Directories = report.Directories[:min(len(report.Directories), maxDirectories)]
+	return report
+}
+
+// topLevelDirectory returns the first directory of path below dir, "." for
+// files directly in dir
+func topLevelDirectory(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	if first, _, ok := strings.Cut(rel, "/"); ok {
+		return first
+	}
+	return "."
+}
+
+// printDebtReport prints the worst files per severity and the debt per
+// top-level directory
+func printDebtReport(w io.Writer, report models.DebtReport) {
+	if len(report.Directories) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n🏚️  Worst files per severity:\n")
+	for _, severity := range severityOrder {
+		files := report.FilesBySeverity[severity]
+		if len(files) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  %s\n", utils.SeverityColor(severity, severity))
+		for _, f := range files {
+			fmt.Fprintf(w, "    %5d  %s\n", f.Issues, f.Path)
+		}
+	}
+
+	fmt.Fprintf(w, "\n📁 Debt by directory:\n")
+	fmt.Fprintf(w, "%-30s", "Directory")
+	for _, severity := range severityOrder {
+		fmt.Fprintf(w, " %9s", severity)
+	}
+	fmt.Fprintf(w, " %9s %9s\n", "total", "effort")
+	fmt.Fprintln(w, strings.Repeat("-", 30+10*(len(severityOrder)+2)))
+	for _, d := range report.Directories {
+		fmt.Fprintf(w, "%-30s", utils.Truncate(d.Directory, 30))
+		for _, severity := range severityOrder {
+			fmt.Fprintf(w, " %9d", d.BySeverity[severity])
+		}
+		fmt.Fprintf(w, " %9d %9s\n", d.Issues, formatEffort(d.EffortMinutes))
+	}
+}
+
+// formatEffort formats minutes of effort as e.g. "45m" or "3h20m"
+func formatEffort(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"sort"
+
+	"code-analyzer/utils"
+)
+
+// dedupeFindings merges findings with the same fingerprint, which analyzers
+// with overlapping file types produce when they report the same block (an
+// HTML comment in a .php file, say). The most severe finding is kept in the
+// place of the first one and lists every check that reported it under
+// `checks`. It returns the findings left and the number merged away.
+func dedupeFindings(findings []finding) ([]finding, int) {
+	first := make(map[string]int, len(findings))
+	checks := make(map[int][]string)
+	kept := make([]finding, 0, len(findings))
+	for _, f := range findings {
+		fingerprint := utils.Fingerprint(f.Issue)
+		i, seen := first[fingerprint]
+		if !seen {
+			first[fingerprint] = len(kept)
+			kept = append(kept, f)
+			continue
+		}
+		if checks[i] == nil {
+			checks[i] = []string{kept[i].checkName()}
+		}
+		checks[i] = append(checks[i], f.checkName())
+		if severityWeight[f.Issue.Severity] > severityWeight[kept[i].Issue.Severity] {
+			kept[i] = f
+		}
+	}
+
+	for i, names := range checks {
+		sort.Strings(names)
+		unique := names[:1]
+		for _, name := range names[1:] {
+			if name != unique[len(unique)-1] {
+				unique = append(unique, name)
+			}
+		}
+		if len(unique) > 1 {
+			kept[i].Issue.Checks = unique
+		}
+	}
+	return kept, len(findings) - len(kept)
+}
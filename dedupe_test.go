@@ -0,0 +1,117 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestDedupeFindings(t *testing.T) {
+	issue := func(severity, rule, description string, line int) models.Issue {
+		return models.Issue{Path: "app/page.php", Line: line, Description: description, Severity: severity, RuleID: rule}
+	}
+	block := "Commented out HTML code block (40 bytes)"
+
+	tests := []struct {
+		name     string
+		findings []finding
+		want     []finding
+		merged   int
+	}{
+		{
+			name: "distinct issues are kept",
+			findings: []finding{
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 9)},
+			},
+			want: []finding{
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 9)},
+			},
+		},
+		{
+			name: "the more severe finding wins in the place of the first",
+			findings: []finding{
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+				{Analyzer: "js", Issue: issue("minor", "js/commented-code", "Commented out JS code block (12 bytes)", 5)},
+				{Analyzer: "php", Issue: issue("major", "php/commented-code", block, 3)},
+			},
+			want: []finding{
+				{Analyzer: "php", Issue: func() models.Issue {
+					i := issue("major", "php/commented-code", block, 3)
+					i.Checks = []string{"html/commented-code", "php/commented-code"}
+					return i
+				}()},
+				{Analyzer: "js", Issue: issue("minor", "js/commented-code", "Commented out JS code block (12 bytes)", 5)},
+			},
+			merged: 1,
+		},
+		{
+			name: "equal severity keeps the first finding",
+			findings: []finding{
+				{Analyzer: "php", Issue: issue("minor", "php/commented-code", block, 3)},
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+			},
+			want: []finding{
+				{Analyzer: "php", Issue: func() models.Issue {
+					i := issue("minor", "php/commented-code", block, 3)
+					i.Checks = []string{"html/commented-code", "php/commented-code"}
+					return i
+				}()},
+			},
+			merged: 1,
+		},
+		{
+			name: "checks are sorted and unique",
+			findings: []finding{
+				{Analyzer: "structured", Issue: issue("major", "structured/conflict", block, 3)},
+				{Analyzer: "conflicts", Issue: issue("major", "conflicts/marker", block, 3)},
+				{Analyzer: "structured", Issue: issue("major", "structured/conflict", block, 3)},
+				{Analyzer: "conflicts", Issue: issue("major", "conflicts/marker", block, 3)},
+			},
+			want: []finding{
+				{Analyzer: "structured", Issue: func() models.Issue {
+					i := issue("major", "structured/conflict", block, 3)
+					i.Checks = []string{"conflicts/marker", "structured/conflict"}
+					return i
+				}()},
+			},
+			merged: 3,
+		},
+		{
+			name: "repeats of one check list no checks",
+			findings: []finding{
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+			},
+			want: []finding{
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+			},
+			merged: 1,
+		},
+		{
+			name: "differently worded descriptions are not merged",
+			findings: []finding{
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+				{Analyzer: "php", Issue: issue("minor", "php/commented-code", "Commented out PHP code block (40 bytes)", 3)},
+			},
+			want: []finding{
+				{Analyzer: "html", Issue: issue("minor", "html/commented-code", block, 3)},
+				{Analyzer: "php", Issue: issue("minor", "php/commented-code", "Commented out PHP code block (40 bytes)", 3)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, merged := dedupeFindings(tt.findings)
+			if merged != tt.merged {
+				t.Errorf("expected %d merged, got %d", tt.merged, merged)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/config"
+	"code-analyzer/utils"
+)
+
+// defaultConfigFile is read when -config is not given
+const defaultConfigFile = "analysis-config.yaml"
+
+// embeddedDefaultConfig is the default config shipped inside the binary, used
+// when no config file exists so the tool runs with zero setup
+//
+//go:embed analysis-config.yaml
+var embeddedDefaultConfig []byte
+
+// loadConfig loads the config at path. When -config was not given (explicit
+// is false) and the default file does not exist, the embedded default is used
+// and builtin is true.
+func loadConfig(path string, explicit bool) (cfg *config.AppConfig, builtin bool, err error) {
+	cfg, err = config.LoadConfig(path)
+	if err == nil || explicit || !errors.Is(err, os.ErrNotExist) {
+		return cfg, false, err
+	}
+
+	utils.Infof("ℹ️  No %s found, using the built-in default config (see -print-default-config)\n", path)
+	cfg, err = config.ParseConfig(embeddedDefaultConfig)
+	return cfg, true, err
+}
+
+// autoDetect enables, among the language analyzers the config enables, only
+// those that handle at least one file in the scan root, so a zero-config run
+// does not report on languages the project does not use. Language-agnostic
+// analyzers keep their setting. It returns the analyzers left enabled.
+func autoDetect(cfg *config.AppConfig, all map[string]analyzers.Analyzer) []string {
+	candidates := make(map[string]scheduledAnalyzer)
+	for name, ac := range cfg.Analyzers {
+		if !ac.Enabled {
+			continue
+		}
+		if _, ok := all[name].(analyzers.FileMatcher); ok {
+			candidates[name] = scheduledAnalyzer{
+				Analyzer: all[name],
+				Config:   analyzers.Config{ExcludePaths: ac.Exclude, Extensions: ac.Extensions},
+			}
+		}
+	}
+
+	found := make(map[string]bool)
+	_ = utils.Walk(cfg.Dir, cfg.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		for name, s := range candidates {
+			if found[name] || utils.ShouldSkip(path, s.Config.ExcludePaths) {
+				continue
+			}
+			if s.Analyzer.(analyzers.FileMatcher).Handles(path, s.Config) {
+				found[name] = true
+			}
+		}
+		// Every language is present; no need to look further
+		if len(found) == len(candidates) {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+
+	var enabled []string
+	for name, ac := range cfg.Analyzers {
+		if _, ok := candidates[name]; ok && !found[name] {
+			ac.Enabled = false
+			cfg.Analyzers[name] = ac
+		}
+		if ac.Enabled {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	return enabled
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/config"
+	"code-analyzer/models"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
+)
+
+// engineConfig is the /config.json a Code Climate engine receives
+type engineConfig struct {
+	IncludePaths []string `json:"include_paths"`
+	// Config is the engine-specific section; it holds an analysis config
+	Config json.RawMessage `json:"config"`
+}
+
+// runEngine runs as a Code Climate engine: it reads the engine config, scans
+// the code directory and streams every issue to stdout as JSON followed by a
+// NUL byte. Logs go to stderr. It exits 1 when an analyzer fails.
+func runEngine(args []string) {
+	fs := flag.NewFlagSet("engine", flag.ExitOnError)
+	configJSON := fs.String("config-json", "/config.json", "Engine config written by the Code Climate runner")
+	codeDir := fs.String("code", "/code", "Directory holding the code to analyze")
+	_ = fs.Parse(args)
+
+	// Only issues may reach stdout
+	stdout = io.Discard
+
+	cfg, include, err := loadEngineConfig(*configJSON, *codeDir)
+	if err != nil {
+		utils.Errorf("❌ Failed to load engine config: %v\n", err)
+		os.Exit(1)
+	}
+	utils.SetHardLinkDedup(!cfg.UnstableInodes)
+	policies, err := loadPolicies(cfg)
+	if err != nil {
+		utils.Errorf("❌ Failed to load policies: %v\n", err)
+		os.Exit(1)
+	}
+	all := newAnalyzers()
+	docs := newRuleDocs(cfg.RuleDocs, all)
+
+	names := make([]string, 0, len(cfg.Analyzers))
+	for name, analyzerCfg := range cfg.Analyzers {
+		if analyzerCfg.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := bufio.NewWriter(os.Stdout)
+	failed := false
+	for _, name := range names {
+		analyzer, ok := all[name]
+		if !ok {
+			utils.Warnf("⚠️  Unknown analyzer in config: %s\n", name)
+			continue
+		}
+
+		runConfig := analyzerRunConfig(cfg, name, cfg.Analyzers[name])
+		runConfig.Quiet = true
+		issues, err := analyzer.Run(context.Background(), runConfig)
+		if err != nil {
+			utils.Errorf("❌ Analyzer %s failed: %v\n", name, err)
+			failed = true
+			continue
+		}
+
+		for _, issue := range issues {
+			rel, err := filepath.Rel(*codeDir, issue.Path)
+			if err != nil {
+				rel = issue.Path
+			}
+			issue.Path = filepath.ToSlash(rel)
+			if !engineIncludes(include, issue.Path) {
+				continue
+			}
+			issue.Analyzer = name
+			docs.Apply(&issue)
+			policies.Apply(name, &issue)
+			if belowMinSeverity(cfg, issue) {
+				continue
+			}
+			if err := writeEngineIssue(out, finding{Analyzer: name, Issue: issue}); err != nil {
+				utils.Errorf("❌ Failed to write issue: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		// Stream each analyzer's issues as soon as they are known
+		if err := out.Flush(); err != nil {
+			utils.Errorf("❌ Failed to write issues: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// loadEngineConfig builds the analysis config for engine mode. The engine
+// config's `config` section wins, then an analysis-config.yaml in the code
+// directory, then the built-in default with language auto-detection. The
+// scan always covers the code directory and writes no artifacts or reports.
+func loadEngineConfig(configJSON, codeDir string) (*config.AppConfig, []string, error) {
+	var engine engineConfig
+	data, err := os.ReadFile(configJSON)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &engine); err != nil {
+			return nil, nil, err
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, nil, err
+	}
+
+	var cfg *config.AppConfig
+	detect := false
+	if raw := strings.TrimSpace(string(engine.Config)); raw != "" && raw != "null" && raw != "{}" {
+		cfg, err = config.ParseConfig(engine.Config)
+	} else if cfg, err = config.LoadConfig(filepath.Join(codeDir, defaultConfigFile)); errors.Is(err, os.ErrNotExist) {
+		cfg, err = config.ParseConfig(embeddedDefaultConfig)
+		detect = true
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg.Dir = codeDir
+	cfg.Output = ""
+	if detect {
+		autoDetect(cfg, newAnalyzers())
+	}
+	return cfg, engine.IncludePaths, nil
+}
+
+// engineIncludes reports whether path is covered by the engine's
+// include_paths: a listed file, or a file under a listed directory (ending
+// in "/"). Without include_paths every file is included.
+func engineIncludes(include []string, path string) bool {
+	if len(include) == 0 {
+		return true
+	}
+	for _, p := range include {
+		p = strings.TrimPrefix(p, "./")
+		if path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeEngineIssue writes one issue in the Code Climate format, NUL-terminated
+func writeEngineIssue(w io.Writer, f finding) error {
+	line := max(f.Issue.Line, 1)
+	end := max(endLine(f), line)
+	data, err := json.Marshal(models.CodeClimateIssue{
+		Type:        "issue",
+		CheckName:   f.checkName(),
+		Description: f.Issue.Description,
+		Categories:  []string{codeClimateCategory(f.Issue)},
+		Location: models.Location{
+			Path:  f.Issue.Path,
+			Lines: models.Lines{Begin: line, End: end},
+		},
+		Severity:          severity.Format(severity.GitLab, f.Issue.Severity),
+		Fingerprint:       utils.Fingerprint(f.Issue),
+		RemediationPoints: remediationPoints(f.Issue),
+		Content:           docsContent(f.Issue),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, 0))
+	return err
+}
@@ -0,0 +1,93 @@
+package examples
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "Regenerate expected outputs")
+
+// profiles maps each example config to the outputs it is expected to produce
+var profiles = map[string][]string{
+	"mr": {
+		"gl-code-quality-report.json",
+		"gl-code-quality-report.meta.json",
+		"conflicts-analysis.json",
+		"php-analysis.json",
+		"new-issues.json",
+	},
+	"nightly": {
+		"gl-code-quality-report.json",
+		"gl-code-quality-report.meta.json",
+		"conflicts-analysis.json",
+		"html-analysis.json",
+		"js-analysis.json",
+		"php-analysis.json",
+		"size-analysis.json",
+		"results.sarif",
+	},
+}
+
+// durations vary between runs and are not compared
+var durations = regexp.MustCompile(`"duration_ms": \d+`)
+
+// issues never seen before are first seen on the day of the run
+var today = []byte(`"first_seen": "` + time.Now().Format("2006-01-02") + `"`)
+
+// TestVerifyExamples runs the tool against the fixture project with each
+// profile and compares the produced artifacts with examples/expected
+func TestVerifyExamples(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "code-analyzer")
+	build := exec.Command("go", "build", "-o", binary, "..")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\n%s", err, out)
+	}
+
+	for profile, outputs := range profiles {
+		t.Run(profile, func(t *testing.T) {
+			outDir := filepath.Join("out", profile)
+			if err := os.RemoveAll(outDir); err != nil {
+				t.Fatalf("Failed to clean output directory: %v", err)
+			}
+
+			cmd := exec.Command(binary, "-config", profile+"-config.yaml")
+			cmd.Env = append(os.Environ(), "CI_PIPELINE_ID=example")
+			// Only the artifacts matter, not the console output or exit code
+			_ = cmd.Run()
+
+			for _, name := range outputs {
+				actual, err := os.ReadFile(filepath.Join(outDir, name))
+				if err != nil {
+					t.Fatalf("Expected output %s was not produced: %v", name, err)
+				}
+				actual = durations.ReplaceAll(actual, []byte(`"duration_ms": 0`))
+				actual = bytes.ReplaceAll(actual, today, []byte(`"first_seen": "today"`))
+
+				expectedPath := filepath.Join("expected", profile, name)
+				if *update {
+					if err := os.MkdirAll(filepath.Dir(expectedPath), 0755); err != nil {
+						t.Fatalf("Failed to create expected directory: %v", err)
+					}
+					if err := os.WriteFile(expectedPath, actual, 0644); err != nil {
+						t.Fatalf("Failed to update %s: %v", expectedPath, err)
+					}
+					continue
+				}
+
+				expected, err := os.ReadFile(expectedPath)
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", expectedPath, err)
+				}
+				if !bytes.Equal(actual, expected) {
+					t.Errorf("%s differs from %s (run `go test ./examples -update` to accept)\n--- got ---\n%s", name, expectedPath, actual)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+<?php
+
+namespace App\Http\Controllers;
+
+use App\Models\Payment;
+
+class PaymentController extends Controller
+{
+    public function index()
+    {
+        $payments = Payment::latest()->paginate(20);
+        foreach ($payments as $payment) {
+            $payment->customer_name = $payment->customer->name;
+        }
+
+        return view('payments.index', ['payments' => $payments]);
+    }
+
+    public function store()
+    {
+        return redirect('/payments');
+    }
+}
@@ -0,0 +1,23 @@
+<?php
+
+namespace App\Payments;
+
+class Gateway
+{
+    public function charge($amount)
+    {
+        return $this->client->post('/charge', ['amount' => $amount]);
+    }
+
+    // public function refund($id)
+    // {
+    //     return $this->client->post('/refund', ['id' => $id]);
+    // }
+
+    /*
+    private function legacyCharge($amount)
+    {
+        return false;
+    }
+    */
+}
@@ -0,0 +1,16 @@
+import { render } from './render';
+
+// Boot the application
+render(document.getElementById('app'));
+
+// const legacy = require('./legacy');
+// legacy.init();
+
+/*
+function oldRender(el) {
+    return el.innerHTML = '';
+}
+*/
+
+// KEEP: re-enable once the analytics consent banner ships
+// trackPageView(window.location.pathname);
@@ -0,0 +1,16 @@
+<?php
+
+use App\Http\Controllers\PaymentController;
+use Illuminate\Support\Facades\Route;
+
+Route::get('/', function () {
+    return view('welcome');
+});
+
+Route::post('/payments', [PaymentController::class, 'store']);
+
+Route::post('/payments/{id}/refund', function ($id) {
+    $payment = Payment::findOrFail($id);
+    $payment->update(['refunded' => true]);
+    return redirect('/payments');
+});
@@ -0,0 +1,152 @@
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Entry is one issue a user marked as a false positive
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	// Rule is the check name of the issue (e.g. "php/commented-function")
+	Rule   string `json:"rule"`
+	Path   string `json:"path,omitempty"`
+	Reason string `json:"reason"`
+	// MarkedAt is the date (YYYY-MM-DD) the issue was marked
+	MarkedAt string `json:"marked_at"`
+}
+
+// Store is the local set of issues marked as false positives
+type Store struct {
+	Entries []Entry `json:"entries"`
+
+	index map[string]int
+}
+
+// Load reads a store file; a missing file yields an empty store
+func Load(file string) (*Store, error) {
+	s := &Store{}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		s.buildIndex()
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse false positives %s: %v", file, err)
+	}
+	s.buildIndex()
+	return s, nil
+}
+
+func (s *Store) buildIndex() {
+	s.index = make(map[string]int, len(s.Entries))
+	for i, e := range s.Entries {
+		s.index[e.Fingerprint] = i
+	}
+}
+
+// Save writes the store to file
+func (s *Store) Save(file string) error {
+	return utils.WriteArtifact(file, s)
+}
+
+// Mark records an entry, replacing an earlier one with the same fingerprint
+func (s *Store) Mark(e Entry) {
+	if i, ok := s.index[e.Fingerprint]; ok {
+		s.Entries[i] = e
+		return
+	}
+	s.index[e.Fingerprint] = len(s.Entries)
+	s.Entries = append(s.Entries, e)
+}
+
+// Unmark removes the entry with the fingerprint and reports whether it existed
+func (s *Store) Unmark(fingerprint string) bool {
+	i, ok := s.index[fingerprint]
+	if !ok {
+		return false
+	}
+	s.Entries = append(s.Entries[:i], s.Entries[i+1:]...)
+	s.buildIndex()
+	return true
+}
+
+// Contains reports whether the fingerprint was marked as a false positive
+func (s *Store) Contains(fingerprint string) bool {
+	_, ok := s.index[fingerprint]
+	return ok
+}
+
+// Filter returns the issues that were not marked as false positives
+func (s *Store) Filter(issues []models.Issue) []models.Issue {
+	if len(s.Entries) == 0 {
+		return issues
+	}
+	var kept []models.Issue
+	for _, issue := range issues {
+		if !s.Contains(utils.Fingerprint(issue)) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// RuleAggregate counts the false positives of one rule without any
+// fingerprint, path or date
+type RuleAggregate struct {
+	Rule           string `json:"rule"`
+	FalsePositives int    `json:"false_positives"`
+	// Extensions counts false positives per file extension ("" for none)
+	Extensions map[string]int `json:"extensions"`
+	// Reasons counts false positives per reason, lowercased and trimmed
+	Reasons map[string]int `json:"reasons"`
+}
+
+// Aggregates is the anonymized export shared with rule authors
+type Aggregates struct {
+	Timestamp      string          `json:"timestamp"`
+	FalsePositives int             `json:"false_positives"`
+	Rules          []RuleAggregate `json:"rules"`
+}
+
+// Aggregate summarizes the store per rule, most false positives first
+func (s *Store) Aggregate() Aggregates {
+	byRule := make(map[string]*RuleAggregate)
+	for _, e := range s.Entries {
+		agg := byRule[e.Rule]
+		if agg == nil {
+			agg = &RuleAggregate{Rule: e.Rule, Extensions: map[string]int{}, Reasons: map[string]int{}}
+			byRule[e.Rule] = agg
+		}
+		agg.FalsePositives++
+		agg.Extensions[strings.ToLower(path.Ext(e.Path))]++
+		agg.Reasons[strings.ToLower(strings.TrimSpace(e.Reason))]++
+	}
+
+	report := Aggregates{
+		Timestamp:      utils.GetTimestamp(),
+		FalsePositives: len(s.Entries),
+		Rules:          make([]RuleAggregate, 0, len(byRule)),
+	}
+	for _, agg := range byRule {
+		report.Rules = append(report.Rules, *agg)
+	}
+	sort.Slice(report.Rules, func(i, j int) bool {
+		if report.Rules[i].FalsePositives != report.Rules[j].FalsePositives {
+			return report.Rules[i].FalsePositives > report.Rules[j].FalsePositives
+		}
+		return report.Rules[i].Rule < report.Rules[j].Rule
+	})
+	return report
+}
@@ -0,0 +1,62 @@
+package feedback
+
+import (
+	"path/filepath"
+	"testing"
+
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+func TestMarkSaveLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "false-positives.json")
+	s, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Mark(Entry{Fingerprint: "a", Rule: "php-check", Reason: "docblock"})
+	s.Mark(Entry{Fingerprint: "b", Rule: "sql-check", Reason: "not a query"})
+	s.Mark(Entry{Fingerprint: "a", Rule: "php-check", Reason: "example code"})
+	if err := s.Save(file); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Entries) != 2 || loaded.Entries[0].Reason != "example code" {
+		t.Errorf("unexpected entries %+v", loaded.Entries)
+	}
+	if !loaded.Unmark("a") || loaded.Unmark("a") || loaded.Contains("a") || !loaded.Contains("b") {
+		t.Errorf("unexpected entries after unmark %+v", loaded.Entries)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	keep := models.Issue{Path: "a.php", Line: 1, Description: "kept"}
+	drop := models.Issue{Path: "a.php", Line: 2, Description: "dropped"}
+	s, _ := Load(filepath.Join(t.TempDir(), "missing.json"))
+	s.Mark(Entry{Fingerprint: utils.Fingerprint(drop), Rule: "php-check"})
+
+	got := s.Filter([]models.Issue{keep, drop})
+	if len(got) != 1 || got[0].Description != "kept" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	s, _ := Load(filepath.Join(t.TempDir(), "missing.json"))
+	s.Mark(Entry{Fingerprint: "1", Rule: "php-check", Path: "app/A.php", Reason: "Docblock "})
+	s.Mark(Entry{Fingerprint: "2", Rule: "php-check", Path: "app/B.PHP", Reason: "docblock"})
+	s.Mark(Entry{Fingerprint: "3", Rule: "sql-check", Path: "db/seed.sql", Reason: "seed data"})
+
+	agg := s.Aggregate()
+	if agg.FalsePositives != 3 || len(agg.Rules) != 2 {
+		t.Fatalf("got %+v", agg)
+	}
+	php := agg.Rules[0]
+	if php.Rule != "php-check" || php.FalsePositives != 2 || php.Extensions[".php"] != 2 || php.Reasons["docblock"] != 2 {
+		t.Errorf("got %+v", php)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"code-analyzer/feedback"
+	"code-analyzer/utils"
+)
+
+// runFeedbackCommand handles `code-analyzer feedback <mark|unmark|list|export>` and exits
+func runFeedbackCommand(args []string) {
+	usage := "Usage: code-analyzer feedback mark|unmark|list|export [--config <file>] [--file <false-positives.json>] ...\n"
+	if len(args) == 0 {
+		utils.Errorf("%s", usage)
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "mark", "unmark", "list", "export":
+	default:
+		utils.Errorf("%s", usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("feedback "+args[0], flag.ExitOnError)
+	configFile := fs.String("config", defaultConfigFile, "Path to YAML or JSON configuration file naming the false positives store")
+	file := fs.String("file", "", "False positives store (overrides `false_positives` in the config)")
+	fingerprint := fs.String("fingerprint", "", "Fingerprint of the issue, as in the GitLab report or IDE output (mark, unmark)")
+	rule := fs.String("rule", "", "Check name of the issue, e.g. php-check (mark)")
+	path := fs.String("path", "", "File of the issue; only its extension is exported (mark)")
+	reason := fs.String("reason", "", "Why the issue is a false positive; exported as written, so keep it generic (mark)")
+	output := fs.String("output", "", "Write the anonymized aggregates to this file instead of stdout (export)")
+	_ = fs.Parse(args[1:])
+
+	store := *file
+	if store == "" {
+		cfg, _, err := loadConfig(*configFile, false)
+		if err != nil {
+			utils.Errorf("❌ Failed to load config file: %v\n", err)
+			os.Exit(2)
+		}
+		store = cfg.FalsePositives
+	}
+	if store == "" {
+		utils.Errorf("❌ No false positives store: set `false_positives` in the config or pass --file\n")
+		os.Exit(2)
+	}
+
+	s, err := feedback.Load(store)
+	if err != nil {
+		utils.Errorf("❌ Failed to load false positives: %v\n", err)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "mark":
+		if *fingerprint == "" || *rule == "" || strings.TrimSpace(*reason) == "" {
+			utils.Errorf("❌ feedback mark requires --fingerprint, --rule and --reason\n")
+			os.Exit(2)
+		}
+		s.Mark(feedback.Entry{
+			Fingerprint: *fingerprint,
+			Rule:        *rule,
+			Path:        *path,
+			Reason:      strings.TrimSpace(*reason),
+			MarkedAt:    time.Now().Format("2006-01-02"),
+		})
+		saveFeedback(s, store)
+		fmt.Printf("✅ Marked %s as a false positive of %s (%s)\n", *fingerprint, *rule, store)
+	case "unmark":
+		if *fingerprint == "" {
+			utils.Errorf("❌ feedback unmark requires --fingerprint\n")
+			os.Exit(2)
+		}
+		if !s.Unmark(*fingerprint) {
+			utils.Errorf("❌ %s is not marked as a false positive\n", *fingerprint)
+			os.Exit(1)
+		}
+		saveFeedback(s, store)
+		fmt.Printf("✅ Unmarked %s\n", *fingerprint)
+	case "list":
+		if len(s.Entries) == 0 {
+			fmt.Printf("No false positives recorded in %s\n", store)
+			return
+		}
+		fmt.Printf("%-32s %-14s %-10s %s\n", "Fingerprint", "Rule", "Marked", "Reason")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, e := range s.Entries {
+			fmt.Printf("%-32s %-14s %-10s %s\n", e.Fingerprint, e.Rule, e.MarkedAt, e.Reason)
+		}
+	case "export":
+		report := s.Aggregate()
+		if *output != "" {
+			if err := utils.WriteArtifact(*output, report); err != nil {
+				utils.Errorf("❌ Failed to write aggregates: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ False positive aggregates written: %s (%d rules)\n", *output, len(report.Rules))
+			return
+		}
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	}
+}
+
+func saveFeedback(s *feedback.Store, file string) {
+	if err := s.Save(file); err != nil {
+		utils.Errorf("❌ Failed to save false positives: %v\n", err)
+		os.Exit(1)
+	}
+}
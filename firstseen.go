@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code-analyzer/baseline"
+	"code-analyzer/history"
+	"code-analyzer/utils"
+)
+
+// firstSeenLayout is the format of Issue.FirstSeen
+const firstSeenLayout = "2006-01-02"
+
+// stampFirstSeen dates each finding with the day it was first observed: the
+// earliest run of the history file that reported it or the day its baseline
+// entry was accepted, whichever came first. Issues seen nowhere are first
+// seen today. A missing history file is not an error; base may be nil.
+func stampFirstSeen(historyFile string, base *baseline.Baseline, findings []finding, now time.Time) error {
+	first := make(map[string]string)
+	if historyFile != "" {
+		runs, err := history.Load(historyFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		first = history.FirstSeen(runs)
+	}
+	if base != nil {
+		// Expired entries still tell when their issue was accepted
+		for _, e := range base.Entries {
+			if seen, ok := first[e.Fingerprint]; e.AddedAt != "" && (!ok || e.AddedAt < seen) {
+				first[e.Fingerprint] = e.AddedAt
+			}
+		}
+	}
+
+	today := now.Format(firstSeenLayout)
+	for i := range findings {
+		date, ok := first[utils.Fingerprint(findings[i].Issue)]
+		if !ok {
+			date = today
+		}
+		findings[i].Issue.FirstSeen = date
+	}
+	return nil
+}
+
+// olderThan keeps the findings first seen more than days before now
+func olderThan(findings []finding, days int, now time.Time) []finding {
+	cutoff := now.AddDate(0, 0, -days).Format(firstSeenLayout)
+	var kept []finding
+	for _, f := range findings {
+		if f.Issue.FirstSeen < cutoff {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,167 @@
+package fix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fixable lists the analyzers whose issues are commented-out code blocks
+// that can be deleted: nothing else is ever removed
+var Fixable = map[string]bool{"html": true, "js": true, "php": true}
+
+// contextLines is the number of unchanged lines around each hunk of a diff
+const contextLines = 3
+
+// Range is a 1-based, inclusive range of lines
+type Range struct {
+	Start int
+	End   int
+}
+
+// Skipped is a range that was not removed, and why
+type Skipped struct {
+	Range
+	Reason string
+}
+
+// Remove deletes the given line ranges from content. Overlapping ranges are
+// merged. A range is only removed when its lines hold nothing but a comment,
+// so a block sharing a line with live code is skipped rather than cut.
+func Remove(content string, ranges []Range) (fixed string, removed []Range, skipped []Skipped) {
+	lines := splitLines(content)
+	drop := make([]bool, len(lines))
+
+	for _, r := range merge(ranges) {
+		if r.Start < 1 || r.End > len(lines) || r.Start > r.End {
+			skipped = append(skipped, Skipped{r, "lines are out of range"})
+			continue
+		}
+		if reason := commentOnly(lines[r.Start-1 : r.End]); reason != "" {
+			skipped = append(skipped, Skipped{r, reason})
+			continue
+		}
+		for i := r.Start - 1; i < r.End; i++ {
+			drop[i] = true
+		}
+		removed = append(removed, r)
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		if !drop[i] {
+			b.WriteString(line)
+		}
+	}
+	return b.String(), removed, skipped
+}
+
+// Diff returns a unified diff, applicable with `git apply`, that deletes the
+// removed ranges (as returned by Remove) from content
+func Diff(path, content string, removed []Range) string {
+	if len(removed) == 0 {
+		return ""
+	}
+	lines := splitLines(content)
+	drop := make([]bool, len(lines)+1)
+	for _, r := range removed {
+		for i := r.Start; i <= r.End; i++ {
+			drop[i] = true
+		}
+	}
+
+	// Hunks cover each range plus context; close ones share a hunk
+	var hunks []Range
+	for _, r := range removed {
+		h := Range{Start: max(1, r.Start-contextLines), End: min(len(lines), r.End+contextLines)}
+		if n := len(hunks); n > 0 && h.Start <= hunks[n-1].End+1 {
+			hunks[n-1].End = h.End
+			continue
+		}
+		hunks = append(hunks, h)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", path, path, path, path)
+	dropped := 0
+	for _, h := range hunks {
+		oldCount := h.End - h.Start + 1
+		inHunk := 0
+		for i := h.Start; i <= h.End; i++ {
+			if drop[i] {
+				inHunk++
+			}
+		}
+		newStart, newCount := h.Start-dropped, oldCount-inHunk
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.Start, oldCount, newStart, newCount)
+		for i := h.Start; i <= h.End; i++ {
+			prefix := " "
+			if drop[i] {
+				prefix = "-"
+			}
+			line := lines[i-1]
+			b.WriteString(prefix + strings.TrimSuffix(line, "\n") + "\n")
+			if !strings.HasSuffix(line, "\n") {
+				b.WriteString("\\ No newline at end of file\n")
+			}
+		}
+		dropped += inHunk
+	}
+	return b.String()
+}
+
+// splitLines splits content into lines that keep their terminators
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// merge sorts ranges and merges overlapping ones
+func merge(ranges []Range) []Range {
+	sorted := append([]Range{}, ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var merged []Range
+	for _, r := range sorted {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// commentOnly returns why lines are not a whole comment, or "" when they
+// are: all line comments, or one block comment opening at the start of the
+// first line and closing at the end of the last
+func commentOnly(lines []string) string {
+	lineComments := true
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "//") && !strings.HasPrefix(trimmed, "#") {
+			lineComments = false
+			break
+		}
+	}
+	if lineComments {
+		return ""
+	}
+
+	block := strings.TrimSpace(strings.Join(lines, ""))
+	for _, delims := range [][2]string{{"/*", "*/"}, {"<!--", "-->"}} {
+		if strings.HasPrefix(block, delims[0]) {
+			if strings.Index(block, delims[1]) == len(block)-len(delims[1]) {
+				return ""
+			}
+			return "the comment does not end the block's last line"
+		}
+	}
+	return "the block shares lines with code"
+}
@@ -0,0 +1,72 @@
+package fix
+
+import "testing"
+
+func TestRemove(t *testing.T) {
+	content := `<?php
+class User
+{
+    // public function old()
+    // {
+    //     return 1;
+    // }
+
+    public function name() { return 'a'; } /* function legacy() {
+    } */
+
+    /*
+    function older() {}
+    */
+}
+`
+	fixed, removed, skipped := Remove(content, []Range{{4, 7}, {9, 10}, {12, 14}, {13, 13}})
+
+	want := `<?php
+class User
+{
+
+    public function name() { return 'a'; } /* function legacy() {
+    } */
+
+}
+`
+	if fixed != want {
+		t.Errorf("got\n%s\nwant\n%s", fixed, want)
+	}
+	if len(removed) != 2 || removed[0] != (Range{4, 7}) || removed[1] != (Range{12, 14}) {
+		t.Errorf("unexpected removed ranges %+v", removed)
+	}
+	if len(skipped) != 1 || skipped[0].Range != (Range{9, 10}) {
+		t.Errorf("unexpected skipped ranges %+v", skipped)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	content := "a\nb\n// x\n// y\nc\nd\ne\nf\ng\nh\ni\n<!-- <p>z</p> -->"
+	fixed, removed, _ := Remove(content, []Range{{3, 4}, {12, 12}})
+	if fixed != "a\nb\nc\nd\ne\nf\ng\nh\ni\n" {
+		t.Fatalf("unexpected fixed content %q", fixed)
+	}
+
+	want := `diff --git a/x.html b/x.html
+--- a/x.html
++++ b/x.html
+@@ -1,7 +1,5 @@
+ a
+ b
+-// x
+-// y
+ c
+ d
+ e
+@@ -9,4 +7,3 @@
+ g
+ h
+ i
+-<!-- <p>z</p> -->
+\ No newline at end of file
+`
+	if got := Diff("x.html", content, removed); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
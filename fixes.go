@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/fix"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// runFixes applies planned removals: in place (-fix), printed on stdout as a
+// diff (-fix-dry-run) or written to patchPath as a diff (-fix-patch)
+func runFixes(fixes []fileFix, dryRun bool, patchPath string) {
+	reportSkippedFixes(fixes)
+	files, removed, skipped := fixCounts(fixes)
+
+	switch {
+	case dryRun:
+		fmt.Print(fixesPatch(fixes))
+		utils.Infof("🧹 Would remove %d commented-out code blocks in %d files (%d skipped)\n", removed, files, skipped)
+	case patchPath != "":
+		if err := writeFixesPatch(patchPath, fixes); err != nil {
+			utils.Errorf("❌ Failed to write fix patch: %v\n", err)
+			return
+		}
+		utils.Infof("🧹 Fix patch written: %s (%d commented-out code blocks in %d files, %d skipped)\n", patchPath, removed, files, skipped)
+	default:
+		n, err := applyFixes(fixes)
+		if err != nil {
+			utils.Errorf("❌ Failed to apply fixes after removing %d blocks: %v\n", n, err)
+			return
+		}
+		utils.Infof("🧹 Removed %d commented-out code blocks in %d files (%d skipped)\n", removed, files, skipped)
+	}
+}
+
+// writeFixesPatch writes the diff of the planned removals to path
+func writeFixesPatch(path string, fixes []fileFix) error {
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, []byte(fixesPatch(fixes)), 0644)
+}
+
+// fileFix is the planned removal of the commented-out blocks of one file
+type fileFix struct {
+	Path string
+	// PatchPath is Path as it appears in the diff headers
+	PatchPath string
+	Content   string
+	Fixed     string
+	Removed   []fix.Range
+	Skipped   []fix.Skipped
+}
+
+// planFixes works out, per file, which commented-out code blocks reported by
+// the fixable analyzers can be deleted. Nothing is written. root is the scan
+// directory the issue paths start from.
+func planFixes(findings []finding, root string) ([]fileFix, error) {
+	ranges := make(map[string][]fix.Range)
+	for _, f := range findings {
+		if !fix.Fixable[f.Analyzer] || f.Issue.Category != models.CategoryDeadCode || f.Issue.Line < 1 || f.Issue.Metadata == nil || f.Issue.Metadata.LineSpan < 1 {
+			continue
+		}
+		ranges[f.Issue.Path] = append(ranges[f.Issue.Path], fix.Range{
+			Start: f.Issue.Line,
+			End:   f.Issue.Line + f.Issue.Metadata.LineSpan - 1,
+		})
+	}
+
+	paths := make([]string, 0, len(ranges))
+	for path := range ranges {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	patchPath, err := patchPaths(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixes []fileFix
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		ff := fileFix{Path: path, Content: string(data)}
+		if ff.PatchPath, err = patchPath(path); err != nil {
+			return nil, err
+		}
+		ff.Fixed, ff.Removed, ff.Skipped = fix.Remove(ff.Content, ranges[path])
+		fixes = append(fixes, ff)
+	}
+	return fixes, nil
+}
+
+// patchPaths returns a function mapping issue paths under root to diff paths.
+// Those are relative to the top of the git work tree holding root, so the
+// patch applies with git apply, or to root itself outside a work tree.
+func patchPaths(root string) (func(string) (string, error), error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	// git reports the top level with symlinks resolved
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, err
+	}
+	base := resolved
+	if out, err := exec.Command("git", "-C", resolved, "rev-parse", "--show-toplevel").Output(); err == nil {
+		if top := strings.TrimSpace(string(out)); top != "" {
+			base = filepath.Clean(top)
+		}
+	}
+
+	return func(path string) (string, error) {
+		p, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		rel, err := filepath.Rel(abs, p)
+		if err != nil {
+			return "", err
+		}
+		rel, err = filepath.Rel(base, filepath.Join(resolved, rel))
+		if err != nil {
+			return "", err
+		}
+		return filepath.ToSlash(rel), nil
+	}, nil
+}
+
+// fixesPatch returns the unified diff of all planned removals, with paths
+// relative to the git top level or, outside a work tree, the scan directory
+func fixesPatch(fixes []fileFix) string {
+	var b strings.Builder
+	for _, ff := range fixes {
+		b.WriteString(fix.Diff(ff.PatchPath, ff.Content, ff.Removed))
+	}
+	return b.String()
+}
+
+// applyFixes writes the fixed files in place and returns the number of
+// blocks removed
+func applyFixes(fixes []fileFix) (int, error) {
+	removed := 0
+	for _, ff := range fixes {
+		if len(ff.Removed) == 0 {
+			continue
+		}
+		// WriteFile keeps the permissions of an existing file
+		if err := os.WriteFile(ff.Path, []byte(ff.Fixed), 0644); err != nil {
+			return removed, err
+		}
+		removed += len(ff.Removed)
+	}
+	return removed, nil
+}
+
+// reportSkippedFixes explains which blocks were left alone
+func reportSkippedFixes(fixes []fileFix) {
+	for _, ff := range fixes {
+		for _, s := range ff.Skipped {
+			utils.Warnf("⚠️  Not removing %s:%d-%d: %s\n", ff.Path, s.Start, s.End, s.Reason)
+		}
+	}
+}
+
+// fixCounts returns the number of files changed and blocks removed and skipped
+func fixCounts(fixes []fileFix) (files, removed, skipped int) {
+	for _, ff := range fixes {
+		if len(ff.Removed) > 0 {
+			files++
+		}
+		removed += len(ff.Removed)
+		skipped += len(ff.Skipped)
+	}
+	return files, removed, skipped
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestFixesPatchAppliesWithAbsoluteDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	content := `<?php
+class User
+{
+    // public function old()
+    // {
+    //     return 1;
+    // }
+
+    public function name() { return 'a'; }
+}
+`
+	tests := []struct {
+		name    string
+		gitInit bool
+		header  string
+	}{
+		{"git work tree", true, "diff --git a/proj/app/User.php b/proj/app/User.php\n"},
+		{"plain directory", false, "diff --git a/app/User.php b/app/User.php\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top := t.TempDir()
+			git := func(dir string, args ...string) (string, error) {
+				cmd := exec.Command("git", args...)
+				cmd.Dir = dir
+				out, err := cmd.CombinedOutput()
+				return string(out), err
+			}
+			if tt.gitInit {
+				if out, err := git(top, "init", "-q"); err != nil {
+					t.Fatalf("git init failed: %v\n%s", err, out)
+				}
+			}
+
+			// Absolute scan directory, as with -dir /tmp/proj
+			dir := filepath.Join(top, "proj")
+			path := filepath.Join(dir, "app", "User.php")
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("Failed to create dir: %v", err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			findings := []finding{{
+				Analyzer: "php",
+				Issue: models.Issue{
+					Path:     path,
+					Line:     4,
+					Category: models.CategoryDeadCode,
+					Metadata: &models.IssueMetadata{LineSpan: 4},
+				},
+			}}
+			fixes, err := planFixes(findings, dir)
+			if err != nil {
+				t.Fatalf("planFixes failed: %v", err)
+			}
+			patch := fixesPatch(fixes)
+			if !strings.HasPrefix(patch, tt.header) {
+				t.Fatalf("unexpected patch header:\n%s", patch)
+			}
+
+			patchFile := filepath.Join(t.TempDir(), "fixes.patch")
+			if err := os.WriteFile(patchFile, []byte(patch), 0644); err != nil {
+				t.Fatalf("Failed to write patch: %v", err)
+			}
+			applyDir := dir
+			if tt.gitInit {
+				applyDir = top
+			}
+			if out, err := git(applyDir, "apply", "--check", patchFile); err != nil {
+				t.Errorf("git apply --check failed: %v\n%s\n%s", err, out, patch)
+			}
+		})
+	}
+}
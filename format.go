@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"code-analyzer/ide"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
+)
+
+// Console output formats
+const (
+	formatTable   = "table"
+	formatCompact = "compact"
+	formatSummary = "summary"
+	formatIDE     = "ide"
+	formatAzure   = "azure"
+	formatPlain   = "plain"
+)
+
+// Color modes for the -color flag
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// stdout receives banners, tables and summaries. It is discarded in compact
+// and summary modes so only issue lines or the summary table reach the terminal.
+var stdout io.Writer = os.Stdout
+
+// sortedByLocation returns a copy of findings ordered by path, line, column
+// and check, so the order does not depend on the order analyzers ran in
+func sortedByLocation(findings []finding) []finding {
+	sorted := append([]finding{}, findings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Issue, sorted[j].Issue
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return sorted[i].checkName() < sorted[j].checkName()
+	})
+	return sorted
+}
+
+// printCompact prints one `path:line: severity [check] message` line per issue,
+// ordered by path and line so output is grep-able and editor-jumpable
+func printCompact(w io.Writer, findings []finding) {
+	sorted := sortedByLocation(findings)
+
+	for _, f := range sorted {
+		fmt.Fprintf(w, "%s:%d: %s [%s] %s\n", f.Issue.Path, f.Issue.Line, utils.SeverityColor(f.Issue.Severity, f.Issue.Severity), f.checkName(), f.Issue.Description)
+	}
+}
+
+// printPlain prints gcc-style `path:line:column: severity: message [check]`
+// diagnostics, which Vim, Emacs and Kakoune read into quickfix lists as they
+// are. The column is 1 when the rule does not know it; issues about a whole
+// file are printed as `path: severity: message [check]`.
+func printPlain(w io.Writer, findings []finding) {
+	sorted := sortedByLocation(findings)
+
+	for _, f := range sorted {
+		location := f.Issue.Path
+		if f.Issue.Line > 0 {
+			location += fmt.Sprintf(":%d:%d", f.Issue.Line, max(f.Issue.Column, 1))
+		}
+		description := strings.ReplaceAll(f.Issue.Description, "\n", " ")
+		fmt.Fprintf(w, "%s: %s: %s [%s]\n", location, severity.Format(severity.Plain, f.Issue.Severity), description, f.checkName())
+	}
+}
+
+// azureProperty and azureMessage escape values of Azure Pipelines logging
+// commands, so a path or description cannot end or inject a command
+var (
+	azureProperty = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", ";", "%3B", "]", "%5D")
+	azureMessage  = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A")
+)
+
+// printAzure prints one `##vso[task.logissue]` command per issue so Azure
+// Pipelines lists them in the run summary: critical and blocker issues as
+// errors, the rest as warnings
+func printAzure(w io.Writer, findings []finding) {
+	sorted := sortedByLocation(findings)
+
+	for _, f := range sorted {
+		props := fmt.Sprintf("type=%s;sourcepath=%s", severity.Format(severity.Azure, f.Issue.Severity), azureProperty.Replace(f.Issue.Path))
+		if f.Issue.Line > 0 {
+			props += fmt.Sprintf(";linenumber=%d", f.Issue.Line)
+		}
+		props += ";code=" + azureProperty.Replace(f.checkName())
+		fmt.Fprintf(w, "##vso[task.logissue %s]%s: %s\n", props, f.Issue.Severity, azureMessage.Replace(f.Issue.Description))
+	}
+}
+
+// printIDE writes the editor plugin report: issues grouped per file with line
+// ranges and suggestions, in the stable shape of the ide package
+func printIDE(w io.Writer, root string, findings []finding) error {
+	ideFindings := make([]ide.Finding, len(findings))
+	for i, f := range findings {
+		ideFindings[i] = ide.Finding{Analyzer: f.Analyzer, Check: f.checkName(), Issue: f.Issue}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ide.Build(root, ideFindings))
+}
+
+// defaultFormat is summary in CI, where per-file tables are noise, and table
+// everywhere else
+func defaultFormat() string {
+	if os.Getenv("CI") != "" {
+		return formatSummary
+	}
+	return formatTable
+}
+
+// severityWeight ranks files in the summary: one issue of a severity
+// outweighs any realistic number of issues of the next lower one
+var severityWeight = map[string]int{"blocker": 10000, "critical": 1000, "major": 100, "minor": 10, "info": 1}
+
+// printSummary prints the summary format: issue counts per analyzer and
+// severity, the 5 worst files and the verdict that decides the exit code
+func printSummary(w io.Writer, ran []string, findings []finding, verdict runVerdict) {
+	counts := make(map[string]map[string]int)
+	for _, name := range ran {
+		counts[name] = make(map[string]int)
+	}
+	totals := make(map[string]int)
+	for _, f := range findings {
+		if counts[f.Analyzer] == nil {
+			counts[f.Analyzer] = make(map[string]int)
+		}
+		counts[f.Analyzer][f.Issue.Severity]++
+		totals[f.Issue.Severity]++
+	}
+
+	fmt.Fprintf(w, "%-12s", "Analyzer")
+	for _, severity := range severityOrder {
+		fmt.Fprintf(w, " %9s", severity)
+	}
+	fmt.Fprintf(w, " %9s\n", "total")
+	fmt.Fprintln(w, strings.Repeat("-", 12+10*(len(severityOrder)+1)))
+	for _, name := range ran {
+		printSummaryRow(w, name, counts[name])
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 12+10*(len(severityOrder)+1)))
+	printSummaryRow(w, "all", totals)
+
+	if worst := worstFiles(findings, 5); len(worst) > 0 {
+		fmt.Fprintf(w, "\n🔥 Worst files:\n")
+		for i, file := range worst {
+			fmt.Fprintf(w, "%2d. %s (%s)\n", i+1, file.path, file.breakdown())
+		}
+	}
+
+	fmt.Fprintln(w)
+	line := verdict.describe(len(findings))
+	if verdict.passed() {
+		fmt.Fprintf(w, "✅ %s\n", utils.Green("PASSED: "+line))
+	} else {
+		fmt.Fprintf(w, "❌ %s\n", utils.SeverityColor("critical", "FAILED: "+line))
+	}
+}
+
+// printSummaryRow prints one analyzer's counts, coloring non-zero cells
+func printSummaryRow(w io.Writer, name string, counts map[string]int) {
+	total := 0
+	fmt.Fprintf(w, "%-12s", name)
+	for _, severity := range severityOrder {
+		cell := fmt.Sprintf(" %9d", counts[severity])
+		if counts[severity] > 0 {
+			cell = utils.SeverityColor(severity, cell)
+		}
+		fmt.Fprint(w, cell)
+		total += counts[severity]
+	}
+	fmt.Fprintf(w, " %9d\n", total)
+}
+
+// fileSeverities holds the issue counts of one file
+type fileSeverities struct {
+	path   string
+	counts map[string]int
+	score  int
+}
+
+func (f fileSeverities) breakdown() string {
+	var parts []string
+	for _, severity := range severityOrder {
+		if n := f.counts[severity]; n > 0 {
+			parts = append(parts, utils.SeverityColor(severity, fmt.Sprintf("%d %s", n, severity)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// worstFiles returns the n files with the highest severity-weighted issue counts
+func worstFiles(findings []finding, n int) []fileSeverities {
+	byPath := make(map[string]*fileSeverities)
+	for _, f := range findings {
+		file := byPath[f.Issue.Path]
+		if file == nil {
+			file = &fileSeverities{path: f.Issue.Path, counts: make(map[string]int)}
+			byPath[f.Issue.Path] = file
+		}
+		file.counts[f.Issue.Severity]++
+		file.score += severityWeight[f.Issue.Severity]
+	}
+
+	files := make([]fileSeverities, 0, len(byPath))
+	for _, file := range byPath {
+		files = append(files, *file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].score != files[j].score {
+			return files[i].score > files[j].score
+		}
+		return files[i].path < files[j].path
+	})
+	if len(files) > n {
+		files = files[:n]
+	}
+	return files
+}
+
+// printQuietSummary prints the single line -quiet reduces the console to
+func printQuietSummary(w io.Writer, verdict runVerdict, findings []finding) {
+	line := verdict.describe(len(findings))
+	if !verdict.passed() {
+		fmt.Fprintf(w, "⚠️  %s\n", utils.Yellow(line))
+		return
+	}
+	fmt.Fprintf(w, "✅ %s\n", utils.Green(line))
+}
+
+// useColor resolves the -color flag: auto colors only a terminal, and never
+// when NO_COLOR is set (https://no-color.org) or TERM is dumb
+func useColor(mode string, f *os.File) (bool, error) {
+	switch mode {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto:
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		return isTerminal(f), nil
+	}
+	return false, fmt.Errorf("unknown color mode %q (expected \"auto\", \"always\" or \"never\")", mode)
+}
+
+// severityOrder lists severities from most to least severe
+var severityOrder = severity.Order
+
+// printSeveritySummary prints issue counts per severity, most severe first
+func printSeveritySummary(w io.Writer, findings []finding) {
+	if len(findings) == 0 {
+		return
+	}
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Issue.Severity]++
+	}
+
+	var parts []string
+	for _, severity := range severityOrder {
+		if n := counts[severity]; n > 0 {
+			parts = append(parts, utils.SeverityColor(severity, fmt.Sprintf("%d %s", n, severity)))
+		}
+	}
+	fmt.Fprintf(w, "\n🧮 %d issues: %s\n", len(findings), strings.Join(parts, ", "))
+}
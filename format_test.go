@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestPrintAzureEscaping(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue models.Issue
+		want  string
+	}{
+		{
+			name:  "plain issue",
+			issue: models.Issue{Path: "app/User.php", Line: 4, Severity: "critical", RuleID: "php/commented-function", Description: "Commented out function"},
+			want:  "##vso[task.logissue type=error;sourcepath=app/User.php;linenumber=4;code=php/commented-function]critical: Commented out function\n",
+		},
+		{
+			name:  "percent signs in properties and message",
+			issue: models.Issue{Path: "app/100%.php", Line: 1, Severity: "minor", RuleID: "size/%d", Description: "50% commented"},
+			want:  "##vso[task.logissue type=warning;sourcepath=app/100%AZP25.php;linenumber=1;code=size/%AZP25d]minor: 50%AZP25 commented\n",
+		},
+		{
+			name:  "semicolons and brackets only escaped in properties",
+			issue: models.Issue{Path: "app/a;b].php", Severity: "minor", RuleID: "x;y", Description: "a;b]c"},
+			want:  "##vso[task.logissue type=warning;sourcepath=app/a%3Bb%5D.php;code=x%3By]minor: a;b]c\n",
+		},
+		{
+			name:  "line breaks",
+			issue: models.Issue{Path: "app/a\r\nb.php", Line: 2, Severity: "minor", RuleID: "whitespace/trailing", Description: "first\r\nsecond\nthird"},
+			want:  "##vso[task.logissue type=warning;sourcepath=app/a%0D%0Ab.php;linenumber=2;code=whitespace/trailing]minor: first%0D%0Asecond%0Athird\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printAzure(&out, []finding{{Analyzer: "php", Issue: tt.issue}})
+			if out.String() != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package gitdiff
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineRange is an inclusive range of line numbers in the new version of a file
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// ChangedLines maps a cleaned, slash-separated path to the line ranges added or modified
+type ChangedLines map[string][]LineRange
+
+var hunkRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// Load runs `git diff` between base and the working tree and returns the changed
+// lines, with paths relative to the current directory
+func Load(base string) (ChangedLines, error) {
+	return diff(base)
+}
+
+// LoadStaged returns the lines changed in the index (`git diff --cached`),
+// i.e. what the next commit would add, with paths relative to the current
+// directory
+func LoadStaged() (ChangedLines, error) {
+	return diff("--cached")
+}
+
+// diff runs `git diff` with the given revision argument and parses its output
+func diff(arg string) (ChangedLines, error) {
+	cmd := exec.Command("git", "diff", "--relative", "--no-color", "--no-ext-diff", "-U0", arg)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git diff %s failed: %s", arg, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git diff %s failed: %v", arg, err)
+	}
+	return Parse(string(out)), nil
+}
+
+// Parse extracts the changed line ranges from unified diff output
+func Parse(diff string) ChangedLines {
+	changed := make(ChangedLines)
+	current := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "+++ ") {
+			target := strings.TrimPrefix(line, "+++ ")
+			if target == "/dev/null" {
+				current = ""
+			} else {
+				current = normalize(strings.TrimPrefix(target, "b/"))
+			}
+			continue
+		}
+
+		if current == "" {
+			continue
+		}
+
+		m := hunkRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		start, _ := strconv.Atoi(m[1])
+		count := 1
+		if m[2] != "" {
+			count, _ = strconv.Atoi(m[2])
+		}
+		// Pure deletions have no lines in the new file
+		if count == 0 {
+			continue
+		}
+		changed[current] = append(changed[current], LineRange{Start: start, End: start + count - 1})
+	}
+
+	return changed
+}
+
+// Files returns the changed paths
+func (c ChangedLines) Files() []string {
+	files := make([]string, 0, len(c))
+	for path := range c {
+		files = append(files, path)
+	}
+	return files
+}
+
+// Contains reports whether the given line of path falls within a changed hunk
+func (c ChangedLines) Contains(path string, line int) bool {
+	for _, r := range c[normalize(path)] {
+		if line >= r.Start && line <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize makes issue paths and diff paths comparable
+func normalize(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := filepath.Abs("."); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
@@ -0,0 +1,54 @@
+package gitdiff
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	diff := `diff --git a/app/User.php b/app/User.php
+index 1111111..2222222 100644
+--- a/app/User.php
++++ b/app/User.php
+@@ -10,0 +11,3 @@ class User
++    // public function old()
++    // {
++    // }
+@@ -40 +43 @@ class User
+-        return 1;
++        return 2;
+@@ -50,2 +53,0 @@ class User
+-        $a = 1;
+-        $b = 2;
+diff --git a/old.js b/old.js
+deleted file mode 100644
+--- a/old.js
++++ /dev/null
+@@ -1,2 +0,0 @@
+-var x = 1;
+-var y = 2;
+`
+
+	changed := Parse(diff)
+
+	tests := []struct {
+		path     string
+		line     int
+		expected bool
+	}{
+		{"app/User.php", 11, true},
+		{"app/User.php", 13, true},
+		{"app/User.php", 14, false},
+		{"app/User.php", 43, true},
+		{"app/User.php", 53, false},
+		{"./app/User.php", 12, true},
+		{"old.js", 1, false},
+	}
+
+	for _, tt := range tests {
+		if got := changed.Contains(tt.path, tt.line); got != tt.expected {
+			t.Errorf("Contains(%q, %d) = %v, expected %v", tt.path, tt.line, got, tt.expected)
+		}
+	}
+
+	if len(changed.Files()) != 1 {
+		t.Errorf("expected 1 changed file, got %v", changed.Files())
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/models"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
+)
+
+func generateGitLabReport(outputPath string, findings []finding) error {
+	var report []models.CodeQualityIssue
+
+	for _, finding := range findings {
+		fingerprint := utils.Fingerprint(finding.Issue)
+
+		// Ensure path is relative to project root if possible
+		// finding.Issue.Path should already be relative or absolute depending on how it was found.
+
+		report = append(report, models.CodeQualityIssue{
+			Description: finding.Issue.Description,
+			CheckName:   finding.checkName(),
+			Fingerprint: fingerprint,
+			Severity:    severity.Format(severity.GitLab, finding.Issue.Severity),
+			Categories:  []string{codeClimateCategory(finding.Issue)},
+			Location: models.Location{
+				Path: finding.Issue.Path,
+				Lines: models.Lines{
+					Begin: finding.Issue.Line,
+					End:   endLine(finding),
+				},
+				Positions: issuePositions(finding.Issue),
+			},
+			RemediationPoints: remediationPoints(finding.Issue),
+			Content:           docsContent(finding.Issue),
+		})
+	}
+
+	// Write to file
+	// Ensure directory exists
+	dir := filepath.Dir(outputPath)
+	if dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// codeClimateCategories maps issue categories to Code Climate categories,
+// which the GitLab report shares
+var codeClimateCategories = map[string]string{
+	models.CategoryDeadCode:        "Clarity",
+	models.CategoryBugRisk:         "Bug Risk",
+	models.CategorySecurity:        "Security",
+	models.CategoryComplexity:      "Complexity",
+	models.CategoryStyle:           "Style",
+	models.CategoryCompliance:      "Style",
+	models.CategoryMaintainability: "Clarity",
+	models.CategoryPerformance:     "Performance",
+}
+
+// codeClimateCategory returns the Code Climate category of an issue; issues
+// without a known category are Clarity
+func codeClimateCategory(issue models.Issue) string {
+	if category, ok := codeClimateCategories[issue.Category]; ok {
+		return category
+	}
+	return "Clarity"
+}
+
+// remediationPointsPerMinute converts the rules' effort estimates into
+// remediation points
+const remediationPointsPerMinute = 10000
+
+// remediationPoints returns the remediation points of an issue, 0 when its
+// rule gives no effort estimate
+func remediationPoints(issue models.Issue) int {
+	if issue.Metadata == nil {
+		return 0
+	}
+	return issue.Metadata.EffortMinutes * remediationPointsPerMinute
+}
+
+// endLine returns the last line of a finding spanning several lines, or 0:
+// the end its rule recorded, or else the span of the flagged block. Conflict
+// markers are reported one per line while their span sizes the whole block,
+// so only the opening marker, which records the block's end, spans lines.
+func endLine(f finding) int {
+	if f.Issue.EndLine > f.Issue.Line {
+		return f.Issue.EndLine
+	}
+	if f.Analyzer != "conflicts" && f.Issue.Line > 0 && f.Issue.Metadata != nil && f.Issue.Metadata.LineSpan > 1 {
+		return f.Issue.Line + f.Issue.Metadata.LineSpan - 1
+	}
+	return 0
+}
+
+// issuePositions returns the exact extent of an issue, or nil when the rule
+// did not record its columns
+func issuePositions(issue models.Issue) *models.Positions {
+	if issue.Column < 1 || issue.EndLine < 1 {
+		return nil
+	}
+	return &models.Positions{
+		Begin: models.Position{Line: issue.Line, Column: issue.Column},
+		End:   models.Position{Line: issue.EndLine, Column: issue.EndColumn},
+	}
+}
+
+// gitLabMetadataPath returns the path of the companion metadata file for a report,
+// e.g. gl-code-quality-report.json -> gl-code-quality-report.meta.json
+func gitLabMetadataPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".meta.json"
+}
+
+// generateGitLabMetadata writes per-rule issue counts and a rule index next to the
+// GitLab report so MR bots can render a summary without re-aggregating issues
+func generateGitLabMetadata(outputPath, reportPath string, findings []finding) error {
+	rules := make(map[string]*models.CodeQualityRuleSummary)
+	for _, f := range findings {
+		name := f.checkName()
+		summary, ok := rules[name]
+		if !ok {
+			summary = &models.CodeQualityRuleSummary{
+				CheckName:  name,
+				Analyzer:   f.Analyzer,
+				DocsURL:    f.Issue.DocsURL,
+				BySeverity: make(map[string]int),
+			}
+			rules[name] = summary
+		}
+		summary.Total++
+		summary.BySeverity[f.Issue.Severity]++
+	}
+
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metadata := models.CodeQualityMetadata{
+		Timestamp:   utils.GetTimestamp(),
+		Report:      reportPath,
+		TotalIssues: len(findings),
+		Rules:       []models.CodeQualityRuleSummary{},
+	}
+	for i, name := range names {
+		rules[name].Index = i
+		metadata.Rules = append(metadata.Rules, *rules[name])
+	}
+
+	return utils.WriteArtifact(outputPath, metadata)
+}
@@ -0,0 +1,94 @@
+// Package heatmap aggregates commented-code density per directory and
+// renders it as a treemap
+package heatmap
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultDepth is the directory depth files are aggregated to
+const DefaultDepth = 3
+
+// File is the line count of one analyzed file and how many of its lines are
+// commented-out code
+type File struct {
+	Lines     int
+	Commented int
+}
+
+// Node is a directory with the totals of every file below it
+type Node struct {
+	Name      string  `json:"name"`
+	Path      string  `json:"path"`
+	Files     int     `json:"files"`
+	Lines     int     `json:"lines"`
+	Commented int     `json:"commented_lines"`
+	Density   float64 `json:"density"` // Commented share of the lines, 0-1
+	Children  []*Node `json:"children,omitempty"`
+}
+
+// Build aggregates files, keyed by slash-separated paths relative to the scan
+// root, into a directory tree. Directories deeper than depth are folded into
+// their ancestor at that depth. Children are ordered by lines, largest first.
+func Build(files map[string]File, depth int) *Node {
+	if depth <= 0 {
+		depth = DefaultDepth
+	}
+	root := &Node{Name: ".", Path: "."}
+	index := map[string]*Node{".": root}
+
+	for file, stats := range files {
+		commented := min(stats.Commented, stats.Lines)
+
+		dir := path.Dir(file)
+		var parts []string
+		if dir != "." {
+			parts = strings.Split(dir, "/")
+		}
+		if len(parts) > depth {
+			parts = parts[:depth]
+		}
+
+		// Every directory on the way gets the file's totals
+		node := root
+		node.add(stats.Lines, commented)
+		for i := range parts {
+			p := strings.Join(parts[:i+1], "/")
+			child, ok := index[p]
+			if !ok {
+				child = &Node{Name: parts[i], Path: p}
+				index[p] = child
+				node.Children = append(node.Children, child)
+			}
+			child.add(stats.Lines, commented)
+			node = child
+		}
+	}
+
+	root.finish()
+	return root
+}
+
+func (n *Node) add(lines, commented int) {
+	n.Files++
+	n.Lines += lines
+	n.Commented += commented
+}
+
+// finish computes densities and orders children, recursively
+func (n *Node) finish() {
+	if n.Lines > 0 {
+		n.Density = float64(n.Commented) / float64(n.Lines)
+	}
+	sort.Slice(n.Children, func(i, j int) bool {
+		if n.Children[i].Lines != n.Children[j].Lines {
+			return n.Children[i].Lines > n.Children[j].Lines
+		}
+		return n.Children[i].Name < n.Children[j].Name
+	})
+	for _, c := range n.Children {
+		c.finish()
+	}
+}
@@ -0,0 +1,80 @@
+package heatmap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	files := map[string]File{
+		"README.md":                       {Lines: 10},
+		"app/Http/Controllers/User.php":   {Lines: 100, Commented: 20},
+		"app/Http/Controllers/Deep/A.php": {Lines: 50, Commented: 5},
+		"app/Models/User.php":             {Lines: 50},
+		"lib/x.js":                        {Lines: 5, Commented: 9}, // capped at its lines
+	}
+	root := Build(files, 2)
+
+	if root.Files != 5 || root.Lines != 215 || root.Commented != 30 {
+		t.Fatalf("unexpected root totals %+v", root)
+	}
+
+	tests := []struct {
+		path             string
+		files, commented int
+		density          float64
+	}{
+		{"app", 3, 25, 0.125},
+		{"app/Http", 2, 25, 25.0 / 150},
+		{"app/Models", 1, 0, 0},
+		{"lib", 1, 5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			n := find(root, tt.path)
+			if n == nil {
+				t.Fatalf("no node for %s", tt.path)
+			}
+			if n.Files != tt.files || n.Commented != tt.commented || n.Density != tt.density {
+				t.Errorf("got %+v", n)
+			}
+		})
+	}
+	if find(root, "app/Http/Controllers") != nil {
+		t.Error("directories below depth 2 should be folded")
+	}
+	if root.Children[0].Path != "app" {
+		t.Errorf("children not ordered by lines: first is %s", root.Children[0].Path)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	root := Build(map[string]File{
+		"a/x.php": {Lines: 100, Commented: 50},
+		"b/y.php": {Lines: 100},
+	}, 2)
+
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, root, 400, 200); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	for _, want := range []string{"<svg", "a: 50 of 100 lines commented (50.0%)", `fill="#b30000"`, `fill="#f0f0f0"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %q:\n%s", want, svg)
+		}
+	}
+}
+
+func find(n *Node, path string) *Node {
+	if n.Path == path {
+		return n
+	}
+	for _, c := range n.Children {
+		if found := find(c, path); found != nil {
+			return found
+		}
+	}
+	return nil
+}
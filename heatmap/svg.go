@@ -0,0 +1,90 @@
+package heatmap
+
+import (
+	"fmt"
+	"html"
+	"io"
+)
+
+// svgSaturation is the density drawn in the darkest color; denser
+// directories are not distinguished further
+const svgSaturation = 0.25
+
+// WriteSVG renders the tree as a treemap: each directory's area is
+// proportional to its lines and its color to its commented-code density.
+// Rectangles are split alternately horizontally and vertically per level.
+func WriteSVG(w io.Writer, root *Node, width, height int) error {
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n", width, height, width, height)
+	printf(`<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	var draw func(n *Node, x, y, w, h float64, horizontal bool)
+	draw = func(n *Node, x, y, w, h float64, horizontal bool) {
+		if w < 1 || h < 1 {
+			return
+		}
+		if len(n.Children) == 0 || n.Lines == 0 {
+			printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="#ffffff"><title>%s: %d of %d lines commented (%.1f%%)</title></rect>`+"\n",
+				x, y, w, h, densityColor(n.Density), html.EscapeString(n.Path), n.Commented, n.Lines, n.Density*100)
+			if w > 60 && h > 16 {
+				printf(`<text x="%.1f" y="%.1f" fill="#000000">%s</text>`+"\n", x+4, y+13, html.EscapeString(n.Name))
+			}
+			return
+		}
+
+		// Files directly in this directory keep their share of the area
+		offset := 0.0
+		children := append([]*Node{}, n.Children...)
+		if own := n.Lines - childLines(n); own > 0 {
+			children = append(children, &Node{Name: n.Name, Path: n.Path, Lines: own, Commented: n.Commented - childCommented(n)})
+			last := children[len(children)-1]
+			last.Density = float64(last.Commented) / float64(last.Lines)
+		}
+		for _, c := range children {
+			share := float64(c.Lines) / float64(n.Lines)
+			if horizontal {
+				draw(c, x+offset*w, y, share*w, h, !horizontal)
+			} else {
+				draw(c, x, y+offset*h, w, share*h, !horizontal)
+			}
+			offset += share
+		}
+	}
+	draw(root, 0, 0, float64(width), float64(height), true)
+	printf("</svg>\n")
+	return err
+}
+
+func childLines(n *Node) int {
+	total := 0
+	for _, c := range n.Children {
+		total += c.Lines
+	}
+	return total
+}
+
+func childCommented(n *Node) int {
+	total := 0
+	for _, c := range n.Children {
+		total += c.Commented
+	}
+	return total
+}
+
+// densityColor interpolates from light grey (no commented code) to dark red
+// (svgSaturation and above)
+func densityColor(density float64) string {
+	t := min(density/svgSaturation, 1)
+	from := [3]float64{0xf0, 0xf0, 0xf0}
+	to := [3]float64{0xb3, 0x00, 0x00}
+	var c [3]int
+	for i := range c {
+		c[i] = int(from[i] + (to[i]-from[i])*t)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2])
+}
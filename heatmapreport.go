@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/config"
+	"code-analyzer/heatmap"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// commentedCodeAnalyzers report commented-out code; their issues make up the
+// heatmap's commented lines
+var commentedCodeAnalyzers = map[string]bool{"html": true, "php": true, "js": true}
+
+// heatmapReport is the JSON heatmap export
+type heatmapReport struct {
+	Timestamp string        `json:"timestamp"`
+	Depth     int           `json:"depth"`
+	Root      *heatmap.Node `json:"root"`
+}
+
+// generateHeatmap writes the commented-code density per directory. Lines are
+// counted in the files the scheduled commented-code analyzers handle;
+// commented lines are the spans of their issues.
+func generateHeatmap(cfg config.HeatmapConfig, rootDir string, followSymlinks bool, scheduled []scheduledAnalyzer, findings []finding) error {
+	var matchers []scheduledAnalyzer
+	for _, s := range scheduled {
+		if _, ok := s.Analyzer.(analyzers.FileMatcher); ok && commentedCodeAnalyzers[s.Name] {
+			matchers = append(matchers, s)
+		}
+	}
+
+	files := make(map[string]heatmap.File)
+	err := utils.Walk(rootDir, followSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		for _, s := range matchers {
+			if !s.inRoot(path) || utils.ShouldSkip(path, s.Config.ExcludePaths) || !s.Config.MatchesExt(path) || s.Config.TooLarge(info) {
+				continue
+			}
+			if s.Analyzer.(analyzers.FileMatcher).Handles(path, s.Config) {
+				if data, err := os.ReadFile(path); err == nil {
+					files[heatmapPath(rootDir, path)] = heatmap.File{Lines: countLines(data)}
+				}
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range findings {
+		if !commentedCodeAnalyzers[f.Analyzer] || f.Issue.Category != models.CategoryDeadCode {
+			continue
+		}
+		key := heatmapPath(rootDir, f.Issue.Path)
+		file, ok := files[key]
+		if !ok {
+			continue
+		}
+		span := 1
+		if f.Issue.Metadata != nil && f.Issue.Metadata.LineSpan > 0 {
+			span = f.Issue.Metadata.LineSpan
+		}
+		file.Commented += span
+		files[key] = file
+	}
+
+	depth := cfg.Depth
+	if depth <= 0 {
+		depth = heatmap.DefaultDepth
+	}
+	root := heatmap.Build(files, depth)
+
+	if err := utils.WriteArtifact(cfg.Path, heatmapReport{Timestamp: utils.GetTimestamp(), Depth: depth, Root: root}); err != nil {
+		return err
+	}
+	if cfg.SVG == "" {
+		return nil
+	}
+	if dir := filepath.Dir(cfg.SVG); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	out, err := os.Create(cfg.SVG)
+	if err != nil {
+		return err
+	}
+	if err := heatmap.WriteSVG(out, root, 1200, 800); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// heatmapPath returns path relative to the scan root, slash-separated
+func heatmapPath(rootDir, path string) string {
+	if rel, err := filepath.Rel(rootDir, path); err == nil {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
+
+// countLines counts lines, including a last line without a newline
+func countLines(data []byte) int {
+	n := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,156 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Run is one analysis run as recorded in the history file
+type Run struct {
+	Timestamp  string         `json:"timestamp"`
+	Pipeline   string         `json:"pipeline,omitempty"`
+	Commit     string         `json:"commit,omitempty"`
+	Ref        string         `json:"ref,omitempty"`
+	Issues     int            `json:"issues"`
+	BySeverity map[string]int `json:"by_severity"`
+	ByAnalyzer map[string]int `json:"by_analyzer"`
+	Findings   []Finding      `json:"findings"`
+}
+
+// Finding is one issue of a recorded run
+type Finding struct {
+	Fingerprint string `json:"fingerprint"`
+	Analyzer    string `json:"analyzer"`
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	Severity    string `json:"severity"`
+}
+
+// Append adds a run to the history file, creating it if needed. Runs are
+// stored as JSON Lines, so appending never rewrites earlier runs, or in a
+// SQLite database for .sqlite, .sqlite3 and .db paths.
+func Append(file string, run Run) error {
+	if isSQLite(file) {
+		return appendSQLite(file, run)
+	}
+	if dir := filepath.Dir(file); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads every run of the history file, oldest first
+func Load(file string) ([]Run, error) {
+	if isSQLite(file) {
+		return loadSQLite(file)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 256*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal([]byte(line), &run); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid run: %v", file, n, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, scanner.Err()
+}
+
+// FirstSeen maps the fingerprint of every recorded finding to the date
+// (YYYY-MM-DD) of the earliest run that reported it
+func FirstSeen(runs []Run) map[string]string {
+	first := make(map[string]string)
+	for _, run := range runs {
+		date := run.Timestamp
+		if len(date) > len("2006-01-02") {
+			date = date[:len("2006-01-02")]
+		}
+		for _, f := range run.Findings {
+			if seen, ok := first[f.Fingerprint]; !ok || date < seen {
+				first[f.Fingerprint] = date
+			}
+		}
+	}
+	return first
+}
+
+// DirectoryDebt is the number of issues under a directory
+type DirectoryDebt struct {
+	Directory  string
+	Issues     int
+	BySeverity map[string]int
+}
+
+// Directories groups a run's findings by their directory, truncated to depth
+// path segments, most issues first
+func Directories(run Run, depth int) []DirectoryDebt {
+	byDir := make(map[string]*DirectoryDebt)
+	for _, f := range run.Findings {
+		dir := Directory(f.Path, depth)
+		d := byDir[dir]
+		if d == nil {
+			d = &DirectoryDebt{Directory: dir, BySeverity: make(map[string]int)}
+			byDir[dir] = d
+		}
+		d.Issues++
+		d.BySeverity[f.Severity]++
+	}
+
+	result := make([]DirectoryDebt, 0, len(byDir))
+	for _, d := range byDir {
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Issues != result[j].Issues {
+			return result[i].Issues > result[j].Issues
+		}
+		return result[i].Directory < result[j].Directory
+	})
+	return result
+}
+
+// Directory returns the first depth directories of a file path ("." for
+// files at the root)
+func Directory(file string, depth int) string {
+	dir := path.Dir(filepath.ToSlash(filepath.Clean(file)))
+	if dir == "." || depth <= 0 {
+		return dir
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,169 @@
+package history
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAppendLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history", "runs.jsonl")
+	first := Run{Timestamp: "2026-10-01T00:00:00Z", Issues: 2, Findings: []Finding{{Path: "a.php"}, {Path: "b.php"}}}
+	second := Run{Timestamp: "2026-10-02T00:00:00Z", Issues: 1, Findings: []Finding{{Path: "a.php"}}}
+
+	for _, run := range []Run{first, second} {
+		if err := Append(file, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].Issues != 2 || runs[1].Timestamp != second.Timestamp {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+}
+
+func TestAppendLoad_SQLite(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		// CI images must provide it, so the backend is always tested there
+		if os.Getenv("CI") != "" {
+			t.Fatal("sqlite3 is not installed")
+		}
+		t.Skip("sqlite3 is not installed")
+	}
+	file := filepath.Join(t.TempDir(), "history", "runs.sqlite")
+	first := Run{
+		Timestamp:  "2026-10-01T00:00:00Z",
+		Commit:     "abc",
+		Issues:     2,
+		BySeverity: map[string]int{"major": 2},
+		ByAnalyzer: map[string]int{"php": 2},
+		Findings: []Finding{
+			{Fingerprint: "f1", Analyzer: "php", Path: "app/O'Brien.php", Line: 3, Severity: "major"},
+			{Fingerprint: "f2", Analyzer: "php", Path: "b.php", Line: 7, Severity: "major"},
+		},
+	}
+	second := Run{Timestamp: "2026-10-02T00:00:00Z", BySeverity: map[string]int{}, ByAnalyzer: map[string]int{}}
+
+	for _, run := range []Run{first, second} {
+		if err := Append(file, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || !reflect.DeepEqual(runs[0], first) || runs[1].Timestamp != second.Timestamp || len(runs[1].Findings) != 0 {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.sqlite")); err == nil {
+		t.Error("expected an error for a missing history")
+	}
+}
+
+func TestAppendLoad_SQLiteSpecialCharacters(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		if os.Getenv("CI") != "" {
+			t.Fatal("sqlite3 is not installed")
+		}
+		t.Skip("sqlite3 is not installed")
+	}
+	file := filepath.Join(t.TempDir(), "runs.db")
+	tricky := "it's a \\back\\slash, \"quoted\"\nnext line\r\n'); DROP TABLE runs; --"
+	run := Run{
+		Timestamp:  "2026-10-01T00:00:00Z",
+		Pipeline:   tricky,
+		Commit:     "abc'",
+		Ref:        "feature/it's\\new\n",
+		Issues:     1,
+		BySeverity: map[string]int{"major": 1},
+		ByAnalyzer: map[string]int{tricky: 1},
+		Findings: []Finding{
+			{Fingerprint: "f'1", Analyzer: tricky, Path: "app/O'Brien\\Legacy\n.php", Line: 3, Severity: "major"},
+			{Fingerprint: "f2", Analyzer: "php", Path: ".import evil.csv runs", Line: 4, Severity: "minor"},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		if err := Append(file, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || !reflect.DeepEqual(runs[0], run) || !reflect.DeepEqual(runs[1], run) {
+		t.Errorf("values did not round-trip:\n%+v\nwant\n%+v", runs, run)
+	}
+}
+
+func TestCheckSQLiteVersion(t *testing.T) {
+	tests := []struct {
+		out string
+		ok  bool
+	}{
+		{"3.45.1 2024-01-30 16:01:20 e876e51a0ed5c5b3126f52e532044363a014bc594cfefa87ffb5b82257cc467a (64-bit)\n", true},
+		{"3.33.0 2020-08-14 13:23:32 fca8dc8b578f215a969cd899336378966156154710873e68b3d9ac5881b0ff3f\n", true},
+		{"4.0.0\n", true},
+		{"3.32.3 2020-06-18 14:00:33 7ebdfa80be8e8e73324b8d66b3460222eb74c7e9dfd655b48d6ca7e1933cc8fd\n", false},
+		{"2.8.17\n", false},
+		{"", false},
+		{"sqlite3: not a version\n", false},
+	}
+	for _, tt := range tests {
+		if err := checkSQLiteVersion(tt.out); (err == nil) != tt.ok {
+			t.Errorf("checkSQLiteVersion(%q) = %v, want ok %v", tt.out, err, tt.ok)
+		}
+	}
+}
+
+func TestFirstSeen(t *testing.T) {
+	runs := []Run{
+		{Timestamp: "2026-10-02T08:00:00Z", Findings: []Finding{{Fingerprint: "a"}, {Fingerprint: "b"}}},
+		// Runs appended out of order still yield the earliest date
+		{Timestamp: "2026-09-15T23:00:00Z", Findings: []Finding{{Fingerprint: "a"}}},
+		{Timestamp: "2026-10-03T08:00:00Z", Findings: []Finding{{Fingerprint: "c"}}},
+	}
+	first := FirstSeen(runs)
+	want := map[string]string{"a": "2026-09-15", "b": "2026-10-02", "c": "2026-10-03"}
+	if len(first) != len(want) {
+		t.Fatalf("expected %v, got %v", want, first)
+	}
+	for fingerprint, date := range want {
+		if first[fingerprint] != date {
+			t.Errorf("%s: expected %s, got %s", fingerprint, date, first[fingerprint])
+		}
+	}
+}
+
+func TestDirectories(t *testing.T) {
+	run := Run{Findings: []Finding{
+		{Path: "app/Http/Controllers/A.php", Severity: "major"},
+		{Path: "app/Http/Middleware/B.php", Severity: "minor"},
+		{Path: "app/Models/C.php", Severity: "minor"},
+		{Path: "README.md", Severity: "info"},
+	}}
+
+	got := Directories(run, 2)
+	want := []struct {
+		dir    string
+		issues int
+	}{{"app/Http", 2}, {".", 1}, {"app/Models", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i, w := range want {
+		if got[i].Directory != w.dir || got[i].Issues != w.issues {
+			t.Errorf("entry %d: got %s=%d, want %s=%d", i, got[i].Directory, got[i].Issues, w.dir, w.issues)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sqliteSchema creates the tables of a SQLite history: one row per run, and
+// its findings in a table of their own so trends can be queried with SQL
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS runs (
+  id INTEGER PRIMARY KEY,
+  timestamp TEXT NOT NULL,
+  pipeline TEXT NOT NULL DEFAULT '',
+  commit_sha TEXT NOT NULL DEFAULT '',
+  ref TEXT NOT NULL DEFAULT '',
+  issues INTEGER NOT NULL,
+  by_severity TEXT NOT NULL DEFAULT '{}',
+  by_analyzer TEXT NOT NULL DEFAULT '{}'
+);
+CREATE TABLE IF NOT EXISTS findings (
+  run_id INTEGER NOT NULL REFERENCES runs(id),
+  fingerprint TEXT NOT NULL,
+  analyzer TEXT NOT NULL,
+  path TEXT NOT NULL,
+  line INTEGER NOT NULL,
+  severity TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS findings_run ON findings(run_id);
+CREATE INDEX IF NOT EXISTS findings_fingerprint ON findings(fingerprint);
+`
+
+// minSQLiteVersion is the oldest sqlite3 command with `.mode json`
+var minSQLiteVersion = [2]int{3, 33}
+
+// sqliteCheck is the result of probing the sqlite3 command, done once per run
+var sqliteCheck struct {
+	once sync.Once
+	err  error
+}
+
+// isSQLite reports whether a history path names a SQLite database
+func isSQLite(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".sqlite", ".sqlite3", ".db":
+		return true
+	}
+	return false
+}
+
+// sqliteImport stages a run in temporary tables that sqlite3 fills from CSV
+// files itself, so no recorded value is ever parsed as SQL, then copies it
+// into the history in one transaction. The %s are the CSV files.
+const sqliteImport = `CREATE TEMP TABLE new_run (timestamp TEXT, pipeline TEXT, commit_sha TEXT, ref TEXT, issues INTEGER, by_severity TEXT, by_analyzer TEXT);
+CREATE TEMP TABLE new_findings (fingerprint TEXT, analyzer TEXT, path TEXT, line INTEGER, severity TEXT);
+.import --csv --schema temp %s new_run
+.import --csv --schema temp %s new_findings
+BEGIN;
+INSERT INTO runs (timestamp, pipeline, commit_sha, ref, issues, by_severity, by_analyzer) SELECT * FROM temp.new_run;
+INSERT INTO findings SELECT (SELECT max(id) FROM runs), fingerprint, analyzer, path, line, severity FROM temp.new_findings;
+COMMIT;
+`
+
+// appendSQLite inserts a run and its findings in one transaction, creating
+// the tables if needed
+func appendSQLite(file string, run Run) error {
+	bySeverity, err := json.Marshal(run.BySeverity)
+	if err != nil {
+		return err
+	}
+	byAnalyzer, err := json.Marshal(run.ByAnalyzer)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "code-analyzer-history-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	// sqlite3 reads dot-command arguments in single quotes verbatim
+	if strings.ContainsAny(dir, "'\n") {
+		return fmt.Errorf("%s: unsupported temporary directory %q", file, dir)
+	}
+
+	runFile := filepath.Join(dir, "run.csv")
+	if err := writeCSV(runFile, [][]string{{run.Timestamp, run.Pipeline, run.Commit, run.Ref, strconv.Itoa(run.Issues), string(bySeverity), string(byAnalyzer)}}); err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(run.Findings))
+	for _, f := range run.Findings {
+		rows = append(rows, []string{f.Fingerprint, f.Analyzer, f.Path, strconv.Itoa(f.Line), f.Severity})
+	}
+	findingsFile := filepath.Join(dir, "findings.csv")
+	if err := writeCSV(findingsFile, rows); err != nil {
+		return err
+	}
+
+	script := sqliteSchema + fmt.Sprintf(sqliteImport, "'"+runFile+"'", "'"+findingsFile+"'")
+	_, err = sqlite(file, script)
+	return err
+}
+
+// writeCSV writes rows to a CSV file for sqlite3 to import
+func writeCSV(file string, rows [][]string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// loadSQLite reads every run of a SQLite history, oldest first
+func loadSQLite(file string) ([]Run, error) {
+	// sqlite3 creates missing databases; a missing history is an error
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ID         int64  `json:"id"`
+		Timestamp  string `json:"timestamp"`
+		Pipeline   string `json:"pipeline"`
+		Commit     string `json:"commit_sha"`
+		Ref        string `json:"ref"`
+		Issues     int    `json:"issues"`
+		BySeverity string `json:"by_severity"`
+		ByAnalyzer string `json:"by_analyzer"`
+	}
+	if err := query(file, "SELECT * FROM runs ORDER BY id;", &rows); err != nil {
+		return nil, err
+	}
+	var findings []struct {
+		RunID int64 `json:"run_id"`
+		Finding
+	}
+	if err := query(file, "SELECT * FROM findings ORDER BY run_id, rowid;", &findings); err != nil {
+		return nil, err
+	}
+
+	runs := make([]Run, len(rows))
+	index := make(map[int64]int, len(rows))
+	for i, row := range rows {
+		runs[i] = Run{
+			Timestamp: row.Timestamp,
+			Pipeline:  row.Pipeline,
+			Commit:    row.Commit,
+			Ref:       row.Ref,
+			Issues:    row.Issues,
+			Findings:  []Finding{},
+		}
+		if err := json.Unmarshal([]byte(row.BySeverity), &runs[i].BySeverity); err != nil {
+			return nil, fmt.Errorf("%s: run %d: invalid by_severity: %v", file, row.ID, err)
+		}
+		if err := json.Unmarshal([]byte(row.ByAnalyzer), &runs[i].ByAnalyzer); err != nil {
+			return nil, fmt.Errorf("%s: run %d: invalid by_analyzer: %v", file, row.ID, err)
+		}
+		index[row.ID] = i
+	}
+	for _, f := range findings {
+		if i, ok := index[f.RunID]; ok {
+			runs[i].Findings = append(runs[i].Findings, f.Finding)
+		}
+	}
+	return runs, nil
+}
+
+// query runs a SELECT and decodes its rows, which sqlite3 prints as a JSON
+// array, or not at all when there are none
+func query(file, sql string, rows interface{}) error {
+	out, err := sqlite(file, ".mode json\n"+sql+"\n")
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(out, rows); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	return nil
+}
+
+// sqlite runs a script against a database with the sqlite3 command, the way
+// the git integrations run git, so the tool stays free of cgo
+func sqlite(file, script string) ([]byte, error) {
+	if err := checkSQLite(); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if dir := filepath.Dir(file); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := exec.Command("sqlite3", "-bail", file)
+	cmd.Stdin = strings.NewReader(script)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", file, msg)
+		}
+		return nil, fmt.Errorf("%s: sqlite3: %v", file, err)
+	}
+	return out, nil
+}
+
+// checkSQLite makes sure the sqlite3 command is on the PATH and new enough,
+// so an old one fails with a clear error rather than a parse failure
+func checkSQLite() error {
+	sqliteCheck.once.Do(func() {
+		if _, err := exec.LookPath("sqlite3"); err != nil {
+			sqliteCheck.err = fmt.Errorf("SQLite history needs the sqlite3 command on the PATH: %v", err)
+			return
+		}
+		out, err := exec.Command("sqlite3", "-version").Output()
+		if err != nil {
+			sqliteCheck.err = fmt.Errorf("sqlite3 -version: %v", err)
+			return
+		}
+		sqliteCheck.err = checkSQLiteVersion(string(out))
+	})
+	return sqliteCheck.err
+}
+
+// checkSQLiteVersion checks the output of `sqlite3 -version`, e.g.
+// "3.45.1 2024-01-30 16:01:20 ..."
+func checkSQLiteVersion(out string) error {
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return fmt.Errorf("unrecognized sqlite3 version %q", strings.TrimSpace(out))
+	}
+	parts := strings.Split(fields[0], ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("unrecognized sqlite3 version %q", fields[0])
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return fmt.Errorf("unrecognized sqlite3 version %q", fields[0])
+	}
+	if major < minSQLiteVersion[0] || major == minSQLiteVersion[0] && minor < minSQLiteVersion[1] {
+		return fmt.Errorf("SQLite history needs sqlite3 %d.%d or newer, found %s", minSQLiteVersion[0], minSQLiteVersion[1], fields[0])
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"code-analyzer/history"
+	"code-analyzer/utils"
+)
+
+// recordHistory appends this run's findings (after baseline filtering, as
+// reported) and per-severity and per-analyzer totals to the history file
+func recordHistory(path string, findings []finding) error {
+	run := history.Run{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Pipeline:   os.Getenv("CI_PIPELINE_ID"),
+		Commit:     os.Getenv("CI_COMMIT_SHA"),
+		Ref:        os.Getenv("CI_COMMIT_REF_NAME"),
+		Issues:     len(findings),
+		BySeverity: map[string]int{},
+		ByAnalyzer: map[string]int{},
+		Findings:   make([]history.Finding, 0, len(findings)),
+	}
+	for _, f := range findings {
+		run.BySeverity[f.Issue.Severity]++
+		run.ByAnalyzer[f.Analyzer]++
+		run.Findings = append(run.Findings, history.Finding{
+			Fingerprint: utils.Fingerprint(f.Issue),
+			Analyzer:    f.Analyzer,
+			Path:        f.Issue.Path,
+			Line:        f.Issue.Line,
+			Severity:    f.Issue.Severity,
+		})
+	}
+	return history.Append(path, run)
+}
+
+// runHistoryCommand handles `code-analyzer history <trend|dirs>` and exits
+func runHistoryCommand(args []string) {
+	if len(args) == 0 || (args[0] != "trend" && args[0] != "dirs") {
+		utils.Errorf("Usage: code-analyzer history trend|dirs [--config <file>] [--file <history.jsonl|history.sqlite>] [--last N] [--depth N]\n")
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("history "+args[0], flag.ExitOnError)
+	configFile := fs.String("config", defaultConfigFile, "Path to YAML or JSON configuration file naming the history file")
+	file := fs.String("file", "", "History file to read (overrides `history` in the config)")
+	last := fs.Int("last", 20, "Number of most recent runs to show (trend)")
+	depth := fs.Int("depth", 2, "Directory depth issues are grouped by (dirs)")
+	_ = fs.Parse(args[1:])
+
+	path := *file
+	if path == "" {
+		cfg, _, err := loadConfig(*configFile, false)
+		if err != nil {
+			utils.Errorf("❌ Failed to load config file: %v\n", err)
+			os.Exit(2)
+		}
+		path = cfg.History
+	}
+	if path == "" {
+		utils.Errorf("❌ No history file: set `history` in the config or pass --file\n")
+		os.Exit(2)
+	}
+
+	runs, err := history.Load(path)
+	if err != nil {
+		utils.Errorf("❌ Failed to load history: %v\n", err)
+		os.Exit(2)
+	}
+	if len(runs) == 0 {
+		fmt.Printf("No runs recorded in %s\n", path)
+		return
+	}
+
+	if args[0] == "trend" {
+		printTrend(runs, *last)
+	} else {
+		printDirectoryDebt(runs[len(runs)-1], *depth)
+	}
+}
+
+func printTrend(runs []history.Run, last int) {
+	if last > 0 && len(runs) > last {
+		runs = runs[len(runs)-last:]
+	}
+
+	fmt.Printf("%-20s %-10s %7s %7s", "Run", "Commit", "Issues", "Delta")
+	for _, sev := range severityOrder {
+		fmt.Printf(" %8s", sev)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 47+9*len(severityOrder)))
+
+	for i, run := range runs {
+		delta := "-"
+		if i > 0 {
+			delta = fmt.Sprintf("%+d", run.Issues-runs[i-1].Issues)
+		}
+		fmt.Printf("%-20s %-10s %7d %7s", run.Timestamp, shortCommit(run.Commit), run.Issues, delta)
+		for _, sev := range severityOrder {
+			fmt.Printf(" %8d", run.BySeverity[sev])
+		}
+		fmt.Println()
+	}
+}
+
+// shortCommit abbreviates a commit SHA the way git log --oneline does
+func shortCommit(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	if sha == "" {
+		return "-"
+	}
+	return sha
+}
+
+func printDirectoryDebt(run history.Run, depth int) {
+	fmt.Printf("Issues by directory in the latest run (%s)\n\n", run.Timestamp)
+	fmt.Printf("%-50s %7s", "Directory", "Issues")
+	for _, sev := range severityOrder {
+		fmt.Printf(" %8s", sev)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 58+9*len(severityOrder)))
+
+	for _, d := range history.Directories(run, depth) {
+		fmt.Printf("%-50s %7d", utils.Truncate(d.Directory, 50), d.Issues)
+		for _, sev := range severityOrder {
+			fmt.Printf(" %8d", d.BySeverity[sev])
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,73 @@
+// Package hotspots ranks files that both change often and carry many issues
+package hotspots
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"code-analyzer/models"
+)
+
+// Churn returns the number of commits touching each file below dir since
+// the given git date (e.g. "6 months ago"), keyed by slash-separated paths
+// relative to dir
+func Churn(dir, since string) (map[string]int, error) {
+	args := []string{"log", "--format=", "--name-only", "--no-renames", "--relative"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	cmd := exec.Command("git", append(args, "--", ".")...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git log failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git log failed: %v", err)
+	}
+	return ParseLog(string(out)), nil
+}
+
+// ParseLog counts the commits of each file in `git log --format= --name-only`
+// output, where every commit lists the files it touched once
+func ParseLog(log string) map[string]int {
+	commits := make(map[string]int)
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if file := strings.TrimSpace(scanner.Text()); file != "" {
+			commits[file]++
+		}
+	}
+	return commits
+}
+
+// Rank scores each file with commits and issues by commits × issues and
+// returns the limit highest, ordered by score, then issues, then path
+func Rank(commits, issues map[string]int, limit int) []models.Hotspot {
+	hotspots := []models.Hotspot{}
+	for path, n := range issues {
+		c := commits[path]
+		if c == 0 || n == 0 {
+			continue
+		}
+		hotspots = append(hotspots, models.Hotspot{Path: path, Commits: c, Issues: n, Score: c * n})
+	}
+	sort.Slice(hotspots, func(i, j int) bool {
+		a, b := hotspots[i], hotspots[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Issues != b.Issues {
+			return a.Issues > b.Issues
+		}
+		return a.Path < b.Path
+	})
+	if limit > 0 && len(hotspots) > limit {
+		hotspots = hotspots[:limit]
+	}
+	return hotspots
+}
@@ -0,0 +1,35 @@
+package hotspots
+
+import (
+	"reflect"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestParseLog(t *testing.T) {
+	log := "\na.php\nlib/b.js\n\na.php\n\nc.css\n"
+	want := map[string]int{"a.php": 2, "lib/b.js": 1, "c.css": 1}
+	if got := ParseLog(log); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRank(t *testing.T) {
+	commits := map[string]int{"a.php": 10, "b.php": 2, "c.php": 3, "d.php": 1}
+	issues := map[string]int{"a.php": 1, "b.php": 5, "c.php": 2, "e.php": 9}
+
+	got := Rank(commits, issues, 2)
+	// a.php and b.php tie on score; b.php has more issues
+	want := []models.Hotspot{
+		{Path: "b.php", Commits: 2, Issues: 5, Score: 10},
+		{Path: "a.php", Commits: 10, Issues: 1, Score: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if all := Rank(commits, issues, 0); len(all) != 3 {
+		t.Errorf("expected 3 hotspots without a limit, got %+v", all)
+	}
+}
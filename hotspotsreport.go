@@ -0,0 +1,48 @@
+package main
+
+import (
+	"code-analyzer/config"
+	"code-analyzer/hotspots"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// defaultHotspotsSince is how far back the git history is counted when
+// hotspots.since is unset
+const defaultHotspotsSince = "6 months ago"
+
+// buildHotspotsReport ranks the files under dir by the commits touching them
+// since cfg.Since times their issues. It fails when dir is not in a git
+// repository.
+func buildHotspotsReport(cfg config.HotspotsConfig, dir string, findings []finding) (models.HotspotsReport, error) {
+	since, files := cfg.Since, cfg.Files
+	if since == "" {
+		since = defaultHotspotsSince
+	}
+	if files == 0 {
+		files = 20
+	}
+
+	commits, err := hotspots.Churn(dir, since)
+	if err != nil {
+		return models.HotspotsReport{}, err
+	}
+	issues := make(map[string]int)
+	for _, f := range findings {
+		issues[heatmapPath(dir, f.Issue.Path)]++
+	}
+
+	return models.HotspotsReport{
+		Timestamp: utils.GetTimestamp(),
+		Since:     since,
+		Hotspots:  hotspots.Rank(commits, issues, files),
+	}, nil
+}
+
+// hotspotsPath returns where the hotspots ranking is written
+func hotspotsPath(cfg config.HotspotsConfig) string {
+	if cfg.Path == "" {
+		return "hotspots.json"
+	}
+	return cfg.Path
+}
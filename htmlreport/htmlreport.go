@@ -0,0 +1,299 @@
+// Package htmlreport writes a standalone HTML report. Each analyzer's section
+// is rendered to a partial file as soon as the analyzer finishes, and the
+// partials are streamed into the final report at the end, so rendered rows
+// are never held in memory and finished sections survive an aborted run.
+// Filters that need every analyzer's issues, such as the baseline and
+// deduplication, are applied to the rows when the report is assembled.
+package htmlreport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/models"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
+)
+
+// Writer builds one HTML report
+type Writer struct {
+	path     string
+	dir      string // Partials, one file per section
+	partials []partial
+}
+
+// partial is a rendered analyzer section or the hotspots. Sections keep what
+// assembling needs to filter their rows; the hotspots are copied as is.
+type partial struct {
+	path    string
+	section *section
+}
+
+// section is an analyzer's run: the key Finish filters it by, its label and
+// error, and the fingerprint and severity of each row, in order
+type section struct {
+	key      string
+	analyzer string
+	err      string
+	rows     []row
+}
+
+type row struct {
+	fingerprint string
+	severity    string
+}
+
+// Summary is shown at the top of the report. Issues and Severities are
+// filled in from the sections.
+type Summary struct {
+	Timestamp  string
+	Root       string
+	Issues     int
+	Severities []SeverityCount
+	Succeeded  int
+	Analyzers  int
+}
+
+// SeverityCount is the number of issues of one severity
+type SeverityCount struct {
+	Severity string
+	Issues   int
+}
+
+// Reported is what the report shows of the sections' issues: how many
+// issues of each fingerprint are left per section key after filtering, and
+// how many each section had over the cap. A nil Issues keeps every issue.
+type Reported struct {
+	Issues   map[string]map[string]int
+	Overflow map[string]int
+}
+
+// New prepares a report at path. Partials go to path + ".d", replacing those
+// of a previous run.
+func New(path string) (*Writer, error) {
+	dir := path + ".d"
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Writer{path: path, dir: dir}, nil
+}
+
+// AddSection renders one analyzer's issues to its partial file, key being
+// what Finish's Reported knows the section by. A failed analyzer gets a
+// section with its error.
+func (w *Writer) AddSection(key, analyzer string, issues []models.Issue, runErr error) error {
+	sorted := append([]models.Issue{}, issues...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		if sorted[i].Line != sorted[j].Line {
+			return sorted[i].Line < sorted[j].Line
+		}
+		if sorted[i].Column != sorted[j].Column {
+			return sorted[i].Column < sorted[j].Column
+		}
+		return sorted[i].RuleID < sorted[j].RuleID
+	})
+
+	s := &section{key: key, analyzer: analyzer}
+	if runErr != nil {
+		s.err = runErr.Error()
+	}
+	rows := make([]rowData, len(sorted))
+	for i, issue := range sorted {
+		rows[i] = rowData{Fingerprint: utils.Fingerprint(issue), Issue: issue}
+		s.rows = append(s.rows, row{fingerprint: rows[i].Fingerprint, severity: issue.Severity})
+	}
+
+	path := filepath.Join(w.dir, fmt.Sprintf("%03d-%s.html", len(w.partials)+1, analyzer))
+	data := sectionData{Analyzer: analyzer, Issues: len(rows), Error: s.err}
+	err := create(path, func(out io.Writer) error {
+		if err := sectionStartTemplate.Execute(out, data); err != nil {
+			return err
+		}
+		if data.Error == "" {
+			if err := rowsTemplate.Execute(out, rows); err != nil {
+				return err
+			}
+		}
+		return sectionEndTemplate.Execute(out, data)
+	})
+	if err != nil {
+		return err
+	}
+	w.partials = append(w.partials, partial{path: path, section: s})
+	return nil
+}
+
+// AddHotspots renders the files that change often and carry the most issues
+// to a partial file after the sections added so far
+func (w *Writer) AddHotspots(report models.HotspotsReport) error {
+	path := filepath.Join(w.dir, fmt.Sprintf("%03d-hotspots.html", len(w.partials)+1))
+	if err := create(path, func(out io.Writer) error { return hotspotsTemplate.Execute(out, report) }); err != nil {
+		return err
+	}
+	w.partials = append(w.partials, partial{path: path})
+	return nil
+}
+
+// Finish assembles the report from the summary and the partials, in the order
+// they were added, keeping the rows reported lists, and removes the partials
+func (w *Writer) Finish(summary Summary, reported Reported) error {
+	keep := make([][]bool, len(w.partials))
+	counts := make(map[string]int)
+	summary.Issues = 0
+	for i, p := range w.partials {
+		if p.section == nil {
+			continue
+		}
+		keep[i] = p.section.keep(reported)
+		for j, r := range p.section.rows {
+			if keep[i][j] {
+				summary.Issues++
+				counts[r.severity]++
+			}
+		}
+	}
+	summary.Severities = nil
+	for _, sev := range severity.Order {
+		if n := counts[sev]; n > 0 {
+			summary.Severities = append(summary.Severities, SeverityCount{Severity: sev, Issues: n})
+		}
+	}
+
+	if dir := filepath.Dir(w.path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	err := create(w.path, func(out io.Writer) error {
+		if err := headerTemplate.Execute(out, summary); err != nil {
+			return err
+		}
+		for i, p := range w.partials {
+			var err error
+			if p.section == nil {
+				err = appendFile(out, p.path)
+			} else {
+				err = appendSection(out, p, keep[i], reported.Overflow[p.section.key])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(out, footer)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(w.dir)
+}
+
+// keep reports which rows of the section the report shows: all of them
+// without a filter, else as many of each fingerprint as are reported
+func (s *section) keep(reported Reported) []bool {
+	keep := make([]bool, len(s.rows))
+	left := maps.Clone(reported.Issues[s.key])
+	for i, r := range s.rows {
+		if reported.Issues == nil {
+			keep[i] = true
+		} else if left[r.fingerprint] > 0 {
+			left[r.fingerprint]--
+			keep[i] = true
+		}
+	}
+	return keep
+}
+
+// appendSection writes a section with the rows of its partial keep selects,
+// rendering the heading and the overflow note again with the final counts
+func appendSection(w io.Writer, p partial, keep []bool, overflow int) error {
+	data := sectionData{Analyzer: p.section.analyzer, Error: p.section.err, Overflow: overflow}
+	for _, k := range keep {
+		if k {
+			data.Issues++
+		}
+	}
+	if err := sectionStartTemplate.Execute(w, data); err != nil {
+		return err
+	}
+	if data.Error == "" && data.Issues > 0 {
+		if err := copyRows(w, p.path, keep); err != nil {
+			return err
+		}
+	}
+	return sectionEndTemplate.Execute(w, data)
+}
+
+// copyRows copies the rows of a partial that keep selects, in order. A row
+// runs from its rowPrefix line to the line ending in </tr>, as snippets may
+// span lines; the rest of the partial is skipped.
+func copyRows(w io.Writer, path string, keep []bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	index, inRow := -1, false
+	for {
+		line, err := r.ReadString('\n')
+		if strings.HasPrefix(line, rowPrefix) {
+			index++
+			inRow = true
+		}
+		if inRow && index < len(keep) && keep[index] {
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+		if strings.HasSuffix(strings.TrimRight(line, "\r\n"), "</tr>") {
+			inRow = false
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// create writes a new file with write
+func create(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(f)
+	err = write(out)
+	if err == nil {
+		err = out.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func appendFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,156 @@
+package htmlreport
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+func TestWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "report.html")
+	w, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.AddSection("/php", "php", []models.Issue{
+		{Path: "b.php", Line: 2, Severity: "minor", Description: "Commented <b>function</b>"},
+		{Path: "a.php", Line: 9, Severity: "major", Description: "first", Snippet: "// if (a < b)"},
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddSection("/js", "js", nil, errors.New("walk failed")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Finished sections are on disk, whole, before the report is assembled
+	partials, _ := filepath.Glob(path + ".d/*.html")
+	if len(partials) != 2 {
+		t.Fatalf("expected 2 partials, got %v", partials)
+	}
+	if data, _ := os.ReadFile(partials[0]); !strings.Contains(string(data), "<h2>php (2 issues)</h2>") || !strings.HasSuffix(string(data), "</table>\n</section>\n") {
+		t.Errorf("partial is not a whole section:\n%s", data)
+	}
+
+	if err := w.Finish(Summary{Timestamp: "now", Root: ".", Succeeded: 1, Analyzers: 2}, Reported{Overflow: map[string]int{"/php": 3}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{
+		"1/2 analyzers succeeded &middot; 2 issues",
+		`<span class="sev major">major</span>: 1`,
+		"<h2>php (2 issues)</h2>",
+		"Commented &lt;b&gt;function&lt;/b&gt;",
+		"first<pre>// if (a &lt; b)</pre>",
+		"3 more issues over max_issues_per_analyzer are left out.",
+		"Analyzer failed: walk failed",
+		"</html>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report lacks %q", want)
+		}
+	}
+	if strings.Index(html, "a.php") > strings.Index(html, "b.php") {
+		t.Error("issues not ordered by path")
+	}
+	if strings.Index(html, "<h2>php") > strings.Index(html, "<h2>js") {
+		t.Error("sections not in the order they were added")
+	}
+	if _, err := os.Stat(path + ".d"); !os.IsNotExist(err) {
+		t.Error("partials not removed")
+	}
+}
+
+func TestWriterHotspots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	w, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddSection("/php", "php", []models.Issue{{Path: "a.php", Line: 1, Severity: "minor"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddHotspots(models.HotspotsReport{
+		Since:    "6 months ago",
+		Hotspots: []models.Hotspot{{Path: "a.php", Commits: 4, Issues: 1, Score: 4}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Finish(Summary{}, Reported{}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "<tr><td>a.php</td><td>4</td><td>1</td><td>4</td></tr>") {
+		t.Errorf("report lacks the hotspot row:\n%s", html)
+	}
+	// Hotspots are not issues
+	if !strings.Contains(html, "&middot; 1 issues") {
+		t.Error("hotspots counted as issues")
+	}
+}
+
+func TestWriterReported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	w, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := models.Issue{Path: "a.php", Line: 1, Severity: "major", Description: "kept"}
+	baselined := models.Issue{Path: "a.php", Line: 5, Severity: "minor", Description: "baselined", Snippet: "// one\n// two </tr>"}
+	duplicate := models.Issue{Path: "b.php", Line: 3, Severity: "minor", Description: "twice"}
+	if err := w.AddSection("app/php", "php (app)", []models.Issue{kept, baselined, duplicate, duplicate}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddSection("app/html", "html (app)", []models.Issue{duplicate}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	reported := Reported{
+		Issues: map[string]map[string]int{
+			"app/php": {utils.Fingerprint(kept): 1, utils.Fingerprint(duplicate): 1},
+		},
+		Overflow: map[string]int{"app/php": 2},
+	}
+	if err := w.Finish(Summary{}, reported); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{
+		"&middot; 2 issues",
+		`<span class="sev major">major</span>: 1`,
+		`<span class="sev minor">minor</span>: 1`,
+		"<h2>php (app) (2 issues)</h2>",
+		"2 more issues over max_issues_per_analyzer are left out.",
+		"<h2>html (app) (0 issues)</h2>\n<p>No issues.</p>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report lacks %q:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "baselined") || strings.Contains(html, "// two") {
+		t.Errorf("report shows a filtered issue:\n%s", html)
+	}
+	if n := strings.Count(html, "twice"); n != 1 {
+		t.Errorf("expected the duplicate issue once, got %d", n)
+	}
+}
@@ -0,0 +1,97 @@
+package htmlreport
+
+import (
+	"html/template"
+
+	"code-analyzer/models"
+)
+
+var headerTemplate = template.Must(template.New("header").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Code Analysis Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #ddd; font-size: 14px; }
+th { background: #f4f4f4; }
+.sev { font-weight: bold; text-transform: uppercase; font-size: 12px; }
+.blocker, .critical { color: #b30000; }
+.major { color: #b36b00; }
+.minor { color: #007a99; }
+.info { color: #666; }
+.error { color: #b30000; }
+pre { margin: 4px 0 0; padding: 4px 8px; background: #f8f8f8; font-size: 12px; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>Code Analysis Report</h1>
+<p>{{.Timestamp}} &middot; {{.Root}} &middot; {{.Succeeded}}/{{.Analyzers}} analyzers succeeded &middot; {{.Issues}} issues</p>
+<ul>
+{{- range .Severities}}
+<li><span class="sev {{.Severity}}">{{.Severity}}</span>: {{.Issues}}</li>
+{{- end}}
+</ul>
+`))
+
+// sectionData is an analyzer section's heading, error and overflow note
+type sectionData struct {
+	Analyzer string
+	Issues   int
+	Overflow int
+	Error    string
+}
+
+// rowData is an issue row, marked with the issue's fingerprint
+type rowData struct {
+	Fingerprint string
+	models.Issue
+}
+
+// rowPrefix starts every issue row, so assembling can tell the rows of a
+// partial from its heading
+const rowPrefix = `<tr data-fingerprint="`
+
+var sectionStartTemplate = template.Must(template.New("sectionStart").Parse(`<section>
+<h2>{{.Analyzer}} ({{.Issues}} issues)</h2>
+{{- if .Error}}
+<p class="error">Analyzer failed: {{.Error}}</p>
+{{- else if .Issues}}
+<table>
+<tr><th>Severity</th><th>File</th><th>Line</th><th>Rule</th><th>Description</th></tr>
+{{- end}}
+`))
+
+var rowsTemplate = template.Must(template.New("rows").Parse(`
+{{- range .}}<tr data-fingerprint="{{.Fingerprint}}"><td class="sev {{.Severity}}">{{.Severity}}</td><td>{{.Path}}</td><td>{{.Line}}</td><td>{{if .DocsURL}}<a href="{{.DocsURL}}">{{.RuleID}}</a>{{else}}{{.RuleID}}{{end}}</td><td>{{.Description}}{{if .Snippet}}<pre>{{.Snippet}}</pre>{{end}}</td></tr>
+{{end}}`))
+
+var sectionEndTemplate = template.Must(template.New("sectionEnd").Parse(`
+{{- if .Error}}
+{{- else if .Issues}}</table>
+{{else}}<p>No issues.</p>
+{{end}}
+{{- if .Overflow}}<p>{{.Overflow}} more issues over max_issues_per_analyzer are left out.</p>
+{{end}}</section>
+`))
+
+var hotspotsTemplate = template.Must(template.New("hotspots").Parse(`<section>
+<h2>Hotspots ({{len .Hotspots}} files)</h2>
+<p>Files changed most often since {{.Since}}, weighted by their issues.</p>
+{{- if .Hotspots}}
+<table>
+<tr><th>File</th><th>Commits</th><th>Issues</th><th>Score</th></tr>
+{{- range .Hotspots}}
+<tr><td>{{.Path}}</td><td>{{.Commits}}</td><td>{{.Issues}}</td><td>{{.Score}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No changed file has issues.</p>
+{{- end}}
+</section>
+`))
+
+const footer = `</body>
+</html>
+`
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"code-analyzer/config"
+	"code-analyzer/htmlreport"
+	"code-analyzer/utils"
+)
+
+// htmlSectionKey is what the HTML report knows an analyzer run's section by,
+// the key overCap counts its overflow under
+func htmlSectionKey(project, analyzer string) string {
+	return project + "/" + analyzer
+}
+
+// htmlReported lists what the HTML report keeps of the sections streamed as
+// the analyzers finished: the reported findings, after the baseline, dedupe,
+// cap and age filters, and the issues each run had over the cap. Like the
+// GitLab report, the sections are scoped to the changed lines when
+// gitlab_report_scope is changed_lines.
+func htmlReported(cfg *config.AppConfig, findings []finding, overflow map[string]int) htmlreport.Reported {
+	if cfg.GitLabReportScope == "changed_lines" {
+		if changed, err := loadChangedLines(cfg); err != nil {
+			utils.Warnf("⚠️  Cannot scope HTML report to changed lines, reporting all issues: %v\n", err)
+		} else {
+			findings = filterChangedLines(findings, changed)
+		}
+	}
+
+	reported := htmlreport.Reported{Issues: make(map[string]map[string]int), Overflow: overflow}
+	for _, f := range findings {
+		key := htmlSectionKey(f.Project, f.Analyzer)
+		if reported.Issues[key] == nil {
+			reported.Issues[key] = make(map[string]int)
+		}
+		reported.Issues[key][utils.Fingerprint(f.Issue)]++
+	}
+	return reported
+}
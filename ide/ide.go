@@ -0,0 +1,124 @@
+// Package ide builds the issue report consumed by editor plugins. Its JSON
+// shape is versioned and kept stable independently of the CI artifacts.
+package ide
+
+import (
+	"path/filepath"
+	"sort"
+
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Version is bumped whenever a field is removed or changes meaning; new
+// optional fields do not change it
+const Version = 1
+
+// Report holds every issue of a run, grouped per file
+type Report struct {
+	Version int    `json:"version"`
+	Root    string `json:"root"`
+	Files   []File `json:"files"`
+}
+
+// File is one file with its issues ordered by line
+type File struct {
+	Path   string  `json:"path"`
+	Issues []Issue `json:"issues"`
+}
+
+// Issue is one finding with the range an editor should highlight
+type Issue struct {
+	Fingerprint string `json:"fingerprint"`
+	Analyzer    string `json:"analyzer"`
+	Check       string `json:"check"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Range       Range  `json:"range"`
+	Suggestion  string `json:"suggestion,omitempty"`
+}
+
+// Range is a 1-based, inclusive line range. The columns are set, counting
+// characters, when the rule knows the exact extent of the finding.
+type Range struct {
+	StartLine   int `json:"start_line"`
+	EndLine     int `json:"end_line"`
+	StartColumn int `json:"start_column,omitempty"`
+	EndColumn   int `json:"end_column,omitempty"`
+}
+
+// Finding is an issue together with the analyzer and check that reported it
+type Finding struct {
+	Analyzer string
+	Check    string
+	Issue    models.Issue
+}
+
+// suggestions are the quick-fix hints shown next to an analyzer's issues
+var suggestions = map[string]string{
+	"html":       "Delete the commented-out markup; version control keeps the history.",
+	"php":        "Delete the commented-out function or restore it if it is still needed.",
+	"js":         "Delete the commented-out code; version control keeps the history.",
+	"conflicts":  "Resolve the merge conflict and remove the conflict markers.",
+	"size":       "Split the file or wrap the long lines.",
+	"license":    "Add or update the license header.",
+	"whitespace": "Normalize the whitespace (indentation, trailing spaces, final newline).",
+	"encoding":   "Re-save the file as UTF-8 without a byte order mark.",
+	"sql":        "Move the query into a repository or query builder.",
+	"churn":      "Review the file's history and clean up the leftovers of repeated edits.",
+}
+
+// Build groups findings per file. Files are ordered by path and issues by
+// line, so the output is stable between runs with the same findings.
+func Build(root string, findings []Finding) Report {
+	byPath := make(map[string][]Issue)
+	for _, f := range findings {
+		path := filepath.ToSlash(f.Issue.Path)
+		byPath[path] = append(byPath[path], Issue{
+			Fingerprint: utils.Fingerprint(f.Issue),
+			Analyzer:    f.Analyzer,
+			Check:       f.Check,
+			Severity:    f.Issue.Severity,
+			Message:     f.Issue.Description,
+			Range:       issueRange(f.Analyzer, f.Issue),
+			Suggestion:  suggestions[f.Analyzer],
+		})
+	}
+
+	report := Report{Version: Version, Root: root, Files: make([]File, 0, len(byPath))}
+	for path, issues := range byPath {
+		sort.SliceStable(issues, func(i, j int) bool {
+			if issues[i].Range.StartLine != issues[j].Range.StartLine {
+				return issues[i].Range.StartLine < issues[j].Range.StartLine
+			}
+			return issues[i].Fingerprint < issues[j].Fingerprint
+		})
+		report.Files = append(report.Files, File{Path: path, Issues: issues})
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Path < report.Files[j].Path
+	})
+	return report
+}
+
+// issueRange spans the exact extent of the finding when the rule recorded
+// it, the flagged block when its size is known, and the reported line
+// otherwise. File-level issues (line 0) start at line 1. Conflict markers are
+// reported one per line while their span sizes the whole conflict block, so
+// only the opening marker, which records the block's end, covers the block.
+func issueRange(analyzer string, issue models.Issue) Range {
+	if issue.Line > 0 && issue.Column > 0 && issue.EndLine >= issue.Line {
+		return Range{StartLine: issue.Line, EndLine: issue.EndLine, StartColumn: issue.Column, EndColumn: issue.EndColumn}
+	}
+	start := issue.Line
+	if start < 1 {
+		start = 1
+	}
+	end := start
+	if issue.EndLine > start {
+		end = issue.EndLine
+	} else if analyzer != "conflicts" && issue.Metadata != nil && issue.Metadata.LineSpan > 1 {
+		end = start + issue.Metadata.LineSpan - 1
+	}
+	return Range{StartLine: start, EndLine: end}
+}
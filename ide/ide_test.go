@@ -0,0 +1,56 @@
+package ide
+
+import (
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestBuild(t *testing.T) {
+	findings := []Finding{
+		{Analyzer: "php", Check: "php-check", Issue: models.Issue{Path: "b.php", Line: 20, Severity: "minor", Description: "second"}},
+		{Analyzer: "php", Check: "php-check", Issue: models.Issue{Path: "b.php", Line: 3, Severity: "minor", Description: "first", Metadata: &models.IssueMetadata{LineSpan: 5}}},
+		{Analyzer: "conflicts", Check: "conflicts-check", Issue: models.Issue{Path: "a.js", Line: 9, Severity: "critical", Description: "=======", Metadata: &models.IssueMetadata{LineSpan: 5}}},
+		{Analyzer: "size", Check: "size-check", Issue: models.Issue{Path: "a.js", Line: 0, Severity: "info", Description: "too big"}},
+	}
+
+	report := Build(".", findings)
+	if report.Version != Version || len(report.Files) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Files[0].Path != "a.js" || report.Files[1].Path != "b.php" {
+		t.Errorf("files not ordered by path: %s, %s", report.Files[0].Path, report.Files[1].Path)
+	}
+
+	tests := []struct {
+		name       string
+		issue      Issue
+		start, end int
+	}{
+		{"file-level issue starts at line 1", report.Files[0].Issues[0], 1, 1},
+		{"conflict marker covers its own line", report.Files[0].Issues[1], 9, 9},
+		{"block spans its lines", report.Files[1].Issues[0], 3, 7},
+		{"single line", report.Files[1].Issues[1], 20, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.issue.Range.StartLine != tt.start || tt.issue.Range.EndLine != tt.end {
+				t.Errorf("got range %+v, want %d-%d", tt.issue.Range, tt.start, tt.end)
+			}
+			if tt.issue.Suggestion == "" || tt.issue.Fingerprint == "" {
+				t.Errorf("missing suggestion or fingerprint: %+v", tt.issue)
+			}
+		})
+	}
+}
+
+func TestBuild_ExactRange(t *testing.T) {
+	report := Build(".", []Finding{{Analyzer: "html", Check: "html-check", Issue: models.Issue{
+		Path: "a.html", Line: 4, Column: 3, EndLine: 6, EndColumn: 7, Severity: "minor", Description: "block",
+		Metadata: &models.IssueMetadata{LineSpan: 3},
+	}}})
+	want := Range{StartLine: 4, EndLine: 6, StartColumn: 3, EndColumn: 7}
+	if got := report.Files[0].Issues[0].Range; got != want {
+		t.Errorf("got range %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/bitbucket"
+	"code-analyzer/config"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
+)
+
+// bitbucketAnnotationTypes maps analyzers to annotation types; the rest are code smells
+var bitbucketAnnotationTypes = map[string]string{
+	"conflicts": "BUG",
+	"sql":       "VULNERABILITY",
+}
+
+// publishBitbucketInsights attaches a Code Insights report with one annotation
+// per issue to the commit being built. The report fails when the run does,
+// like the exit code. It returns the number of annotations sent.
+func publishBitbucketInsights(cfg config.BitbucketInsightsConfig, findings []finding, verdict runVerdict) (int, error) {
+	commit, ok := bitbucket.CommitFromEnv()
+	if !ok {
+		return 0, errors.New("not running in Bitbucket Pipelines (BITBUCKET_WORKSPACE, BITBUCKET_REPO_SLUG and BITBUCKET_COMMIT are required)")
+	}
+	reportID := cfg.ReportID
+	if reportID == "" {
+		reportID = "code-analyzer"
+	}
+	token := ""
+	if cfg.TokenEnv != "" {
+		token = os.Getenv(cfg.TokenEnv)
+	}
+
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Issue.Severity]++
+	}
+	var parts []string
+	for _, sev := range severityOrder {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+		}
+	}
+	details := fmt.Sprintf("%d issues", len(findings))
+	if len(parts) > 0 {
+		details += ": " + strings.Join(parts, ", ")
+	}
+
+	report := bitbucket.Report{
+		Title:      "Code Analysis",
+		Details:    details,
+		ReportType: "BUG",
+		Reporter:   "code-analyzer",
+		Result:     "PASSED",
+		Data: []bitbucket.Datum{
+			{Title: "Issues", Type: "NUMBER", Value: len(findings)},
+			{Title: "Critical issues", Type: "NUMBER", Value: counts["blocker"] + counts["critical"]},
+			{Title: "Analyzers succeeded", Type: "TEXT", Value: fmt.Sprintf("%d/%d", verdict.Succeeded, verdict.Total)},
+		},
+	}
+	if !verdict.passed() {
+		report.Result = "FAILED"
+	}
+	if origin, build := os.Getenv("BITBUCKET_GIT_HTTP_ORIGIN"), os.Getenv("BITBUCKET_BUILD_NUMBER"); origin != "" && build != "" {
+		report.Link = origin + "/addon/pipelines/home#!/results/" + build
+	}
+
+	// Most severe first, so the 1000-annotation limit keeps what matters
+	sorted := sortedByLocation(findings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return severityWeight[sorted[i].Issue.Severity] > severityWeight[sorted[j].Issue.Severity]
+	})
+	annotations := make([]bitbucket.Annotation, 0, len(sorted))
+	for _, f := range sorted {
+		annotationType, ok := bitbucketAnnotationTypes[f.Analyzer]
+		if !ok {
+			annotationType = "CODE_SMELL"
+		}
+		annotations = append(annotations, bitbucket.Annotation{
+			ExternalID:     utils.Fingerprint(f.Issue),
+			AnnotationType: annotationType,
+			Summary:        annotationSummary(f.Issue.Description),
+			Severity:       severity.Format(severity.Bitbucket, f.Issue.Severity),
+			Path:           filepath.ToSlash(filepath.Clean(f.Issue.Path)),
+			Line:           f.Issue.Line,
+		})
+	}
+
+	return bitbucket.NewClient(token).Publish(commit, reportID, report, annotations)
+}
+
+// annotationSummary shortens a description to the 450 characters an
+// annotation summary allows
+func annotationSummary(description string) string {
+	runes := []rune(description)
+	if len(runes) <= 450 {
+		return description
+	}
+	return string(runes[:447]) + "..."
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// capIssues keeps at most max issues per analyzer and project, the most
+// severe first, so reports of large monorepos stay small enough to upload.
+// The kept findings stay in their order; the counts dropped are returned by
+// "<project>/<analyzer>", the key of the run summary. A max of 0 keeps
+// everything.
+func capIssues(findings []finding, max int) ([]finding, map[string]int) {
+	if max <= 0 {
+		return findings, nil
+	}
+	drop, dropped := overCap(findings, max)
+	kept := make([]finding, 0, len(findings)-len(drop))
+	for i, f := range findings {
+		if !drop[i] {
+			kept = append(kept, f)
+		}
+	}
+	return kept, dropped
+}
+
+// overCap returns the indexes of the findings over max per analyzer and
+// project, and the counts dropped by "<project>/<analyzer>"
+func overCap(findings []finding, max int) (map[int]bool, map[string]int) {
+	groups := make(map[string][]int)
+	for i, f := range findings {
+		key := f.Project + "/" + f.Analyzer
+		groups[key] = append(groups[key], i)
+	}
+
+	dropped := make(map[string]int)
+	drop := make(map[int]bool)
+	for key, indexes := range groups {
+		if len(indexes) <= max {
+			continue
+		}
+		// Most severe first, then by location so the cut is the same every run
+		sort.SliceStable(indexes, func(a, b int) bool {
+			fa, fb := findings[indexes[a]].Issue, findings[indexes[b]].Issue
+			if wa, wb := severityWeight[fa.Severity], severityWeight[fb.Severity]; wa != wb {
+				return wa > wb
+			}
+			if fa.Path != fb.Path {
+				return fa.Path < fb.Path
+			}
+			return fa.Line < fb.Line
+		})
+		for _, i := range indexes[max:] {
+			drop[i] = true
+		}
+		dropped[key] = len(indexes) - max
+	}
+	return drop, dropped
+}
+
+// artifactSelector picks the issues of an analyzer's artifact the way the
+// reports do: the ones prepare keeps, capped at max, the most severe first.
+// The cap counts the analyzer's own issues, before the baseline and dedupe.
+func artifactSelector(analyzer string, prepare func(string, *models.Issue) bool, max int) func([]models.Issue) ([]bool, int) {
+	return func(issues []models.Issue) ([]bool, int) {
+		keep := make([]bool, len(issues))
+		var findings []finding
+		var indexes []int
+		for i, issue := range issues {
+			if prepare(analyzer, &issue) {
+				keep[i] = true
+				findings = append(findings, finding{Analyzer: analyzer, Issue: issue})
+				indexes = append(indexes, i)
+			}
+		}
+		if max <= 0 {
+			return keep, 0
+		}
+		drop, _ := overCap(findings, max)
+		for i := range drop {
+			keep[indexes[i]] = false
+		}
+		return keep, len(drop)
+	}
+}
+
+// warnOverflow warns about the analyzers whose issues were capped
+func warnOverflow(dropped map[string]int, max int) {
+	keys := make([]string, 0, len(dropped))
+	for key := range dropped {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name, analyzer, _ := strings.Cut(key, "/")
+		utils.Warnf("⚠️  %s: %d issues over max_issues_per_analyzer (%d) left out of the reports\n", project{Name: name}.label(analyzer), dropped[key], max)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestCapIssues(t *testing.T) {
+	at := func(project, analyzer, severity, path string, line int) finding {
+		return finding{Analyzer: analyzer, Project: project, Issue: models.Issue{Path: path, Line: line, Severity: severity}}
+	}
+	minorB := at("", "php", "minor", "app/b.php", 1)
+	critical := at("", "php", "critical", "app/z.php", 9)
+	minorA := at("", "php", "minor", "app/a.php", 5)
+	jsMinor := at("", "js", "minor", "web/app.js", 1)
+	apiMinor := at("api", "php", "minor", "api/a.php", 1)
+
+	tests := []struct {
+		name    string
+		max     int
+		in      []finding
+		want    []finding
+		dropped map[string]int
+	}{
+		{
+			name: "zero keeps everything",
+			in:   []finding{minorB, critical, minorA},
+			want: []finding{minorB, critical, minorA},
+		},
+		{
+			name:    "at the cap nothing is dropped",
+			max:     3,
+			in:      []finding{minorB, critical, minorA},
+			want:    []finding{minorB, critical, minorA},
+			dropped: map[string]int{},
+		},
+		{
+			name:    "one over the cap drops the least severe, last by location",
+			max:     2,
+			in:      []finding{minorB, critical, minorA},
+			want:    []finding{critical, minorA},
+			dropped: map[string]int{"/php": 1},
+		},
+		{
+			name:    "the most severe are kept in their order",
+			max:     1,
+			in:      []finding{minorB, critical, minorA},
+			want:    []finding{critical},
+			dropped: map[string]int{"/php": 2},
+		},
+		{
+			name:    "analyzers and projects are capped separately",
+			max:     1,
+			in:      []finding{minorB, jsMinor, apiMinor, minorA},
+			want:    []finding{jsMinor, apiMinor, minorA},
+			dropped: map[string]int{"/php": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dropped := capIssues(tt.in, tt.max)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kept\n%+v\nwant\n%+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(dropped, tt.dropped) {
+				t.Errorf("dropped %v, want %v", dropped, tt.dropped)
+			}
+		})
+	}
+}
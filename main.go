@@ -1,141 +1,848 @@
 package main
 
 import (
-	"crypto/md5"
-	"encoding/hex"
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"sort"
 	"strings"
+	"syscall"
+	"time"
 
+	"code-analyzer/allowlist"
 	"code-analyzer/analyzers"
 	"code-analyzer/analyzers/conflicts"
+	"code-analyzer/analyzers/docs"
+	"code-analyzer/analyzers/encoding"
 	"code-analyzer/analyzers/html"
 	"code-analyzer/analyzers/js"
+	"code-analyzer/analyzers/license"
 	"code-analyzer/analyzers/php"
+	"code-analyzer/analyzers/size"
+	"code-analyzer/analyzers/sql"
+	"code-analyzer/analyzers/stats"
+	"code-analyzer/analyzers/structured"
+	"code-analyzer/analyzers/templates"
+	"code-analyzer/analyzers/whitespace"
+	"code-analyzer/baseline"
+	"code-analyzer/blame"
+	"code-analyzer/churn"
 	"code-analyzer/config"
+	"code-analyzer/crashreport"
+	"code-analyzer/feedback"
+	"code-analyzer/gitdiff"
+	"code-analyzer/htmlreport"
 	"code-analyzer/models"
+	"code-analyzer/policy"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
 )
 
 func main() {
+	// Subcommands; "analyze" is the default and may be omitted
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfigCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "validate-config" {
+		runValidateConfig(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "engine" {
+		runEngine(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		runHistoryCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "feedback" {
+		runFeedbackCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "tui" {
+		runTriage(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "pre-commit" {
+		runPreCommit(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "install-hook" {
+		runInstallHook(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
 	// CLI flags
-	configFile := flag.String("config", "analysis-config.yaml", "Path to YAML configuration file")
+	configFile := flag.String("config", defaultConfigFile, "Path or http(s) URL of the YAML or JSON configuration file (\"-\" reads stdin; pin URLs with #sha256=<hex>); the built-in default is used when the default file is missing")
+	dir := flag.String("dir", "", "Directory to scan (overrides `dir` in the config)")
+	printDefaultConfig := flag.Bool("print-default-config", false, "Print the built-in default config and exit")
+	only := flag.String("only", "", "Comma-separated list of analyzers to run (overrides enabled flags)")
+	ext := flag.String("ext", "", "Comma-separated file extensions to restrict every analyzer to (e.g. .php,.blade.php)")
+	updateBaseline := flag.Bool("update-baseline", false, "Write all current issues to the configured baseline file")
+	format := flag.String("format", "", "Console output: \"table\", \"compact\" (one `path:line: severity [check] message` line per issue), \"plain\" (gcc-style `path:line:column: severity: message` diagnostics for editor quickfix lists), \"summary\" (one severity table), \"ide\" (issues grouped per file as JSON, for editor plugins) or \"azure\" (Azure Pipelines logging commands); defaults to summary in CI, table otherwise")
+	showProgress := flag.Bool("progress", true, "Report per-analyzer progress on stderr (a bar on terminals, periodic lines in CI)")
+	quiet := flag.Bool("quiet", false, "Print only a one-line summary (artifacts and reports are still written)")
+	verbose := flag.Bool("verbose", false, "Also log per-file decisions, skip reasons and analyzer timings on stderr")
+	progressJSON := flag.String("progress-json", "", "Also stream progress events as JSON lines to `fd:N`, `unix:PATH` or a file")
+	colorMode := flag.String("color", colorAuto, "Colorize severities: \"auto\" (terminals only, off with NO_COLOR), \"always\" or \"never\"")
+	logFormat := flag.String("log-format", utils.LogFormatText, "Format of warnings, progress and analyzer stats on stderr: \"text\" or \"json\"")
+	verify := flag.Bool("verify", false, "Read-only gate check: run analyzers but write no artifacts or reports; print the summary and exit 1 when an analyzer fails")
+	fast := flag.Bool("fast", false, "Quick conflicts-only check: terse output, no artifacts, exit 1 on findings")
+	fixInPlace := flag.Bool("fix", false, "Delete the commented-out code blocks found by the html, js and php analyzers, editing files in place")
+	fixDryRun := flag.Bool("fix-dry-run", false, "Print the diff -fix would apply on stdout instead of editing files")
+	fixPatch := flag.String("fix-patch", "", "Write the diff -fix would apply to this file instead of editing files")
+	tightenRatchet := flag.Bool("tighten-ratchet", false, "Lower the counts in the configured ratchet file to the current ones where they dropped (creates the file when missing)")
+	since := flag.String("since", "", "Analyze only files touched between this git ref and HEAD and report the issues the range introduced and removed")
+	timeout := flag.Duration("timeout", 0, "Stop the analyzers after this long and report the issues found so far (e.g. 10m; overrides `timeout_seconds`)")
+	concurrency := flag.Int("concurrency", 0, "Number of analyzers to run at the same time, at most one CPU-bound analyzer per core (overrides `concurrency`; analyzer tables are not printed above 1)")
+	olderThanDays := flag.Int("older-than", 0, "Only report issues first seen more than this many days ago, from the history and baseline (overrides `older_than_days`)")
+	minSeverity := flag.String("min-severity", "", "Leave issues below this severity (blocker, critical, major, minor, info) out of the output, the reports and the gate (overrides `min_severity`)")
+	profile := flag.String("profile", "", "Write pprof CPU and heap profiles of the analysis (cpu.pprof, heap.pprof) to this directory")
 	flag.Parse()
 
+	if err := utils.SetLogFormat(*logFormat, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if *printDefaultConfig {
+		_, _ = os.Stdout.Write(embeddedDefaultConfig)
+		return
+	}
+
 	// Load config file
-	cfg, err := config.LoadConfig(*configFile)
+	explicitConfig := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			explicitConfig = true
+		}
+	})
+	cfg, builtinConfig, err := loadConfig(*configFile, explicitConfig)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed to load config file: %v\n", err)
+		utils.Errorf("❌ Failed to load config file: %v\n", err)
+		os.Exit(1)
+	}
+	if *dir != "" {
+		cfg.Dir = *dir
+	}
+	if *minSeverity != "" {
+		cfg.MinSeverity = *minSeverity
+	}
+	if *olderThanDays != 0 {
+		cfg.OlderThanDays = *olderThanDays
+	}
+	if cfg.OlderThanDays < 0 {
+		utils.Errorf("❌ older_than_days must not be negative, got %d\n", cfg.OlderThanDays)
 		os.Exit(1)
 	}
+	if *concurrency > 0 {
+		cfg.Concurrency = *concurrency
+	}
+	for name, analyzerCfg := range cfg.Analyzers {
+		if _, err := analyzerCfg.BudgetDuration(); err != nil {
+			utils.Errorf("❌ Invalid budget for analyzer %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+	if cfg.MinSeverity != "" && !severity.Valid(cfg.MinSeverity) {
+		utils.Errorf("❌ Unknown minimum severity %q (expected one of %s)\n", cfg.MinSeverity, strings.Join(severity.Order, ", "))
+		os.Exit(1)
+	}
+	utils.SetHardLinkDedup(!cfg.UnstableInodes)
+	if *verify {
+		if *updateBaseline {
+			utils.Errorf("❌ -verify and -update-baseline are mutually exclusive\n")
+			os.Exit(1)
+		}
+		if *tightenRatchet {
+			utils.Errorf("❌ -verify and -tighten-ratchet are mutually exclusive\n")
+			os.Exit(1)
+		}
+		if *profile != "" {
+			utils.Errorf("❌ -verify and -profile are mutually exclusive: -verify writes nothing\n")
+			os.Exit(1)
+		}
+		applyVerifyMode(cfg)
+	}
+	fixModes := 0
+	for _, set := range []bool{*fixInPlace, *fixDryRun, *fixPatch != ""} {
+		if set {
+			fixModes++
+		}
+	}
+	if fixModes > 1 {
+		utils.Errorf("❌ -fix, -fix-dry-run and -fix-patch are mutually exclusive\n")
+		os.Exit(1)
+	}
+	if fixModes > 0 && *verify {
+		utils.Errorf("❌ -verify cannot be combined with -fix, -fix-dry-run or -fix-patch\n")
+		os.Exit(1)
+	}
+	if *tightenRatchet && cfg.Ratchet.Path == "" {
+		utils.Errorf("❌ -tighten-ratchet requires `ratchet.path` to be set in config\n")
+		os.Exit(1)
+	}
+
+	// Opt-in health reporting; a nil reporter discards everything
+	var reporter *crashreport.Reporter
+	if cfg.CrashReporting.Enabled && cfg.CrashReporting.Endpoint != "" {
+		slowAfter := time.Duration(cfg.CrashReporting.SlowAnalyzerSeconds) * time.Second
+		reporter = crashreport.New(cfg.CrashReporting.Endpoint, slowAfter)
+		defer func() {
+			if r := recover(); r != nil {
+				reporter.Panic(r, debug.Stack())
+				flushCrashReport(reporter)
+				panic(r)
+			}
+		}()
+	}
 
-	// Build analyzer list
+	if *fast {
+		runFastConflicts(cfg, *only, analyzers.ParseExtensions(*ext))
+		return
+	}
+
+	if *format == "" {
+		*format = defaultFormat()
+		if *verify {
+			*format = formatSummary
+		}
+	}
+	if *fixDryRun {
+		// The diff is the only output on stdout
+		if *format != formatTable && *format != formatSummary {
+			utils.Errorf("❌ -fix-dry-run prints a diff and cannot be combined with -format %s\n", *format)
+			os.Exit(1)
+		}
+		*format = formatTable
+	}
+	switch *format {
+	case formatTable:
+	case formatCompact, formatSummary, formatIDE, formatAzure, formatPlain:
+		stdout = io.Discard
+	default:
+		utils.Errorf("❌ Unknown format %q (expected \"table\", \"compact\", \"plain\", \"summary\", \"ide\" or \"azure\")\n", *format)
+		os.Exit(1)
+	}
+
+	if *fixDryRun {
+		stdout = io.Discard
+	}
+
+	color, err := useColor(*colorMode, os.Stdout)
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	utils.SetColor(color)
+
+	if *since != "" {
+		runSinceRange(cfg, *since, builtinConfig, *only, analyzers.ParseExtensions(*ext))
+		return
+	}
+
+	if *quiet && *verbose {
+		utils.Errorf("❌ -quiet and -verbose are mutually exclusive\n")
+		os.Exit(1)
+	}
+	if *quiet {
+		stdout = io.Discard
+		*showProgress = false
+	}
+	var verboseOut io.Writer
+	if *verbose {
+		verboseOut = os.Stderr
+	}
+
+	// Compile severity policies
+	policies, err := loadPolicies(cfg)
+	if err != nil {
+		utils.Errorf("❌ Failed to load policies: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Build analyzer list, per project in monorepos
 	var analyzersToRun []struct {
 		Name      string
 		Analyzer  analyzers.Analyzer
 		Extension string
+		Project   project
 	}
-	allAnalyzers := map[string]analyzers.Analyzer{
-		"html":      html.NewHTMLAnalyzer(),
-		"php":       php.NewPHPAnalyzer(),
-		"js":        js.NewJSAnalyzer(),
-		"conflicts": conflicts.NewConflictsAnalyzer(),
+	allAnalyzers := newAnalyzers()
+	ruleDocs := newRuleDocs(cfg.RuleDocs, allAnalyzers)
+	projects, err := resolveProjects(cfg, allAnalyzers)
+	if err != nil {
+		utils.Errorf("❌ Invalid projects: %v\n", err)
+		os.Exit(1)
 	}
 
 	analyzersConfig := make(map[string]config.AnalyzerConfig)
 
-	// Determine which analyzers to run based on config
-	for name, analyzerCfg := range cfg.Analyzers {
-		if analyzerCfg.Enabled {
-			if analyzer, exists := allAnalyzers[name]; exists {
-				analyzersToRun = append(analyzersToRun, struct {
-					Name      string
-					Analyzer  analyzers.Analyzer
-					Extension string
-				}{
-					Name:      strings.ToUpper(name),
-					Analyzer:  analyzer,
-					Extension: name,
-				})
-				analyzersConfig[name] = analyzerCfg
-			} else {
-				fmt.Fprintf(os.Stderr, "⚠️  Unknown analyzer in config: %s\n", name)
+	// Determine which analyzers to run based on config, in a stable order
+	names := make([]string, 0, len(cfg.Analyzers))
+	for name := range cfg.Analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	onlySet := parseOnly(*only)
+
+	// Zero-config run: only analyze the languages the project uses
+	if builtinConfig && onlySet == nil {
+		enabled := autoDetect(cfg, allAnalyzers)
+		utils.Infof("ℹ️  Auto-detected analyzers: %s (run `code-analyzer -print-default-config > %s` to customize)\n", strings.Join(enabled, ", "), defaultConfigFile)
+	}
+
+	for _, proj := range projects {
+		for _, name := range names {
+			analyzerCfg := cfg.Analyzers[name]
+			enabled := proj.enabled(name, analyzerCfg.Enabled)
+			if onlySet != nil {
+				enabled = onlySet[name]
+			}
+			if enabled {
+				if analyzer, exists := allAnalyzers[name]; exists {
+					analyzersToRun = append(analyzersToRun, struct {
+						Name      string
+						Analyzer  analyzers.Analyzer
+						Extension string
+						Project   project
+					}{
+						Name:      proj.label(strings.ToUpper(name)),
+						Analyzer:  analyzer,
+						Extension: name,
+						Project:   proj,
+					})
+					analyzersConfig[name] = analyzerCfg
+				} else if proj.Name == projects[0].Name { // Warn once, not per project
+					utils.Warnf("⚠️  Unknown analyzer in config: %s\n", name)
+				}
 			}
 		}
 	}
 
 	if len(analyzersToRun) == 0 {
-		fmt.Fprintf(os.Stderr, "No enabled analyzers found in config\n")
+		utils.Errorf("No enabled analyzers found in config\n")
 		os.Exit(1)
 	}
 
-	fmt.Printf("🔍 Code Analysis Tool (ALL ANALYZERS)\n")
-	fmt.Println(strings.Repeat("=", 61))
-	fmt.Printf("Config File: %s\n", *configFile)
-	fmt.Printf("Scanning: %s\n", cfg.Dir)
-	fmt.Printf("Running: %d analyzers\n", len(analyzersToRun))
-	fmt.Println()
+	fmt.Fprintf(stdout, "🔍 Code Analysis Tool (ALL ANALYZERS)\n")
+	fmt.Fprintln(stdout, strings.Repeat("=", 61))
+	fmt.Fprintf(stdout, "Config File: %s\n", *configFile)
+	fmt.Fprintf(stdout, "Scanning: %s\n", cfg.Dir)
+	fmt.Fprintf(stdout, "Running: %d analyzers\n", len(analyzersToRun))
+	fmt.Fprintln(stdout)
+
+	// Interrupting the run (or hitting the timeout) stops the analyzers, and
+	// the reports are still written with the issues found so far. A second
+	// interrupt kills the process.
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-sigCtx.Done()
+		stop()
+	}()
+	ctx := sigCtx
+	if *timeout == 0 {
+		*timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
 
+	// Profile the analysis and the reports, for performance investigations
+	var stopProfile func() error
+	if *profile != "" {
+		if stopProfile, err = startProfile(*profile); err != nil {
+			utils.Errorf("❌ Failed to start profiling: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	runStarted := time.Now()
 	successCount := 0
-	var allIssues []struct {
-		Analyzer string
-		Issue    models.Issue
+	var allIssues []finding
+	var ranAnalyzers []analyzerRun
+	var scheduled []scheduledAnalyzer
+	diagnostics := &analyzers.Diagnostics{}
+	belowMin := 0
+
+	// One artifact for every analyzer replaces the analyzers' own
+	var combined *analyzers.Artifacts
+	if cfg.Output != "" && cfg.Artifacts.Combined != "" {
+		combined = &analyzers.Artifacts{}
 	}
 
-	// Run all updated analyzers
-	for i, item := range analyzersToRun {
-		fmt.Println()
-		fmt.Println(strings.Repeat("=", 60))
-		fmt.Printf("📊 Running Analyzer %d/%d: %s\n", i+1, len(analyzersToRun), item.Name)
-		fmt.Println(strings.Repeat("=", 60))
-		fmt.Println()
+	var events *analyzers.EventStream
+	if *progressJSON != "" {
+		sink, err := openEventSink(*progressJSON)
+		if err != nil {
+			utils.Errorf("❌ Failed to open progress event stream: %v\n", err)
+			os.Exit(1)
+		}
+		defer sink.Close()
+		events = analyzers.NewEventStream(sink)
+	}
 
-		// Get specific config for this analyzer from YAML
-		analyzerYamlCfg := analyzersConfig[item.Extension]
+	totalFiles := 0
+	if *showProgress || events != nil || cfg.SummaryFile != "" {
+		totalFiles = countFiles(cfg.Dir, cfg.FollowSymlinks)
+	}
 
-		// Map YAML config to run config
-		runConfig := analyzers.Config{
-			RootDir:      cfg.Dir,
-			TopN:         analyzerYamlCfg.TopN,
-			MinValue:     analyzerYamlCfg.Min,
-			MinRatio:     analyzerYamlCfg.MinRatio,
-			SortBy:       analyzerYamlCfg.Sort,
-			ExcludePaths: analyzerYamlCfg.Exclude,
+	var progress *analyzers.Progress
+	if *showProgress || events != nil {
+		out, interactive := io.Writer(os.Stderr), isInteractive(os.Stderr) && !utils.JSONLogging()
+		if !*showProgress {
+			out, interactive = io.Discard, false
 		}
+		progress = analyzers.NewProgress(out, totalFiles, interactive)
+		progress.Events = events
+		progress.Structured = *showProgress && utils.JSONLogging()
+		events.RunStarted(len(analyzersToRun), totalFiles)
+	}
 
-		// Set default values if not present
-		if runConfig.SortBy == "" {
-			runConfig.SortBy = "ratio"
+	// Issues users marked as false positives are left out of the run
+	var falsePositives *feedback.Store
+	if cfg.FalsePositives != "" {
+		if falsePositives, err = feedback.Load(cfg.FalsePositives); err != nil {
+			utils.Errorf("❌ Failed to load false positives: %v\n", err)
+			os.Exit(1)
 		}
-		if runConfig.MinValue == 0 {
-			runConfig.MinValue = 1
+	}
+
+	// Allowlisted issues are demoted until their exception expires; expired
+	// exceptions of issues still found fail the run
+	var allowed *allowlist.Allowlist
+	allowlistExpired := false
+	found := make(map[string]bool)
+	if cfg.Allowlist != "" {
+		if allowed, err = allowlist.Load(cfg.Allowlist); err != nil {
+			utils.Errorf("❌ Failed to load allowlist: %v\n", err)
+			os.Exit(1)
 		}
-		if runConfig.TopN == 0 {
-			runConfig.TopN = 100
+	}
+
+	// Raw findings tell which suppression entries still hide issues
+	var suppressions *suppressionUsage
+	if cfg.SuppressionReport != "" {
+		suppressions = newSuppressionUsage()
+	}
+
+	// Sections are rendered as analyzers finish and assembled at the end
+	var htmlReport *htmlreport.Writer
+	if cfg.HTMLReport != "" {
+		if htmlReport, err = htmlreport.New(cfg.HTMLReport); err != nil {
+			utils.Errorf("❌ Failed to start HTML report: %v\n", err)
 		}
+	}
 
-		// Set output file
-		if cfg.Output != "" {
-			runConfig.OutputFile = filepath.Join(cfg.Output, fmt.Sprintf("%s-analysis.json", item.Extension))
+	// Run all updated analyzers. With concurrency above 1 they run at the
+	// same time and print no tables; their results are processed in order
+	// either way.
+	concurrent := cfg.Concurrency > 1 && len(analyzersToRun) > 1
+	if concurrent {
+		fmt.Fprintf(stdout, "\n⚡ Running %d analyzers, %d at a time\n", len(analyzersToRun), cfg.Concurrency)
+	}
+	// prepare applies the rule docs, policies and allowlist to an analyzer's
+	// issue and reports whether it is at least min_severity. Analyzers call
+	// it for their artifacts, so those leave out the same issues and are
+	// capped by the same severities.
+	prepare := func(analyzer string, issue *models.Issue) bool {
+		issue.Analyzer = analyzer
+		ruleDocs.Apply(issue)
+		policies.Apply(analyzer, issue)
+		if allowed != nil && allowed.Allows(utils.Fingerprint(*issue), runStarted) {
+			issue.Severity = severity.Info
+		}
+		return !belowMinSeverity(cfg, *issue)
+	}
+	results := make([]analyzerResult, len(analyzersToRun))
+	for i, item := range analyzersToRun {
+		runConfig := item.Project.runConfig(analyzerRunConfig(cfg, item.Extension, analyzersConfig[item.Extension]), cfg, item.Extension)
+		if cfg.MinSeverity != "" || cfg.MaxIssuesPerAnalyzer > 0 {
+			runConfig.Select = artifactSelector(item.Extension, prepare, cfg.MaxIssuesPerAnalyzer)
+		}
+		if combined != nil {
+			runConfig.OutputFile = ""
+			runConfig.Artifacts = combined
+			runConfig.ArtifactKey = item.Project.artifactKey(item.Extension)
+		}
+		runConfig.OnlyExtensions = analyzers.ParseExtensions(*ext)
+		runConfig.Quiet = *quiet || *format != formatTable || *fixDryRun || concurrent
+		if !concurrent {
+			runConfig.Progress = progress
+		}
+		runConfig.Stats = analyzers.NewStats()
+		runConfig.Verbose = verboseOut
+		runConfig.Diagnostics = diagnostics
+		results[i].config = runConfig
+	}
+
+	verb := "Running"
+	if concurrent {
+		verb = "Finished"
+	}
+	printHeader := func(i int) {
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, strings.Repeat("=", 60))
+		fmt.Fprintf(stdout, "📊 %s Analyzer %d/%d: %s\n", verb, i+1, len(analyzersToRun), analyzersToRun[i].Name)
+		fmt.Fprintln(stdout, strings.Repeat("=", 60))
+		fmt.Fprintln(stdout)
+	}
+
+	runAnalyzer := func(i int) {
+		item, r := analyzersToRun[i], &results[i]
+		if ctx.Err() != nil {
+			r.skipped = true
+			return
+		}
+		if !concurrent {
+			printHeader(i)
+			events.AnalyzerStarted(item.Extension, progress.Total())
+			progress.Start(item.Name)
+		}
+		analyzerCfg := analyzersConfig[item.Extension]
+		budget, _ := analyzerCfg.BudgetDuration()
+		limitCtx, budgetCtx, cancel := analyzerContexts(ctx, analyzerCfg.TimeoutSeconds, budget)
+		defer cancel()
+		started := time.Now()
+		r.issues, r.err = item.Analyzer.Run(budgetCtx, r.config)
+		r.elapsed = time.Since(started)
+		if r.err != nil && budgetCtx.Err() != nil {
+			switch {
+			case ctx.Err() != nil:
+				r.partial = true
+				r.err = fmt.Errorf("run %s, stopped with %d issues found", interruption(ctx, *timeout), len(r.issues))
+			case limitCtx.Err() != nil:
+				r.partial = true
+				r.err = fmt.Errorf("time budget of %ds exceeded, stopped with %d issues found", analyzerCfg.TimeoutSeconds, len(r.issues))
+			default:
+				// A soft budget keeps what was found without failing the run
+				r.overBudget = true
+				r.err = nil
+			}
+		}
+		if !concurrent {
+			progress.Finish()
 		}
+	}
+
+	skipWarned := false
+	processResult := func(i int) {
+		item, r := analyzersToRun[i], results[i]
+		if r.skipped {
+			if !skipWarned {
+				utils.Warnf("⚠️  Run %s, skipping %d analyzers; the reports are partial\n", interruption(ctx, *timeout), len(analyzersToRun)-i)
+				skipWarned = true
+			}
+			return
+		}
+		if concurrent {
+			printHeader(i)
+			events.AnalyzerStarted(item.Extension, progress.Total())
+		}
+		issues, err, elapsed := r.issues, r.err, r.elapsed
+		runConfig := r.config
+		scheduled = append(scheduled, scheduledAnalyzer{Name: item.Extension, Analyzer: item.Analyzer, Config: runConfig})
 
-		issues, err := item.Analyzer.Run(runConfig)
+		reporter.AnalyzerFinished(item.Extension, elapsed)
+		stats := runConfig.Stats.Summary()
+		utils.LogAttrs(slog.LevelInfo, "analyzer finished",
+			slog.String("analyzer", item.Extension),
+			slog.Int("issues", len(issues)),
+			slog.Int64("duration_ms", elapsed.Milliseconds()),
+			slog.Int("files_analyzed", stats.FilesAnalyzed),
+			slog.Int64("bytes_read", stats.BytesRead),
+			slog.Int("files_skipped_too_large", stats.SkippedTooLarge),
+			slog.Int("files_skipped_excluded", stats.SkippedExcluded),
+			slog.Bool("failed", err != nil),
+			slog.Bool("over_budget", r.overBudget))
+		if *verbose && !utils.JSONLogging() {
+			fmt.Fprintf(os.Stderr, "⏱️  %s finished in %s (%d issues, %d files, %s read, %d skipped as too large, %d excluded)\n",
+				item.Name, elapsed.Round(time.Millisecond), len(issues), stats.FilesAnalyzed, utils.FormatBytes(int(stats.BytesRead)), stats.SkippedTooLarge, stats.SkippedExcluded)
+		}
+		if r.overBudget {
+			utils.Warnf("⚠️  Analyzer %s ran over its budget of %s, reporting the %d issues found so far\n", item.Name, analyzersConfig[item.Extension].Budget, len(issues))
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Analyzer %s failed: %v\n", item.Name, err)
+			utils.Errorf("❌ Analyzer %s failed: %v\n", item.Name, err)
+			reporter.AnalyzerFailed(item.Extension, err)
 		} else {
 			successCount++
+		}
+		if suppressions != nil {
+			suppressions.Tally(item.Extension, issues, err)
+		}
+		// Interrupted analyzers still report what they found
+		if err == nil || r.partial {
+			if falsePositives != nil {
+				issues = falsePositives.Filter(issues)
+			}
+			kept := issues[:0]
 			for _, issue := range issues {
-				allIssues = append(allIssues, struct {
-					Analyzer string
-					Issue    models.Issue
-				}{
+				if allowed != nil {
+					found[utils.Fingerprint(issue)] = true
+				}
+				if !prepare(item.Extension, &issue) {
+					belowMin++
+					continue
+				}
+				kept = append(kept, issue)
+			}
+			issues = kept
+			addSnippets(cfg, issues)
+			for j := range issues {
+				events.IssueFound(issues[j])
+				allIssues = append(allIssues, finding{
 					Analyzer: item.Extension,
-					Issue:    issue,
+					Project:  item.Project.Name,
+					Issue:    issues[j],
 				})
 			}
 		}
+		if htmlReport != nil {
+			if err := htmlReport.AddSection(htmlSectionKey(item.Project.Name, item.Extension), item.Project.label(item.Extension), issues, err); err != nil {
+				utils.Errorf("❌ Failed to write HTML report section: %v\n", err)
+			}
+		}
+		events.AnalyzerFinished(len(issues), elapsed, err)
+		ranAnalyzers = append(ranAnalyzers, analyzerRun{Name: item.Extension, Project: item.Project.Name, Stats: runConfig.Stats, Elapsed: elapsed, Err: err, OverBudget: r.overBudget})
+	}
+
+	limit := 1
+	if concurrent {
+		limit = cfg.Concurrency
+	}
+	runScheduled(len(analyzersToRun), limit, func(i int) bool {
+		return analyzers.IsCPUBound(analyzersToRun[i].Analyzer)
+	}, runAnalyzer, func(i int, value interface{}, stack []byte) {
+		// A panicking analyzer fails like one returning an error
+		results[i].err = fmt.Errorf("panic: %v", value)
+		reporter.Panic(value, stack)
+		if !concurrent {
+			progress.Finish()
+		}
+	}, processResult)
+
+	if allowed != nil {
+		for _, e := range allowed.Expired(runStarted, found) {
+			utils.Errorf("❌ Allowlist entry %s expired on %s: %s\n", e.Fingerprint, e.Expires, e.Reason)
+			allowlistExpired = true
+		}
+	}
+
+	if combined != nil {
+		combinedPath := filepath.Join(cfg.Output, filepath.FromSlash(cfg.Artifacts.CombinedName(artifactTimestamp)))
+		report := models.CombinedAnalysisReport{
+			Timestamp:     utils.GetTimestamp(),
+			ScanDirectory: cfg.Dir,
+			Analyzers:     combined.Reports(),
+		}
+		if err := utils.WriteArtifact(combinedPath, report); err != nil {
+			utils.Errorf("❌ Failed to write combined artifact: %v\n", err)
+		} else {
+			fmt.Fprintf(stdout, "\n✅ Combined artifact generated: %s\n", combinedPath)
+		}
+	}
+
+	// Combine signals from the analyzers into file-level churn issues
+	if cfg.Churn.Enabled {
+		byAnalyzer := make(map[string][]models.Issue)
+		for _, f := range allIssues {
+			byAnalyzer[f.Analyzer] = append(byAnalyzer[f.Analyzer], f.Issue)
+		}
+		detector := churn.Detector{MinSmallBlocks: cfg.Churn.MinSmallBlocks, MinSignals: cfg.Churn.MinSignals}
+		churned := detector.Detect(byAnalyzer)
+		if suppressions != nil {
+			suppressions.Tally(churn.Analyzer, churned, nil)
+		}
+		kept := churned[:0]
+		for _, issue := range churned {
+			ruleDocs.Apply(&issue)
+			policies.Apply(churn.Analyzer, &issue)
+			if belowMinSeverity(cfg, issue) {
+				belowMin++
+				continue
+			}
+			kept = append(kept, issue)
+			allIssues = append(allIssues, finding{Analyzer: churn.Analyzer, Issue: issue})
+		}
+		churned = kept
+		printChurned(churned)
+		if htmlReport != nil {
+			if err := htmlReport.AddSection(htmlSectionKey("", churn.Analyzer), churn.Analyzer, churned, nil); err != nil {
+				utils.Errorf("❌ Failed to write HTML report section: %v\n", err)
+			}
+		}
+	}
+
+	if belowMin > 0 {
+		utils.Infof("🔽 %d issues below %s left out (min_severity)\n", belowMin, cfg.MinSeverity)
+	}
+
+	// Analyzers with overlapping file types can report the same issue
+	allIssues, merged := dedupeFindings(allIssues)
+	if merged > 0 {
+		utils.Infof("🔗 %d duplicate issues reported by more than one analyzer merged\n", merged)
+	}
+
+	// Delete the commented-out code found, or show what would be deleted
+	// Removals are planned before any file changes, so the fixes.patch
+	// artifact always applies to the code as analyzed
+	var fixesDiff string
+	if fixModes > 0 || cfg.Output != "" {
+		if fixes, err := planFixes(allIssues, cfg.Dir); err != nil {
+			utils.Errorf("❌ Failed to plan fixes: %v\n", err)
+		} else {
+			if cfg.Output != "" {
+				fixesDiff = fixesPatch(fixes)
+			}
+			if fixModes > 0 {
+				runFixes(fixes, *fixDryRun, *fixPatch)
+			}
+		}
+	}
+
+	// Surface rule failures that were recovered during the run
+	if entries := diagnostics.Entries(); len(entries) > 0 {
+		reporter.RulePanics(entries)
+		utils.Warnf("\n⚠️  %d rule failures were recovered during analysis\n", len(entries))
+		if cfg.Output != "" {
+			diagPath := filepath.Join(cfg.Output, "diagnostics.json")
+			report := models.DiagnosticsReport{Timestamp: utils.GetTimestamp(), Diagnostics: entries}
+			if err := utils.WriteArtifact(diagPath, report); err != nil {
+				utils.Errorf("❌ Failed to write diagnostics: %v\n", err)
+			} else {
+				fmt.Fprintf(stdout, "✅ Diagnostics written: %s\n", diagPath)
+			}
+		}
+	}
+
+	// Record the current issues as the new baseline if requested
+	var newIssuesExpired []baseline.Entry
+	var base *baseline.Baseline
+	if *updateBaseline {
+		if cfg.Baseline == "" {
+			utils.Errorf("❌ -update-baseline requires `baseline` to be set in config\n")
+			os.Exit(1)
+		}
+		if err := writeBaseline(cfg.Baseline, allIssues); err != nil {
+			utils.Errorf("❌ Failed to write baseline: %v\n", err)
+		} else {
+			fmt.Fprintf(stdout, "\n✅ Baseline updated: %s (%d issues)\n", cfg.Baseline, len(allIssues))
+		}
+	} else if cfg.Baseline != "" {
+		// Drop known issues
+		if base, err = baseline.Load(cfg.Baseline); err != nil {
+			utils.Errorf("❌ Failed to load baseline: %v\n", err)
+			os.Exit(1)
+		}
+		if cfg.BaselineMaxAgeDays > 0 {
+			newIssuesExpired = base.Expire(time.Duration(cfg.BaselineMaxAgeDays)*24*time.Hour, time.Now())
+			printExpired(newIssuesExpired, cfg.BaselineMaxAgeDays)
+		}
+		allIssues = filterBaseline(allIssues, base)
+	}
+
+	// Date each issue by when it was first observed
+	if err := stampFirstSeen(cfg.History, base, allIssues, runStarted); err != nil {
+		utils.Warnf("⚠️  Cannot tell when issues were first seen: %v\n", err)
+	}
+
+	// Attribute the reported issues to whoever last changed their line
+	if cfg.Blame {
+		annotator := blame.NewAnnotator()
+		for i := range allIssues {
+			allIssues[i].Issue.Blame = annotator.Line(allIssues[i].Issue.Path, allIssues[i].Issue.Line)
+		}
+	}
+
+	// Group the reported issues by the owners of their files
+	owned := false
+	if cfg.CodeOwners.Enabled {
+		if err := assignOwners(cfg.CodeOwners, allIssues); err != nil {
+			utils.Warnf("⚠️  Cannot group issues by owner: %v\n", err)
+		} else {
+			owned = true
+		}
+	}
+
+	// Fail only when counts went up against the committed ratchet
+	ratchetFailed := false
+	if cfg.Ratchet.Path != "" {
+		if ratchetFailed, err = checkRatchet(cfg.Ratchet, allIssues, *tightenRatchet); err != nil {
+			utils.Errorf("❌ Ratchet check failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	verdict := runVerdict{
+		Succeeded:        successCount,
+		Total:            len(analyzersToRun),
+		RatchetFailed:    ratchetFailed,
+		AllowlistExpired: allowlistExpired,
+	}
+
+	// Keep reports uploadable; the baseline and the ratchet saw every issue
+	allIssues, overflow := capIssues(allIssues, cfg.MaxIssuesPerAnalyzer)
+	warnOverflow(overflow, cfg.MaxIssuesPerAnalyzer)
+
+	// The history records every issue, so first-seen dates stay accurate
+	observed := allIssues
+	if cfg.OlderThanDays > 0 {
+		allIssues = olderThan(allIssues, cfg.OlderThanDays, runStarted)
+		if recent := len(observed) - len(allIssues); recent > 0 {
+			utils.Infof("⏳ %d issues first seen within the last %d days left out (older_than_days)\n", recent, cfg.OlderThanDays)
+		}
+	}
+
+	// The HTML report shows the reported issues, like the GitLab report
+	var htmlIssues htmlreport.Reported
+	if htmlReport != nil {
+		htmlIssues = htmlReported(cfg, allIssues, overflow)
+	}
+
+	// From here on allIssues is final and shared, read-only, by the reports
+	baselined := cfg.Baseline != "" && !*updateBaseline
+	var reports []reportJob
+
+	if baselined && !*verify {
+		// Write the delta for MR bots
+		deltaPath := "new-issues.json"
+		if cfg.Output != "" {
+			deltaPath = filepath.Join(cfg.Output, deltaPath)
+		}
+		reports = append(reports, reportJob{action: "generate new issues report", generate: func(out io.Writer) error {
+			if err := generateNewIssuesReport(deltaPath, cfg.Baseline, allIssues, newIssuesExpired); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "\n✅ New issues report generated: %s (%d new)\n", deltaPath, len(allIssues))
+			return nil
+		}})
+	}
+
+	if fixesDiff != "" {
+		patchPath := filepath.Join(cfg.Output, "fixes.patch")
+		reports = append(reports, reportJob{action: "write fixes patch", generate: func(out io.Writer) error {
+			if err := os.WriteFile(patchPath, []byte(fixesDiff), 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Fixes patch written: %s (apply with git apply)\n", patchPath)
+			return nil
+		}})
 	}
 
 	// Generate GitLab Code Quality Report if configured
@@ -145,70 +852,631 @@ func main() {
 		// We do NOT automatically join with cfg.Output anymore, as that forces it into artifacts/
 		// Users should specify full relative path in config if they want it in artifacts/
 
-		if err := generateGitLabReport(reportPath, allIssues); err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Failed to generate GitLab report: %v\n", err)
+		reports = append(reports, reportJob{action: "generate GitLab report", generate: func(out io.Writer) error {
+			reportIssues := allIssues
+			if cfg.GitLabReportScope == "changed_lines" {
+				// Only issues on lines changed by the MR go to the widget; artifacts stay complete
+				changed, err := loadChangedLines(cfg)
+				if err != nil {
+					utils.Warnf("⚠️  Cannot scope GitLab report to changed lines, reporting all issues: %v\n", err)
+				} else {
+					reportIssues = filterChangedLines(allIssues, changed)
+					fmt.Fprintf(out, "\n🔎 GitLab report scoped to changed lines: %d of %d issues\n", len(reportIssues), len(allIssues))
+				}
+			}
+
+			if err := generateGitLabReport(reportPath, reportIssues); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "\n✅ GitLab Code Quality Report generated: %s\n", reportPath)
+
+			metadataPath := gitLabMetadataPath(reportPath)
+			if err := generateGitLabMetadata(metadataPath, reportPath, reportIssues); err != nil {
+				return fmt.Errorf("metadata: %v", err)
+			}
+			fmt.Fprintf(out, "✅ GitLab report metadata generated: %s\n", metadataPath)
+			return nil
+		}})
+	}
+
+	// Write the MR summary comment body
+	if cfg.MRComment.Path != "" {
+		reports = append(reports, reportJob{action: "generate MR comment", generate: func(out io.Writer) error {
+			if err := generateMRComment(cfg.MRComment.Path, mrCommentReportURL(cfg.MRComment.ReportURL), allIssues, baselined); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ MR comment generated: %s\n", cfg.MRComment.Path)
+			return nil
+		}})
+	}
+
+	if cfg.SummaryFile != "" {
+		elapsed := time.Since(runStarted)
+		reports = append(reports, reportJob{action: "write run summary", generate: func(out io.Writer) error {
+			if err := writeRunSummary(cfg.SummaryFile, elapsed, totalFiles, ranAnalyzers, allIssues, overflow, verdict); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Run summary written: %s\n", cfg.SummaryFile)
+			return nil
+		}})
+	}
+
+	if cfg.CSVReport != "" {
+		reports = append(reports, reportJob{action: "write CSV report", generate: func(out io.Writer) error {
+			if err := writeCSVReport(cfg.CSVReport, allIssues); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ CSV report written: %s (%d issues)\n", cfg.CSVReport, len(allIssues))
+			return nil
+		}})
+	}
+
+	if cfg.SARIFReport != "" {
+		reports = append(reports, reportJob{action: "write SARIF report", generate: func(out io.Writer) error {
+			if err := writeSARIFReport(cfg.SARIFReport, allIssues); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ SARIF report written: %s (%d issues)\n", cfg.SARIFReport, len(allIssues))
+			return nil
+		}})
+	}
+
+	if suppressions != nil {
+		reports = append(reports, reportJob{action: "write suppression report", generate: func(out io.Writer) error {
+			report := suppressions.Report(base, falsePositives)
+			if err := utils.WriteArtifact(cfg.SuppressionReport, report); err != nil {
+				return err
+			}
+			printStaleSuppressions(out, report.Stale)
+			fmt.Fprintf(out, "✅ Suppression report written: %s (%d stale, %d used)\n", cfg.SuppressionReport, len(report.Stale), len(report.Used))
+			return nil
+		}})
+	}
+
+	if cfg.BitbucketInsights.Enabled {
+		reports = append(reports, reportJob{action: "publish Bitbucket Code Insights report", warnOnly: true, generate: func(out io.Writer) error {
+			sent, err := publishBitbucketInsights(cfg.BitbucketInsights, allIssues, verdict)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Bitbucket Code Insights report published (%d annotations)\n", sent)
+			return nil
+		}})
+	}
+
+	if len(cfg.Notifications) > 0 {
+		reports = append(reports, reportJob{action: "send notifications", warnOnly: true, generate: func(out io.Writer) error {
+			return sendNotifications(out, cfg.Notifications, allIssues, baselined, verdict)
+		}})
+	}
+
+	if cfg.Webhook.URL != "" {
+		reports = append(reports, reportJob{action: "deliver findings to webhook", warnOnly: true, generate: func(out io.Writer) error {
+			report := buildFindingsReport(allIssues, baselined, verdict)
+			if err := sendWebhook(cfg.Webhook, report); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Findings delivered to webhook (%d issues)\n", len(allIssues))
+			return nil
+		}})
+	}
+
+	var debt models.DebtReport
+	if cfg.Debt.Enabled {
+		debt = buildDebtReport(cfg.Debt, cfg.Dir, allIssues)
+	}
+	if cfg.Debt.Enabled && cfg.Debt.Path != "" {
+		reports = append(reports, reportJob{action: "write debt report", generate: func(out io.Writer) error {
+			if err := utils.WriteArtifact(cfg.Debt.Path, debt); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Debt report written: %s\n", cfg.Debt.Path)
+			return nil
+		}})
+	}
+
+	if cfg.Hotspots.Enabled {
+		if report, err := buildHotspotsReport(cfg.Hotspots, cfg.Dir, allIssues); err != nil {
+			utils.Warnf("⚠️  Failed to rank hotspots: %v\n", err)
 		} else {
-			fmt.Printf("\n✅ GitLab Code Quality Report generated: %s\n", reportPath)
+			if htmlReport != nil {
+				if err := htmlReport.AddHotspots(report); err != nil {
+					utils.Errorf("❌ Failed to write HTML report section: %v\n", err)
+				}
+			}
+			path := hotspotsPath(cfg.Hotspots)
+			reports = append(reports, reportJob{action: "write hotspots report", generate: func(out io.Writer) error {
+				if err := utils.WriteArtifact(path, report); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "✅ Hotspots report written: %s (%d files)\n", path, len(report.Hotspots))
+				return nil
+			}})
 		}
 	}
 
-	fmt.Println()
-	fmt.Println(strings.Repeat("=", 60))
-	if successCount == len(analyzersToRun) {
-		fmt.Printf("✅ Analysis Complete: %d/%d analyzers succeeded\n", successCount, len(analyzersToRun))
+	if owned && cfg.CodeOwners.Artifacts != "" {
+		reports = append(reports, reportJob{action: "generate per-owner reports", generate: func(out io.Writer) error {
+			n, err := writeOwnerReports(cfg.CodeOwners.Artifacts, allIssues)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Per-owner reports generated: %s (%d owners)\n", cfg.CodeOwners.Artifacts, n)
+			return nil
+		}})
+	}
+
+	if cfg.History != "" {
+		reports = append(reports, reportJob{action: "record history", generate: func(out io.Writer) error {
+			if err := recordHistory(cfg.History, observed); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Run recorded in history: %s\n", cfg.History)
+			return nil
+		}})
+	}
+
+	if htmlReport != nil {
+		reports = append(reports, reportJob{action: "generate HTML report", generate: func(out io.Writer) error {
+			summary := htmlreport.Summary{
+				Timestamp: utils.GetTimestamp(),
+				Root:      cfg.Dir,
+				Succeeded: successCount,
+				Analyzers: len(analyzersToRun),
+			}
+			if err := htmlReport.Finish(summary, htmlIssues); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ HTML report generated: %s\n", cfg.HTMLReport)
+			return nil
+		}})
+	}
+
+	if cfg.Heatmap.Path != "" {
+		reports = append(reports, reportJob{action: "generate heatmap", generate: func(out io.Writer) error {
+			if err := generateHeatmap(cfg.Heatmap, cfg.Dir, cfg.FollowSymlinks, scheduled, allIssues); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Heatmap generated: %s\n", cfg.Heatmap.Path)
+			if cfg.Heatmap.SVG != "" {
+				fmt.Fprintf(out, "✅ Heatmap treemap generated: %s\n", cfg.Heatmap.SVG)
+			}
+			return nil
+		}})
+	}
+
+	generateReports(reports)
+
+	if stopProfile != nil {
+		if err := stopProfile(); err != nil {
+			utils.Errorf("❌ Failed to write profiles: %v\n", err)
+		} else {
+			fmt.Fprintf(stdout, "✅ Profiles written: %s\n", *profile)
+		}
+	}
+
+	// Report files that no analyzer looked at
+	if coverage, total, err := computeCoverage(cfg.Dir, cfg.FollowSymlinks, scheduled); err != nil {
+		utils.Warnf("⚠️  Failed to compute coverage: %v\n", err)
 	} else {
-		fmt.Printf("⚠️  Analysis Complete: %d/%d analyzers succeeded\n", successCount, len(analyzersToRun))
+		printCoverage(coverage, total)
+	}
+
+	printSeveritySummary(stdout, allIssues)
+	if len(cfg.Projects) > 0 {
+		fmt.Fprintln(stdout)
+		printProjectSummary(stdout, projects, allIssues)
+	}
+	if owned {
+		printOwnerSummary(stdout, allIssues)
+	}
+	if cfg.Debt.Enabled {
+		printDebtReport(stdout, debt)
+	}
+
+	flushCrashReport(reporter)
+
+	if *format == formatCompact {
+		printCompact(os.Stdout, allIssues)
+	}
+	if *format == formatPlain {
+		printPlain(os.Stdout, allIssues)
+	}
+	if *format == formatAzure {
+		printAzure(os.Stdout, allIssues)
+	}
+	if *format == formatIDE {
+		if err := printIDE(os.Stdout, cfg.Dir, allIssues); err != nil {
+			utils.Errorf("❌ Failed to write IDE report: %v\n", err)
+		}
+	}
+	if *format == formatSummary && !*quiet {
+		var ran []string
+		for _, item := range analyzersToRun {
+			// Projects run the same analyzers
+			if !slices.Contains(ran, item.Extension) {
+				ran = append(ran, item.Extension)
+			}
+		}
+		sort.Strings(ran)
+		if cfg.Churn.Enabled {
+			ran = append(ran, churn.Analyzer)
+		}
+		if len(cfg.Projects) > 0 {
+			printProjectSummary(os.Stdout, projects, allIssues)
+			fmt.Fprintln(os.Stdout)
+		}
+		printSummary(os.Stdout, ran, allIssues, verdict)
+		if owned {
+			printOwnerSummary(os.Stdout, allIssues)
+		}
+		if cfg.Debt.Enabled {
+			printDebtReport(os.Stdout, debt)
+		}
+	}
+	if *quiet {
+		printQuietSummary(os.Stdout, verdict, allIssues)
+	}
+	events.RunFinished(successCount, len(analyzersToRun), len(allIssues))
+	utils.LogAttrs(slog.LevelInfo, "run finished",
+		slog.Int("succeeded", successCount),
+		slog.Int("analyzers", len(analyzersToRun)),
+		slog.Int("issues", len(allIssues)))
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, strings.Repeat("=", 60))
+	banner := fmt.Sprintf("Analysis Complete: %d/%d analyzers succeeded", successCount, len(analyzersToRun))
+	if !verdict.passed() {
+		for _, reason := range verdict.reasons() {
+			fmt.Fprintf(stdout, "❌ Failed: %s\n", reason)
+		}
+		fmt.Fprintf(stdout, "⚠️  %s\n", utils.Yellow(banner))
 		os.Exit(1)
 	}
-	fmt.Println(strings.Repeat("=", 60))
+	fmt.Fprintf(stdout, "✅ %s\n", utils.Green(banner))
+	fmt.Fprintln(stdout, strings.Repeat("=", 60))
 }
 
-func generateGitLabReport(outputPath string, findings []struct {
+// belowMinSeverity reports whether min_severity leaves the issue out of the
+// run
+func belowMinSeverity(cfg *config.AppConfig, issue models.Issue) bool {
+	return cfg.MinSeverity != "" && !severity.AtLeast(issue.Severity, cfg.MinSeverity)
+}
+
+// analyzerContexts applies an analyzer's time limits to the run's context:
+// limit ends at timeout_seconds, which fails the analyzer, and budget, derived
+// from it, also ends at the soft budget. Either is unlimited when 0.
+func analyzerContexts(ctx context.Context, timeoutSeconds int, budget time.Duration) (limit, budgeted context.Context, cancel context.CancelFunc) {
+	limit, cancelLimit := ctx, context.CancelFunc(func() {})
+	if timeoutSeconds > 0 {
+		limit, cancelLimit = context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+	}
+	budgeted, cancelBudget := limit, context.CancelFunc(func() {})
+	if budget > 0 {
+		budgeted, cancelBudget = context.WithTimeout(limit, budget)
+	}
+	return limit, budgeted, func() {
+		cancelBudget()
+		cancelLimit()
+	}
+}
+
+// interruption describes why the run's context is done
+func interruption(ctx context.Context, timeout time.Duration) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Sprintf("timed out after %s", timeout)
+	}
+	return "interrupted"
+}
+
+// printChurned lists the files the churn heuristic flagged
+func printChurned(churned []models.Issue) {
+	if len(churned) == 0 {
+		return
+	}
+	fmt.Fprintf(stdout, "\n🌀 %d files show signs of repeated paste-over edits and need refactoring:\n", len(churned))
+	for _, issue := range churned {
+		fmt.Fprintf(stdout, "   %s: %s\n", issue.Path, strings.TrimPrefix(issue.Description, churn.DescriptionPrefix))
+	}
+}
+
+// flushCrashReport sends buffered health events; failures never affect the run
+func flushCrashReport(reporter *crashreport.Reporter) {
+	if err := reporter.Flush(); err != nil {
+		utils.Warnf("⚠️  Failed to send crash report: %v\n", err)
+	}
+}
+
+// parseOnly turns the -only flag into a set of analyzer names, nil when unset
+func parseOnly(only string) map[string]bool {
+	if only == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, name := range strings.Split(only, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// runFastConflicts runs the quick conflicts-only check and exits
+func runFastConflicts(cfg *config.AppConfig, only string, extensions []string) {
+	if onlySet := parseOnly(only); onlySet != nil && (len(onlySet) != 1 || !onlySet["conflicts"]) {
+		utils.Errorf("❌ -fast only supports -only conflicts\n")
+		os.Exit(1)
+	}
+
+	maxFileSize := cfg.Analyzers["conflicts"].MaxFileSize
+	if maxFileSize == 0 {
+		maxFileSize = cfg.MaxFileSize
+	}
+
+	issues, err := conflicts.FastScan(analyzers.Config{
+		RootDir:        cfg.Dir,
+		ExcludePaths:   cfg.Analyzers["conflicts"].Exclude,
+		OnlyExtensions: extensions,
+		FollowSymlinks: cfg.FollowSymlinks,
+		MaxFileSize:    maxFileSize,
+		MarkerSizes:    cfg.Analyzers["conflicts"].MarkerSizes,
+	})
+	if err != nil {
+		utils.Errorf("❌ Conflict scan failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(stdout, "%s:%d: %s\n", issue.Path, issue.Line, issue.Description)
+	}
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// loadPolicies compiles the severity policies: the built-in test code
+// downgrade first, so configured policies can override it
+func loadPolicies(cfg *config.AppConfig) (policy.Chain, error) {
+	// Every run path loads the policies, so the severity mapping the
+	// normalizer and the reports use is set up here too
+	mapping, err := severity.NewMapping(cfg.Severities.Aliases, map[string]map[string]string{
+		severity.GitLab:    cfg.Severities.GitLab,
+		severity.Bitbucket: cfg.Severities.Bitbucket,
+		severity.Azure:     cfg.Severities.Azure,
+		severity.Plain:     cfg.Severities.Plain,
+		severity.SARIF:     cfg.Severities.SARIF,
+	})
+	if err != nil {
+		return nil, err
+	}
+	severity.SetMapping(mapping)
+	normalize := policy.Chain{policy.NewSeverityNormalizer()}
+
+	chain, err := policy.ParseAll(cfg.Policies)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.TestPaths.Disabled {
+		return append(normalize, chain...), nil
+	}
+	downgrade, err := policy.NewTestDowngrade(cfg.TestPaths.Patterns)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(normalize, downgrade), chain...), nil
+}
+
+// newAnalyzers returns every available analyzer by config name
+func newAnalyzers() map[string]analyzers.Analyzer {
+	return map[string]analyzers.Analyzer{
+		"html":       html.NewHTMLAnalyzer(),
+		"php":        php.NewPHPAnalyzer(),
+		"js":         js.NewJSAnalyzer(),
+		"conflicts":  conflicts.NewConflictsAnalyzer(),
+		"size":       size.NewSizeAnalyzer(),
+		"license":    license.NewLicenseAnalyzer(),
+		"whitespace": whitespace.NewWhitespaceAnalyzer(),
+		"encoding":   encoding.NewEncodingAnalyzer(),
+		"sql":        sql.NewSQLAnalyzer(),
+		"stats":      stats.NewStatsAnalyzer(),
+		"templates":  templates.NewTemplatesAnalyzer(),
+		"docs":       docs.NewDocsAnalyzer(),
+		"structured": structured.NewStructuredAnalyzer(),
+	}
+}
+
+// addSnippets fills in the context snippets of issues unless they are
+// turned off with a negative snippet_lines
+func addSnippets(cfg *config.AppConfig, issues []models.Issue) {
+	switch {
+	case cfg.SnippetLines < 0:
+	case cfg.SnippetLines == 0:
+		utils.AddSnippets(issues, utils.DefaultSnippetLines)
+	default:
+		utils.AddSnippets(issues, cfg.SnippetLines)
+	}
+}
+
+// artifactTimestamp fills {timestamp} in artifact names, the same for every
+// artifact of a run
+var artifactTimestamp = time.Now().UTC().Format(config.ArtifactTimestampLayout)
+
+// analyzerRunConfig maps an analyzer's YAML config to its run config,
+// applying defaults. Run-time settings (progress, stats, output mode) are left
+// to the caller.
+func analyzerRunConfig(cfg *config.AppConfig, name string, analyzerYamlCfg config.AnalyzerConfig) analyzers.Config {
+	runConfig := analyzers.Config{
+		RootDir:            cfg.Dir,
+		TopN:               analyzerYamlCfg.TopN,
+		MinValue:           analyzerYamlCfg.Min,
+		MinRatio:           analyzerYamlCfg.MinRatio,
+		SortBy:             analyzerYamlCfg.Sort,
+		ExcludePaths:       analyzerYamlCfg.Exclude,
+		FollowSymlinks:     cfg.FollowSymlinks,
+		MaxFileSize:        analyzerYamlCfg.MaxFileSize,
+		IgnoreComments:     analyzerYamlCfg.IgnoreComments,
+		Extensions:         analyzerYamlCfg.Extensions,
+		MarkerSizes:        analyzerYamlCfg.MarkerSizes,
+		TargetBranch:       analyzerYamlCfg.TargetBranch,
+		Headers:            analyzerYamlCfg.Headers,
+		RequireCurrentYear: analyzerYamlCfg.RequireCurrentYear,
+		WhitespaceChecks:   analyzerYamlCfg.Checks,
+		MaxBytes:           analyzerYamlCfg.MaxBytes,
+		MaxLines:           analyzerYamlCfg.MaxLines,
+		MaxLineLength:      analyzerYamlCfg.MaxLineLength,
+		MaxStringLength:    analyzerYamlCfg.MaxStringLength,
+		MaxComplexity:      analyzerYamlCfg.MaxComplexity,
+		MaxNesting:         analyzerYamlCfg.MaxNesting,
+		LaravelPaths:       analyzerYamlCfg.LaravelPaths,
+	}
+
+	// Set default values if not present
+	if runConfig.SortBy == "" {
+		runConfig.SortBy = "ratio"
+	}
+	if runConfig.MinValue == 0 {
+		runConfig.MinValue = 1
+	}
+	if runConfig.TopN == 0 {
+		runConfig.TopN = 100
+	}
+	if runConfig.MaxFileSize == 0 {
+		runConfig.MaxFileSize = cfg.MaxFileSize
+	}
+
+	// Set output file
+	if cfg.Output != "" {
+		runConfig.OutputFile = filepath.Join(cfg.Output, filepath.FromSlash(cfg.Artifacts.ArtifactName(name, "", artifactTimestamp)))
+	}
+	return runConfig
+}
+
+// finding is an issue together with the analyzer that reported it and, in
+// monorepos, the project it was found in
+type finding struct {
 	Analyzer string
+	Project  string
 	Issue    models.Issue
-}) error {
-	var report []models.CodeQualityIssue
-
-	for _, finding := range findings {
-		// Create fingerprint
-		hashContent := fmt.Sprintf("%s:%d:%s", finding.Issue.Description, finding.Issue.Line, finding.Issue.Path)
-		hasher := md5.New()
-		hasher.Write([]byte(hashContent))
-		fingerprint := hex.EncodeToString(hasher.Sum(nil))
-
-		// Ensure path is relative to project root if possible
-		// finding.Issue.Path should already be relative or absolute depending on how it was found.
-
-		report = append(report, models.CodeQualityIssue{
-			Description: finding.Issue.Description,
-			CheckName:   fmt.Sprintf("%s-check", finding.Analyzer),
-			Fingerprint: fingerprint,
-			Severity:    finding.Issue.Severity,
-			Location: models.Location{
-				Path: finding.Issue.Path,
-				Lines: models.Lines{
-					Begin: finding.Issue.Line,
-				},
-			},
-		})
+}
+
+// checkName returns the Code Quality check name for a finding: its rule ID,
+// or "<analyzer>-check" for issues of rules without one
+func (f finding) checkName() string {
+	if f.Issue.RuleID != "" {
+		return f.Issue.RuleID
+	}
+	return fmt.Sprintf("%s-check", f.Analyzer)
+}
+
+// filterBaseline drops findings whose fingerprint is part of the baseline
+func filterBaseline(findings []finding, base *baseline.Baseline) []finding {
+	var kept []finding
+	for _, f := range findings {
+		if !base.Contains(utils.Fingerprint(f.Issue)) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// loadChangedLines computes the lines changed against the configured diff base
+func loadChangedLines(cfg *config.AppConfig) (gitdiff.ChangedLines, error) {
+	base := cfg.DiffBase
+	if base == "" {
+		base = os.Getenv("CI_MERGE_REQUEST_DIFF_BASE_SHA")
+	}
+	if base == "" {
+		return nil, fmt.Errorf("no diff base: set `diff_base` or run in a merge request pipeline")
 	}
+	return gitdiff.Load(base)
+}
 
-	// Write to file
-	// Ensure directory exists
-	dir := filepath.Dir(outputPath)
-	if dir != "." && dir != "" {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
+// filterChangedLines keeps findings whose line falls within a changed hunk
+func filterChangedLines(findings []finding, changed gitdiff.ChangedLines) []finding {
+	var kept []finding
+	for _, f := range findings {
+		if changed.Contains(f.Issue.Path, f.Issue.Line) {
+			kept = append(kept, f)
 		}
 	}
+	return kept
+}
 
-	file, err := os.Create(outputPath)
+// writeBaseline records findings as the new baseline, keeping the date of
+// entries that were already accepted so they keep aging
+func writeBaseline(path string, findings []finding) error {
+	previous, err := baseline.Load(path)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(report)
+	today := baseline.Today()
+	entries := []baseline.Entry{}
+	for _, f := range findings {
+		fingerprint := utils.Fingerprint(f.Issue)
+		addedAt := previous.AddedAt(fingerprint)
+		if addedAt == "" {
+			addedAt = today
+		}
+		entries = append(entries, baseline.Entry{
+			Fingerprint: fingerprint,
+			CheckName:   f.checkName(),
+			Path:        f.Issue.Path,
+			Line:        f.Issue.Line,
+			Description: f.Issue.Description,
+			AddedAt:     addedAt,
+		})
+	}
+	return baseline.Write(path, entries)
+}
+
+// printExpired lists baseline entries whose issues are reported again
+func printExpired(expired []baseline.Entry, maxAgeDays int) {
+	if len(expired) == 0 {
+		return
+	}
+	fmt.Fprintf(stdout, "\n⌛ %d baseline entries expired (accepted more than %d days ago) and are reported again:\n", len(expired), maxAgeDays)
+	for _, e := range expired {
+		fmt.Fprintf(stdout, "   %s:%d  %s (accepted %s)\n", e.Path, e.Line, e.Description, e.AddedAt)
+	}
+}
+
+func generateNewIssuesReport(outputPath, baselinePath string, findings []finding, expired []baseline.Entry) error {
+	report := models.NewIssuesReport{
+		Timestamp: utils.GetTimestamp(),
+		Baseline:  baselinePath,
+		TotalNew:  len(findings),
+		Issues:    []models.NewIssue{},
+	}
+
+	for _, e := range expired {
+		report.Expired = append(report.Expired, models.ExpiredBaselineEntry{
+			Fingerprint: e.Fingerprint,
+			CheckName:   e.CheckName,
+			Path:        e.Path,
+			Line:        e.Line,
+			Description: e.Description,
+			AddedAt:     e.AddedAt,
+		})
+	}
+
+	for _, f := range findings {
+		report.Issues = append(report.Issues, models.NewIssue{
+			Fingerprint: utils.Fingerprint(f.Issue),
+			CheckName:   f.checkName(),
+			Category:    f.Issue.Category,
+			Path:        f.Issue.Path,
+			Line:        f.Issue.Line,
+			Severity:    f.Issue.Severity,
+			Description: f.Issue.Description,
+			Snippet:     utils.ReadLine(f.Issue.Path, f.Issue.Line),
+			Metadata:    f.Issue.Metadata,
+			Blame:       f.Issue.Blame,
+			Owners:      f.Issue.Owners,
+			Checks:      f.Issue.Checks,
+			FirstSeen:   f.Issue.FirstSeen,
+		})
+	}
+
+	return utils.WriteArtifact(outputPath, report)
 }
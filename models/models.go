@@ -1,52 +1,278 @@
 package models
 
+// Issue categories group rules by the kind of problem they find
+const (
+	CategoryDeadCode        = "dead-code"
+	CategoryBugRisk         = "bug-risk"
+	CategorySecurity        = "security"
+	CategoryComplexity      = "complexity"
+	CategoryStyle           = "style"
+	CategoryCompliance      = "compliance"
+	CategoryMaintainability = "maintainability"
+	CategoryPerformance     = "performance"
+)
+
 // Issue represents a specific finding in a file
 type Issue struct {
 	Path        string `json:"path"`
 	Description string `json:"description"`
 	Line        int    `json:"line"`
-	Severity    string `json:"severity"`
+	// Column, EndLine and EndColumn give the exact extent of the finding when
+	// the rule knows it (1-based; columns count characters, the end is inclusive)
+	Column    int            `json:"column,omitempty"`
+	EndLine   int            `json:"end_line,omitempty"`
+	EndColumn int            `json:"end_column,omitempty"`
+	Severity  string         `json:"severity"`
+	Metadata  *IssueMetadata `json:"metadata,omitempty"`
+	// RuleID identifies the rule that reported the issue, e.g. "php/commented-function"
+	RuleID string `json:"rule_id,omitempty"`
+	// Analyzer is the key of the analyzer that ran the rule, e.g. "php"
+	Analyzer string `json:"analyzer,omitempty"`
+	// Category is the kind of problem, one of the Category constants
+	Category string `json:"category,omitempty"`
+	// DocsURL links to the remediation guidance of the rule
+	DocsURL string `json:"docs_url,omitempty"`
+	// Checks lists every check that reported the issue when more than one did
+	Checks []string `json:"checks,omitempty"`
+	// Snippet is the flagged line with a few lines of context around it
+	Snippet string   `json:"snippet,omitempty"`
+	Blame   *Blame   `json:"blame,omitempty"`
+	Owners  []string `json:"owners,omitempty"`
+	// FirstSeen is the date (YYYY-MM-DD) the issue was first observed, from
+	// the run history or the baseline
+	FirstSeen string `json:"first_seen,omitempty"`
+}
+
+// Blame attributes the line of an issue to the commit that last changed it
+type Blame struct {
+	Author string `json:"author"`
+	Email  string `json:"email,omitempty"`
+	Commit string `json:"commit"`
+	Date   string `json:"date"` // Author date, YYYY-MM-DD (UTC)
+}
+
+// IssueMetadata carries structured sizing data so tooling can prioritize
+// fixes without parsing descriptions
+type IssueMetadata struct {
+	Bytes         int `json:"bytes,omitempty"`          // Size of the flagged block
+	LineSpan      int `json:"line_span,omitempty"`      // Lines covered by the flagged block
+	EffortMinutes int `json:"effort_minutes,omitempty"` // Rule-provided estimate to fix
+}
+
+// Diagnostic represents a non-fatal problem encountered while analyzing a file
+type Diagnostic struct {
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Stack   string `json:"stack"`
+	// Value is the recovered panic value, for crash reports to classify
+	Value interface{} `json:"-"`
+}
+
+// DiagnosticsReport represents all diagnostics recorded during a run
+type DiagnosticsReport struct {
+	Timestamp   string       `json:"timestamp"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// CombinedAnalysisReport holds the report of every analyzer of a run, by
+// analyzer name ("<project>/<analyzer>" in monorepos), in one artifact
+type CombinedAnalysisReport struct {
+	Timestamp     string                 `json:"timestamp"`
+	ScanDirectory string                 `json:"scan_directory"`
+	Analyzers     map[string]interface{} `json:"analyzers"`
+}
+
+// KeptBlock represents a commented block marked with `KEEP:` that is
+// excluded from dead-code metrics but listed for periodic review
+type KeptBlock struct {
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	Bytes       int    `json:"bytes"`
+	Description string `json:"description"`
+	Reason      string `json:"reason"`
 }
 
 // CodeQualityIssue represents a GitLab Code Quality report issue
 type CodeQualityIssue struct {
-	Description string   `json:"description"`
-	CheckName   string   `json:"check_name"`
-	Fingerprint string   `json:"fingerprint"`
-	Severity    string   `json:"severity"`
-	Location    Location `json:"location"`
+	Description       string   `json:"description"`
+	CheckName         string   `json:"check_name"`
+	Fingerprint       string   `json:"fingerprint"`
+	Severity          string   `json:"severity"`
+	Categories        []string `json:"categories,omitempty"`
+	Location          Location `json:"location"`
+	RemediationPoints int      `json:"remediation_points,omitempty"`
+	// Content is the Code Climate markdown body, linking to the rule's docs
+	Content *CodeQualityContent `json:"content,omitempty"`
+}
+
+// CodeQualityContent is the markdown explanation of a Code Climate issue
+type CodeQualityContent struct {
+	Body string `json:"body"`
+}
+
+// NewIssue represents an issue that is not part of the baseline
+type NewIssue struct {
+	Fingerprint string         `json:"fingerprint"`
+	CheckName   string         `json:"check_name"`
+	Category    string         `json:"category,omitempty"`
+	Path        string         `json:"path"`
+	Line        int            `json:"line"`
+	Severity    string         `json:"severity"`
+	Description string         `json:"description"`
+	Snippet     string         `json:"snippet"`
+	Metadata    *IssueMetadata `json:"metadata,omitempty"`
+	Blame       *Blame         `json:"blame,omitempty"`
+	Owners      []string       `json:"owners,omitempty"`
+	Checks      []string       `json:"checks,omitempty"` // Every check that reported the issue, when more than one did
+	FirstSeen   string         `json:"first_seen,omitempty"`
+}
+
+// NewIssuesReport represents the delta between the current run and the baseline
+type NewIssuesReport struct {
+	Timestamp string     `json:"timestamp"`
+	Baseline  string     `json:"baseline"`
+	TotalNew  int        `json:"total_new"`
+	Issues    []NewIssue `json:"issues"`
+	// Expired lists baseline entries older than baseline_max_age_days; their
+	// issues are reported again in Issues
+	Expired []ExpiredBaselineEntry `json:"expired,omitempty"`
+}
+
+// ExpiredBaselineEntry is a baseline entry that is no longer honored
+type ExpiredBaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	CheckName   string `json:"check_name"`
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	Description string `json:"description"`
+	AddedAt     string `json:"added_at"`
+}
+
+// SuppressionReport tells which baseline and false positive entries still
+// hide issues, so ignore lists can be pruned
+type SuppressionReport struct {
+	Timestamp string `json:"timestamp"`
+	// Stale entries matched no issue of the analyzers that ran
+	Stale []SuppressionUsage `json:"stale"`
+	// Used entries hid at least one issue
+	Used []SuppressionUsage `json:"used"`
+	// Unchecked counts entries of analyzers that did not run or failed
+	Unchecked int `json:"unchecked"`
+}
+
+// SuppressionUsage is one baseline or false positive entry and the issues it hid
+type SuppressionUsage struct {
+	// Source is "baseline" or "false_positives"
+	Source      string `json:"source"`
+	Fingerprint string `json:"fingerprint"`
+	CheckName   string `json:"check_name"`
+	Path        string `json:"path,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	// Since is the date the entry was added
+	Since  string `json:"since,omitempty"`
+	Hidden int    `json:"hidden"`
+}
+
+// CodeQualityMetadata is the companion summary written next to the GitLab report
+type CodeQualityMetadata struct {
+	Timestamp   string                   `json:"timestamp"`
+	Report      string                   `json:"report"`
+	TotalIssues int                      `json:"total_issues"`
+	Rules       []CodeQualityRuleSummary `json:"rules"`
+}
+
+// CodeQualityRuleSummary holds the issue counts of one check in the GitLab report
+type CodeQualityRuleSummary struct {
+	Index      int            `json:"index"`
+	CheckName  string         `json:"check_name"`
+	Analyzer   string         `json:"analyzer"`
+	Total      int            `json:"total"`
+	BySeverity map[string]int `json:"by_severity"`
+	// DocsURL links to the remediation guidance of the check
+	DocsURL string `json:"docs_url,omitempty"`
 }
 
 type Location struct {
 	Path  string `json:"path"`
 	Lines Lines  `json:"lines"`
+	// Positions is the exact extent of the finding, when its columns are known
+	Positions *Positions `json:"positions,omitempty"`
+}
+
+// Positions is a range of 1-based line and column positions
+type Positions struct {
+	Begin Position `json:"begin"`
+	End   Position `json:"end"`
+}
+
+// Position is a 1-based line and column
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
 }
 
 type Lines struct {
 	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
 }
 
 // HTMLFileAnalysis represents analysis results for an HTML file
 type HTMLFileAnalysis struct {
-	Path           string  `json:"path"`
-	TotalLines     int     `json:"total_lines"`
-	CommentedLines int     `json:"commented_lines"`
-	CommentedBytes int     `json:"commented_bytes"`
-	TotalBytes     int     `json:"total_bytes"`
-	CommentRatio   float64 `json:"comment_ratio"`
-	LargestBlock   int     `json:"largest_block"`
-	Issues         []Issue `json:"issues"`
+	Path           string      `json:"path"`
+	TotalLines     int         `json:"total_lines"`
+	CommentedLines int         `json:"commented_lines"`
+	CommentedBytes int         `json:"commented_bytes"`
+	TotalBytes     int         `json:"total_bytes"`
+	CommentRatio   float64     `json:"comment_ratio"`
+	LargestBlock   int         `json:"largest_block"`
+	Issues         []Issue     `json:"issues"`
+	Kept           []KeptBlock `json:"-"`
 }
 
 // HTMLAnalysisReport represents the complete HTML analysis report
 type HTMLAnalysisReport struct {
-	Timestamp      string             `json:"timestamp"`
-	ScanDirectory  string             `json:"scan_directory"`
-	TotalFiles     int                `json:"total_files"`
-	TotalCommented int                `json:"total_commented_bytes"`
-	SortMode       string             `json:"sort_mode"`
-	MinComments    int                `json:"min_comments"`
-	Results        []HTMLFileAnalysis `json:"results"`
+	Timestamp         string             `json:"timestamp"`
+	ScanDirectory     string             `json:"scan_directory"`
+	TotalFiles        int                `json:"total_files"`
+	TotalCommented    int                `json:"total_commented_bytes"`
+	SortMode          string             `json:"sort_mode"`
+	MinComments       int                `json:"min_comments"`
+	Results           []HTMLFileAnalysis `json:"results"`
+	IntentionallyKept []KeptBlock        `json:"intentionally_kept"`
+	SkippedTooLarge   []SkippedFile      `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// TemplateFileAnalysis represents analysis results for a Twig, Jinja or
+// Handlebars template
+type TemplateFileAnalysis struct {
+	Path           string      `json:"path"`
+	Engine         string      `json:"engine"`
+	TotalLines     int         `json:"total_lines"`
+	CommentedLines int         `json:"commented_lines"`
+	CommentedBytes int         `json:"commented_bytes"`
+	TotalBytes     int         `json:"total_bytes"`
+	CommentRatio   float64     `json:"comment_ratio"`
+	LargestBlock   int         `json:"largest_block"`
+	Issues         []Issue     `json:"issues"`
+	Kept           []KeptBlock `json:"-"`
+}
+
+// TemplateAnalysisReport represents the complete template analysis report
+type TemplateAnalysisReport struct {
+	Timestamp         string                 `json:"timestamp"`
+	ScanDirectory     string                 `json:"scan_directory"`
+	TotalFiles        int                    `json:"total_files"`
+	TotalCommented    int                    `json:"total_commented_bytes"`
+	SortMode          string                 `json:"sort_mode"`
+	MinComments       int                    `json:"min_comments"`
+	Results           []TemplateFileAnalysis `json:"results"`
+	IntentionallyKept []KeptBlock            `json:"intentionally_kept"`
+	SkippedTooLarge   []SkippedFile          `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
 }
 
 // PHPFileAnalysis represents analysis results for a PHP file
@@ -56,10 +282,47 @@ type PHPFileAnalysis struct {
 	CommentedFunctions int      `json:"commented_functions"`
 	FunctionList       []string `json:"function_list"`
 	CommentedList      []string `json:"commented_list"`
-	CommentRatio       float64  `json:"comment_ratio"`
-	TotalBytes         int      `json:"total_bytes"`
-	CommentedBytes     int      `json:"commented_bytes"`
-	Issues             []Issue  `json:"issues"`
+	// FunctionRatio is the share of the functions that are commented out, in %
+	FunctionRatio float64 `json:"function_ratio"`
+	// CommentedTypes counts the commented-out classes, traits, interfaces and enums
+	CommentedTypes    int      `json:"commented_types"`
+	CommentedTypeList []string `json:"commented_type_list"`
+	// CommentedBytes, CommentedLines and LargestBlock measure the comments
+	// that look like code, and CommentRatio their share of the file's bytes
+	TotalLines     int         `json:"total_lines"`
+	CommentedLines int         `json:"commented_lines"`
+	CommentedBytes int         `json:"commented_bytes"`
+	TotalBytes     int         `json:"total_bytes"`
+	CommentRatio   float64     `json:"comment_ratio"`
+	LargestBlock   int         `json:"largest_block"`
+	Issues         []Issue     `json:"issues"`
+	Kept           []KeptBlock `json:"-"`
+
+	// RouteClosures are the routes of a route file defined with closures
+	RouteClosures []RouteClosure `json:"route_closures,omitempty"`
+	// NPlusOneQueries are the relationships read in loops over queries
+	// without eager loading
+	NPlusOneQueries []NPlusOneQuery `json:"n_plus_one_queries,omitempty"`
+}
+
+// NPlusOneQuery is a probable N+1 query: a relationship read in a loop over
+// an Eloquent query that does not eager load it
+type NPlusOneQuery struct {
+	// Access is the relationship access, such as $post->author
+	Access string `json:"access"`
+	// Loop is the iterated expression, such as $posts
+	Loop string `json:"loop"`
+	Line int    `json:"line"`
+}
+
+// RouteClosure is a Laravel route whose action is an inline closure
+type RouteClosure struct {
+	Method  string `json:"method"`
+	URI     string `json:"uri"`
+	Line    int    `json:"line"`
+	EndLine int    `json:"end_line"`
+	// Logic reports whether the closure runs queries or other logic
+	Logic bool `json:"logic"`
 }
 
 // PHPAnalysisReport represents the complete PHP analysis report
@@ -69,16 +332,40 @@ type PHPAnalysisReport struct {
 	TotalFiles         int               `json:"total_files"`
 	TotalFunctions     int               `json:"total_functions"`
 	CommentedFunctions int               `json:"commented_functions"`
+	CommentedTypes     int               `json:"commented_types"`
+	TotalCommented     int               `json:"total_commented_bytes"`
 	Results            []PHPFileAnalysis `json:"results"`
+	IntentionallyKept  []KeptBlock       `json:"intentionally_kept"`
+	SkippedTooLarge    []SkippedFile     `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
 }
 
 // ConflictFileAnalysis represents analysis results for a file with conflicts
 type ConflictFileAnalysis struct {
-	Path             string   `json:"path"`
-	ConflictLines    []int    `json:"conflict_lines"`
-	ConflictBlocks   int      `json:"conflict_blocks"`
-	ConflictSnippets []string `json:"conflict_snippets"`
-	Issues           []Issue  `json:"issues"`
+	Path             string          `json:"path"`
+	ConflictLines    []int           `json:"conflict_lines"`
+	ConflictBlocks   int             `json:"conflict_blocks"`
+	ConflictSnippets []string        `json:"conflict_snippets"`
+	Blocks           []ConflictBlock `json:"blocks"`
+	Issues           []Issue         `json:"issues"`
+}
+
+// ConflictBlock represents one <<<<<<< ... >>>>>>> region with a preview of each side
+type ConflictBlock struct {
+	BeginLine     int      `json:"begin_line"`
+	EndLine       int      `json:"end_line"`
+	OursLabel     string   `json:"ours_label"`
+	TheirsLabel   string   `json:"theirs_label"`
+	OursLines     int      `json:"ours_lines"`
+	TheirsLines   int      `json:"theirs_lines"`
+	OursPreview   []string `json:"ours_preview"`
+	TheirsPreview []string `json:"theirs_preview"`
+	Complete      bool     `json:"complete"` // false when the end marker is missing
+	Diff3         bool     `json:"diff3"`    // true when a ||||||| base section is present
+	BaseLabel     string   `json:"base_label,omitempty"`
+	BaseLines     int      `json:"base_lines,omitempty"`
+	BasePreview   []string `json:"base_preview,omitempty"`
 }
 
 // ConflictAnalysisReport represents the complete conflict analysis report
@@ -88,27 +375,445 @@ type ConflictAnalysisReport struct {
 	TotalFiles     int                    `json:"total_files"`
 	TotalConflicts int                    `json:"total_conflicts"`
 	Results        []ConflictFileAnalysis `json:"results"`
+	// Leftover .orig merge backup files
+	MergeBackups []string `json:"merge_backups,omitempty"`
+	// Files predicted to conflict with TargetBranch (pre-merge simulation)
+	TargetBranch       string        `json:"target_branch,omitempty"`
+	PredictedConflicts []string      `json:"predicted_conflicts,omitempty"`
+	SkippedTooLarge    []SkippedFile `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
 }
 
 // JSFileAnalysis represents analysis results for a JS/TS file
 type JSFileAnalysis struct {
-	Path           string  `json:"path"`
-	TotalLines     int     `json:"total_lines"`
-	CommentedLines int     `json:"commented_lines"`
-	CommentedBytes int     `json:"commented_bytes"`
-	TotalBytes     int     `json:"total_bytes"`
-	CommentRatio   float64 `json:"comment_ratio"`
-	LargestBlock   int     `json:"largest_block"`
-	Issues         []Issue `json:"issues"`
+	Path           string      `json:"path"`
+	TotalLines     int         `json:"total_lines"`
+	CommentedLines int         `json:"commented_lines"`
+	CommentedBytes int         `json:"commented_bytes"`
+	TotalBytes     int         `json:"total_bytes"`
+	CommentRatio   float64     `json:"comment_ratio"`
+	LargestBlock   int         `json:"largest_block"`
+	Issues         []Issue     `json:"issues"`
+	Kept           []KeptBlock `json:"-"`
+	// ComplexFunctions are the functions above max_complexity, most complex first
+	ComplexFunctions []FunctionComplexity `json:"complex_functions,omitempty"`
+	// DeepFunctions are the functions nested deeper than max_nesting, deepest first
+	DeepFunctions []FunctionNesting `json:"deep_functions,omitempty"`
+}
+
+// FunctionComplexity is the cyclomatic complexity of a function: 1 plus its
+// decision points
+type FunctionComplexity struct {
+	Name       string `json:"name"`
+	Line       int    `json:"line"`
+	EndLine    int    `json:"end_line"`
+	Complexity int    `json:"complexity"`
+}
+
+// FunctionNesting is how deeply control blocks and nested functions are
+// nested inside a function, and the line where that depth is first reached
+type FunctionNesting struct {
+	Name      string `json:"name"`
+	Line      int    `json:"line"`
+	EndLine   int    `json:"end_line"`
+	Depth     int    `json:"depth"`
+	DepthLine int    `json:"depth_line"`
 }
 
 // JSAnalysisReport represents the complete JS analysis report
 type JSAnalysisReport struct {
-	Timestamp      string           `json:"timestamp"`
-	ScanDirectory  string           `json:"scan_directory"`
-	TotalFiles     int              `json:"total_files"`
-	TotalCommented int              `json:"total_commented_bytes"`
-	SortMode       string           `json:"sort_mode"`
-	MinComments    int              `json:"min_comments"`
-	Results        []JSFileAnalysis `json:"results"`
+	Timestamp         string           `json:"timestamp"`
+	ScanDirectory     string           `json:"scan_directory"`
+	TotalFiles        int              `json:"total_files"`
+	TotalCommented    int              `json:"total_commented_bytes"`
+	SortMode          string           `json:"sort_mode"`
+	MinComments       int              `json:"min_comments"`
+	MaxComplexity     int              `json:"max_complexity"`
+	MaxNesting        int              `json:"max_nesting"`
+	Results           []JSFileAnalysis `json:"results"`
+	IntentionallyKept []KeptBlock      `json:"intentionally_kept"`
+	SkippedTooLarge   []SkippedFile    `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// SizeFileAnalysis represents analysis results for an oversized file
+type SizeFileAnalysis struct {
+	Path          string  `json:"path"`
+	TotalBytes    int     `json:"total_bytes"`
+	TotalLines    int     `json:"total_lines"`
+	LongestLine   int     `json:"longest_line"`
+	LongLineCount int     `json:"long_line_count"`
+	LongLines     []int   `json:"long_lines"` // First 20 long line numbers
+	Issues        []Issue `json:"issues"`
+}
+
+// SizeAnalysisReport represents the complete size analysis report
+type SizeAnalysisReport struct {
+	Timestamp     string             `json:"timestamp"`
+	ScanDirectory string             `json:"scan_directory"`
+	TotalFiles    int                `json:"total_files"`
+	MaxBytes      int                `json:"max_bytes"`
+	MaxLines      int                `json:"max_lines"`
+	MaxLineLength int                `json:"max_line_length"`
+	Results       []SizeFileAnalysis `json:"results"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// LineCounts are the code, comment and blank lines of a set of files
+type LineCounts struct {
+	Files   int `json:"files"`
+	Code    int `json:"code"`
+	Comment int `json:"comment"`
+	Blank   int `json:"blank"`
+}
+
+// LanguageStats are the line counts of the files of one language
+type LanguageStats struct {
+	Language string `json:"language"`
+	LineCounts
+	CommentRatio float64 `json:"comment_ratio"` // Comment lines per 100 code and comment lines
+}
+
+// DirectoryStats are the line counts of the files under a top-level
+// directory of the scan root ("." for files directly in it)
+type DirectoryStats struct {
+	Directory string `json:"directory"`
+	LineCounts
+	CommentRatio float64 `json:"comment_ratio"`
+}
+
+// LanguageStatsReport represents the complete language statistics report
+type LanguageStatsReport struct {
+	Timestamp     string           `json:"timestamp"`
+	ScanDirectory string           `json:"scan_directory"`
+	Total         LineCounts       `json:"total"`
+	Languages     []LanguageStats  `json:"languages"`
+	Directories   []DirectoryStats `json:"directories"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// LicenseFileAnalysis represents a file with a missing or outdated license header
+type LicenseFileAnalysis struct {
+	Path   string  `json:"path"`
+	Status string  `json:"status"` // "missing" or "outdated"
+	Detail string  `json:"detail"`
+	Issues []Issue `json:"issues"`
+}
+
+// LicenseAnalysisReport represents the complete license header analysis report
+type LicenseAnalysisReport struct {
+	Timestamp     string                `json:"timestamp"`
+	ScanDirectory string                `json:"scan_directory"`
+	CheckedFiles  int                   `json:"checked_files"`
+	TotalFiles    int                   `json:"total_files"`
+	Missing       int                   `json:"missing"`
+	Outdated      int                   `json:"outdated"`
+	Results       []LicenseFileAnalysis `json:"results"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// WhitespaceFileAnalysis represents analysis results for a file with whitespace problems
+type WhitespaceFileAnalysis struct {
+	Path          string  `json:"path"`
+	TrailingLines int     `json:"trailing_lines"`
+	CRLFLines     int     `json:"crlf_lines"`
+	LFLines       int     `json:"lf_lines"`
+	TabIndented   int     `json:"tab_indented_lines"`
+	SpaceIndented int     `json:"space_indented_lines"`
+	Issues        []Issue `json:"issues"`
+}
+
+// WhitespaceAnalysisReport represents the complete whitespace analysis report
+type WhitespaceAnalysisReport struct {
+	Timestamp       string                   `json:"timestamp"`
+	ScanDirectory   string                   `json:"scan_directory"`
+	TotalFiles      int                      `json:"total_files"`
+	Results         []WhitespaceFileAnalysis `json:"results"`
+	SkippedTooLarge []SkippedFile            `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// EncodingFileAnalysis represents a text file that is not UTF-8
+type EncodingFileAnalysis struct {
+	Path     string  `json:"path"`
+	Encoding string  `json:"encoding"`
+	Issues   []Issue `json:"issues"`
+}
+
+// EncodingAnalysisReport represents the complete encoding analysis report
+type EncodingAnalysisReport struct {
+	Timestamp       string                 `json:"timestamp"`
+	ScanDirectory   string                 `json:"scan_directory"`
+	TotalFiles      int                    `json:"total_files"`
+	Results         []EncodingFileAnalysis `json:"results"`
+	SkippedTooLarge []SkippedFile          `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// StructuredFileAnalysis represents a JSON or YAML file that does not parse
+// cleanly
+type StructuredFileAnalysis struct {
+	Path   string  `json:"path"`
+	Format string  `json:"format"`
+	Issues []Issue `json:"issues"`
+}
+
+// StructuredAnalysisReport represents the complete JSON/YAML validity report
+type StructuredAnalysisReport struct {
+	Timestamp       string                   `json:"timestamp"`
+	ScanDirectory   string                   `json:"scan_directory"`
+	TotalFiles      int                      `json:"total_files"`
+	Results         []StructuredFileAnalysis `json:"results"`
+	SkippedTooLarge []SkippedFile            `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// DocsFileAnalysis represents a Markdown file with commented-out HTML or
+// broken relative links
+type DocsFileAnalysis struct {
+	Path           string      `json:"path"`
+	CommentedLines int         `json:"commented_lines"`
+	CommentedBytes int         `json:"commented_bytes"`
+	BrokenLinks    []string    `json:"broken_links"`
+	Issues         []Issue     `json:"issues"`
+	Kept           []KeptBlock `json:"-"`
+}
+
+// DocsAnalysisReport represents the complete docs analysis report
+type DocsAnalysisReport struct {
+	Timestamp         string             `json:"timestamp"`
+	ScanDirectory     string             `json:"scan_directory"`
+	TotalFiles        int                `json:"total_files"`
+	TotalCommented    int                `json:"total_commented_bytes"`
+	TotalBrokenLinks  int                `json:"total_broken_links"`
+	Results           []DocsFileAnalysis `json:"results"`
+	IntentionallyKept []KeptBlock        `json:"intentionally_kept"`
+	SkippedTooLarge   []SkippedFile      `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// ConfigDrift is one place where a repository config diverges from a preset
+type ConfigDrift struct {
+	Analyzer string `json:"analyzer,omitempty"`
+	Field    string `json:"field"`
+	Kind     string `json:"kind"`
+	Preset   string `json:"preset"`
+	Actual   string `json:"actual"`
+}
+
+// ConfigDriftReport represents the output of `config lint --against`
+type ConfigDriftReport struct {
+	Timestamp string        `json:"timestamp"`
+	Config    string        `json:"config"`
+	Preset    string        `json:"preset"`
+	Loosened  int           `json:"loosened"`
+	Drift     []ConfigDrift `json:"drift"`
+}
+
+// SkippedFile is a file an analyzer did not read because it exceeds max_file_size
+type SkippedFile struct {
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+}
+
+// ProgressEvent is one line of the -progress-json event stream
+type ProgressEvent struct {
+	Event       string `json:"event"`
+	Time        string `json:"time"`
+	Analyzer    string `json:"analyzer,omitempty"`
+	Processed   int    `json:"processed,omitempty"`
+	TotalFiles  int    `json:"total_files,omitempty"`
+	Analyzers   int    `json:"analyzers,omitempty"`
+	Issues      int    `json:"issues,omitempty"`
+	Succeeded   int    `json:"succeeded,omitempty"`
+	DurationMS  int64  `json:"duration_ms,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SQLFileAnalysis represents a file with oversized inline SQL strings
+type SQLFileAnalysis struct {
+	Path          string  `json:"path"`
+	SQLStrings    int     `json:"sql_strings"`
+	SQLBytes      int     `json:"sql_bytes"`
+	LargestString int     `json:"largest_string"`
+	Issues        []Issue `json:"issues"`
+}
+
+// SQLAnalysisReport represents the complete inline SQL analysis report
+type SQLAnalysisReport struct {
+	Timestamp       string            `json:"timestamp"`
+	ScanDirectory   string            `json:"scan_directory"`
+	TotalFiles      int               `json:"total_files"`
+	MaxStringLength int               `json:"max_string_length"`
+	Results         []SQLFileAnalysis `json:"results"`
+	SkippedTooLarge []SkippedFile     `json:"skipped_too_large"`
+	// Stats describes the work the analyzer did
+	Stats *AnalyzerStats `json:"stats,omitempty"`
+}
+
+// AnalyzerStats describes the work one analyzer did: its wall time, the
+// files it read and how many bytes, and the files it skipped and why
+type AnalyzerStats struct {
+	DurationMS      int64 `json:"duration_ms"`
+	FilesAnalyzed   int   `json:"files_analyzed"`
+	BytesRead       int64 `json:"bytes_read"`
+	SkippedTooLarge int   `json:"files_skipped_too_large"`
+	SkippedExcluded int   `json:"files_skipped_excluded"`
+	// IssuesOverflow counts the issues over max_issues_per_analyzer left out of the artifact
+	IssuesOverflow int `json:"issues_overflow,omitempty"`
+}
+
+// RunSummary is the machine-readable overview written to summary_file
+type RunSummary struct {
+	Timestamp  string               `json:"timestamp"`
+	DurationMS int64                `json:"duration_ms"`
+	TotalFiles int                  `json:"total_files"`
+	Issues     int                  `json:"issues"`
+	BySeverity map[string]int       `json:"by_severity"`
+	Analyzers  []AnalyzerRunSummary `json:"analyzers"`
+	Gate       GateResult           `json:"gate"`
+}
+
+// AnalyzerRunSummary holds the counts of one analyzer in a RunSummary
+type AnalyzerRunSummary struct {
+	Name            string         `json:"name"`
+	Project         string         `json:"project,omitempty"`
+	Failed          bool           `json:"failed"`
+	Error           string         `json:"error,omitempty"`
+	OverBudget      bool           `json:"over_budget,omitempty"` // Stopped at its budget; the issues are partial
+	DurationMS      int64          `json:"duration_ms"`
+	FilesAnalyzed   int            `json:"files_analyzed"`
+	SkippedTooLarge int            `json:"files_skipped_too_large"`
+	SkippedExcluded int            `json:"files_skipped_excluded"`
+	BytesRead       int64          `json:"bytes_read"`
+	Issues          int            `json:"issues"`
+	IssuesOverflow  int            `json:"issues_overflow,omitempty"` // Issues left out by max_issues_per_analyzer
+	BySeverity      map[string]int `json:"by_severity"`
+}
+
+// GateResult is the pass/fail verdict that decides the exit code
+type GateResult struct {
+	Passed    bool     `json:"passed"`
+	Succeeded int      `json:"succeeded"`
+	Analyzers int      `json:"analyzers"`
+	Reasons   []string `json:"reasons,omitempty"` // What failed the run besides failed analyzers
+}
+
+// CodeClimateIssue is an issue in the Code Climate engine specification
+type CodeClimateIssue struct {
+	Type        string   `json:"type"` // Always "issue"
+	CheckName   string   `json:"check_name"`
+	Description string   `json:"description"`
+	Categories  []string `json:"categories"`
+	Location    Location `json:"location"`
+	Severity    string   `json:"severity"`
+	Fingerprint string   `json:"fingerprint"`
+	// RemediationPoints estimates the effort to fix the issue
+	RemediationPoints int `json:"remediation_points,omitempty"`
+	// Content links to the rule's docs
+	Content *CodeQualityContent `json:"content,omitempty"`
+}
+
+// FindingsReport is the unified report of a run delivered to webhooks: every
+// issue of every analyzer with the run's totals
+type FindingsReport struct {
+	Version    int             `json:"version"`
+	Timestamp  string          `json:"timestamp"`
+	Project    string          `json:"project,omitempty"`
+	Pipeline   string          `json:"pipeline,omitempty"`
+	Commit     string          `json:"commit,omitempty"`
+	Ref        string          `json:"ref,omitempty"`
+	Baselined  bool            `json:"baselined"` // Issues are new issues, counted against a baseline
+	Gate       GateResult      `json:"gate"`
+	Total      int             `json:"total"`
+	BySeverity map[string]int  `json:"by_severity"`
+	Issues     []ReportedIssue `json:"issues"`
+}
+
+// ReportedIssue is one issue of the findings report
+type ReportedIssue struct {
+	Fingerprint string         `json:"fingerprint"`
+	Analyzer    string         `json:"analyzer"`
+	CheckName   string         `json:"check_name"`
+	Category    string         `json:"category,omitempty"`
+	Path        string         `json:"path"`
+	Line        int            `json:"line"`
+	Severity    string         `json:"severity"`
+	Description string         `json:"description"`
+	Metadata    *IssueMetadata `json:"metadata,omitempty"`
+	Blame       *Blame         `json:"blame,omitempty"`
+	Owners      []string       `json:"owners,omitempty"`
+	Checks      []string       `json:"checks,omitempty"` // Every check that reported the issue, when more than one did
+}
+
+// RangeReport lists the issues a commit range introduced and removed
+type RangeReport struct {
+	Timestamp    string          `json:"timestamp"`
+	Since        string          `json:"since"`
+	Head         string          `json:"head"`
+	FilesTouched int             `json:"files_touched"`
+	Introduced   []ReportedIssue `json:"introduced"`
+	// Removed issues carry their location as of Since
+	Removed []ReportedIssue `json:"removed"`
+}
+
+// OwnerReport lists the issues of files owned by one CODEOWNERS owner
+type OwnerReport struct {
+	Timestamp  string          `json:"timestamp"`
+	Owner      string          `json:"owner"`
+	Total      int             `json:"total"`
+	BySeverity map[string]int  `json:"by_severity"`
+	Issues     []ReportedIssue `json:"issues"`
+}
+
+// DebtReport ranks the files and top-level directories carrying the most
+// debt
+type DebtReport struct {
+	Timestamp string `json:"timestamp"`
+	// FilesBySeverity lists, per severity, the files with the most issues of it
+	FilesBySeverity map[string][]FileDebt `json:"files_by_severity"`
+	Directories     []DirectoryDebt       `json:"directories"`
+}
+
+// FileDebt is the number of issues of one severity in a file
+type FileDebt struct {
+	Path   string `json:"path"`
+	Issues int    `json:"issues"`
+}
+
+// DirectoryDebt aggregates the issues under a top-level directory of the
+// scan root ("." for files directly in it)
+type DirectoryDebt struct {
+	Directory     string         `json:"directory"`
+	Issues        int            `json:"issues"`
+	BySeverity    map[string]int `json:"by_severity"`
+	EffortMinutes int            `json:"effort_minutes"`
+}
+
+// HotspotsReport ranks the files that change often and carry the most issues
+type HotspotsReport struct {
+	Timestamp string    `json:"timestamp"`
+	Since     string    `json:"since"` // Start of the git history counted
+	Hotspots  []Hotspot `json:"hotspots"`
+}
+
+// Hotspot is a file's commits in the counted history and its issues
+type Hotspot struct {
+	Path    string `json:"path"`
+	Commits int    `json:"commits"`
+	Issues  int    `json:"issues"`
+	Score   int    `json:"score"` // Commits × issues
 }
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// mrCommentMarker is an invisible first line MR bots can search for to update
+// their previous comment instead of posting a new one
+const mrCommentMarker = "<!-- code-analyzer-summary -->"
+
+// mrCommentReportURL returns the configured report link, falling back to the
+// job's artifacts browser in GitLab CI
+func mrCommentReportURL(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if jobURL := os.Getenv("CI_JOB_URL"); jobURL != "" {
+		return jobURL + "/artifacts/browse"
+	}
+	return ""
+}
+
+// generateMRComment writes a Markdown comment body with issue totals per
+// category (analyzer) and severity that an MR bot can post verbatim.
+// baselined notes that only issues missing from the baseline are counted.
+func generateMRComment(outputPath, reportURL string, findings []finding, baselined bool) error {
+	counts := make(map[string]map[string]int)
+	totals := make(map[string]int)
+	for _, f := range findings {
+		if counts[f.Analyzer] == nil {
+			counts[f.Analyzer] = make(map[string]int)
+		}
+		counts[f.Analyzer][f.Issue.Severity]++
+		totals[f.Issue.Severity]++
+	}
+	categories := make([]string, 0, len(counts))
+	for name := range counts {
+		categories = append(categories, name)
+	}
+	sort.Strings(categories)
+
+	var b strings.Builder
+	fmt.Fprintln(&b, mrCommentMarker)
+	fmt.Fprintln(&b, "### 🔍 Code quality summary")
+	fmt.Fprintln(&b)
+
+	scope := "issues"
+	if baselined {
+		scope = "new issues (not in the baseline)"
+	}
+	if len(findings) == 0 {
+		fmt.Fprintf(&b, "✅ No %s found.\n", scope)
+	} else {
+		var parts []string
+		for _, severity := range severityOrder {
+			if n := totals[severity]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", n, severity))
+			}
+		}
+		fmt.Fprintf(&b, "**%d %s**: %s\n\n", len(findings), scope, strings.Join(parts, ", "))
+
+		fmt.Fprint(&b, "| Category |")
+		for _, severity := range severityOrder {
+			fmt.Fprintf(&b, " %s |", strings.ToUpper(severity[:1])+severity[1:])
+		}
+		fmt.Fprintln(&b, " Total |")
+		fmt.Fprint(&b, "|---|")
+		fmt.Fprintln(&b, strings.Repeat("---:|", len(severityOrder)+1))
+		for _, name := range categories {
+			writeMRCommentRow(&b, name, counts[name])
+		}
+		writeMRCommentRow(&b, "**Total**", totals)
+		writeMRCommentOwners(&b, findings)
+		writeMRCommentCriticals(&b, findings)
+	}
+
+	if reportURL != "" {
+		fmt.Fprintf(&b, "\n📄 [Full report](%s)\n", reportURL)
+	}
+
+	dir := filepath.Dir(outputPath)
+	if dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(outputPath, []byte(b.String()), 0644)
+}
+
+func writeMRCommentRow(b *strings.Builder, name string, counts map[string]int) {
+	total := 0
+	fmt.Fprintf(b, "| %s |", name)
+	for _, severity := range severityOrder {
+		fmt.Fprintf(b, " %d |", counts[severity])
+		total += counts[severity]
+	}
+	fmt.Fprintf(b, " %d |\n", total)
+}
+
+// mrCommentOwners is the number of authors listed in the owners table
+const mrCommentOwners = 10
+
+// writeMRCommentOwners lists the authors who last changed the most flagged
+// lines, when the issues were attributed with `blame: true`
+func writeMRCommentOwners(b *strings.Builder, findings []finding) {
+	type owner struct {
+		name     string
+		issues   int
+		critical int
+		latest   string
+	}
+	byAuthor := make(map[string]*owner)
+	for _, f := range findings {
+		if f.Issue.Blame == nil {
+			continue
+		}
+		o := byAuthor[f.Issue.Blame.Author]
+		if o == nil {
+			o = &owner{name: f.Issue.Blame.Author}
+			byAuthor[o.name] = o
+		}
+		o.issues++
+		if f.Issue.Severity == "critical" || f.Issue.Severity == "blocker" {
+			o.critical++
+		}
+		if f.Issue.Blame.Date > o.latest {
+			o.latest = f.Issue.Blame.Date
+		}
+	}
+	if len(byAuthor) == 0 {
+		return
+	}
+
+	owners := make([]*owner, 0, len(byAuthor))
+	for _, o := range byAuthor {
+		owners = append(owners, o)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].issues != owners[j].issues {
+			return owners[i].issues > owners[j].issues
+		}
+		return owners[i].name < owners[j].name
+	})
+
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "#### Last changed by")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "| Author | Issues | Critical | Latest change |")
+	fmt.Fprintln(b, "|---|---:|---:|---|")
+	for i, o := range owners {
+		if i == mrCommentOwners {
+			fmt.Fprintf(b, "| _%d more authors_ | | | |\n", len(owners)-mrCommentOwners)
+			break
+		}
+		fmt.Fprintf(b, "| %s | %d | %d | %s |\n", strings.ReplaceAll(o.name, "|", "\\|"), o.issues, o.critical, o.latest)
+	}
+}
+
+// mrCommentCriticals is the number of critical issues quoted with their snippets
+const mrCommentCriticals = 10
+
+// writeMRCommentCriticals quotes the blocker and critical issues with their
+// context snippets, so reviewers can judge them without opening the files
+func writeMRCommentCriticals(b *strings.Builder, findings []finding) {
+	var criticals []finding
+	for _, f := range findings {
+		if f.Issue.Severity == "critical" || f.Issue.Severity == "blocker" {
+			criticals = append(criticals, f)
+		}
+	}
+	if len(criticals) == 0 {
+		return
+	}
+	criticals = sortedByLocation(criticals)
+
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "#### Critical issues")
+	for i, f := range criticals {
+		if i == mrCommentCriticals {
+			fmt.Fprintf(b, "\n_%d more critical issues in the full report_\n", len(criticals)-mrCommentCriticals)
+			break
+		}
+		fmt.Fprintf(b, "\n**`%s:%d`** (%s): %s", f.Issue.Path, f.Issue.Line, f.Issue.Severity, f.Issue.Description)
+		if f.Issue.DocsURL != "" {
+			fmt.Fprintf(b, " ([docs](%s))", f.Issue.DocsURL)
+		}
+		fmt.Fprintln(b)
+		if f.Issue.Snippet != "" {
+			fence := codeFence(f.Issue.Snippet)
+			fmt.Fprintf(b, "\n%s\n%s\n%s\n", fence, f.Issue.Snippet, fence)
+		}
+	}
+}
+
+// codeFence returns a backtick fence longer than any backtick run in text
+func codeFence(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"code-analyzer/config"
+	"code-analyzer/notify"
+)
+
+// sendNotifications posts the run summary to every configured webhook. A
+// failing webhook does not keep the others from being notified.
+func sendNotifications(out io.Writer, targets []config.NotificationConfig, findings []finding, baselined bool, verdict runVerdict) error {
+	summary := notify.Summary{
+		Project:    os.Getenv("CI_PROJECT_PATH"),
+		Ref:        os.Getenv("CI_COMMIT_REF_NAME"),
+		Pipeline:   os.Getenv("CI_PIPELINE_ID"),
+		URL:        os.Getenv("CI_PIPELINE_URL"),
+		Issues:     len(findings),
+		New:        baselined,
+		BySeverity: map[string]int{},
+		Succeeded:  verdict.Succeeded,
+		Analyzers:  verdict.Total,
+		Passed:     verdict.passed(),
+	}
+	for _, f := range findings {
+		summary.BySeverity[f.Issue.Severity]++
+		if f.Issue.Severity == "critical" || f.Issue.Severity == "blocker" {
+			summary.Criticals = append(summary.Criticals, notify.Issue{
+				Analyzer: f.Analyzer,
+				Path:     f.Issue.Path,
+				Line:     f.Issue.Line,
+				Severity: f.Issue.Severity,
+				Message:  f.Issue.Description,
+			})
+		}
+	}
+	for _, sev := range severityOrder {
+		if n := summary.BySeverity[sev]; n > 0 {
+			summary.Severities = append(summary.Severities, notify.SeverityCount{Severity: sev, Issues: n})
+		}
+	}
+
+	var errs []error
+	for _, t := range targets {
+		target := notify.Target{
+			Type:       t.Type,
+			WebhookURL: t.WebhookURL,
+			Template:   t.Template,
+			Criticals:  t.Criticals,
+			MinIssues:  t.MinIssues,
+		}
+		sent, err := notify.Send(target, summary)
+		if err != nil {
+			errs = append(errs, err)
+		} else if sent {
+			fmt.Fprintf(out, "✅ %s notification sent\n", t.Type)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,164 @@
+// Package notify posts run summaries to Slack and Microsoft Teams incoming
+// webhooks
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Webhook types
+const (
+	TypeSlack = "slack"
+	TypeTeams = "teams"
+)
+
+// Target is one webhook to notify
+type Target struct {
+	Type       string
+	WebhookURL string
+	// Template is a text/template rendered with the Summary (DefaultTemplate when empty)
+	Template string
+	// Criticals lists the run's critical and blocker issues in the message
+	Criticals bool
+	// MinIssues skips the notification when the run reports fewer issues
+	MinIssues int
+}
+
+// Summary is the data a message template is rendered with
+type Summary struct {
+	Project    string
+	Ref        string
+	Pipeline   string
+	URL        string
+	Issues     int
+	New        bool // Issues are new issues, counted against a baseline
+	BySeverity map[string]int
+	Severities []SeverityCount // Non-zero counts, most severe first
+	Succeeded  int
+	Analyzers  int
+	Passed     bool
+	Criticals  []Issue
+}
+
+// SeverityCount is the number of issues of one severity
+type SeverityCount struct {
+	Severity string
+	Issues   int
+}
+
+// Issue is a critical or blocker issue listed in the message
+type Issue struct {
+	Analyzer string
+	Path     string
+	Line     int
+	Severity string
+	Message  string
+}
+
+// maxCriticals caps the issues listed in one message
+const maxCriticals = 10
+
+// DefaultTemplate is used when a target has no template
+const DefaultTemplate = `{{if .Passed}}✅{{else}}❌{{end}} Code analysis{{if .Project}} of {{.Project}}{{end}}{{if .Ref}} ({{.Ref}}){{end}}: {{.Issues}}{{if .New}} new{{end}} issues, {{.Succeeded}}/{{.Analyzers}} analyzers succeeded
+{{- range .Severities}}
+• {{.Severity}}: {{.Issues}}{{end}}
+{{- if .Criticals}}
+
+Critical issues:{{range .Criticals}}
+• {{.Path}}:{{.Line}} [{{.Analyzer}}] {{.Message}}{{end}}{{end}}
+{{- if .URL}}
+
+{{.URL}}{{end}}`
+
+// ShouldNotify reports whether the run reaches the target's threshold
+func (t Target) ShouldNotify(s Summary) bool {
+	return s.Issues >= t.MinIssues
+}
+
+// Render renders the target's message for the summary
+func (t Target) Render(s Summary) (string, error) {
+	text := t.Template
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New(t.Type).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %v", err)
+	}
+
+	if !t.Criticals {
+		s.Criticals = nil
+	} else if len(s.Criticals) > maxCriticals {
+		s.Criticals = s.Criticals[:maxCriticals]
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, s); err != nil {
+		return "", fmt.Errorf("invalid template: %v", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// payload wraps the message in the JSON body the webhook type expects
+func (t Target) payload(text string) ([]byte, error) {
+	switch t.Type {
+	case TypeSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case TypeTeams:
+		return json.Marshal(map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  "Code analysis",
+			"text":     strings.ReplaceAll(text, "\n", "\n\n"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown notification type %q (expected %q or %q)", t.Type, TypeSlack, TypeTeams)
+	}
+}
+
+// Send posts the summary to the target unless it is below the threshold.
+// $VARIABLES in the webhook URL are expanded, so the secret can stay in a CI
+// variable. It reports whether a message was sent.
+func Send(t Target, s Summary) (bool, error) {
+	if !t.ShouldNotify(s) {
+		return false, nil
+	}
+	endpoint := os.ExpandEnv(t.WebhookURL)
+	if endpoint == "" {
+		return false, fmt.Errorf("%s notification has no webhook_url", t.Type)
+	}
+
+	text, err := t.Render(s)
+	if err != nil {
+		return false, err
+	}
+	body, err := t.payload(text)
+	if err != nil {
+		return false, err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The URL is the webhook's secret; keep it out of logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return false, fmt.Errorf("%s webhook: %v", t.Type, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("%s webhook returned %s", t.Type, resp.Status)
+	}
+	return true, nil
+}
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testSummary() Summary {
+	return Summary{
+		Project:    "group/app",
+		Issues:     3,
+		New:        true,
+		BySeverity: map[string]int{"critical": 1, "minor": 2},
+		Severities: []SeverityCount{{"critical", 1}, {"minor", 2}},
+		Succeeded:  2,
+		Analyzers:  3,
+		Criticals:  []Issue{{Analyzer: "conflicts", Path: "a.php", Line: 4, Severity: "critical", Message: "Merge conflict marker"}},
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   Target
+		contains []string
+		absent   []string
+	}{
+		{
+			name:     "default template",
+			target:   Target{Type: TypeSlack},
+			contains: []string{"❌ Code analysis of group/app: 3 new issues, 2/3 analyzers succeeded", "• critical: 1", "• minor: 2"},
+			absent:   []string{"a.php:4"},
+		},
+		{
+			name:     "criticals",
+			target:   Target{Type: TypeSlack, Criticals: true},
+			contains: []string{"Critical issues:", "• a.php:4 [conflicts] Merge conflict marker"},
+		},
+		{
+			name:     "custom template",
+			target:   Target{Type: TypeTeams, Template: `{{.Project}} has {{index .BySeverity "critical"}} criticals`},
+			contains: []string{"group/app has 1 criticals"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.target.Render(testSummary())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("message lacks %q:\n%s", want, text)
+				}
+			}
+			for _, unwanted := range tt.absent {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("message contains %q:\n%s", unwanted, text)
+				}
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received map[string]string
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if err := json.NewDecoder(req.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("TEAMS_WEBHOOK", server.URL)
+
+	target := Target{Type: TypeTeams, WebhookURL: "$TEAMS_WEBHOOK", MinIssues: 3}
+	sent, err := Send(target, testSummary())
+	if err != nil || !sent {
+		t.Fatalf("expected a message, got sent=%v err=%v", sent, err)
+	}
+	if received["@type"] != "MessageCard" || !strings.Contains(received["text"], "3 new issues") {
+		t.Errorf("unexpected Teams payload %v", received)
+	}
+
+	// Below the threshold nothing is posted
+	target.MinIssues = 4
+	if sent, err := Send(target, testSummary()); err != nil || sent || requests != 1 {
+		t.Errorf("expected no message below min_issues, got sent=%v err=%v requests=%d", sent, err, requests)
+	}
+}
+
+func TestSend_HidesWebhookURL(t *testing.T) {
+	_, err := Send(Target{Type: TypeSlack, WebhookURL: "http://127.0.0.1:1/services/SECRET"}, testSummary())
+	if err == nil || strings.Contains(err.Error(), "SECRET") {
+		t.Errorf("expected an error without the webhook URL, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code-analyzer/codeowners"
+	"code-analyzer/config"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// unownedGroup collects issues of files no CODEOWNERS rule assigns
+const unownedGroup = "(unowned)"
+
+// assignOwners sets the CODEOWNERS owners of every finding's file. Issue
+// paths are matched as relative to the current directory, which is expected
+// to be the repository root.
+func assignOwners(cfg config.CodeOwnersConfig, findings []finding) error {
+	path := cfg.Path
+	if path == "" {
+		var err error
+		if path, err = codeowners.Find("."); err != nil {
+			return err
+		}
+	}
+	rules, err := codeowners.Load(path)
+	if err != nil {
+		return err
+	}
+	for i := range findings {
+		findings[i].Issue.Owners = rules.Owners(findings[i].Issue.Path)
+	}
+	return nil
+}
+
+// groupByOwner groups findings per owner, an issue counting for each of its
+// owners, and returns the owners with the most issues first
+func groupByOwner(findings []finding) ([]string, map[string][]finding) {
+	groups := make(map[string][]finding)
+	for _, f := range findings {
+		if len(f.Issue.Owners) == 0 {
+			groups[unownedGroup] = append(groups[unownedGroup], f)
+		}
+		for _, owner := range f.Issue.Owners {
+			groups[owner] = append(groups[owner], f)
+		}
+	}
+
+	owners := make([]string, 0, len(groups))
+	for owner := range groups {
+		owners = append(owners, owner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if len(groups[owners[i]]) != len(groups[owners[j]]) {
+			return len(groups[owners[i]]) > len(groups[owners[j]])
+		}
+		return owners[i] < owners[j]
+	})
+	return owners, groups
+}
+
+// printOwnerSummary prints issue counts per owner and severity
+func printOwnerSummary(w io.Writer, findings []finding) {
+	if len(findings) == 0 {
+		return
+	}
+	owners, groups := groupByOwner(findings)
+
+	fmt.Fprintf(w, "\n👥 Issues by owner:\n")
+	fmt.Fprintf(w, "%-30s", "Owner")
+	for _, severity := range severityOrder {
+		fmt.Fprintf(w, " %9s", severity)
+	}
+	fmt.Fprintf(w, " %9s\n", "total")
+	fmt.Fprintln(w, strings.Repeat("-", 30+10*(len(severityOrder)+1)))
+	for _, owner := range owners {
+		counts := make(map[string]int)
+		for _, f := range groups[owner] {
+			counts[f.Issue.Severity]++
+		}
+		fmt.Fprintf(w, "%-30s", utils.Truncate(owner, 30))
+		for _, severity := range severityOrder {
+			fmt.Fprintf(w, " %9d", counts[severity])
+		}
+		fmt.Fprintf(w, " %9d\n", len(groups[owner]))
+	}
+}
+
+// unsafeFileChars are replaced in owner names to derive artifact file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ownerFileName returns the artifact file name of an owner, e.g.
+// "org-web.json" for "@org/web"
+func ownerFileName(owner string) string {
+	if owner == unownedGroup {
+		return "unowned.json"
+	}
+	name := unsafeFileChars.ReplaceAllString(strings.TrimPrefix(owner, "@"), "-")
+	return strings.Trim(name, "-") + ".json"
+}
+
+// writeOwnerReports writes one JSON file of issues per owner into dir and
+// returns how many were written
+func writeOwnerReports(dir string, findings []finding) (int, error) {
+	owners, groups := groupByOwner(findings)
+	for _, owner := range owners {
+		report := models.OwnerReport{
+			Timestamp:  utils.GetTimestamp(),
+			Owner:      owner,
+			Total:      len(groups[owner]),
+			BySeverity: map[string]int{},
+			Issues:     make([]models.ReportedIssue, 0, len(groups[owner])),
+		}
+		for _, f := range groups[owner] {
+			report.BySeverity[f.Issue.Severity]++
+			report.Issues = append(report.Issues, reportedIssue(f))
+		}
+		if err := utils.WriteArtifact(filepath.Join(dir, ownerFileName(owner)), report); err != nil {
+			return 0, err
+		}
+	}
+	return len(owners), nil
+}
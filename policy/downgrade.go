@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"code-analyzer/models"
+	"code-analyzer/severity"
+)
+
+// DefaultTestPatterns recognize test and fixture code in common layouts
+var DefaultTestPatterns = []string{
+	"test", "tests", "Tests", "__tests__", "spec", "specs",
+	"fixture", "fixtures", "__fixtures__", "testdata", "__mocks__",
+	"*_test.*", "*.test.*", "*.spec.*", "*Test.php",
+}
+
+// TestDowngrade lowers the severity of issues in test and fixture code by one
+// level (info stays info): commented code there costs far less than in
+// production code.
+type TestDowngrade struct {
+	patterns []string
+}
+
+// NewTestDowngrade builds the policy from glob patterns (path.Match syntax),
+// each matched against every directory and the file name of an issue's path.
+// DefaultTestPatterns are used when patterns is empty.
+func NewTestDowngrade(patterns []string) (*TestDowngrade, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultTestPatterns
+	}
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid test path pattern %q: %v", p, err)
+		}
+	}
+	return &TestDowngrade{patterns: patterns}, nil
+}
+
+// Matches reports whether p is test or fixture code
+func (d *TestDowngrade) Matches(p string) bool {
+	for _, segment := range strings.Split(filepath.ToSlash(p), "/") {
+		for _, pattern := range d.patterns {
+			if ok, _ := path.Match(pattern, segment); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply downgrades the issue when its path is test or fixture code
+func (d *TestDowngrade) Apply(analyzer string, issue *models.Issue) {
+	if !d.Matches(issue.Path) {
+		return
+	}
+	for i, level := range severity.Order[:len(severity.Order)-1] {
+		if issue.Severity == level {
+			issue.Severity = severity.Order[i+1]
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package policy
+
+import (
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestTestDowngrade(t *testing.T) {
+	defaults, err := NewTestDowngrade(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, err := NewTestDowngrade([]string{"qa", "*.stub.php"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		policy   *TestDowngrade
+		path     string
+		severity string
+		want     string
+	}{
+		{"tests directory", defaults, "src/tests/Unit/UserTest.php", "major", "minor"},
+		{"fixture directory", defaults, "app/fixtures/page.html", "critical", "major"},
+		{"test file name", defaults, "web/components/button.spec.js", "minor", "info"},
+		{"info stays info", defaults, "tests/a.php", "info", "info"},
+		{"blocker becomes critical", defaults, "__tests__/a.js", "blocker", "critical"},
+		{"production code", defaults, "app/Http/Controllers/UserController.php", "major", "major"},
+		{"partial segment does not match", defaults, "app/latest/contest.php", "major", "major"},
+		{"custom directory", custom, "qa/a.php", "major", "minor"},
+		{"custom file pattern", custom, "app/user.stub.php", "major", "minor"},
+		{"defaults replaced", custom, "tests/a.php", "major", "major"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := models.Issue{Path: tt.path, Severity: tt.severity}
+			tt.policy.Apply("php", &issue)
+			if issue.Severity != tt.want {
+				t.Errorf("got %s, want %s", issue.Severity, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTestDowngrade_InvalidPattern(t *testing.T) {
+	if _, err := NewTestDowngrade([]string{"[test"}); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
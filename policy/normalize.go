@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"sync"
+
+	"code-analyzer/models"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
+)
+
+// SeverityNormalizer maps the severity of every issue onto the canonical set
+// of the severity package, so a rule emitting e.g. "medium" is reported with
+// a severity every output format understands. Severities it cannot map become
+// major, with one warning per value. It is safe for concurrent use.
+type SeverityNormalizer struct {
+	mu     sync.Mutex
+	warned map[string]bool
+}
+
+// NewSeverityNormalizer returns the normalizer; it runs first in the chain
+func NewSeverityNormalizer() *SeverityNormalizer {
+	return &SeverityNormalizer{warned: make(map[string]bool)}
+}
+
+// Apply replaces the issue's severity with its canonical form
+func (n *SeverityNormalizer) Apply(analyzer string, issue *models.Issue) {
+	canonical, ok := severity.Normalize(issue.Severity)
+	if !ok {
+		n.mu.Lock()
+		warn := !n.warned[issue.Severity]
+		n.warned[issue.Severity] = true
+		n.mu.Unlock()
+		if warn {
+			utils.Warnf("⚠️  Unknown severity %q from the %s analyzer, reported as major; map it under `severities.aliases`\n", issue.Severity, analyzer)
+		}
+		canonical = severity.Major
+	}
+	issue.Severity = canonical
+}
@@ -0,0 +1,161 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code-analyzer/models"
+	"code-analyzer/severity"
+)
+
+// Policy is evaluated against every issue before it is reported and may
+// adjust it (e.g. raise its severity for sensitive modules)
+type Policy interface {
+	// Apply mutates the issue in place; analyzer is the analyzer key (e.g. "php")
+	Apply(analyzer string, issue *models.Issue)
+}
+
+// Chain applies a list of policies in order, later policies win
+type Chain []Policy
+
+// Apply runs every policy in the chain against the issue
+func (c Chain) Apply(analyzer string, issue *models.Issue) {
+	for _, p := range c {
+		p.Apply(analyzer, issue)
+	}
+}
+
+// condition is a single `<field> <op> "<value>"` test
+type condition struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+// SeverityRule sets the severity of issues matching all of its conditions.
+// It is built from an expression such as:
+//
+//	severity = critical when path startsWith "app/Payments"
+//	severity = info when analyzer == "html" and path contains "legacy/"
+//	severity = major when category == "security"
+type SeverityRule struct {
+	Expression string
+	severity   string
+	conditions []condition
+}
+
+var expressionRegex = regexp.MustCompile(`^\s*severity\s*=\s*(\w+)\s+when\s+(.+?)\s*$`)
+var conditionRegex = regexp.MustCompile(`^\s*(\w+)\s+(startsWith|endsWith|contains|matches|==|!=)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+var andSplitRegex = regexp.MustCompile(`\s+and\s+`)
+
+// Parse compiles a policy expression into a SeverityRule
+func Parse(expression string) (*SeverityRule, error) {
+	m := expressionRegex.FindStringSubmatch(expression)
+	if m == nil {
+		return nil, fmt.Errorf("invalid policy %q: expected `severity = <level> when <condition>`", expression)
+	}
+
+	level := strings.ToLower(m[1])
+	if !severity.Valid(level) {
+		return nil, fmt.Errorf("invalid policy %q: unknown severity %q", expression, m[1])
+	}
+
+	rule := &SeverityRule{Expression: expression, severity: level}
+	for _, part := range andSplitRegex.Split(m[2], -1) {
+		cm := conditionRegex.FindStringSubmatch(part)
+		if cm == nil {
+			return nil, fmt.Errorf("invalid policy %q: cannot parse condition %q", expression, part)
+		}
+
+		field := cm[1]
+		switch field {
+		case "path", "analyzer", "rule", "category", "severity", "description":
+		default:
+			return nil, fmt.Errorf("invalid policy %q: unknown field %q", expression, field)
+		}
+
+		cond := condition{field: field, op: cm[2], value: strings.ReplaceAll(cm[3], `\"`, `"`)}
+		if cond.op == "matches" {
+			re, err := regexp.Compile(cond.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid policy %q: %v", expression, err)
+			}
+			cond.re = re
+		}
+		rule.conditions = append(rule.conditions, cond)
+	}
+
+	return rule, nil
+}
+
+// ParseAll compiles a list of expressions into a Chain
+func ParseAll(expressions []string) (Chain, error) {
+	var chain Chain
+	for _, expr := range expressions {
+		rule, err := Parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, rule)
+	}
+	return chain, nil
+}
+
+// Values returns the values the rule compares field with using ==, so
+// callers can check them against the analyzers, rules and categories that
+// exist
+func (r *SeverityRule) Values(field string) []string {
+	var values []string
+	for _, cond := range r.conditions {
+		if cond.field == field && cond.op == "==" {
+			values = append(values, cond.value)
+		}
+	}
+	return values
+}
+
+// Apply sets the rule's severity when every condition matches
+func (r *SeverityRule) Apply(analyzer string, issue *models.Issue) {
+	for _, cond := range r.conditions {
+		if !cond.matches(analyzer, issue) {
+			return
+		}
+	}
+	issue.Severity = r.severity
+}
+
+func (c condition) matches(analyzer string, issue *models.Issue) bool {
+	var actual string
+	switch c.field {
+	case "path":
+		actual = issue.Path
+	case "analyzer":
+		actual = analyzer
+	case "rule":
+		actual = issue.RuleID
+	case "category":
+		actual = issue.Category
+	case "severity":
+		actual = issue.Severity
+	case "description":
+		actual = issue.Description
+	}
+
+	switch c.op {
+	case "startsWith":
+		return strings.HasPrefix(actual, c.value)
+	case "endsWith":
+		return strings.HasSuffix(actual, c.value)
+	case "contains":
+		return strings.Contains(actual, c.value)
+	case "matches":
+		return c.re.MatchString(actual)
+	case "==":
+		return actual == c.value
+	case "!=":
+		return actual != c.value
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestSeverityRule_Apply(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		analyzer   string
+		issue      models.Issue
+		expected   string
+	}{
+		{
+			name:       "Path prefix match",
+			expression: `severity = critical when path startsWith "app/Payments"`,
+			analyzer:   "php",
+			issue:      models.Issue{Path: "app/Payments/Gateway.php", Severity: "major"},
+			expected:   "critical",
+		},
+		{
+			name:       "Path prefix no match",
+			expression: `severity = critical when path startsWith "app/Payments"`,
+			analyzer:   "php",
+			issue:      models.Issue{Path: "app/Users/User.php", Severity: "major"},
+			expected:   "major",
+		},
+		{
+			name:       "Path contains match under a scan directory",
+			expression: `severity = critical when path contains "app/Payments/"`,
+			analyzer:   "php",
+			issue:      models.Issue{Path: "src/app/Payments/Gateway.php", Severity: "major"},
+			expected:   "critical",
+		},
+		{
+			name:       "Path regex match at a directory boundary",
+			expression: `severity = critical when path matches "(^|/)app/Payments/"`,
+			analyzer:   "php",
+			issue:      models.Issue{Path: "/builds/shop/app/Payments/Gateway.php", Severity: "major"},
+			expected:   "critical",
+		},
+		{
+			name:       "Path regex no match in a lookalike directory",
+			expression: `severity = critical when path matches "(^|/)app/Payments/"`,
+			analyzer:   "php",
+			issue:      models.Issue{Path: "webapp/Payments/Gateway.php", Severity: "major"},
+			expected:   "major",
+		},
+		{
+			name:       "Combined conditions",
+			expression: `severity = info when analyzer == "html" and path contains "legacy/"`,
+			analyzer:   "html",
+			issue:      models.Issue{Path: "public/legacy/index.html", Severity: "minor"},
+			expected:   "info",
+		},
+		{
+			name:       "Rule and category match",
+			expression: `severity = blocker when rule == "php/commented-function" and category == "dead-code"`,
+			analyzer:   "php",
+			issue:      models.Issue{RuleID: "php/commented-function", Category: "dead-code", Severity: "major"},
+			expected:   "blocker",
+		},
+		{
+			name:       "Combined conditions partial match",
+			expression: `severity = info when analyzer == "html" and path contains "legacy/"`,
+			analyzer:   "js",
+			issue:      models.Issue{Path: "public/legacy/app.js", Severity: "minor"},
+			expected:   "minor",
+		},
+		{
+			name:       "Regex match",
+			expression: `severity = blocker when description matches "^Merge conflict"`,
+			analyzer:   "conflicts",
+			issue:      models.Issue{Description: "Merge conflict marker: =======", Severity: "critical"},
+			expected:   "blocker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+
+			issue := tt.issue
+			rule.Apply(tt.analyzer, &issue)
+			if issue.Severity != tt.expected {
+				t.Errorf("expected severity %q, got %q", tt.expected, issue.Severity)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	invalid := []string{
+		`severity critical when path startsWith "app"`,
+		`severity = urgent when path startsWith "app"`,
+		`severity = major when owner == "team"`,
+		`severity = major when path startsWith app`,
+		`severity = major when path matches "("`,
+	}
+
+	for _, expr := range invalid {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("expected error for %q, got nil", expr)
+		}
+	}
+}
+
+func TestSeverityNormalizer(t *testing.T) {
+	n := NewSeverityNormalizer()
+	for in, want := range map[string]string{"Medium": "major", "minor": "minor", "bogus": "major"} {
+		issue := models.Issue{Severity: in}
+		n.Apply("php", &issue)
+		if issue.Severity != want {
+			t.Errorf("%q normalized to %q, want %q", in, issue.Severity, want)
+		}
+	}
+}
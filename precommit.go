@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/gitdiff"
+	"code-analyzer/utils"
+)
+
+// preCommitHookMarker identifies hook scripts written by install-hook, so
+// reinstalling may replace them but never a hand-written hook
+const preCommitHookMarker = "# Installed by code-analyzer install-hook"
+
+// runPreCommit handles `code-analyzer pre-commit` and exits
+func runPreCommit(args []string) {
+	os.Exit(preCommit(args))
+}
+
+// preCommit analyzes what is staged for the next commit. The staged version
+// of every staged file is checked out into a temporary directory, so
+// unstaged edits neither hide nor add findings, and only issues on staged
+// lines are reported, one `path:line: severity [check] message` line each.
+// It returns 1 on conflict markers (checked first, before any other
+// analyzer runs) or on critical and blocker issues, 2 on errors.
+func preCommit(args []string) int {
+	flags := flag.NewFlagSet("pre-commit", flag.ExitOnError)
+	configFile := flags.String("config", defaultConfigFile, "Path to YAML or JSON configuration file; the built-in default is used when the default file is missing")
+	only := flags.String("only", "", "Comma-separated list of analyzers to run (overrides enabled flags)")
+	_ = flags.Parse(args)
+
+	explicitConfig := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			explicitConfig = true
+		}
+	})
+
+	// Git runs hooks from the top of the work tree; do the same when run by hand
+	top, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		utils.Errorf("❌ pre-commit must run inside a git work tree: %v\n", err)
+		return 2
+	}
+	if err := os.Chdir(top); err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 2
+	}
+
+	cfg, builtinConfig, err := loadConfig(*configFile, explicitConfig)
+	if err != nil {
+		utils.Errorf("❌ Failed to load config file: %v\n", err)
+		return 2
+	}
+	// A hook only gates; it writes no artifacts or reports
+	applyVerifyMode(cfg)
+	utils.SetHardLinkDedup(!cfg.UnstableInodes)
+	stdout = io.Discard
+
+	color, _ := useColor(colorAuto, os.Stdout)
+	utils.SetColor(color)
+
+	policies, err := loadPolicies(cfg)
+	if err != nil {
+		utils.Errorf("❌ Failed to load policies: %v\n", err)
+		return 2
+	}
+
+	staged, err := gitdiff.LoadStaged()
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 2
+	}
+	scanDir, err := repoScanDir(top, cfg.Dir)
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 2
+	}
+	files := filesUnder(staged.Files(), scanDir)
+	if len(files) == 0 {
+		return 0
+	}
+
+	snapshot, err := os.MkdirTemp("", "code-analyzer-pre-commit-")
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 2
+	}
+	defer os.RemoveAll(snapshot)
+	checkout := append([]string{"checkout-index", "--prefix=" + snapshot + string(filepath.Separator), "--"}, files...)
+	if _, err := gitOutput(checkout...); err != nil {
+		utils.Errorf("❌ Failed to check out staged files: %v\n", err)
+		return 2
+	}
+	root := filepath.Join(snapshot, scanDir)
+
+	all := newAnalyzers()
+	onlySet := parseOnly(*only)
+	if builtinConfig && onlySet == nil {
+		cfg.Dir = root
+		autoDetect(cfg, all)
+	}
+
+	names := enabledAnalyzers(cfg, all, onlySet)
+	// Conflict markers are cheap to find and always fatal: look for them first
+	sort.SliceStable(names, func(i, j int) bool {
+		return names[i] == "conflicts" && names[j] != "conflicts"
+	})
+
+	var findings []finding
+	for _, name := range names {
+		issues, err := analyzeSnapshot(cfg, snapshot, root, name, all[name], policies, nil)
+		if err != nil {
+			utils.Errorf("❌ Analyzer %s failed: %v\n", name, err)
+			return 2
+		}
+
+		var found []finding
+		for _, f := range issues {
+			if staged.Contains(f.Issue.Path, f.Issue.Line) {
+				found = append(found, f)
+			}
+		}
+
+		if name == "conflicts" && len(found) > 0 {
+			printCompact(os.Stdout, found)
+			utils.Errorf("❌ Conflict markers are staged; resolve them before committing\n")
+			return 1
+		}
+		findings = append(findings, found...)
+	}
+
+	printCompact(os.Stdout, findings)
+
+	blocking := 0
+	for _, f := range findings {
+		if f.Issue.Severity == "critical" || f.Issue.Severity == "blocker" {
+			blocking++
+		}
+	}
+	if blocking > 0 {
+		utils.Errorf("❌ %d critical or blocker issues in staged changes; fix them or commit with --no-verify\n", blocking)
+		return 1
+	}
+	return 0
+}
+
+// runInstallHook handles `code-analyzer install-hook`: it writes a git
+// pre-commit hook running `code-analyzer pre-commit` with this binary, and
+// exits. A hook that install-hook did not write is only replaced with -force.
+func runInstallHook(args []string) {
+	flags := flag.NewFlagSet("install-hook", flag.ExitOnError)
+	configFile := flags.String("config", "", "Configuration file the hook passes to pre-commit (default: pre-commit's default)")
+	force := flags.Bool("force", false, "Replace an existing pre-commit hook not written by install-hook")
+	_ = flags.Parse(args)
+
+	hooksDir, err := gitOutput("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		utils.Errorf("❌ install-hook must run inside a git work tree: %v\n", err)
+		os.Exit(2)
+	}
+	binary, err := os.Executable()
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		os.Exit(2)
+	}
+
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+	if err := installHook(hookPath, binary, *configFile, *force); err != nil {
+		utils.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Pre-commit hook installed: %s\n", hookPath)
+}
+
+// installHook writes the pre-commit hook script to path
+func installHook(path, binary, configFile string, force bool) error {
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if !force && !strings.Contains(string(existing), preCommitHookMarker) {
+			return fmt.Errorf("%s already exists; rerun with -force to replace it", path)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	command := shellQuote(binary) + " pre-commit"
+	if configFile != "" {
+		command += " -config " + shellQuote(configFile)
+	}
+	script := "#!/bin/sh\n" + preCommitHookMarker + "\nexec " + command + "\n"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a file it overwrites
+	return os.Chmod(path, 0755)
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"code-analyzer", `'code-analyzer'`},
+		{"/opt/my tools/code-analyzer", `'/opt/my tools/code-analyzer'`},
+		{"it's", `'it'\''s'`},
+		{"'", `''\'''`},
+		{"", `''`},
+	}
+	_, err := exec.LookPath("sh")
+	hasShell := err == nil
+	for _, tt := range tests {
+		got := shellQuote(tt.value)
+		if got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+		if !hasShell {
+			continue
+		}
+		// The shell reads the quoted word back as the original value
+		out, err := exec.Command("sh", "-c", "printf %s "+got).Output()
+		if err != nil {
+			t.Errorf("sh failed on %s: %v", got, err)
+		} else if string(out) != tt.value {
+			t.Errorf("sh read %s as %q, want %q", got, out, tt.value)
+		}
+	}
+}
+
+func TestInstallHook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".git", "hooks", "pre-commit")
+	read := func() string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read hook: %v", err)
+		}
+		return string(content)
+	}
+
+	if err := installHook(path, "/opt/my tools/code-analyzer", "it's.yaml", false); err != nil {
+		t.Fatalf("installHook failed: %v", err)
+	}
+	if want := `exec '/opt/my tools/code-analyzer' pre-commit -config 'it'\''s.yaml'`; !strings.Contains(read(), want) {
+		t.Errorf("expected the hook to run %s, got:\n%s", want, read())
+	}
+
+	// Our own hook is replaced without -force
+	if err := installHook(path, "code-analyzer", "", false); err != nil {
+		t.Fatalf("reinstalling our hook failed: %v", err)
+	}
+	if !strings.Contains(read(), "exec 'code-analyzer' pre-commit\n") {
+		t.Errorf("expected the hook to be replaced, got:\n%s", read())
+	}
+
+	// Someone else's hook is left alone
+	foreign := "#!/bin/sh\nnpx lint-staged\n"
+	if err := os.WriteFile(path, []byte(foreign), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+	if err := installHook(path, "code-analyzer", "", false); err == nil || !strings.Contains(err.Error(), "-force") {
+		t.Errorf("expected an error asking for -force, got %v", err)
+	}
+	if read() != foreign {
+		t.Errorf("expected the existing hook to be kept, got:\n%s", read())
+	}
+
+	if err := installHook(path, "code-analyzer", "", true); err != nil {
+		t.Fatalf("installHook with force failed: %v", err)
+	}
+	if !strings.Contains(read(), preCommitHookMarker) {
+		t.Errorf("expected -force to replace the hook, got:\n%s", read())
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfile starts a CPU profile written to dir/cpu.pprof. The returned
+// function stops it and writes the heap profile to dir/heap.pprof.
+func startProfile(dir string) (func() error, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	cpu, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		_ = cpu.Close()
+		return nil, err
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := cpu.Close(); err != nil {
+			return fmt.Errorf("CPU profile: %v", err)
+		}
+		heap, err := os.Create(filepath.Join(dir, "heap.pprof"))
+		if err != nil {
+			return fmt.Errorf("heap profile: %v", err)
+		}
+		defer heap.Close()
+		// Up-to-date statistics of the objects still in use
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(heap); err != nil {
+			return fmt.Errorf("heap profile: %v", err)
+		}
+		return nil
+	}, nil
+}
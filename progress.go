@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"code-analyzer/utils"
+)
+
+// countFiles indexes the scan root once so every analyzer's progress can be
+// reported against the same total
+func countFiles(rootDir string, followSymlinks bool) int {
+	total := 0
+	_ = utils.Walk(rootDir, followSymlinks, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			total++
+		}
+		return nil
+	})
+	return total
+}
+
+// isInteractive reports whether f is a terminal a progress bar can be redrawn
+// on. CI job logs get periodic lines instead, even when a TTY is allocated.
+func isInteractive(f *os.File) bool {
+	if os.Getenv("CI") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// isTerminal reports whether f is a character device such as a TTY
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// openEventSink opens the -progress-json target: "fd:N" for an inherited file
+// descriptor, "unix:PATH" for a Unix socket, anything else is a file path
+func openEventSink(target string) (io.WriteCloser, error) {
+	switch {
+	case strings.HasPrefix(target, "fd:"):
+		fd, err := strconv.Atoi(strings.TrimPrefix(target, "fd:"))
+		if err != nil || fd < 0 {
+			return nil, fmt.Errorf("invalid file descriptor in %q", target)
+		}
+		return os.NewFile(uintptr(fd), target), nil
+	case strings.HasPrefix(target, "unix:"):
+		return net.Dial("unix", strings.TrimPrefix(target, "unix:"))
+	default:
+		return os.Create(target)
+	}
+}
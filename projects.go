@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/config"
+)
+
+// project is a part of the scan: the whole scan root, or one of the
+// `projects` of a monorepo
+type project struct {
+	Name      string // Empty when the config has no projects
+	Dir       string
+	Exclude   []string
+	Analyzers map[string]bool // nil when the top-level enabled flags apply
+}
+
+// resolveProjects returns the configured projects with their directories
+// under the scan root, or the scan root as the only, unnamed project
+func resolveProjects(cfg *config.AppConfig, known map[string]analyzers.Analyzer) ([]project, error) {
+	if len(cfg.Projects) == 0 {
+		return []project{{Dir: cfg.Dir}}, nil
+	}
+
+	seen := make(map[string]bool)
+	projects := make([]project, 0, len(cfg.Projects))
+	for i, p := range cfg.Projects {
+		switch {
+		case p.Name == "":
+			return nil, fmt.Errorf("projects[%d]: name is required", i)
+		case p.Name == "." || p.Name == ".." || strings.ContainsAny(p.Name, `/\`):
+			// The name is the directory of the project's artifacts
+			return nil, fmt.Errorf("project %q: name cannot be a path", p.Name)
+		case seen[p.Name]:
+			return nil, fmt.Errorf("project %q is defined twice", p.Name)
+		case p.Dir == "":
+			return nil, fmt.Errorf("project %s: dir is required", p.Name)
+		}
+		seen[p.Name] = true
+
+		dir := p.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(cfg.Dir, dir)
+		}
+		var enabled map[string]bool
+		if len(p.Analyzers) > 0 {
+			enabled = make(map[string]bool, len(p.Analyzers))
+			for _, name := range p.Analyzers {
+				if _, ok := known[name]; !ok {
+					return nil, fmt.Errorf("project %s: unknown analyzer %q", p.Name, name)
+				}
+				enabled[name] = true
+			}
+		}
+		projects = append(projects, project{Name: p.Name, Dir: dir, Exclude: p.Exclude, Analyzers: enabled})
+	}
+	return projects, nil
+}
+
+// enabled reports whether the project runs the analyzer, given whether the
+// top-level config enables it
+func (p project) enabled(name string, enabledAtTop bool) bool {
+	if p.Analyzers == nil {
+		return enabledAtTop
+	}
+	return p.Analyzers[name]
+}
+
+// label qualifies an analyzer name with the project, if any
+func (p project) label(name string) string {
+	if p.Name == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, p.Name)
+}
+
+// runConfig points an analyzer's run config at the project: its directory,
+// its excludes and an artifact of its own
+func (p project) runConfig(runConfig analyzers.Config, cfg *config.AppConfig, analyzer string) analyzers.Config {
+	if p.Name == "" {
+		return runConfig
+	}
+	runConfig.RootDir = p.Dir
+	runConfig.ExcludePaths = append(append([]string{}, runConfig.ExcludePaths...), p.Exclude...)
+	if runConfig.OutputFile != "" {
+		runConfig.OutputFile = filepath.Join(cfg.Output, filepath.FromSlash(cfg.Artifacts.ArtifactName(analyzer, p.Name, artifactTimestamp)))
+	}
+	return runConfig
+}
+
+// artifactKey is the key of an analyzer's report in the combined artifact
+func (p project) artifactKey(analyzer string) string {
+	if p.Name == "" {
+		return analyzer
+	}
+	return p.Name + "/" + analyzer
+}
+
+// printProjectSummary prints the issue counts per project and severity
+func printProjectSummary(w io.Writer, projects []project, findings []finding) {
+	counts := make(map[string]map[string]int, len(projects))
+	for _, p := range projects {
+		counts[p.Name] = make(map[string]int)
+	}
+	totals := make(map[string]int)
+	for _, f := range findings {
+		// Derived issues such as churn belong to no project
+		if counts[f.Project] == nil {
+			counts[f.Project] = make(map[string]int)
+		}
+		counts[f.Project][f.Issue.Severity]++
+		totals[f.Issue.Severity]++
+	}
+
+	fmt.Fprintf(w, "%-12s", "Project")
+	for _, severity := range severityOrder {
+		fmt.Fprintf(w, " %9s", severity)
+	}
+	fmt.Fprintf(w, " %9s\n", "total")
+	fmt.Fprintln(w, strings.Repeat("-", 12+10*(len(severityOrder)+1)))
+	for _, p := range projects {
+		printSummaryRow(w, p.Name, counts[p.Name])
+	}
+	if other := counts[""]; other != nil {
+		printSummaryRow(w, "(none)", other)
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 12+10*(len(severityOrder)+1)))
+	printSummaryRow(w, "all", totals)
+}
@@ -0,0 +1,117 @@
+package ratchet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"code-analyzer/utils"
+)
+
+// DefaultDepth is the directory depth issues are counted at when unset
+const DefaultDepth = 2
+
+// Counts holds issue counts per rule (check name) and directory
+type Counts map[string]map[string]int
+
+// Add counts one issue of rule in dir
+func (c Counts) Add(rule, dir string) {
+	if c[rule] == nil {
+		c[rule] = make(map[string]int)
+	}
+	c[rule][dir]++
+}
+
+// File is the committed ratchet: the highest counts allowed
+type File struct {
+	Timestamp string `json:"timestamp"`
+	Depth     int    `json:"depth"`
+	Counts    Counts `json:"counts"`
+}
+
+// Load reads a ratchet file; a missing file yields nil without an error
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse ratchet %s: %v", path, err)
+	}
+	if f.Counts == nil {
+		f.Counts = Counts{}
+	}
+	return f, nil
+}
+
+// Write stores counts as the ratchet file
+func Write(path string, depth int, counts Counts) error {
+	return utils.WriteArtifact(path, File{
+		Timestamp: utils.GetTimestamp(),
+		Depth:     depth,
+		Counts:    counts,
+	})
+}
+
+// Change is a count of one rule in one directory that differs from the ratchet
+type Change struct {
+	Rule      string
+	Directory string
+	Allowed   int
+	Current   int
+}
+
+// Compare returns the counts that went up and those that went down against
+// the allowed counts, ordered by rule and directory
+func Compare(allowed, current Counts) (increased, decreased []Change) {
+	for rule, dirs := range current {
+		for dir, n := range dirs {
+			if was := allowed[rule][dir]; n > was {
+				increased = append(increased, Change{Rule: rule, Directory: dir, Allowed: was, Current: n})
+			}
+		}
+	}
+	for rule, dirs := range allowed {
+		for dir, was := range dirs {
+			if n := current[rule][dir]; n < was {
+				decreased = append(decreased, Change{Rule: rule, Directory: dir, Allowed: was, Current: n})
+			}
+		}
+	}
+	sortChanges(increased)
+	sortChanges(decreased)
+	return increased, decreased
+}
+
+// Tighten returns the allowed counts lowered to the current ones wherever
+// they dropped; counts that went up keep their allowed value, so tightening
+// never loosens the ratchet
+func Tighten(allowed, current Counts) Counts {
+	tightened := Counts{}
+	for rule, dirs := range allowed {
+		for dir, was := range dirs {
+			if n := min(was, current[rule][dir]); n > 0 {
+				if tightened[rule] == nil {
+					tightened[rule] = make(map[string]int)
+				}
+				tightened[rule][dir] = n
+			}
+		}
+	}
+	return tightened
+}
+
+func sortChanges(changes []Change) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Rule != changes[j].Rule {
+			return changes[i].Rule < changes[j].Rule
+		}
+		return changes[i].Directory < changes[j].Directory
+	})
+}
@@ -0,0 +1,59 @@
+package ratchet
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	allowed := Counts{
+		"php-check": {"app/Http": 4, "app/Models": 2},
+		"sql-check": {"database": 1},
+	}
+	current := Counts{
+		"php-check":  {"app/Http": 5, "app/Models": 1},
+		"size-check": {"resources": 1},
+	}
+
+	increased, decreased := Compare(allowed, current)
+	if len(increased) != 2 || increased[0] != (Change{"php-check", "app/Http", 4, 5}) || increased[1] != (Change{"size-check", "resources", 0, 1}) {
+		t.Errorf("unexpected increases %+v", increased)
+	}
+	if len(decreased) != 2 || decreased[0] != (Change{"php-check", "app/Models", 2, 1}) || decreased[1] != (Change{"sql-check", "database", 1, 0}) {
+		t.Errorf("unexpected decreases %+v", decreased)
+	}
+}
+
+func TestTighten(t *testing.T) {
+	allowed := Counts{"php-check": {"app/Http": 4, "app/Models": 2}, "sql-check": {"database": 1}}
+	current := Counts{"php-check": {"app/Http": 5, "app/Models": 1}, "size-check": {"resources": 1}}
+
+	got := Tighten(allowed, current)
+	if got["php-check"]["app/Http"] != 4 || got["php-check"]["app/Models"] != 1 {
+		t.Errorf("unexpected php-check counts %+v", got["php-check"])
+	}
+	if _, ok := got["sql-check"]; ok {
+		t.Errorf("fixed rule should be dropped, got %+v", got["sql-check"])
+	}
+	if _, ok := got["size-check"]; ok {
+		t.Errorf("tightening must not add new counts, got %+v", got["size-check"])
+	}
+}
+
+func TestLoadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratchet.json")
+	if f, err := Load(path); f != nil || err != nil {
+		t.Fatalf("missing file: got %v, %v", f, err)
+	}
+
+	if err := Write(path, 2, Counts{"php-check": {"app": 3}}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Depth != 2 || f.Counts["php-check"]["app"] != 3 {
+		t.Errorf("unexpected file %+v", f)
+	}
+}
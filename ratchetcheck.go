@@ -0,0 +1,66 @@
+package main
+
+import (
+	"code-analyzer/config"
+	"code-analyzer/history"
+	"code-analyzer/ratchet"
+	"code-analyzer/utils"
+)
+
+// ratchetCounts counts findings per check name and directory
+func ratchetCounts(findings []finding, depth int) ratchet.Counts {
+	counts := ratchet.Counts{}
+	for _, f := range findings {
+		counts.Add(f.checkName(), history.Directory(f.Issue.Path, depth))
+	}
+	return counts
+}
+
+// checkRatchet compares the findings with the committed ratchet file and
+// reports whether any count went up. Counts that dropped are listed with an
+// offer to tighten; with tighten they are written to the file instead. A
+// missing file is created from the current counts with tighten.
+func checkRatchet(cfg config.RatchetConfig, findings []finding, tighten bool) (failed bool, err error) {
+	allowed, err := ratchet.Load(cfg.Path)
+	if err != nil {
+		return false, err
+	}
+
+	if allowed == nil {
+		depth := cfg.Depth
+		if depth == 0 {
+			depth = ratchet.DefaultDepth
+		}
+		if !tighten {
+			utils.Warnf("⚠️  No ratchet file at %s; run with -tighten-ratchet to create it from the current counts\n", cfg.Path)
+			return false, nil
+		}
+		if err := ratchet.Write(cfg.Path, depth, ratchetCounts(findings, depth)); err != nil {
+			return false, err
+		}
+		utils.Infof("✅ Ratchet created: %s\n", cfg.Path)
+		return false, nil
+	}
+
+	// Count at the depth the file was written with, so counts stay comparable
+	current := ratchetCounts(findings, allowed.Depth)
+	increased, decreased := ratchet.Compare(allowed.Counts, current)
+	for _, c := range increased {
+		utils.Errorf("❌ Ratchet: %s in %s went up from %d to %d\n", c.Rule, c.Directory, c.Allowed, c.Current)
+	}
+
+	if len(decreased) > 0 {
+		if tighten {
+			if err := ratchet.Write(cfg.Path, allowed.Depth, ratchet.Tighten(allowed.Counts, current)); err != nil {
+				return len(increased) > 0, err
+			}
+			utils.Infof("✅ Ratchet tightened: %s (%d counts lowered)\n", cfg.Path, len(decreased))
+		} else {
+			utils.Infof("📉 Ratchet: %d counts dropped; run with -tighten-ratchet and commit %s to lock in the improvement\n", len(decreased), cfg.Path)
+			for _, c := range decreased {
+				utils.Infof("   %s in %s: %d → %d\n", c.Rule, c.Directory, c.Allowed, c.Current)
+			}
+		}
+	}
+	return len(increased) > 0, nil
+}
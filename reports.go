@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"code-analyzer/utils"
+)
+
+// reportJob writes one report from the run's final findings. Console lines go
+// to out and are printed in job order once every job is done, so output does
+// not depend on which report finishes first.
+type reportJob struct {
+	action   string // Completes "Failed to ..." in the error message
+	warnOnly bool   // Failures are warnings (best-effort deliveries)
+	generate func(out io.Writer) error
+}
+
+// generateReports runs the jobs concurrently. They share the findings and
+// must treat them as read-only. A job that fails or panics does not affect
+// the others.
+func generateReports(jobs []reportJob) {
+	outputs := make([]bytes.Buffer, len(jobs))
+	errs := make([]error, len(jobs))
+
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("panic: %v", r)
+				}
+			}()
+			errs[i] = job.generate(&outputs[i])
+		}()
+	}
+	wg.Wait()
+
+	for i, job := range jobs {
+		_, _ = stdout.Write(outputs[i].Bytes())
+		switch {
+		case errs[i] == nil:
+		case job.warnOnly:
+			utils.Warnf("⚠️  Failed to %s: %v\n", job.action, errs[i])
+		default:
+			utils.Errorf("❌ Failed to %s: %v\n", job.action, errs[i])
+		}
+	}
+}
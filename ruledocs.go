@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/config"
+	"code-analyzer/models"
+)
+
+// ruleDocsPlaceholder is replaced by the rule ID in rule_docs.base_url
+const ruleDocsPlaceholder = "{rule}"
+
+// ruleDocs resolves the documentation URL of each rule: a rule_docs.rules
+// entry, else rule_docs.base_url, else the link its analyzer ships
+type ruleDocs struct {
+	baseURL   string
+	overrides map[string]string
+	defaults  map[string]string
+}
+
+// newRuleDocs collects the default URLs of every analyzer's rules
+func newRuleDocs(cfg config.RuleDocsConfig, all map[string]analyzers.Analyzer) *ruleDocs {
+	docs := &ruleDocs{baseURL: cfg.BaseURL, overrides: cfg.Rules, defaults: make(map[string]string)}
+	for _, analyzer := range all {
+		if documenter, ok := analyzer.(analyzers.RuleDocumenter); ok {
+			for rule, url := range documenter.RuleDocs() {
+				docs.defaults[rule] = url
+			}
+		}
+	}
+	return docs
+}
+
+// URL returns the documentation URL of a rule, or "" when it has none
+func (d *ruleDocs) URL(rule string) string {
+	if rule == "" {
+		return ""
+	}
+	if url, ok := d.overrides[rule]; ok {
+		return url
+	}
+	if d.baseURL != "" {
+		return strings.ReplaceAll(d.baseURL, ruleDocsPlaceholder, rule)
+	}
+	return d.defaults[rule]
+}
+
+// Apply links an issue to the documentation of its rule
+func (d *ruleDocs) Apply(issue *models.Issue) {
+	issue.DocsURL = d.URL(issue.RuleID)
+}
+
+// docsContent returns the Code Climate content linking to an issue's rule
+// docs, or nil
+func docsContent(issue models.Issue) *models.CodeQualityContent {
+	if issue.DocsURL == "" {
+		return nil
+	}
+	return &models.CodeQualityContent{Body: fmt.Sprintf("[Documentation for %s](%s)", issue.RuleID, issue.DocsURL)}
+}
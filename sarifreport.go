@@ -0,0 +1,158 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+
+	"code-analyzer/models"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
+)
+
+// sarifLog is the part of SARIF 2.1.0 the sarif_report writes: one run with
+// a result per finding
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID      string `json:"id"`
+	HelpURI string `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// sarifProperties carries what SARIF has no field for: the canonical
+// severity, the issue's category and the size and effort metadata
+// prioritization tools rank fixes by
+type sarifProperties struct {
+	Analyzer  string                `json:"analyzer"`
+	Project   string                `json:"project,omitempty"`
+	Category  string                `json:"category,omitempty"`
+	Severity  string                `json:"severity"`
+	FirstSeen string                `json:"first_seen,omitempty"`
+	Checks    []string              `json:"checks,omitempty"`
+	Metadata  *models.IssueMetadata `json:"metadata,omitempty"`
+}
+
+// sarifSchema is the schema the SARIF report declares
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// writeSARIFReport writes one SARIF result per finding, ordered by path and
+// line, with every rule of the findings and its docs link in the driver
+func writeSARIFReport(path string, findings []finding) error {
+	sorted := sortedByLocation(findings)
+
+	docs := make(map[string]string)
+	for _, f := range sorted {
+		if _, ok := docs[f.checkName()]; !ok {
+			docs[f.checkName()] = f.Issue.DocsURL
+		}
+	}
+	rules := make([]sarifRule, 0, len(docs))
+	for id, url := range docs {
+		rules = append(rules, sarifRule{ID: id, HelpURI: url})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	ruleIndex := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		ruleIndex[rule.ID] = i
+	}
+
+	results := make([]sarifResult, 0, len(sorted))
+	for _, f := range sorted {
+		results = append(results, sarifResult{
+			RuleID:    f.checkName(),
+			RuleIndex: ruleIndex[f.checkName()],
+			Level:     severity.Format(severity.SARIF, f.Issue.Severity),
+			Message:   sarifMessage{Text: f.Issue.Description},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.Issue.Path)},
+				Region:           sarifRegionOf(f),
+			}}},
+			PartialFingerprints: map[string]string{"codeAnalyzer/v1": utils.Fingerprint(f.Issue)},
+			Properties: sarifProperties{
+				Analyzer:  f.Analyzer,
+				Project:   f.Project,
+				Category:  f.Issue.Category,
+				Severity:  f.Issue.Severity,
+				FirstSeen: f.Issue.FirstSeen,
+				Checks:    f.Issue.Checks,
+				Metadata:  f.Issue.Metadata,
+			},
+		})
+	}
+
+	return utils.WriteArtifact(path, sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "code-analyzer", Rules: rules}},
+			Results: results,
+		}},
+	})
+}
+
+// sarifRegionOf returns the lines and, when the rule recorded them, columns
+// of a finding, or nil for findings about a whole file. SARIF end columns
+// point past the last character.
+func sarifRegionOf(f finding) *sarifRegion {
+	if f.Issue.Line < 1 {
+		return nil
+	}
+	region := &sarifRegion{StartLine: f.Issue.Line, EndLine: endLine(f)}
+	if positions := issuePositions(f.Issue); positions != nil {
+		region.StartColumn = positions.Begin.Column
+		region.EndLine = positions.End.Line
+		region.EndColumn = positions.End.Column + 1
+	}
+	return region
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+)
+
+// analyzerResult is the outcome of one scheduled analyzer run
+type analyzerResult struct {
+	config  analyzers.Config
+	issues  []models.Issue
+	err     error
+	elapsed time.Duration
+	// partial is set when the run's timeout, an interruption or the
+	// analyzer's timeout_seconds stopped it; err says which
+	partial bool
+	// overBudget is set when the analyzer's soft budget stopped it
+	overBudget bool
+	// skipped is set when the run was over before the analyzer started
+	skipped bool
+}
+
+// runScheduled calls run for each of n analyzers and process for each of
+// them in order once it finished. With a limit above 1 analyzers start in
+// order as soon as one of limit slots is free, CPU-bound ones only while
+// fewer than one per CPU core run; process still sees them in order. A
+// panicking run is recovered and handed to panicked, so it cannot take the
+// other analyzers down with it.
+func runScheduled(n, limit int, cpuBound func(i int) bool, run func(i int), panicked func(i int, value interface{}, stack []byte), process func(i int)) {
+	call := func(i int) {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked(i, r, debug.Stack())
+			}
+		}()
+		run(i)
+	}
+	if limit <= 1 {
+		for i := 0; i < n; i++ {
+			call(i)
+			process(i)
+		}
+		return
+	}
+
+	slots := make(chan struct{}, limit)
+	cpuSlots := make(chan struct{}, runtime.NumCPU())
+	done := make([]chan struct{}, n)
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	go func() {
+		for i := 0; i < n; i++ {
+			slots <- struct{}{}
+			cpu := cpuBound(i)
+			if cpu {
+				cpuSlots <- struct{}{}
+			}
+			go func() {
+				defer close(done[i])
+				defer func() {
+					if cpu {
+						<-cpuSlots
+					}
+					<-slots
+				}()
+				call(i)
+			}()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		<-done[i]
+		process(i)
+	}
+}
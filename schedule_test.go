@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRunScheduledRecoversPanics(t *testing.T) {
+	for _, limit := range []int{1, 3} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			errs := make([]error, 5)
+			var processed []int
+			runScheduled(len(errs), limit, func(i int) bool { return i%2 == 0 }, func(i int) {
+				if i == 2 {
+					panic("boom")
+				}
+			}, func(i int, value interface{}, stack []byte) {
+				if len(stack) == 0 {
+					t.Error("expected the panic stack")
+				}
+				errs[i] = fmt.Errorf("panic: %v", value)
+			}, func(i int) {
+				processed = append(processed, i)
+			})
+
+			if want := []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(processed, want) {
+				t.Errorf("expected %v processed in order, got %v", want, processed)
+			}
+			for i, err := range errs {
+				if (err != nil) != (i == 2) {
+					t.Errorf("analyzer %d: unexpected error %v", i, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Package severity defines the canonical issue severities, maps the
+// severities rules may emit onto them and translates them for each output
+// format.
+package severity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Canonical severities, the levels of the GitLab Code Quality report
+const (
+	Blocker  = "blocker"
+	Critical = "critical"
+	Major    = "major"
+	Minor    = "minor"
+	Info     = "info"
+)
+
+// Order lists the canonical severities from most to least severe
+var Order = []string{Blocker, Critical, Major, Minor, Info}
+
+// Output formats whose severities can be overridden
+const (
+	GitLab    = "gitlab" // GitLab Code Quality report and Code Climate engine
+	Bitbucket = "bitbucket"
+	Azure     = "azure"
+	Plain     = "plain" // gcc-style console diagnostics
+	SARIF     = "sarif" // SARIF result levels
+)
+
+// defaultAliases map severities common in other tools onto canonical ones
+var defaultAliases = map[string]string{
+	"high":    Critical,
+	"error":   Critical,
+	"medium":  Major,
+	"warning": Major,
+	"low":     Minor,
+	"notice":  Info,
+	"note":    Info,
+	"trivial": Info,
+}
+
+// formats holds, per output format, the value written for each canonical
+// severity and the values the format accepts
+var formats = map[string]struct {
+	values   map[string]string
+	accepted []string
+}{
+	GitLab: {
+		values:   map[string]string{Blocker: Blocker, Critical: Critical, Major: Major, Minor: Minor, Info: Info},
+		accepted: Order,
+	},
+	Bitbucket: {
+		values:   map[string]string{Blocker: "CRITICAL", Critical: "CRITICAL", Major: "HIGH", Minor: "MEDIUM", Info: "LOW"},
+		accepted: []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"},
+	},
+	Azure: {
+		values:   map[string]string{Blocker: "error", Critical: "error", Major: "warning", Minor: "warning", Info: "warning"},
+		accepted: []string{"error", "warning"},
+	},
+	Plain: {
+		values:   map[string]string{Blocker: "error", Critical: "error", Major: "warning", Minor: "warning", Info: "note"},
+		accepted: []string{"error", "warning", "note"},
+	},
+	SARIF: {
+		values:   map[string]string{Blocker: "error", Critical: "error", Major: "warning", Minor: "note", Info: "note"},
+		accepted: []string{"error", "warning", "note", "none"},
+	},
+}
+
+// Mapping translates severities in and out of the canonical set
+type Mapping struct {
+	aliases   map[string]string
+	overrides map[string]map[string]string
+}
+
+// current is the mapping used by Normalize and Format
+var current = &Mapping{}
+
+// Valid reports whether s is a canonical severity
+func Valid(s string) bool {
+	for _, level := range Order {
+		if s == level {
+			return true
+		}
+	}
+	return false
+}
+
+// AtLeast reports whether s is as severe as min or more. Severities that
+// are not canonical rank below info.
+func AtLeast(s, min string) bool {
+	return rank(s) <= rank(min)
+}
+
+// rank is the position of s in Order, len(Order) when s is not canonical
+func rank(s string) int {
+	for i, level := range Order {
+		if s == level {
+			return i
+		}
+	}
+	return len(Order)
+}
+
+// NewMapping validates the configured aliases, which are merged over the
+// built-in ones, and the per-format overrides of canonical severities
+func NewMapping(aliases map[string]string, overrides map[string]map[string]string) (*Mapping, error) {
+	m := &Mapping{aliases: make(map[string]string), overrides: make(map[string]map[string]string)}
+	for from, to := range defaultAliases {
+		m.aliases[from] = to
+	}
+	for from, to := range aliases {
+		to = strings.ToLower(to)
+		if !Valid(to) {
+			return nil, fmt.Errorf("severity alias %s: %q is not one of %s", from, to, strings.Join(Order, ", "))
+		}
+		m.aliases[strings.ToLower(from)] = to
+	}
+
+	for format, values := range overrides {
+		f, ok := formats[format]
+		if !ok {
+			return nil, fmt.Errorf("unknown severity output format %q", format)
+		}
+		for from, to := range values {
+			if !Valid(from) {
+				return nil, fmt.Errorf("severities.%s: %q is not a canonical severity", format, from)
+			}
+			if !contains(f.accepted, to) {
+				return nil, fmt.Errorf("severities.%s: %s cannot be written as %q (expected one of %s)", format, from, to, strings.Join(f.accepted, ", "))
+			}
+			if m.overrides[format] == nil {
+				m.overrides[format] = make(map[string]string)
+			}
+			m.overrides[format][from] = to
+		}
+	}
+	return m, nil
+}
+
+// SetMapping makes m the mapping used by Normalize and Format
+func SetMapping(m *Mapping) {
+	current = m
+}
+
+// Normalize returns the canonical severity of s, resolving case and aliases.
+// It reports false for severities it cannot map.
+func Normalize(s string) (string, bool) {
+	return current.Normalize(s)
+}
+
+// Normalize returns the canonical severity of s, see the package function
+func (m *Mapping) Normalize(s string) (string, bool) {
+	lower := strings.ToLower(strings.TrimSpace(s))
+	if Valid(lower) {
+		return lower, true
+	}
+	if to, ok := m.aliases[lower]; ok {
+		return to, true
+	}
+	if to, ok := defaultAliases[lower]; ok {
+		return to, true
+	}
+	return "", false
+}
+
+// Format returns how a canonical severity is written in an output format
+func Format(format, s string) string {
+	return current.Format(format, s)
+}
+
+// Format returns how a canonical severity is written, see the package function
+func (m *Mapping) Format(format, s string) string {
+	if to, ok := m.overrides[format][s]; ok {
+		return to
+	}
+	if to, ok := formats[format].values[s]; ok {
+		return to
+	}
+	return s
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package severity
+
+import "testing"
+
+func TestMapping_Normalize(t *testing.T) {
+	m, err := NewMapping(map[string]string{"Medium": "minor", "severe": "BLOCKER"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"major":    Major,
+		" Info ":   Info,
+		"CRITICAL": Critical,
+		"medium":   Minor, // configured over the built-in alias
+		"severe":   Blocker,
+		"high":     Critical,
+	}
+	for in, want := range tests {
+		if got, ok := m.Normalize(in); !ok || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := m.Normalize("catastrophic"); ok {
+		t.Error("unknown severity normalized")
+	}
+}
+
+func TestMapping_Format(t *testing.T) {
+	m, err := NewMapping(nil, map[string]map[string]string{
+		Bitbucket: {Minor: "LOW"},
+		Azure:     {Major: "error"},
+		Plain:     {Minor: "note"},
+		SARIF:     {Info: "none"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ format, in, want string }{
+		{GitLab, Blocker, "blocker"},
+		{Bitbucket, Minor, "LOW"},
+		{Bitbucket, Major, "HIGH"},
+		{Azure, Major, "error"},
+		{Azure, Minor, "warning"},
+		{Plain, Critical, "error"},
+		{Plain, Major, "warning"},
+		{Plain, Minor, "note"},
+		{Plain, Info, "note"},
+		{SARIF, Blocker, "error"},
+		{SARIF, Major, "warning"},
+		{SARIF, Minor, "note"},
+		{SARIF, Info, "none"},
+	}
+	for _, tt := range tests {
+		if got := m.Format(tt.format, tt.in); got != tt.want {
+			t.Errorf("Format(%s, %s) = %q, want %q", tt.format, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewMapping_Invalid(t *testing.T) {
+	invalid := []struct {
+		aliases   map[string]string
+		overrides map[string]map[string]string
+	}{
+		{aliases: map[string]string{"medium": "moderate"}},
+		{overrides: map[string]map[string]string{"junit": {Major: "warning"}}},
+		{overrides: map[string]map[string]string{SARIF: {Major: "warn"}}},
+		{overrides: map[string]map[string]string{Azure: {"medium": "error"}}},
+		{overrides: map[string]map[string]string{Bitbucket: {Major: "high"}}},
+	}
+	for _, tt := range invalid {
+		if _, err := NewMapping(tt.aliases, tt.overrides); err == nil {
+			t.Errorf("expected an error for %+v", tt)
+		}
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	tests := []struct {
+		s, min string
+		want   bool
+	}{
+		{Blocker, Critical, true},
+		{Critical, Critical, true},
+		{Major, Critical, false},
+		{Info, Info, true},
+		{"unknown", Info, false},
+	}
+	for _, tt := range tests {
+		if got := AtLeast(tt.s, tt.min); got != tt.want {
+			t.Errorf("AtLeast(%q, %q) = %v, want %v", tt.s, tt.min, got, tt.want)
+		}
+	}
+}
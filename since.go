@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/config"
+	"code-analyzer/models"
+	"code-analyzer/policy"
+	"code-analyzer/utils"
+)
+
+// rangeKey identifies an issue across versions of a file. Lines move when
+// code around them changes, so they are not part of it.
+type rangeKey struct {
+	analyzer, path, description string
+}
+
+// runSinceRange analyzes only the files touched between since and HEAD, once
+// as of each commit, prints the issues the range introduced and removed and
+// exits. Both sides are read from git, so the working tree does not matter.
+// It exits 1 when an analyzer fails or the range introduced critical or
+// blocker issues.
+func runSinceRange(cfg *config.AppConfig, since string, builtinConfig bool, only string, extensions []string) {
+	os.Exit(sinceRange(cfg, since, builtinConfig, only, extensions))
+}
+
+func sinceRange(cfg *config.AppConfig, since string, builtinConfig bool, only string, extensions []string) int {
+	top, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		utils.Errorf("❌ -since must run inside a git work tree: %v\n", err)
+		return 1
+	}
+	head, err := gitOutput("rev-parse", "--verify", "HEAD^{commit}")
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 1
+	}
+	if _, err := gitOutput("rev-parse", "--verify", since+"^{commit}"); err != nil {
+		utils.Errorf("❌ Unknown -since ref %s: %v\n", since, err)
+		return 1
+	}
+
+	policies, err := loadPolicies(cfg)
+	if err != nil {
+		utils.Errorf("❌ Failed to load policies: %v\n", err)
+		return 1
+	}
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 1
+	}
+	scanDir, err := repoScanDir(top, dir)
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 1
+	}
+
+	// Renames count as a deletion and an addition, so each side only lists
+	// paths that exist in its commit
+	after, err := rangeFiles(top, since, "d")
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 1
+	}
+	before, err := rangeFiles(top, since, "a")
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 1
+	}
+	after, before = filesUnder(after, scanDir), filesUnder(before, scanDir)
+	touched := make(map[string]bool)
+	for _, path := range append(append([]string{}, after...), before...) {
+		touched[path] = true
+	}
+
+	snapshot, err := os.MkdirTemp("", "code-analyzer-since-")
+	if err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(snapshot)
+	headSnapshot, sinceSnapshot := filepath.Join(snapshot, "head"), filepath.Join(snapshot, "since")
+	if err := archiveSnapshot(top, head, headSnapshot, after); err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 1
+	}
+	if err := archiveSnapshot(top, since, sinceSnapshot, before); err != nil {
+		utils.Errorf("❌ %v\n", err)
+		return 1
+	}
+
+	all := newAnalyzers()
+	onlySet := parseOnly(only)
+	if builtinConfig && onlySet == nil {
+		autoDetect(cfg, all)
+	}
+
+	var introduced, removed []finding
+	failed := false
+	for _, name := range enabledAnalyzers(cfg, all, onlySet) {
+		now, err := analyzeRangeSide(cfg, headSnapshot, scanDir, name, all[name], policies, extensions)
+		var old []finding
+		if err == nil {
+			old, err = analyzeRangeSide(cfg, sinceSnapshot, scanDir, name, all[name], policies, extensions)
+		}
+		if err != nil {
+			utils.Errorf("❌ Analyzer %s failed: %v\n", name, err)
+			failed = true
+			continue
+		}
+		added, gone := diffFindings(old, now)
+		introduced, removed = append(introduced, added...), append(removed, gone...)
+	}
+
+	fmt.Printf("🔎 %s..%s: %d files touched\n", since, shortCommit(head), len(touched))
+	fmt.Printf("\n➕ %d issues introduced\n", len(introduced))
+	printCompact(os.Stdout, introduced)
+	fmt.Printf("\n➖ %d issues removed (locations as of %s)\n", len(removed), since)
+	printCompact(os.Stdout, removed)
+
+	if cfg.Output != "" {
+		report := models.RangeReport{
+			Timestamp:    utils.GetTimestamp(),
+			Since:        since,
+			Head:         head,
+			FilesTouched: len(touched),
+			Introduced:   []models.ReportedIssue{},
+			Removed:      []models.ReportedIssue{},
+		}
+		for _, f := range introduced {
+			report.Introduced = append(report.Introduced, reportedIssue(f))
+		}
+		for _, f := range removed {
+			report.Removed = append(report.Removed, reportedIssue(f))
+		}
+		reportPath := filepath.Join(cfg.Output, "range-report.json")
+		if err := utils.WriteArtifact(reportPath, report); err != nil {
+			utils.Errorf("❌ Failed to write range report: %v\n", err)
+		} else {
+			fmt.Printf("\n✅ Range report written: %s\n", reportPath)
+		}
+	}
+
+	blocking := 0
+	for _, f := range introduced {
+		if f.Issue.Severity == "critical" || f.Issue.Severity == "blocker" {
+			blocking++
+		}
+	}
+	if blocking > 0 {
+		utils.Errorf("❌ %d critical or blocker issues introduced since %s\n", blocking, since)
+	}
+	if failed || blocking > 0 {
+		return 1
+	}
+	return 0
+}
+
+// rangeFiles lists the paths, relative to top, that changed between since
+// and HEAD, excluding the change types in filter (git's --diff-filter)
+func rangeFiles(top, since, filter string) ([]string, error) {
+	out, err := gitOutput("-C", top, "diff", "--name-only", "--no-renames", "-z", "--diff-filter="+filter, since, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, path := range strings.Split(out, "\x00") {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// analyzeRangeSide runs an analyzer over one side of the range; a side
+// without any of the touched files has nothing to report
+func analyzeRangeSide(cfg *config.AppConfig, snapshot, scanDir, name string, analyzer analyzers.Analyzer, policies policy.Chain, extensions []string) ([]finding, error) {
+	root := filepath.Join(snapshot, scanDir)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return analyzeSnapshot(cfg, snapshot, root, name, analyzer, policies, extensions)
+}
+
+// diffFindings returns the findings of after that before lacks (introduced)
+// and those of before that after lacks (removed), counting duplicates
+func diffFindings(before, after []finding) (introduced, removed []finding) {
+	counts := make(map[rangeKey]int)
+	for _, f := range before {
+		counts[rangeKey{f.Analyzer, f.Issue.Path, f.Issue.Description}]++
+	}
+	for _, f := range after {
+		key := rangeKey{f.Analyzer, f.Issue.Path, f.Issue.Description}
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		introduced = append(introduced, f)
+	}
+	for _, f := range before {
+		key := rangeKey{f.Analyzer, f.Issue.Path, f.Issue.Description}
+		if counts[key] > 0 {
+			counts[key]--
+			removed = append(removed, f)
+		}
+	}
+	return introduced, removed
+}
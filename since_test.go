@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestDiffFindings(t *testing.T) {
+	at := func(analyzer, path, description string, line int) finding {
+		return finding{Analyzer: analyzer, Issue: models.Issue{Path: path, Line: line, Description: description}}
+	}
+	unused := at("php", "app/User.php", "Commented out function", 10)
+	moved := at("php", "app/User.php", "Commented out function", 42)
+	fixed := at("js", "web/app.js", "Commented out JS code block (12 bytes)", 3)
+	added := at("php", "app/Order.php", "Commented out function", 7)
+
+	tests := []struct {
+		name       string
+		before     []finding
+		after      []finding
+		introduced []finding
+		removed    []finding
+	}{
+		{
+			name:   "unchanged findings are matched even when they moved",
+			before: []finding{unused},
+			after:  []finding{moved},
+		},
+		{
+			name:       "new and fixed findings",
+			before:     []finding{unused, fixed},
+			after:      []finding{moved, added},
+			introduced: []finding{added},
+			removed:    []finding{fixed},
+		},
+		{
+			name:       "duplicates are counted",
+			before:     []finding{unused},
+			after:      []finding{unused, moved},
+			introduced: []finding{moved},
+		},
+		{
+			// Lines are not compared, so the first one is reported
+			name:    "one of two duplicates fixed",
+			before:  []finding{unused, moved},
+			after:   []finding{moved},
+			removed: []finding{unused},
+		},
+		{
+			name:       "same description in another analyzer is new",
+			before:     []finding{unused},
+			after:      []finding{at("html", "app/User.php", "Commented out function", 10)},
+			introduced: []finding{at("html", "app/User.php", "Commented out function", 10)},
+			removed:    []finding{unused},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			introduced, removed := diffFindings(tt.before, tt.after)
+			if !reflect.DeepEqual(introduced, tt.introduced) {
+				t.Errorf("introduced: got %+v, want %+v", introduced, tt.introduced)
+			}
+			if !reflect.DeepEqual(removed, tt.removed) {
+				t.Errorf("removed: got %+v, want %+v", removed, tt.removed)
+			}
+		})
+	}
+}
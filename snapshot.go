@@ -0,0 +1,175 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/config"
+	"code-analyzer/policy"
+)
+
+// Snapshots are temporary copies of some files of the repository as of a
+// commit or the index, so pre-commit and -since can analyze versions other
+// than the working tree. Their paths are relative to the top of the work tree.
+
+// enabledAnalyzers returns, sorted, the known analyzers the config enables
+// or, when given, the -only set selects
+func enabledAnalyzers(cfg *config.AppConfig, all map[string]analyzers.Analyzer, onlySet map[string]bool) []string {
+	var names []string
+	for name, analyzerCfg := range cfg.Analyzers {
+		enabled := analyzerCfg.Enabled
+		if onlySet != nil {
+			enabled = onlySet[name]
+		}
+		if _, ok := all[name]; ok && enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// analyzeSnapshot runs one analyzer over root, a directory of the snapshot,
+// and returns its issues with severity policies applied and paths relative to
+// the snapshot, i.e. to the top of the work tree, leaving out those below
+// min_severity
+func analyzeSnapshot(cfg *config.AppConfig, snapshot, root, name string, analyzer analyzers.Analyzer, policies policy.Chain, extensions []string) ([]finding, error) {
+	runConfig := analyzerRunConfig(cfg, name, cfg.Analyzers[name])
+	runConfig.RootDir = root
+	runConfig.OnlyExtensions = extensions
+	runConfig.Quiet = true
+	issues, err := analyzer.Run(context.Background(), runConfig)
+	if err != nil {
+		return nil, err
+	}
+	addSnippets(cfg, issues)
+
+	findings := make([]finding, 0, len(issues))
+	for _, issue := range issues {
+		if rel, err := filepath.Rel(snapshot, issue.Path); err == nil {
+			issue.Path = filepath.ToSlash(rel)
+		}
+		issue.Analyzer = name
+		policies.Apply(name, &issue)
+		if belowMinSeverity(cfg, issue) {
+			continue
+		}
+		findings = append(findings, finding{Analyzer: name, Issue: issue})
+	}
+	return findings, nil
+}
+
+// repoScanDir returns the configured scan directory relative to the top of
+// the work tree
+func repoScanDir(top, dir string) (string, error) {
+	if dir == "" {
+		return ".", nil
+	}
+	if filepath.IsAbs(dir) {
+		rel, err := filepath.Rel(top, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("scan directory %s is outside the repository", dir)
+		}
+		dir = rel
+	}
+	return filepath.Clean(dir), nil
+}
+
+// filesUnder returns, sorted, the slash-separated paths that lie under dir
+func filesUnder(paths []string, dir string) []string {
+	prefix := filepath.ToSlash(dir) + "/"
+	var files []string
+	for _, path := range paths {
+		if dir == "." || strings.HasPrefix(path, prefix) {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// archiveSnapshot extracts the given files (relative to top, the top of the
+// work tree) as of rev into dir
+func archiveSnapshot(top, rev, dir string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	cmd := exec.Command("git", append([]string{"archive", "--format=tar", rev, "--"}, files...)...)
+	cmd.Dir = top
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	extractErr := extractTar(out, dir)
+	// Drain what is left so git does not block on a full pipe
+	_, _ = io.Copy(io.Discard, out)
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git archive %s: %s", rev, strings.TrimSpace(stderr.String()))
+	}
+	return extractErr
+}
+
+// extractTar writes the regular files of a tar stream below dir; links and
+// special files are not needed for analysis and are skipped
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("unexpected path %q in archive", header.Name)
+		}
+		target := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+}
+
+// gitOutput runs git and returns its trimmed standard output
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
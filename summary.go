@@ -0,0 +1,84 @@
+package main
+
+import (
+	"time"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// analyzerRun records how one analyzer's run went, for the run summary
+type analyzerRun struct {
+	Name       string
+	Project    string
+	Stats      *analyzers.Stats
+	Elapsed    time.Duration
+	Err        error
+	OverBudget bool // Stopped at its soft budget, with the issues found until then
+}
+
+// writeRunSummary writes the summary_file: issue counts per analyzer and
+// severity (after baseline filtering, as reported), durations, file counts
+// and the gate verdict that decides the exit code. overflow holds the issues
+// left out by max_issues_per_analyzer.
+func writeRunSummary(path string, elapsed time.Duration, totalFiles int, runs []analyzerRun, findings []finding, overflow map[string]int, verdict runVerdict) error {
+	summary := models.RunSummary{
+		Timestamp:  utils.GetTimestamp(),
+		DurationMS: elapsed.Milliseconds(),
+		TotalFiles: totalFiles,
+		Issues:     len(findings),
+		BySeverity: map[string]int{},
+		Analyzers:  []models.AnalyzerRunSummary{},
+	}
+
+	index := make(map[string]int, len(runs))
+	for _, run := range runs {
+		entry := models.AnalyzerRunSummary{
+			Name:       run.Name,
+			Project:    run.Project,
+			Failed:     run.Err != nil,
+			OverBudget: run.OverBudget,
+			DurationMS: run.Elapsed.Milliseconds(),
+			BySeverity: map[string]int{},
+		}
+		if stats := run.Stats.Summary(); stats != nil {
+			entry.FilesAnalyzed = stats.FilesAnalyzed
+			entry.SkippedTooLarge = stats.SkippedTooLarge
+			entry.SkippedExcluded = stats.SkippedExcluded
+			entry.BytesRead = stats.BytesRead
+		}
+		if run.Err != nil {
+			entry.Error = run.Err.Error()
+		}
+		index[run.Project+"/"+run.Name] = len(summary.Analyzers)
+		summary.Analyzers = append(summary.Analyzers, entry)
+	}
+	summary.Gate = models.GateResult{
+		Passed:    verdict.passed(),
+		Succeeded: verdict.Succeeded,
+		Analyzers: verdict.Total,
+		Reasons:   verdict.reasons(),
+	}
+
+	for _, f := range findings {
+		summary.BySeverity[f.Issue.Severity]++
+		// Derived checks such as churn have no run of their own
+		key := f.Project + "/" + f.Analyzer
+		i, ok := index[key]
+		if !ok {
+			i = len(summary.Analyzers)
+			index[key] = i
+			summary.Analyzers = append(summary.Analyzers, models.AnalyzerRunSummary{Name: f.Analyzer, Project: f.Project, BySeverity: map[string]int{}})
+		}
+		summary.Analyzers[i].Issues++
+		summary.Analyzers[i].BySeverity[f.Issue.Severity]++
+	}
+	for key, n := range overflow {
+		if i, ok := index[key]; ok {
+			summary.Analyzers[i].IssuesOverflow = n
+		}
+	}
+
+	return utils.WriteArtifact(path, summary)
+}
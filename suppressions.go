@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"code-analyzer/baseline"
+	"code-analyzer/feedback"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+)
+
+// Sources of suppression entries
+const (
+	suppressionBaseline       = "baseline"
+	suppressionFalsePositives = "false_positives"
+)
+
+// suppressionUsage counts the issues the analyzers found per fingerprint,
+// before false positives and the baseline filter them, to tell which entries
+// still hide something
+type suppressionUsage struct {
+	found  map[string]int
+	ran    map[string]bool
+	failed map[string]bool
+}
+
+func newSuppressionUsage() *suppressionUsage {
+	return &suppressionUsage{found: map[string]int{}, ran: map[string]bool{}, failed: map[string]bool{}}
+}
+
+// Tally records the issues of one analyzer run. Entries of analyzers that
+// failed in any project are not judged: their issues may be missing.
+func (u *suppressionUsage) Tally(analyzer string, issues []models.Issue, err error) {
+	u.ran[analyzer] = true
+	if err != nil {
+		u.failed[analyzer] = true
+	}
+	for _, issue := range issues {
+		u.found[utils.Fingerprint(issue)]++
+	}
+}
+
+// checked reports whether the issues of a check were all looked for
+func (u *suppressionUsage) checked(checkName string) bool {
+	analyzer, _, ok := strings.Cut(checkName, "/")
+	if !ok {
+		analyzer = strings.TrimSuffix(checkName, "-check")
+	}
+	return u.ran[analyzer] && !u.failed[analyzer]
+}
+
+// Report sorts the entries of the baseline and the false positive store,
+// either of which may be nil, into stale and used ones. Expired baseline
+// entries no longer hide anything and are left out.
+func (u *suppressionUsage) Report(base *baseline.Baseline, falsePositives *feedback.Store) models.SuppressionReport {
+	report := models.SuppressionReport{
+		Timestamp: utils.GetTimestamp(),
+		Stale:     []models.SuppressionUsage{},
+		Used:      []models.SuppressionUsage{},
+	}
+	add := func(entry models.SuppressionUsage) {
+		if !u.checked(entry.CheckName) {
+			report.Unchecked++
+			return
+		}
+		entry.Hidden = u.found[entry.Fingerprint]
+		if entry.Hidden == 0 {
+			report.Stale = append(report.Stale, entry)
+		} else {
+			report.Used = append(report.Used, entry)
+		}
+	}
+
+	if base != nil {
+		for _, e := range base.Entries {
+			if base.Contains(e.Fingerprint) {
+				add(models.SuppressionUsage{Source: suppressionBaseline, Fingerprint: e.Fingerprint, CheckName: e.CheckName, Path: e.Path, Line: e.Line, Since: e.AddedAt})
+			}
+		}
+	}
+	if falsePositives != nil {
+		for _, e := range falsePositives.Entries {
+			add(models.SuppressionUsage{Source: suppressionFalsePositives, Fingerprint: e.Fingerprint, CheckName: e.Rule, Path: e.Path, Since: e.MarkedAt})
+		}
+	}
+
+	for _, list := range [][]models.SuppressionUsage{report.Stale, report.Used} {
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].Source != list[j].Source {
+				return list[i].Source < list[j].Source
+			}
+			if list[i].Path != list[j].Path {
+				return list[i].Path < list[j].Path
+			}
+			return list[i].Line < list[j].Line
+		})
+	}
+	return report
+}
+
+// printStaleSuppressions lists the entries that matched no issue
+func printStaleSuppressions(out io.Writer, stale []models.SuppressionUsage) {
+	if len(stale) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\n🧹 %d suppressions matched no issue and can be removed:\n", len(stale))
+	for _, s := range stale {
+		location := s.Path
+		if location == "" {
+			location = s.Fingerprint
+		} else if s.Line > 0 {
+			location = fmt.Sprintf("%s:%d", s.Path, s.Line)
+		}
+		fmt.Fprintf(out, "   %s  %s (%s)\n", location, s.CheckName, s.Source)
+	}
+}
@@ -0,0 +1,344 @@
+package triage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code-analyzer/baseline"
+	"code-analyzer/models"
+)
+
+// contextLines is the number of lines shown around an issue's line
+const contextLines = 3
+
+// file groups the issues of one path
+type file struct {
+	path   string
+	issues []int
+}
+
+// Session is an interactive triage of the issues of a new issues report:
+// browse files, read each issue with its source context, mark issues as
+// accepted and write them to the baseline
+type Session struct {
+	issues       []models.NewIssue
+	files        []file
+	base         *baseline.Baseline
+	baselinePath string
+
+	marked  map[string]bool
+	dirty   bool
+	file    int // Index into files, -1 before a file is opened
+	current int // Index into the open file's issues
+
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// NewSession creates a session over the issues of a report. Issues marked
+// are appended to the entries of base when written to baselinePath.
+func NewSession(issues []models.NewIssue, base *baseline.Baseline, baselinePath string, in io.Reader, out io.Writer) *Session {
+	s := &Session{
+		issues:       issues,
+		base:         base,
+		baselinePath: baselinePath,
+		marked:       make(map[string]bool),
+		file:         -1,
+		in:           bufio.NewScanner(in),
+		out:          out,
+	}
+
+	byPath := make(map[string]int)
+	for i, issue := range issues {
+		j, ok := byPath[issue.Path]
+		if !ok {
+			j = len(s.files)
+			byPath[issue.Path] = j
+			s.files = append(s.files, file{path: issue.Path})
+		}
+		s.files[j].issues = append(s.files[j].issues, i)
+	}
+	sort.Slice(s.files, func(i, j int) bool { return s.files[i].path < s.files[j].path })
+	for _, f := range s.files {
+		sort.SliceStable(f.issues, func(a, b int) bool { return issues[f.issues[a]].Line < issues[f.issues[b]].Line })
+	}
+	return s
+}
+
+// Run reads commands until `q` or the end of input
+func (s *Session) Run() error {
+	fmt.Fprintf(s.out, "%d issues in %d files. Type ? for help.\n", len(s.issues), len(s.files))
+	s.listFiles()
+	for {
+		fmt.Fprint(s.out, "> ")
+		if !s.in.Scan() {
+			fmt.Fprintln(s.out)
+			if s.dirty {
+				fmt.Fprintln(s.out, "Unsaved marks were discarded.")
+			}
+			return s.in.Err()
+		}
+		fields := strings.Fields(s.in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		quit, err := s.command(fields[0], fields[1:])
+		if err != nil {
+			fmt.Fprintf(s.out, "%v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// command runs one command and reports whether the session should end
+func (s *Session) command(name string, args []string) (bool, error) {
+	switch name {
+	case "?", "h", "help":
+		s.help()
+	case "f", "files":
+		s.listFiles()
+	case "o", "open":
+		n, err := number(args, len(s.files))
+		if err != nil {
+			return false, err
+		}
+		s.file, s.current = n, 0
+		s.listIssues()
+	case "l", "list":
+		if s.file < 0 {
+			return false, fmt.Errorf("no file open; use o N")
+		}
+		s.listIssues()
+	case "i", "issue":
+		if s.file < 0 {
+			return false, fmt.Errorf("no file open; use o N")
+		}
+		n, err := number(args, len(s.files[s.file].issues))
+		if err != nil {
+			return false, err
+		}
+		s.current = n
+		s.show()
+	case "s", "show":
+		return false, s.withIssue(func(int) { s.show() })
+	case "n", "next":
+		return false, s.move(1)
+	case "p", "prev":
+		return false, s.move(-1)
+	case "m", "mark":
+		return false, s.withIssue(func(i int) {
+			s.setMarked(i, true)
+			_ = s.move(1)
+		})
+	case "u", "unmark":
+		return false, s.withIssue(func(i int) { s.setMarked(i, false) })
+	case "w", "write":
+		return false, s.write()
+	case "q", "quit":
+		if s.dirty {
+			return false, fmt.Errorf("unsaved marks; w to write them, q! to quit anyway")
+		}
+		return true, nil
+	case "q!":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q; type ? for help", name)
+	}
+	return false, nil
+}
+
+func (s *Session) help() {
+	fmt.Fprint(s.out, `Commands:
+  f           list files           o N   open file N
+  l           list issues of file  i N   show issue N of the open file
+  s           show current issue   n / p next / previous issue
+  m           mark current issue as accepted (baseline) and go to the next
+  u           unmark current issue
+  w           write the baseline with the marked issues
+  q           quit                 q!    quit discarding unsaved marks
+`)
+}
+
+func (s *Session) listFiles() {
+	for i, f := range s.files {
+		marked := 0
+		for _, j := range f.issues {
+			if s.marked[s.issues[j].Fingerprint] {
+				marked++
+			}
+		}
+		fmt.Fprintf(s.out, "%4d  %-60s %4d issues", i+1, f.path, len(f.issues))
+		if marked > 0 {
+			fmt.Fprintf(s.out, ", %d marked", marked)
+		}
+		fmt.Fprintln(s.out)
+	}
+}
+
+func (s *Session) listIssues() {
+	f := s.files[s.file]
+	fmt.Fprintf(s.out, "%s\n", f.path)
+	for n, i := range f.issues {
+		issue := s.issues[i]
+		fmt.Fprintf(s.out, "%s%4d  line %-5d %-8s %s\n", s.mark(issue), n+1, issue.Line, issue.Severity, issue.Description)
+	}
+}
+
+// show prints the current issue and the source lines around it
+func (s *Session) show() {
+	issue := s.issues[s.files[s.file].issues[s.current]]
+	fmt.Fprintf(s.out, "%s[%d/%d] %s:%d %s [%s]\n  %s\n", s.mark(issue), s.current+1, len(s.files[s.file].issues),
+		issue.Path, issue.Line, issue.Severity, issue.CheckName, issue.Description)
+
+	lines, first := sourceContext(issue.Path, issue.Line)
+	if len(lines) == 0 && issue.Snippet != "" {
+		// The file is gone or moved: fall back to the snippet in the report
+		lines, first = strings.Split(strings.TrimRight(issue.Snippet, "\n"), "\n"), 0
+	}
+	for i, line := range lines {
+		if first == 0 {
+			fmt.Fprintf(s.out, "        | %s\n", line)
+			continue
+		}
+		pointer := "  "
+		if first+i == issue.Line {
+			pointer = "> "
+		}
+		fmt.Fprintf(s.out, "%s%5d | %s\n", pointer, first+i, line)
+	}
+}
+
+func (s *Session) mark(issue models.NewIssue) string {
+	if s.marked[issue.Fingerprint] {
+		return "✓"
+	}
+	return " "
+}
+
+// withIssue calls fn with the current issue's index, if a file is open
+func (s *Session) withIssue(fn func(int)) error {
+	if s.file < 0 {
+		return fmt.Errorf("no file open; use o N")
+	}
+	fn(s.files[s.file].issues[s.current])
+	return nil
+}
+
+// move goes to the next or previous issue, across files
+func (s *Session) move(delta int) error {
+	if len(s.files) == 0 {
+		return fmt.Errorf("no issues")
+	}
+	if s.file < 0 {
+		s.file, s.current = 0, 0
+		s.show()
+		return nil
+	}
+
+	s.current += delta
+	switch {
+	case s.current >= len(s.files[s.file].issues):
+		if s.file == len(s.files)-1 {
+			s.current--
+			return fmt.Errorf("last issue")
+		}
+		s.file, s.current = s.file+1, 0
+	case s.current < 0:
+		if s.file == 0 {
+			s.current = 0
+			return fmt.Errorf("first issue")
+		}
+		s.file--
+		s.current = len(s.files[s.file].issues) - 1
+	}
+	s.show()
+	return nil
+}
+
+func (s *Session) setMarked(i int, marked bool) {
+	fingerprint := s.issues[i].Fingerprint
+	if s.marked[fingerprint] != marked {
+		s.dirty = true
+	}
+	if marked {
+		s.marked[fingerprint] = true
+		fmt.Fprintln(s.out, "Marked as accepted.")
+	} else {
+		delete(s.marked, fingerprint)
+		fmt.Fprintln(s.out, "Unmarked.")
+	}
+}
+
+// write stores the baseline with the marked issues added
+func (s *Session) write() error {
+	entries := append([]baseline.Entry{}, s.base.Entries...)
+	today := baseline.Today()
+	added := 0
+	for _, issue := range s.issues {
+		if !s.marked[issue.Fingerprint] || s.base.Contains(issue.Fingerprint) {
+			continue
+		}
+		entries = append(entries, baseline.Entry{
+			Fingerprint: issue.Fingerprint,
+			CheckName:   issue.CheckName,
+			Path:        issue.Path,
+			Line:        issue.Line,
+			Description: issue.Description,
+			AddedAt:     today,
+		})
+		added++
+	}
+	if err := baseline.Write(s.baselinePath, entries); err != nil {
+		return err
+	}
+
+	// Later writes build on what was just written
+	base, err := baseline.Load(s.baselinePath)
+	if err != nil {
+		return err
+	}
+	s.base = base
+	s.dirty = false
+	fmt.Fprintf(s.out, "Baseline written: %s (%d issues added, %d total)\n", s.baselinePath, added, len(entries))
+	return nil
+}
+
+// number parses a 1-based index argument into a 0-based index below n
+func number(args []string, n int) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("expected a number")
+	}
+	i, err := strconv.Atoi(args[0])
+	if err != nil || i < 1 || i > n {
+		return 0, fmt.Errorf("expected a number from 1 to %d", n)
+	}
+	return i - 1, nil
+}
+
+// sourceContext returns the lines around line in path and the number of the
+// first one; nothing when the file cannot be read
+func sourceContext(path string, line int) ([]string, int) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0
+	}
+	defer f.Close()
+
+	first := max(1, line-contextLines)
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for n := 1; scanner.Scan() && n <= line+contextLines; n++ {
+		if n >= first {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	return lines, first
+}
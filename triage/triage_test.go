@@ -0,0 +1,52 @@
+package triage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code-analyzer/baseline"
+	"code-analyzer/models"
+)
+
+func TestSession(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "a.js")
+	if err := os.WriteFile(source, []byte("one\ntwo\nthree\nfour\nfive\nsix\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	baselinePath := filepath.Join(dir, "baseline.json")
+	if err := baseline.Write(baselinePath, []baseline.Entry{{Fingerprint: "old", AddedAt: "2026-01-01"}}); err != nil {
+		t.Fatal(err)
+	}
+	base, err := baseline.Load(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues := []models.NewIssue{
+		{Fingerprint: "b", CheckName: "js-check", Path: filepath.Join(dir, "b.js"), Line: 1, Snippet: "// gone()"},
+		{Fingerprint: "a2", CheckName: "js-check", Path: source, Line: 5},
+		{Fingerprint: "a1", CheckName: "js-check", Path: source, Line: 2},
+	}
+	in := strings.NewReader("o 1\ni 2\nm\nq\nw\nq\n")
+	var out strings.Builder
+	if err := NewSession(issues, base, baselinePath, in, &out).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{">     5 | five", "      2 | two", "unsaved marks", "1 issues added, 2 total", "| // gone()"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	written, err := baseline.Load(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written.Entries) != 2 || !written.Contains("old") || !written.Contains("a2") {
+		t.Errorf("unexpected baseline %+v", written.Entries)
+	}
+}
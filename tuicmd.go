@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"code-analyzer/baseline"
+	"code-analyzer/models"
+	"code-analyzer/triage"
+	"code-analyzer/utils"
+)
+
+// runTriage handles `code-analyzer tui` and exits: an interactive session
+// over a new issues report that writes the accepted issues to the baseline
+func runTriage(args []string) {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	configFile := fs.String("config", defaultConfigFile, "Path to YAML or JSON configuration file naming the baseline and output directory")
+	report := fs.String("report", "", "New issues report to triage (default: new-issues.json in the output directory)")
+	baselinePath := fs.String("baseline", "", "Baseline to update (overrides `baseline` in the config)")
+	_ = fs.Parse(args)
+
+	cfg, _, err := loadConfig(*configFile, false)
+	if err != nil {
+		utils.Errorf("❌ Failed to load config file: %v\n", err)
+		os.Exit(2)
+	}
+	if *baselinePath == "" {
+		*baselinePath = cfg.Baseline
+	}
+	if *baselinePath == "" {
+		utils.Errorf("❌ No baseline: set `baseline` in the config or pass --baseline\n")
+		os.Exit(2)
+	}
+	if *report == "" {
+		*report = filepath.Join(cfg.Output, "new-issues.json")
+	}
+
+	data, err := os.ReadFile(*report)
+	if err != nil {
+		utils.Errorf("❌ Failed to read report: %v\n", err)
+		os.Exit(2)
+	}
+	var delta models.NewIssuesReport
+	if err := json.Unmarshal(data, &delta); err != nil {
+		utils.Errorf("❌ Failed to parse report %s: %v\n", *report, err)
+		os.Exit(2)
+	}
+
+	base, err := baseline.Load(*baselinePath)
+	if err != nil {
+		utils.Errorf("❌ Failed to load baseline: %v\n", err)
+		os.Exit(2)
+	}
+
+	if err := triage.NewSession(delta.Issues, base, *baselinePath, os.Stdin, os.Stdout).Run(); err != nil {
+		utils.Errorf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
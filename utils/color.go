@@ -0,0 +1,52 @@
+package utils
+
+// ANSI escape sequences used for console output
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+var colorEnabled bool
+
+// SetColor turns colorized console output on or off (off by default)
+func SetColor(enabled bool) {
+	colorEnabled = enabled
+}
+
+// SeverityColor wraps text in the color of a severity: red for blocker and
+// critical, yellow for major, cyan for minor. Info and unknown severities,
+// like everything when color is off, are returned unchanged.
+func SeverityColor(severity, text string) string {
+	switch severity {
+	case "blocker":
+		return colorize(ansiBold+ansiRed, text)
+	case "critical":
+		return colorize(ansiRed, text)
+	case "major":
+		return colorize(ansiYellow, text)
+	case "minor":
+		return colorize(ansiCyan, text)
+	}
+	return text
+}
+
+// Green colors success messages
+func Green(text string) string {
+	return colorize(ansiGreen, text)
+}
+
+// Yellow colors warnings
+func Yellow(text string) string {
+	return colorize(ansiYellow, text)
+}
+
+func colorize(code, text string) string {
+	if !colorEnabled || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
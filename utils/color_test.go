@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestSeverityColor(t *testing.T) {
+	SetColor(false)
+	if got := SeverityColor("critical", "critical"); got != "critical" {
+		t.Errorf("expected plain text with color off, got %q", got)
+	}
+
+	SetColor(true)
+	defer SetColor(false)
+
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{"critical", "\033[31mx\033[0m"},
+		{"major", "\033[33mx\033[0m"},
+		{"minor", "\033[36mx\033[0m"},
+		{"info", "x"},
+		{"unknown", "x"},
+	}
+	for _, tt := range tests {
+		if got := SeverityColor(tt.severity, "x"); got != tt.want {
+			t.Errorf("SeverityColor(%q) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
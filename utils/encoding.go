@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encodings reported by DecodeText
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF8BOM = "utf-8-bom"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingLatin1  = "iso-8859-1"
+	EncodingBinary  = "binary"
+)
+
+// ReadText reads a file and decodes it to UTF-8 (see DecodeText)
+func ReadText(path string) (string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	text, encoding := DecodeText(data)
+	return text, encoding, nil
+}
+
+// DecodeText detects the charset of data and returns its UTF-8 text.
+// UTF-16 is recognized by its BOM or by the NUL pattern of ASCII text, other
+// data that is not valid UTF-8 is decoded as Latin-1. Binary data yields "".
+func DecodeText(data []byte) (string, string) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:]), EncodingUTF8BOM
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeUTF16(data[2:], false), EncodingUTF16LE
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeUTF16(data[2:], true), EncodingUTF16BE
+	}
+
+	if enc := sniffUTF16(data); enc != "" {
+		return decodeUTF16(data, enc == EncodingUTF16BE), enc
+	}
+
+	// Binary files (NUL byte in the first block) are not text
+	if bytes.IndexByte(data[:Min(8000, len(data))], 0) != -1 {
+		return "", EncodingBinary
+	}
+
+	if utf8.Valid(data) {
+		return string(data), EncodingUTF8
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(data) * 2)
+	for _, b := range data {
+		sb.WriteRune(rune(b))
+	}
+	return sb.String(), EncodingLatin1
+}
+
+// FirstInvalidUTF8Line returns the 1-based line of the first invalid UTF-8
+// sequence in data, or 0 when data is valid
+func FirstInvalidUTF8Line(data []byte) int {
+	line := 1
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size == 1 {
+			return line
+		}
+		if r == '\n' {
+			line++
+		}
+		data = data[size:]
+	}
+	return 0
+}
+
+// sniffUTF16 recognizes BOM-less UTF-16 from mostly-ASCII text, where every
+// other byte is NUL
+func sniffUTF16(data []byte) string {
+	sample := data[:Min(512, len(data))]
+	if len(sample) < 4 || len(sample)%2 != 0 {
+		return ""
+	}
+
+	evenZeros, oddZeros := 0, 0
+	for i, b := range sample {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+
+	pairs := len(sample) / 2
+	switch {
+	case oddZeros*10 >= pairs*7 && evenZeros == 0:
+		return EncodingUTF16LE
+	case evenZeros*10 >= pairs*7 && oddZeros == 0:
+		return EncodingUTF16BE
+	}
+	return ""
+}
+
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// OpenText opens a file for streaming as UTF-8 text. The encoding is detected
+// from the first block like DecodeText; data that only turns out to be invalid
+// UTF-8 after that block is passed through unchanged.
+func OpenText(path string) (io.ReadCloser, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	head, _ := reader.Peek(8000)
+
+	var src io.Reader = reader
+	encoding := EncodingUTF8
+	switch {
+	case bytes.HasPrefix(head, []byte{0xEF, 0xBB, 0xBF}):
+		reader.Discard(3)
+		encoding = EncodingUTF8BOM
+	case bytes.HasPrefix(head, []byte{0xFF, 0xFE}):
+		reader.Discard(2)
+		src, encoding = &utf16Reader{src: reader}, EncodingUTF16LE
+	case bytes.HasPrefix(head, []byte{0xFE, 0xFF}):
+		reader.Discard(2)
+		src, encoding = &utf16Reader{src: reader, bigEndian: true}, EncodingUTF16BE
+	default:
+		if enc := sniffUTF16(head); enc != "" {
+			src, encoding = &utf16Reader{src: reader, bigEndian: enc == EncodingUTF16BE}, enc
+		} else if bytes.IndexByte(head, 0) != -1 {
+			encoding = EncodingBinary
+		} else if !utf8.Valid(trimPartialRune(head)) {
+			src, encoding = &latin1Reader{src: reader}, EncodingLatin1
+		}
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{src, file}, encoding, nil
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence cut off at the end of a block
+func trimPartialRune(b []byte) []byte {
+	for i := 1; i <= utf8.UTFMax && i <= len(b); i++ {
+		if utf8.RuneStart(b[len(b)-i]) {
+			if !utf8.FullRune(b[len(b)-i:]) {
+				return b[:len(b)-i]
+			}
+			break
+		}
+	}
+	return b
+}
+
+// utf16Reader decodes a UTF-16 stream to UTF-8
+type utf16Reader struct {
+	src       io.Reader
+	bigEndian bool
+	raw       []byte // undecoded bytes: an odd byte or a split surrogate pair
+	out       []byte // decoded bytes not yet returned
+	err       error
+}
+
+func (r *utf16Reader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		buf := make([]byte, 32*1024)
+		n, err := r.src.Read(buf)
+		r.raw = append(r.raw, buf[:n]...)
+		r.err = err
+
+		// Keep an odd trailing byte and a trailing high surrogate for the next read
+		usable := len(r.raw) &^ 1
+		if err == nil && usable >= 2 {
+			last := r.unit(usable - 2)
+			if last >= 0xD800 && last < 0xDC00 {
+				usable -= 2
+			}
+		}
+
+		units := make([]uint16, usable/2)
+		for i := range units {
+			units[i] = r.unit(2 * i)
+		}
+		r.out = []byte(string(utf16.Decode(units)))
+		r.raw = append(r.raw[:0], r.raw[usable:]...)
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *utf16Reader) unit(i int) uint16 {
+	if r.bigEndian {
+		return uint16(r.raw[i])<<8 | uint16(r.raw[i+1])
+	}
+	return uint16(r.raw[i+1])<<8 | uint16(r.raw[i])
+}
+
+// latin1Reader decodes an ISO-8859-1 stream to UTF-8
+type latin1Reader struct {
+	src io.Reader
+	out []byte
+}
+
+func (r *latin1Reader) Read(p []byte) (int, error) {
+	if len(r.out) == 0 {
+		buf := make([]byte, 32*1024)
+		n, err := r.src.Read(buf)
+		for _, b := range buf[:n] {
+			r.out = utf8.AppendRune(r.out, rune(b))
+		}
+		if n == 0 {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		text     string
+		encoding string
+	}{
+		{
+			name:     "UTF-8",
+			data:     []byte("café\n"),
+			text:     "café\n",
+			encoding: EncodingUTF8,
+		},
+		{
+			name:     "UTF-8 with BOM",
+			data:     []byte("\xEF\xBB\xBFa = 1;\n"),
+			text:     "a = 1;\n",
+			encoding: EncodingUTF8BOM,
+		},
+		{
+			name:     "UTF-16LE with BOM",
+			data:     []byte{0xFF, 0xFE, 'h', 0, 'i', 0, '\n', 0},
+			text:     "hi\n",
+			encoding: EncodingUTF16LE,
+		},
+		{
+			name:     "UTF-16BE without BOM",
+			data:     []byte{0, '<', 0, 'p', 0, '>', 0, '\n'},
+			text:     "<p>\n",
+			encoding: EncodingUTF16BE,
+		},
+		{
+			name:     "Latin-1",
+			data:     []byte("caf\xE9\n"),
+			text:     "café\n",
+			encoding: EncodingLatin1,
+		},
+		{
+			name:     "Binary",
+			data:     []byte{0x89, 'P', 'N', 'G', 0, 0, 0, 0x0D, 1},
+			text:     "",
+			encoding: EncodingBinary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, encoding := DecodeText(tt.data)
+			if encoding != tt.encoding {
+				t.Errorf("expected encoding %s, got %s", tt.encoding, encoding)
+			}
+			if text != tt.text {
+				t.Errorf("expected text %q, got %q", tt.text, text)
+			}
+		})
+	}
+}
+
+func TestFirstInvalidUTF8Line(t *testing.T) {
+	if line := FirstInvalidUTF8Line([]byte("a\nb\nc\xE9\n")); line != 3 {
+		t.Errorf("expected line 3, got %d", line)
+	}
+	if line := FirstInvalidUTF8Line([]byte("café\n")); line != 0 {
+		t.Errorf("expected 0 for valid UTF-8, got %d", line)
+	}
+}
+
+func TestOpenText(t *testing.T) {
+	// Long enough to cross the decoders' read chunks, with a surrogate pair
+	text := strings.Repeat("line 😀 café\n", 10000)
+
+	utf16le := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(text)) {
+		utf16le = append(utf16le, byte(u), byte(u>>8))
+	}
+	latin1 := []byte(strings.Repeat("caf\xE9\n", 10000))
+
+	tests := []struct {
+		name     string
+		data     []byte
+		text     string
+		encoding string
+	}{
+		{"UTF-8", []byte(text), text, EncodingUTF8},
+		{"UTF-8 with BOM", append([]byte("\xEF\xBB\xBF"), text...), text, EncodingUTF8BOM},
+		{"UTF-16LE", utf16le, text, EncodingUTF16LE},
+		{"Latin-1", latin1, strings.Repeat("café\n", 10000), EncodingLatin1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file.txt")
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			r, encoding, err := OpenText(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if encoding != tt.encoding {
+				t.Errorf("expected encoding %s, got %s", tt.encoding, encoding)
+			}
+			if string(got) != tt.text {
+				t.Errorf("decoded text differs (got %d bytes, expected %d)", len(got), len(tt.text))
+			}
+		})
+	}
+}
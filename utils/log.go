@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Log formats for messages written to stderr
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+var (
+	logOut  io.Writer = os.Stderr
+	jsonLog *slog.Logger
+)
+
+// SetLogFormat selects how warnings, errors and run statistics are written to
+// w. JSON logs are one object per line with time, level and msg keys.
+func SetLogFormat(format string, w io.Writer) error {
+	switch format {
+	case LogFormatText:
+		jsonLog = nil
+	case LogFormatJSON:
+		jsonLog = slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	default:
+		return fmt.Errorf("unknown log format %q (expected %q or %q)", format, LogFormatText, LogFormatJSON)
+	}
+	logOut = w
+	return nil
+}
+
+// JSONLogging reports whether logs are structured
+func JSONLogging() bool {
+	return jsonLog != nil
+}
+
+// Errorf logs an error; text messages are printed exactly as formatted
+func Errorf(format string, args ...interface{}) {
+	logf(slog.LevelError, format, args...)
+}
+
+// Warnf logs a warning; text messages are printed exactly as formatted
+func Warnf(format string, args ...interface{}) {
+	logf(slog.LevelWarn, format, args...)
+}
+
+// Infof logs an informational message; text messages are printed exactly as formatted
+func Infof(format string, args ...interface{}) {
+	logf(slog.LevelInfo, format, args...)
+}
+
+// LogAttrs writes a structured record in JSON mode. Text mode drops it: the
+// console tables already show the same information to humans.
+func LogAttrs(level slog.Level, msg string, attrs ...slog.Attr) {
+	if jsonLog == nil {
+		return
+	}
+	jsonLog.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func logf(level slog.Level, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if jsonLog == nil {
+		fmt.Fprint(logOut, msg)
+		return
+	}
+	jsonLog.Log(context.Background(), level, plainMessage(msg))
+}
+
+// plainMessage drops the decorations of console messages (surrounding blank
+// lines, leading emoji, "Warning:") that the JSON level already conveys
+func plainMessage(msg string) string {
+	msg = strings.TrimLeftFunc(strings.TrimSpace(msg), func(r rune) bool {
+		return r > unicode.MaxASCII || unicode.IsSpace(r)
+	})
+	return strings.TrimPrefix(msg, "Warning: ")
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// resetLogFormat restores the default text logging after a test
+func resetLogFormat() {
+	_ = SetLogFormat(LogFormatText, os.Stderr)
+}
+
+func TestSetLogFormat_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SetLogFormat(LogFormatJSON, &buf); err != nil {
+		t.Fatal(err)
+	}
+	defer resetLogFormat()
+
+	Warnf("\n⚠️  %d rule failures were recovered during analysis\n", 2)
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a JSON line, got %q: %v", buf.String(), err)
+	}
+	if record["level"] != "WARN" || record["msg"] != "2 rule failures were recovered during analysis" {
+		t.Errorf("unexpected record %v", record)
+	}
+}
+
+func TestSetLogFormat_Text(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SetLogFormat(LogFormatText, &buf); err != nil {
+		t.Fatal(err)
+	}
+	defer resetLogFormat()
+
+	Errorf("❌ Failed: %v\n", "boom")
+	LogAttrs(0, "dropped in text mode")
+
+	if got := buf.String(); got != "❌ Failed: boom\n" {
+		t.Errorf("text messages must be printed as formatted, got %q", got)
+	}
+}
+
+func TestSetLogFormat_Unknown(t *testing.T) {
+	if err := SetLogFormat("xml", os.Stderr); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestPlainMessage(t *testing.T) {
+	tests := map[string]string{
+		"❌ Failed to load config file: x\n":         "Failed to load config file: x",
+		"Warning: Failed to generate artifact: x\n": "Failed to generate artifact: x",
+		"No enabled analyzers found in config\n":    "No enabled analyzers found in config",
+	}
+	for in, want := range tests {
+		if got := plainMessage(in); got != want {
+			t.Errorf("plainMessage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"code-analyzer/models"
+)
+
+// DefaultSnippetLines is the number of lines shown before and after the
+// line of an issue in its snippet
+const DefaultSnippetLines = 2
+
+// maxSnippetLineLength keeps minified code from bloating the reports
+const maxSnippetLineLength = 200
+
+// AddSnippets fills in the Snippet of each issue that has a line with that
+// line and up to context lines around it. Each file is read once; issues of
+// unreadable files keep an empty snippet.
+func AddSnippets(issues []models.Issue, context int) {
+	byPath := make(map[string][]int)
+	for i, issue := range issues {
+		if issue.Line > 0 {
+			byPath[issue.Path] = append(byPath[issue.Path], i)
+		}
+	}
+
+	for path, indexes := range byPath {
+		text, _, err := ReadText(path)
+		if err != nil || text == "" {
+			continue
+		}
+		lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+		for _, i := range indexes {
+			issues[i].Snippet = snippet(lines, issues[i].Line, context)
+		}
+	}
+}
+
+// snippet returns the lines around the 1-based line
+func snippet(lines []string, line, context int) string {
+	if line > len(lines) {
+		return ""
+	}
+	first := max(1, line-context)
+	last := min(len(lines), line+context)
+	selected := make([]string, 0, last-first+1)
+	for _, l := range lines[first-1 : last] {
+		if len(l) > maxSnippetLineLength {
+			cut := maxSnippetLineLength
+			for cut > 0 && !utf8.RuneStart(l[cut]) {
+				cut--
+			}
+			l = l[:cut] + "..."
+		}
+		selected = append(selected, l)
+	}
+	return strings.TrimRight(strings.Join(selected, "\n"), "\n")
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestAddSnippets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.js")
+	long := strings.Repeat("é", 150)
+	if err := os.WriteFile(path, []byte("one\r\ntwo\r\nthree\r\nfour\r\n"+long+"\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := []models.Issue{
+		{Path: path, Line: 1},
+		{Path: path, Line: 4},
+		{Path: path, Line: 0},
+		{Path: path, Line: 9},
+		{Path: filepath.Join(filepath.Dir(path), "missing.js"), Line: 1},
+	}
+	AddSnippets(issues, 1)
+
+	want := []string{"one\ntwo", "three\nfour\n" + strings.Repeat("é", 100) + "...", "", "", ""}
+	for i, issue := range issues {
+		if issue.Snippet != want[i] {
+			t.Errorf("issue %d: got %q, want %q", i, issue.Snippet, want[i])
+		}
+	}
+}
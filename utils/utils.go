@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"bufio"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"code-analyzer/models"
 )
 
 // FormatBytes formats bytes into human-readable format
@@ -69,7 +75,8 @@ func ShouldSkip(path string, customExcludes []string) bool {
 
 }
 
-// WriteArtifact writes an artifact to JSON file
+// WriteArtifact writes an artifact to JSON file, gzip-compressed when the
+// path ends in .gz
 func WriteArtifact(outputPath string, report interface{}) error {
 	dir := filepath.Dir(outputPath)
 	if dir != "." && dir != "" {
@@ -84,11 +91,50 @@ func WriteArtifact(outputPath string, report interface{}) error {
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(report); err != nil {
+	if !strings.HasSuffix(outputPath, ".gz") {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %v", err)
+		}
+		return nil
+	}
+
+	// Compressed artifacts are not read by people, so skip the indentation
+	gz := gzip.NewWriter(file)
+	if err := json.NewEncoder(gz).Encode(report); err != nil {
 		return fmt.Errorf("failed to encode JSON: %v", err)
 	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress artifact: %v", err)
+	}
+	return file.Close()
+}
+
+// Fingerprint returns the stable identifier of an issue used by reports and baselines
+func Fingerprint(issue models.Issue) string {
+	hashContent := fmt.Sprintf("%s:%d:%s", issue.Description, issue.Line, issue.Path)
+	hasher := md5.New()
+	hasher.Write([]byte(hashContent))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
 
-	return nil
+// ReadLine returns the trimmed content of a 1-based line in a file, or "" if unavailable
+func ReadLine(path string, line int) string {
+	if line < 1 {
+		return ""
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		if n == line {
+			return strings.TrimSpace(scanner.Text())
+		}
+	}
+	return ""
 }
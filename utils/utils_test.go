@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteArtifact_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "php", "php-analysis.json.gz")
+	if err := WriteArtifact(path, map[string]int{"total_files": 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("artifact is not gzip-compressed: %v", err)
+	}
+	var report map[string]int
+	if err := json.NewDecoder(gz).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if report["total_files"] != 3 {
+		t.Errorf("unexpected report %v", report)
+	}
+}
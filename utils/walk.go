@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// hardLinkDedup makes Walk visit hard-linked files once
+var hardLinkDedup = true
+
+// SetHardLinkDedup turns deduplication of hard-linked files on or off (on by
+// default). Turn it off on filesystems without stable inode numbers, where
+// distinct files could be mistaken for links of each other.
+func SetHardLinkDedup(enabled bool) {
+	hardLinkDedup = enabled
+}
+
+// Walk walks the file tree rooted at root like filepath.Walk, in lexical order.
+// Symlinks are skipped unless follow is set. Followed links are resolved and
+// every real file or directory is visited once, so link cycles terminate and
+// linked trees are not analyzed twice. Paths keep the link location.
+// Hard-linked files are likewise visited once, at their first path in
+// lexical order, unless disabled with SetHardLinkDedup.
+func Walk(root string, follow bool, fn filepath.WalkFunc) error {
+	return WalkContext(context.Background(), root, follow, fn)
+}
+
+// WalkContext is Walk that stops with the context's error once ctx is done
+func WalkContext(ctx context.Context, root string, follow bool, fn filepath.WalkFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+
+	w := &walker{ctx: ctx, follow: follow, fn: fn, seen: make(map[fileID]bool)}
+	err = w.walk(root, info, false)
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+type walker struct {
+	ctx    context.Context
+	follow bool
+	fn     filepath.WalkFunc
+	// seen maps the real files and directories visited to whether they were
+	// first reached through a symlink
+	seen map[fileID]bool
+}
+
+// visited records a real file and reports whether it was seen before.
+// Directories and link targets are tracked when following links, so cycles
+// terminate and linked files are not repeated. Other files are deduplicated
+// only as hard links, unless SetHardLinkDedup turned that off; when
+// following links they are still recorded, so a link to them is skipped.
+func (w *walker) visited(info os.FileInfo, viaLink bool) bool {
+	dedupHardLink := hardLinkDedup && !info.IsDir() && hardLinked(info)
+	if !w.follow && !dedupHardLink {
+		return false
+	}
+	id, ok := idOf(info)
+	if !ok {
+		return false
+	}
+	if linked, seen := w.seen[id]; seen {
+		return info.IsDir() || viaLink || linked || dedupHardLink
+	}
+	w.seen[id] = viaLink
+	return false
+}
+
+func (w *walker) walk(path string, info os.FileInfo, viaLink bool) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	if w.visited(info, viaLink) {
+		return nil
+	}
+	if !info.IsDir() {
+		return w.fn(path, info, nil)
+	}
+
+	if err := w.fn(path, info, nil); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if err := w.fn(path, info, err); err != nil && err != filepath.SkipDir {
+			return err
+		}
+		return nil
+	}
+
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+
+		var childInfo os.FileInfo
+		link := entry.Type()&os.ModeSymlink != 0
+		if link {
+			if !w.follow {
+				continue
+			}
+			childInfo, err = os.Stat(child)
+		} else {
+			childInfo, err = entry.Info()
+		}
+		if err != nil {
+			if err := w.fn(child, nil, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+
+		if err := w.walk(child, childInfo, link); err != nil {
+			if err == filepath.SkipDir {
+				if childInfo.IsDir() {
+					continue
+				}
+				// SkipDir on a file skips the rest of its directory
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
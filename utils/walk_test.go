@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestWalk_Symlinks(t *testing.T) {
+	root := t.TempDir()
+	mustWrite := func(path string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite(filepath.Join(root, "src", "app.js"))
+	mustWrite(filepath.Join(root, "outside", "lib.js"))
+
+	links := map[string]string{
+		filepath.Join(root, "src", "loop"):    filepath.Join(root, "src"),
+		filepath.Join(root, "src", "copy.js"): filepath.Join(root, "src", "app.js"),
+		filepath.Join(root, "vendor"):         filepath.Join(root, "outside"),
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	files := func(follow bool) []string {
+		var out []string
+		err := Walk(root, follow, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			rel, _ := filepath.Rel(root, path)
+			out = append(out, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Walk failed: %v", err)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	if got := files(false); len(got) != 2 || got[0] != "outside/lib.js" || got[1] != "src/app.js" {
+		t.Errorf("without following, expected only real files, got %v", got)
+	}
+
+	// Each real file once: the loop terminates and the linked copy is not repeated
+	if got := files(true); len(got) != 2 {
+		t.Errorf("with following, expected each real file once, got %v", got)
+	}
+}
+
+func TestWalk_HardLinks(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"src", "vendor"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	original := filepath.Join(root, "vendor", "lib.js")
+	if err := os.WriteFile(original, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(original, filepath.Join(root, "src", "lib.js")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "app.js"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := func(follow bool) []string {
+		var out []string
+		_ = Walk(root, follow, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				rel, _ := filepath.Rel(root, path)
+				out = append(out, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		return out
+	}
+
+	// The first path in lexical order wins
+	for _, follow := range []bool{false, true} {
+		if got := files(follow); len(got) != 2 || got[0] != "src/app.js" || got[1] != "src/lib.js" {
+			t.Errorf("follow %v: expected the hard-linked file once, got %v", follow, got)
+		}
+	}
+
+	// A symlink to a file already visited is still skipped when following
+	if err := os.Symlink(original, filepath.Join(root, "vendor", "link.js")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	SetHardLinkDedup(false)
+	defer SetHardLinkDedup(true)
+	for _, follow := range []bool{false, true} {
+		if got := files(follow); len(got) != 3 {
+			t.Errorf("follow %v: without deduplication, expected every hard-linked path, got %v", follow, got)
+		}
+	}
+}
+
+func TestWalkContext_StopsWhenCancelled(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var visited []string
+	err := WalkContext(ctx, root, false, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		visited = append(visited, filepath.Base(path))
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(visited) != 1 || visited[0] != "a.txt" {
+		t.Errorf("expected the walk to stop after a.txt, visited %v", visited)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package utils
+
+import "os"
+
+// fileID identifies a real file independently of the path it was reached by
+type fileID struct{}
+
+// idOf is unavailable on Windows, where links are walked without deduplication
+func idOf(info os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
+
+func hardLinked(info os.FileInfo) bool {
+	return false
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"code-analyzer/analyzers"
+	"code-analyzer/churn"
+	"code-analyzer/config"
+	"code-analyzer/models"
+	"code-analyzer/policy"
+	"code-analyzer/severity"
+	"code-analyzer/utils"
+)
+
+// sortKeys lists the `sort` values of the analyzers that sort their results
+var sortKeys = map[string][]string{
+	"html": {"ratio", "bytes"},
+	"js":   {"ratio", "bytes"},
+	"php":  {"ratio", "bytes", "functions"},
+	"size": {"lines", "bytes"},
+}
+
+// categories are the issue categories policies can match
+var categories = []string{
+	models.CategoryDeadCode,
+	models.CategoryBugRisk,
+	models.CategorySecurity,
+	models.CategoryComplexity,
+	models.CategoryStyle,
+	models.CategoryCompliance,
+	models.CategoryMaintainability,
+	models.CategoryPerformance,
+}
+
+// runValidateConfig checks a config file without running any analyzer.
+// It exits 1 when the config has errors and 2 when it cannot be read.
+func runValidateConfig(args []string) {
+	fs := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	configFile := fs.String("config", defaultConfigFile, "Path or http(s) URL of the YAML or JSON configuration file (\"-\" reads stdin)")
+	_ = fs.Parse(args)
+
+	data, err := config.Read(*configFile)
+	if err != nil {
+		utils.Errorf("❌ Failed to read config file: %v\n", err)
+		os.Exit(2)
+	}
+	cfg, err := config.ParseConfig(data)
+	if err != nil {
+		utils.Errorf("❌ %s: %v\n", *configFile, err)
+		os.Exit(1)
+	}
+
+	problems, err := config.UnknownKeys(data)
+	if err != nil {
+		utils.Errorf("❌ %s: %v\n", *configFile, err)
+		os.Exit(1)
+	}
+	problems = append(problems, config.Check(cfg)...)
+	problems = append(problems, checkAnalyzerSettings(cfg, newAnalyzers())...)
+	lines := config.KeyLines(data)
+	for i := range problems {
+		if problems[i].Line == 0 {
+			problems[i].Line = lines[problems[i].Key]
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	errors := 0
+	for _, p := range problems {
+		location := *configFile
+		if p.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, p.Line)
+		}
+		if p.Warning {
+			fmt.Printf("%s: ⚠️  warning: %s\n", location, p)
+		} else {
+			errors++
+			fmt.Printf("%s: ❌ error: %s\n", location, p)
+		}
+	}
+	warnings := len(problems) - errors
+
+	if errors > 0 {
+		fmt.Printf("\n❌ %s is invalid: %d errors, %d warnings\n", *configFile, errors, warnings)
+		os.Exit(1)
+	}
+	if warnings > 0 {
+		fmt.Printf("\n✅ %s is valid, with %d warnings\n", *configFile, warnings)
+		return
+	}
+	fmt.Printf("✅ %s is valid\n", *configFile)
+}
+
+// checkAnalyzerSettings reports settings that name analyzers, rules or
+// sort keys that do not exist, and policies, severities and projects that
+// would fail the run
+func checkAnalyzerSettings(cfg *config.AppConfig, all map[string]analyzers.Analyzer) []config.Problem {
+	var problems []config.Problem
+	add := func(key, format string, args ...interface{}) {
+		problems = append(problems, config.Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	names := make([]string, 0, len(cfg.Analyzers))
+	for name := range cfg.Analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key := "analyzers." + name
+		if _, ok := all[name]; !ok {
+			add(key, "unknown analyzer %q", name)
+			continue
+		}
+		if value := cfg.Analyzers[name].Sort; value != "" {
+			if keys, ok := sortKeys[name]; !ok {
+				problems = append(problems, config.Problem{Key: key + ".sort", Message: "the analyzer does not sort its results", Warning: true})
+			} else if !slices.Contains(keys, value) {
+				add(key+".sort", "%q is not one of %v", value, keys)
+			}
+		}
+	}
+
+	rules := []string{churn.RuleChurnedFile}
+	analyzerNames := []string{churn.Analyzer}
+	for name, analyzer := range all {
+		analyzerNames = append(analyzerNames, name)
+		if lister, ok := analyzer.(analyzers.RuleLister); ok {
+			rules = append(rules, lister.RuleIDs()...)
+		}
+	}
+	for i, expression := range cfg.Policies {
+		key := fmt.Sprintf("policies[%d]", i)
+		rule, err := policy.Parse(expression)
+		if err != nil {
+			add(key, "%v", err)
+			continue
+		}
+		// Conditions on names that do not exist never match
+		for _, check := range []struct {
+			field string
+			known []string
+		}{{"analyzer", analyzerNames}, {"rule", rules}, {"category", categories}} {
+			for _, value := range rule.Values(check.field) {
+				if !slices.Contains(check.known, value) {
+					add(key, "unknown %s %q", check.field, value)
+				}
+			}
+		}
+	}
+
+	docRules := make([]string, 0, len(cfg.RuleDocs.Rules))
+	for rule := range cfg.RuleDocs.Rules {
+		docRules = append(docRules, rule)
+	}
+	sort.Strings(docRules)
+	for _, rule := range docRules {
+		key := "rule_docs.rules." + rule
+		if !slices.Contains(rules, rule) {
+			add(key, "unknown rule %q", rule)
+		}
+		if !config.IsRemote(cfg.RuleDocs.Rules[rule]) {
+			add(key, "%q is not an http(s) URL", cfg.RuleDocs.Rules[rule])
+		}
+	}
+	if base := cfg.RuleDocs.BaseURL; base != "" {
+		if !config.IsRemote(base) {
+			add("rule_docs.base_url", "%q is not an http(s) URL", base)
+		} else if !strings.Contains(base, ruleDocsPlaceholder) {
+			problems = append(problems, config.Problem{Key: "rule_docs.base_url", Message: "no " + ruleDocsPlaceholder + " placeholder: every rule links to the same page", Warning: true})
+		}
+	}
+
+	if _, err := severity.NewMapping(cfg.Severities.Aliases, map[string]map[string]string{
+		severity.GitLab:    cfg.Severities.GitLab,
+		severity.Bitbucket: cfg.Severities.Bitbucket,
+		severity.Azure:     cfg.Severities.Azure,
+		severity.Plain:     cfg.Severities.Plain,
+		severity.SARIF:     cfg.Severities.SARIF,
+	}); err != nil {
+		add("severities", "%v", err)
+	}
+	if cfg.MinSeverity != "" && !severity.Valid(cfg.MinSeverity) {
+		add("min_severity", "%q is not one of %v", cfg.MinSeverity, severity.Order)
+	}
+	if _, err := policy.NewTestDowngrade(cfg.TestPaths.Patterns); err != nil {
+		add("test_paths", "%v", err)
+	}
+	if _, err := resolveProjects(cfg, all); err != nil {
+		add("projects", "%v", err)
+	}
+	return problems
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// runVerdict is the outcome of a run that decides its exit code. The console
+// summaries, the findings report, notifications and Code Insights all report
+// it, so none of them can pass a run that exits 1.
+type runVerdict struct {
+	Succeeded int
+	Total     int
+	// RatchetFailed is set when counts went up against the committed ratchet
+	RatchetFailed bool
+	// AllowlistExpired is set when an expired allowlist entry still matches
+	AllowlistExpired bool
+}
+
+// passed reports whether the run succeeds
+func (v runVerdict) passed() bool {
+	return v.Succeeded == v.Total && len(v.reasons()) == 0
+}
+
+// reasons lists what fails the run besides failed analyzers
+func (v runVerdict) reasons() []string {
+	var reasons []string
+	if v.RatchetFailed {
+		reasons = append(reasons, "ratchet counts went up")
+	}
+	if v.AllowlistExpired {
+		reasons = append(reasons, "allowlist entries expired")
+	}
+	return reasons
+}
+
+// describe sums up the run in one line, e.g.
+// "3/3 analyzers succeeded, 12 issues (ratchet counts went up)"
+func (v runVerdict) describe(issues int) string {
+	line := fmt.Sprintf("%d/%d analyzers succeeded, %d issues", v.Succeeded, v.Total, issues)
+	if reasons := v.reasons(); len(reasons) > 0 {
+		line += " (" + strings.Join(reasons, ", ") + ")"
+	}
+	return line
+}
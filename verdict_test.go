@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"code-analyzer/models"
+)
+
+func TestRunVerdict(t *testing.T) {
+	findings := []finding{{Analyzer: "php", Issue: models.Issue{Path: "app/User.php", Line: 4, Severity: "major"}}}
+
+	tests := []struct {
+		name    string
+		verdict runVerdict
+		passed  bool
+		reasons []string
+	}{
+		{"every analyzer succeeded", runVerdict{Succeeded: 2, Total: 2}, true, nil},
+		{"an analyzer failed", runVerdict{Succeeded: 1, Total: 2}, false, nil},
+		{"ratchet counts went up", runVerdict{Succeeded: 2, Total: 2, RatchetFailed: true}, false, []string{"ratchet counts went up"}},
+		{"allowlist entry expired", runVerdict{Succeeded: 2, Total: 2, AllowlistExpired: true}, false, []string{"allowlist entries expired"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.verdict.passed(); got != tt.passed {
+				t.Errorf("expected passed %v, got %v", tt.passed, got)
+			}
+
+			var summary bytes.Buffer
+			printSummary(&summary, []string{"php"}, findings, tt.verdict)
+			want := "PASSED: "
+			if !tt.passed {
+				want = "FAILED: "
+			}
+			if !strings.Contains(summary.String(), want) {
+				t.Errorf("expected summary to contain %q, got:\n%s", want, summary.String())
+			}
+
+			var quiet bytes.Buffer
+			printQuietSummary(&quiet, tt.verdict, findings)
+			if strings.HasPrefix(quiet.String(), "✅") != tt.passed {
+				t.Errorf("quiet summary disagrees with the verdict: %q", quiet.String())
+			}
+
+			gate := buildFindingsReport(findings, false, tt.verdict).Gate
+			if gate.Passed != tt.passed {
+				t.Errorf("expected gate passed %v, got %v", tt.passed, gate.Passed)
+			}
+			if !reflect.DeepEqual(gate.Reasons, tt.reasons) {
+				t.Errorf("expected gate reasons %v, got %v", tt.reasons, gate.Reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"code-analyzer/config"
+)
+
+// applyVerifyMode turns off everything that writes to disk or publishes
+// results, so only the gate remains: analyzer artifacts, reports, history,
+// deliveries, crash reporting and conflict prediction (git merge-tree writes
+// objects into the repository). The baseline is still read.
+func applyVerifyMode(cfg *config.AppConfig) {
+	cfg.Output = ""
+	cfg.GitLabReport = ""
+	cfg.HTMLReport = ""
+	cfg.SummaryFile = ""
+	cfg.CSVReport = ""
+	cfg.SARIFReport = ""
+	cfg.SuppressionReport = ""
+	cfg.History = ""
+	cfg.Debt.Path = ""
+	cfg.CodeOwners.Artifacts = ""
+	cfg.MRComment = config.MRCommentConfig{}
+	cfg.Heatmap = config.HeatmapConfig{}
+	cfg.Hotspots = config.HotspotsConfig{}
+	cfg.Webhook = config.WebhookConfig{}
+	cfg.Notifications = nil
+	cfg.BitbucketInsights.Enabled = false
+	cfg.CrashReporting.Enabled = false
+
+	for name, analyzerCfg := range cfg.Analyzers {
+		analyzerCfg.TargetBranch = ""
+		cfg.Analyzers[name] = analyzerCfg
+	}
+}
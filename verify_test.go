@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"code-analyzer/config"
+)
+
+func TestApplyVerifyMode(t *testing.T) {
+	cfg := &config.AppConfig{
+		Output:            "artifacts",
+		GitLabReport:      "gl-code-quality-report.json",
+		HTMLReport:        "report.html",
+		SummaryFile:       "summary.json",
+		CSVReport:         "issues.csv",
+		SARIFReport:       "issues.sarif",
+		SuppressionReport: "suppressions.json",
+		History:           "history.db",
+		Debt:              config.DebtConfig{Enabled: true, Path: "debt.json"},
+		CodeOwners:        config.CodeOwnersConfig{Enabled: true, Artifacts: "owners"},
+		MRComment:         config.MRCommentConfig{Path: "mr-comment.md", ReportURL: "https://ci.example.com/report"},
+		Heatmap:           config.HeatmapConfig{Path: "heatmap.json", SVG: "heatmap.svg", Depth: 2},
+		Hotspots:          config.HotspotsConfig{Enabled: true, Since: "1 month ago", Files: 5, Path: "hotspots.json"},
+		Webhook:           config.WebhookConfig{URL: "https://quality.example.com/ingest", Secret: "s3cret", Retries: 1},
+		Notifications:     []config.NotificationConfig{{Type: "slack", WebhookURL: "https://hooks.example.com/x"}},
+		BitbucketInsights: config.BitbucketInsightsConfig{Enabled: true, ReportID: "code-analyzer"},
+		CrashReporting:    config.CrashReportingConfig{Enabled: true, Endpoint: "https://tooling.example.com/health"},
+		Baseline:          "baseline.json",
+		Analyzers: map[string]config.AnalyzerConfig{
+			"conflicts": {Enabled: true, TargetBranch: "origin/main"},
+			"php":       {Enabled: true},
+		},
+	}
+
+	applyVerifyMode(cfg)
+
+	cleared := map[string]interface{}{
+		"output":                     cfg.Output,
+		"gitlab_report":              cfg.GitLabReport,
+		"html_report":                cfg.HTMLReport,
+		"summary_file":               cfg.SummaryFile,
+		"csv_report":                 cfg.CSVReport,
+		"sarif_report":               cfg.SARIFReport,
+		"suppression_report":         cfg.SuppressionReport,
+		"history":                    cfg.History,
+		"debt.path":                  cfg.Debt.Path,
+		"codeowners.artifacts":       cfg.CodeOwners.Artifacts,
+		"mr_comment":                 cfg.MRComment,
+		"heatmap":                    cfg.Heatmap,
+		"hotspots":                   cfg.Hotspots,
+		"webhook":                    cfg.Webhook,
+		"notifications":              cfg.Notifications,
+		"bitbucket_insights.enabled": cfg.BitbucketInsights.Enabled,
+		"crash_reporting.enabled":    cfg.CrashReporting.Enabled,
+		"conflicts.target_branch":    cfg.Analyzers["conflicts"].TargetBranch,
+	}
+	for name, value := range cleared {
+		if v := reflect.ValueOf(value); v.IsValid() && !v.IsZero() {
+			t.Errorf("expected %s to be cleared, got %+v", name, value)
+		}
+	}
+
+	// The gate still reads the baseline and runs the analyzers
+	if cfg.Baseline != "baseline.json" {
+		t.Errorf("expected the baseline to be kept, got %q", cfg.Baseline)
+	}
+	if !cfg.Analyzers["conflicts"].Enabled || !cfg.Analyzers["php"].Enabled {
+		t.Errorf("expected the analyzers to stay enabled, got %+v", cfg.Analyzers)
+	}
+}
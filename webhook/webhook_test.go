@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	body := []byte(`{"issues":[]}`)
+
+	tests := []struct {
+		name     string
+		statuses []int
+		wantErr  bool
+		attempts int
+	}{
+		{"delivered", []int{200}, false, 1},
+		{"retried after server errors", []int{502, 503, 204}, false, 3},
+		{"rate limit is retried", []int{429, 200}, false, 2},
+		{"client error is not retried", []int{400}, true, 1},
+		{"gives up after retries", []int{500, 500, 500}, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				got, _ := io.ReadAll(req.Body)
+				if sig := req.Header.Get(SignatureHeader); sig != Sign("s3cret", got) {
+					t.Errorf("bad signature %q", sig)
+				}
+				if req.Header.Get(EventHeader) != "report" {
+					t.Errorf("missing event header")
+				}
+				w.WriteHeader(tt.statuses[attempts])
+				attempts++
+			}))
+			defer server.Close()
+
+			sink := New(server.URL, "s3cret", 2)
+			sink.Backoff = 0
+			err := sink.Send("report", body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("got %d attempts, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Reference value from: printf '{"a":1}' | openssl dgst -sha256 -hmac key
+	want := "sha256=88a67f24bbcdaed0e6c997404bb79a743baf44c6bab2f4c27328e3009d22e342"
+	if got := Sign("key", []byte(`{"a":1}`)); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"code-analyzer/config"
+	"code-analyzer/models"
+	"code-analyzer/utils"
+	"code-analyzer/webhook"
+)
+
+// findingsReportVersion is bumped when a field of the findings report is
+// removed or changes meaning
+const findingsReportVersion = 1
+
+// buildFindingsReport assembles the unified findings report of the run
+func buildFindingsReport(findings []finding, baselined bool, verdict runVerdict) models.FindingsReport {
+	report := models.FindingsReport{
+		Version:    findingsReportVersion,
+		Timestamp:  utils.GetTimestamp(),
+		Project:    os.Getenv("CI_PROJECT_PATH"),
+		Pipeline:   os.Getenv("CI_PIPELINE_ID"),
+		Commit:     os.Getenv("CI_COMMIT_SHA"),
+		Ref:        os.Getenv("CI_COMMIT_REF_NAME"),
+		Baselined:  baselined,
+		Gate:       models.GateResult{Passed: verdict.passed(), Succeeded: verdict.Succeeded, Analyzers: verdict.Total, Reasons: verdict.reasons()},
+		Total:      len(findings),
+		BySeverity: map[string]int{},
+		Issues:     make([]models.ReportedIssue, 0, len(findings)),
+	}
+	for _, f := range findings {
+		report.BySeverity[f.Issue.Severity]++
+		report.Issues = append(report.Issues, reportedIssue(f))
+	}
+	return report
+}
+
+// reportedIssue converts a finding to its findings report form
+func reportedIssue(f finding) models.ReportedIssue {
+	return models.ReportedIssue{
+		Fingerprint: utils.Fingerprint(f.Issue),
+		Analyzer:    f.Analyzer,
+		CheckName:   f.checkName(),
+		Category:    f.Issue.Category,
+		Path:        f.Issue.Path,
+		Line:        f.Issue.Line,
+		Severity:    f.Issue.Severity,
+		Description: f.Issue.Description,
+		Metadata:    f.Issue.Metadata,
+		Blame:       f.Issue.Blame,
+		Owners:      f.Issue.Owners,
+		Checks:      f.Issue.Checks,
+	}
+}
+
+// sendWebhook delivers the findings report to the configured endpoint
+func sendWebhook(cfg config.WebhookConfig, report models.FindingsReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	retries := cfg.Retries
+	if retries == 0 {
+		retries = webhook.DefaultRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+	sink := webhook.New(os.ExpandEnv(cfg.URL), os.ExpandEnv(cfg.Secret), retries)
+	return sink.Send("report", body)
+}